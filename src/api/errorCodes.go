@@ -0,0 +1,52 @@
+package api
+
+// ErrorCodeHeader HTTP header carrying the machine readable code of an error reply
+//
+// Error replies keep their human readable body, the code lets clients tell errors apart without parsing it
+const ErrorCodeHeader = "X-Error-Code"
+
+// AdmissionRuleHeader HTTP header naming the admission rule that let a guest in or refused them, for display at the door
+const AdmissionRuleHeader = "X-Admission-Rule"
+
+// RequestIDHeader HTTP header identifying a request in the audit log, chosen by the client or generated by the server
+const RequestIDHeader = "X-Request-ID"
+
+// Error codes reported in the ErrorCodeHeader of error replies
+const (
+	ErrorCodeInvalidRequest        = "invalid_request"
+	ErrorCodeRequestTooLarge       = "request_too_large"
+	ErrorCodeUnsupportedMediaType  = "unsupported_media_type"
+	ErrorCodeGuestNotFound         = "guest_not_found"
+	ErrorCodeAmbiguousGuest        = "ambiguous_guest"
+	ErrorCodeTableTooSmall         = "table_too_small"
+	ErrorCodeEntourageTooBig       = "entourage_too_big"
+	ErrorCodeAlreadyCheckedIn      = "already_checked_in"
+	ErrorCodeNotArrived            = "not_arrived"
+	ErrorCodeWalkInsSeated         = "walk_ins_seated"
+	ErrorCodeNoFreeTable           = "no_free_table"
+	ErrorCodeWalkInCapReached      = "walk_in_cap_reached"
+	ErrorCodeVenueFull             = "venue_full"
+	ErrorCodeRoomNotFound          = "room_not_found"
+	ErrorCodeRoomExists            = "room_exists"
+	ErrorCodeRoomInUse             = "room_in_use"
+	ErrorCodeRoomFull              = "room_full"
+	ErrorCodeRoomAccessDenied      = "room_access_denied"
+	ErrorCodeAlreadyInRoom         = "already_in_room"
+	ErrorCodeNotInRoom             = "not_in_room"
+	ErrorCodeCategoryNotFound      = "category_not_found"
+	ErrorCodeWaitlistEntryNotFound = "waitlist_entry_not_found"
+	ErrorCodeGuestBanned           = "guest_banned"
+	ErrorCodeOutsideAdmissionHours = "outside_admission_hours"
+	ErrorCodeAgeRestricted         = "age_restricted"
+	ErrorCodeTicketRejected        = "ticket_rejected"
+	ErrorCodeTicketUsed            = "ticket_used"
+	ErrorCodeWebhookNotFound       = "webhook_not_found"
+	ErrorCodeUnauthorized          = "unauthorized"
+	ErrorCodeForbidden             = "forbidden"
+	ErrorCodeOperationNotFound     = "operation_not_found"
+	ErrorCodeNotUndoable           = "operation_not_undoable"
+	ErrorCodeUndoConflict          = "undo_conflict"
+	ErrorCodeInvalidSnapshot       = "invalid_snapshot"
+	ErrorCodeSnapshotOtherEvent    = "snapshot_of_other_event"
+	ErrorCodeInternal              = "internal_error"
+)
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"time"
+)
+
+// Guest lifecycle events delivered to webhooks
+const (
+	EventGuestAdded   = "guest_added"   // a guest was added to the guest list
+	EventGuestArrived = "guest_arrived" // a guest checked in
+	EventGuestLeft    = "guest_left"    // a guest checked out
+	EventTableFull    = "table_full"    // the last seats at a guest's table were taken, by the guest checking in or by walk-ins
+	EventGuestNoShow  = "guest_no_show" // a guest who did not arrive in time was marked as a no-show, their table released
+
+	EventOccupancyWarning  = "occupancy_warning"  // a guest coming in brought attendance to the warning share of the venue capacity
+	EventOccupancyCritical = "occupancy_critical" // a guest coming in brought attendance to the critical share of the venue capacity
+)
+
+// Events Every event type webhooks can be registered for
+var Events = []string{EventGuestAdded, EventGuestArrived, EventGuestLeft, EventTableFull, EventGuestNoShow, EventOccupancyWarning, EventOccupancyCritical}
+
+// Headers of the webhook deliveries
+const (
+	WebhookEventHeader     = "X-Guestlist-Event"
+	WebhookDeliveryHeader  = "X-Guestlist-Delivery"
+	WebhookSignatureHeader = "X-Guestlist-Signature"
+)
+
+// WebhookEvent Body of the requests delivering an event to a webhook
+//
+// # A delivery may be sent more than once, receivers tell them apart with the WebhookDeliveryHeader
+//
+// Occupancy is only sent with the occupancy events
+type WebhookEvent struct {
+	Event     string                 `json:"event"`
+	Time      time.Time              `json:"time"`
+	Guest     WebhookEventGuest      `json:"guest"`
+	Occupancy *WebhookEventOccupancy `json:"occupancy,omitempty"`
+}
+
+// WebhookEventGuest Guest an event happened to
+type WebhookEventGuest struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Table              int    `json:"table"`
+	AccompanyingGuests int    `json:"accompanying_guests"`
+	TimeArrived        string `json:"time_arrived"`
+}
+
+// WebhookEventOccupancy People in the venue once the guest of an occupancy event came in
+type WebhookEventOccupancy struct {
+	Headcount int `json:"headcount"`
+	Capacity  int `json:"capacity"`
+}
@@ -0,0 +1,522 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AddGuestRequest Body of "add a guest to the guest list" requests, an empty category adding a regular guest
+type AddGuestRequest struct {
+	Table              int    `json:"table"`
+	AccompanyingGuests int    `json:"accompanying_guests"`
+	Category           string `json:"category,omitempty"`
+}
+
+// CheckInGuestRequest Body of "guest arrives to the party" requests, Age being the guest's age if it was checked at the door
+type CheckInGuestRequest struct {
+	AccompanyingGuests int  `json:"accompanying_guests"`
+	Age                *int `json:"age,omitempty"`
+}
+
+// ScanTicketRequest Body of "guest's ticket is scanned at the door" requests, Age being the guest's age if it was checked
+type ScanTicketRequest struct {
+	Token              string `json:"token"`
+	AccompanyingGuests int    `json:"accompanying_guests"`
+	Age                *int   `json:"age,omitempty"`
+}
+
+// GuestResponse Reply to "add a guest to the guest list" and "guest arrives to the party" requests
+type GuestResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GuestListResponse Reply to "get the guest list" requests
+type GuestListResponse struct {
+	Guests []ListedGuest `json:"guests"`
+}
+
+// ListedGuest Guest of the guest list
+//
+// Walk-ins have no table of their own, SeatedWith is the ID of the guest whose table they sit at
+type ListedGuest struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Table              int    `json:"table"`
+	AccompanyingGuests int    `json:"accompanying_guests"`
+	WalkIn             bool   `json:"walk_in"`
+	SeatedWith         string `json:"seated_with,omitempty"`
+	RoomID             string `json:"room_id,omitempty"`
+	Category           string `json:"category"`
+}
+
+// SearchGuestsResponse Reply to "search the guest list" requests
+//
+// TopMatch repeats the first candidate, it is nil when no guest matches
+type SearchGuestsResponse struct {
+	Query      string            `json:"query"`
+	TopMatch   *SearchCandidate  `json:"top_match"`
+	Candidates []SearchCandidate `json:"candidates"`
+}
+
+// SearchCandidate Guest whose name is close to a search query
+type SearchCandidate struct {
+	ID                 string  `json:"id"`
+	Name               string  `json:"name"`
+	Table              int     `json:"table"`
+	AccompanyingGuests int     `json:"accompanying_guests"`
+	Arrived            bool    `json:"arrived"`
+	Score              float64 `json:"score"`
+}
+
+// ArrivedGuestsResponse Reply to "get list of guests that have arrived to the party" requests
+type ArrivedGuestsResponse struct {
+	Guests []ArrivedGuest `json:"guests"`
+}
+
+// ArrivedGuest Guest that is at the party
+type ArrivedGuest struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	AccompanyingGuests int    `json:"accompanying_guests"`
+	TimeArrived        string `json:"time_arrived"`
+	WalkIn             bool   `json:"walk_in"`
+	SeatedWith         string `json:"seated_with,omitempty"`
+	RoomID             string `json:"room_id,omitempty"`
+	InRoom             string `json:"in_room,omitempty"`
+	Category           string `json:"category"`
+}
+
+// EmptySeatsResponse Reply to "get the number of empty seats" requests
+//
+// SeatsAvailable only counts the empty seats that can be given away, the tables of the guests who did not arrive
+// being reserved for them unless they were marked as no-shows
+type EmptySeatsResponse struct {
+	SeatsEmpty     int `json:"seats_empty"`
+	SeatsAvailable int `json:"seats_available"`
+}
+
+// AmbiguousGuestResponse Reply to requests naming a guest when several guests share that name
+type AmbiguousGuestResponse struct {
+	Error      string                    `json:"error"`
+	Candidates []AmbiguousGuestCandidate `json:"candidates"`
+}
+
+// AmbiguousGuestCandidate Guest sharing the name of an ambiguous request
+type AmbiguousGuestCandidate struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Table int    `json:"table"`
+}
+
+// ValidationErrorResponse Reply to requests that do not match the OpenAPI document
+type ValidationErrorResponse struct {
+	Error      string   `json:"error"`
+	Violations []string `json:"violations"`
+}
+
+// AddWebhookRequest Body of "register a webhook" requests
+type AddWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+// WebhookResponse Registered webhook, its secret is never sent back
+type WebhookResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookListResponse Reply to "list the webhooks" requests
+type WebhookListResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+// WebhookDeliveriesResponse Reply to "get the delivery log of a webhook" requests
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
+
+// WebhookDelivery Delivery of an event to a webhook
+//
+// NextAttemptAt is only set for pending deliveries and DeliveredAt for delivered ones
+type WebhookDelivery struct {
+	ID             string     `json:"id"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error"`
+	CreatedAt      time.Time  `json:"created_at"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+// AuditRecordsResponse Reply to "get the audit log" requests
+type AuditRecordsResponse struct {
+	Records []AuditRecord `json:"records"`
+}
+
+// AuditRecord Record of a mutating operation
+//
+// Before and After are the JSON states of the target, null when it did not or no longer exists
+type AuditRecord struct {
+	Sequence     int64           `json:"sequence"`
+	Time         time.Time       `json:"time"`
+	Actor        string          `json:"actor"`
+	RequestID    string          `json:"request_id"`
+	Operation    string          `json:"operation"`
+	TargetID     string          `json:"target_id"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	PreviousHash string          `json:"previous_hash"`
+	Hash         string          `json:"hash"`
+}
+
+// AuditVerificationResponse Reply to "verify the audit log" requests
+type AuditVerificationResponse struct {
+	Valid          bool   `json:"valid"`
+	Records        int64  `json:"records"`
+	LastHash       string `json:"last_hash"`
+	BrokenSequence int64  `json:"broken_sequence,omitempty"`
+	Problem        string `json:"problem,omitempty"`
+}
+
+// OperationsResponse Reply to "get the recent operations" requests
+type OperationsResponse struct {
+	Operations []Operation `json:"operations"`
+}
+
+// Operation Operation on a guest, identified by the sequence number of its audit record
+type Operation struct {
+	ID        int64     `json:"id"`
+	Time      time.Time `json:"time"`
+	Actor     string    `json:"actor"`
+	Operation string    `json:"operation"`
+	GuestID   string    `json:"guest_id"`
+	GuestName string    `json:"guest_name"`
+	Undoable  bool      `json:"undoable"`
+}
+
+// UndoResponse Reply to "undo an operation" requests
+type UndoResponse struct {
+	Undone    int64     `json:"undone"`
+	Operation Operation `json:"operation"`
+}
+
+// UndoConflictResponse Reply to "undo an operation" requests refused because the guest changed since the operation
+type UndoConflictResponse struct {
+	Error     string      `json:"error"`
+	Conflicts []Operation `json:"conflicts"`
+}
+
+// Snapshot Description of a snapshot of the whole state of a party
+type Snapshot struct {
+	FormatVersion int       `json:"format_version"`
+	SchemaVersion int       `json:"schema_version"`
+	EventID       string    `json:"event_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	Checksum      string    `json:"checksum"`
+	Guests        int       `json:"guests"`
+	Visits        int       `json:"visits"`
+	UsedTickets   int       `json:"used_tickets"`
+	Webhooks      int       `json:"webhooks"`
+	Rooms         int       `json:"rooms"`
+	RoomAccess    int       `json:"room_access"`
+	Settings      int       `json:"settings"`
+	Waitlist      int       `json:"waitlist"`
+}
+
+// RestoreSnapshotResponse Reply to "restore a snapshot" requests
+//
+// With DryRun the changes are the ones the restoration would make, nothing was changed.
+// WebhooksWithoutSecret are the webhooks of the snapshot that were not restored, to be registered again with their secret.
+type RestoreSnapshotResponse struct {
+	DryRun                bool            `json:"dry_run"`
+	Snapshot              Snapshot        `json:"snapshot"`
+	Changes               SnapshotChanges `json:"changes"`
+	WebhooksWithoutSecret []string        `json:"webhooks_without_secret"`
+}
+
+// SnapshotChanges Changes made by restoring a snapshot to each kind of record
+type SnapshotChanges struct {
+	Guests      RecordChanges `json:"guests"`
+	Visits      RecordChanges `json:"visits"`
+	UsedTickets RecordChanges `json:"used_tickets"`
+	Webhooks    RecordChanges `json:"webhooks"`
+	Rooms       RecordChanges `json:"rooms"`
+	RoomAccess  RecordChanges `json:"room_access"`
+	Settings    RecordChanges `json:"settings"`
+	Waitlist    RecordChanges `json:"waitlist"`
+}
+
+// RecordChanges IDs of the records of one kind added, removed and changed by restoring a snapshot
+type RecordChanges struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// OccupancyReportResponse Reply to "get the occupancy report" requests
+//
+// People are counted as the guest plus their accompanying guests
+type OccupancyReportResponse struct {
+	From               time.Time          `json:"from"`
+	Until              time.Time          `json:"until"`
+	IntervalSeconds    int64              `json:"interval_seconds"`
+	Timeline           []OccupancyBin     `json:"timeline"`
+	Peak               int                `json:"peak"`
+	PeakAt             *time.Time         `json:"peak_at"`
+	Tables             []TableUtilisation `json:"tables"`
+	Visits             int                `json:"visits"`
+	AverageStaySeconds int64              `json:"average_stay_seconds"`
+	Invited            int                `json:"invited"`
+	NoShows            int                `json:"no_shows"`
+	NoShowRate         float64            `json:"no_show_rate"`
+	WalkIns            int                `json:"walk_ins"`
+}
+
+// OccupancyBin Attendance during one interval of the occupancy report
+type OccupancyBin struct {
+	Start      time.Time `json:"start"`
+	Present    int       `json:"present"`
+	Arrivals   int       `json:"arrivals"`
+	Departures int       `json:"departures"`
+}
+
+// TableUtilisation Share of a guest's table taken over the occupancy report
+type TableUtilisation struct {
+	GuestID     string  `json:"guest_id"`
+	Name        string  `json:"name"`
+	Seats       int     `json:"seats"`
+	Utilisation float64 `json:"utilisation"`
+}
+
+// SetNoShowRequest Body of "mark a guest as a no-show" requests
+//
+// NoShow false holds the guest's reservation instead, so that they are not marked automatically
+type SetNoShowRequest struct {
+	NoShow bool `json:"no_show"`
+}
+
+// SetNoShowCutoffRequest Body of "set the no-show cutoff" requests, a nil cutoff disabling no-show detection
+type SetNoShowCutoffRequest struct {
+	Cutoff *time.Time `json:"cutoff"`
+}
+
+// NoShowGuest No-show status of a guest who did not arrive
+type NoShowGuest struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Table           int        `json:"table"`
+	NoShowAt        *time.Time `json:"no_show_at"`
+	ReservationHeld bool       `json:"reservation_held"`
+}
+
+// NoShowsResponse Reply to "get the no-shows" and "release the tables of the guests who did not arrive" requests
+type NoShowsResponse struct {
+	Cutoff        *time.Time    `json:"cutoff"`
+	SeatsReleased int           `json:"seats_released"`
+	Guests        []NoShowGuest `json:"guests"`
+}
+
+// NoShowCutoffResponse Reply to "set the no-show cutoff" requests
+type NoShowCutoffResponse struct {
+	Cutoff *time.Time `json:"cutoff"`
+}
+
+// AdmitWalkInRequest Body of "admit a walk-in" requests
+//
+// SeatedWith is the ID of the guest whose table the walk-in sits at, the free table fitting the party best is chosen when empty.
+// Age is the walk-in's age if it was checked at the door.
+type AdmitWalkInRequest struct {
+	Name               string `json:"name"`
+	AccompanyingGuests int    `json:"accompanying_guests"`
+	SeatedWith         string `json:"seated_with"`
+	Age                *int   `json:"age,omitempty"`
+}
+
+// SetWalkInCapRequest Body of "set the walk-in cap" requests, a nil cap letting any number of walk-ins in
+type SetWalkInCapRequest struct {
+	Cap *int `json:"cap"`
+}
+
+// WalkInResponse Walk-in admitted to the party
+type WalkInResponse struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	AccompanyingGuests int    `json:"accompanying_guests"`
+	TimeArrived        string `json:"time_arrived"`
+	SeatedWith         string `json:"seated_with"`
+}
+
+// FreeTable Table with empty seats that can be given to walk-ins
+type FreeTable struct {
+	GuestID    string `json:"guest_id"`
+	Name       string `json:"name"`
+	EmptySeats int    `json:"empty_seats"`
+}
+
+// WalkInsResponse Reply to "get the walk-ins" requests
+type WalkInsResponse struct {
+	Cap        *int             `json:"cap"`
+	Admitted   int              `json:"admitted"`
+	WalkIns    []WalkInResponse `json:"walk_ins"`
+	FreeTables []FreeTable      `json:"free_tables"`
+}
+
+// WalkInCapResponse Reply to "set the walk-in cap" requests
+type WalkInCapResponse struct {
+	Cap *int `json:"cap"`
+}
+
+// SetVenueCapacityRequest Body of "set the venue capacity" requests, a nil capacity removing the limit
+type SetVenueCapacityRequest struct {
+	Capacity *int `json:"capacity"`
+}
+
+// HeadcountResponse Reply to "get the live headcount" and "set the venue capacity" requests
+//
+// Capacity and Remaining are nil when the venue has no capacity
+type HeadcountResponse struct {
+	Headcount         int     `json:"headcount"`
+	Capacity          *int    `json:"capacity"`
+	Remaining         *int    `json:"remaining"`
+	Level             string  `json:"level"`
+	WarningThreshold  float64 `json:"warning_threshold"`
+	CriticalThreshold float64 `json:"critical_threshold"`
+}
+
+// AddRoomRequest Body of "add a room" requests, a nil capacity letting any number of people in the room
+type AddRoomRequest struct {
+	Capacity   *int `json:"capacity"`
+	Restricted bool `json:"restricted"`
+}
+
+// SetRoomCapacityRequest Body of "set the capacity of a room" requests, a nil capacity removing the limit
+type SetRoomCapacityRequest struct {
+	Capacity *int `json:"capacity"`
+}
+
+// AssignRoomRequest Body of "put a guest's table in a room" requests, a nil room taking the table out of any room
+type AssignRoomRequest struct {
+	Room *string `json:"room"`
+}
+
+// RoomResponse Room of the venue with the people in it and the seats at its tables
+//
+// Capacity and Remaining are nil when the room has no capacity
+type RoomResponse struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Capacity       *int   `json:"capacity"`
+	Restricted     bool   `json:"restricted"`
+	Headcount      int    `json:"headcount"`
+	Remaining      *int   `json:"remaining"`
+	Tables         int    `json:"tables"`
+	SeatsEmpty     int    `json:"seats_empty"`
+	SeatsAvailable int    `json:"seats_available"`
+}
+
+// RoomsResponse Reply to "list the rooms" requests
+type RoomsResponse struct {
+	Rooms []RoomResponse `json:"rooms"`
+}
+
+// SetCategoryRequest Body of "put a guest in a category" requests
+type SetCategoryRequest struct {
+	Category string `json:"category"`
+}
+
+// SetCategoryRulesRequest Body of "set the rules of a category" requests, a nil entourage limit removing it
+// and a nil waitlist priority keeping the category's
+type SetCategoryRulesRequest struct {
+	ReservedTable         bool `json:"reserved_table"`
+	BypassCapacity        bool `json:"bypass_capacity"`
+	MaxAccompanyingGuests *int `json:"max_accompanying_guests"`
+	WaitlistPriority      *int `json:"waitlist_priority,omitempty"`
+}
+
+// CategoryResponse Category of guests with its rules and attendance figures
+//
+// MaxAccompanyingGuests is nil when the category has no entourage limit
+type CategoryResponse struct {
+	Category              string `json:"category"`
+	ReservedTable         bool   `json:"reserved_table"`
+	BypassCapacity        bool   `json:"bypass_capacity"`
+	MaxAccompanyingGuests *int   `json:"max_accompanying_guests"`
+	WaitlistPriority      int    `json:"waitlist_priority"`
+	Guests                int    `json:"guests"`
+	ArrivedGuests         int    `json:"arrived_guests"`
+	PeopleAtParty         int    `json:"people_at_party"`
+}
+
+// CategoriesResponse Reply to "list the categories" requests
+type CategoriesResponse struct {
+	Categories []CategoryResponse `json:"categories"`
+}
+
+// JoinWaitlistRequest Body of "join the waitlist" requests, an empty category standing for regular guests
+type JoinWaitlistRequest struct {
+	Name               string `json:"name"`
+	AccompanyingGuests int    `json:"accompanying_guests"`
+	Category           string `json:"category"`
+	Age                *int   `json:"age,omitempty"`
+}
+
+// WaitlistEntryResponse Party waiting for seats, with the waitlist priority of its category
+type WaitlistEntryResponse struct {
+	ID                 string    `json:"id"`
+	Name               string    `json:"name"`
+	AccompanyingGuests int       `json:"accompanying_guests"`
+	Category           string    `json:"category"`
+	Priority           int       `json:"priority"`
+	JoinedAt           time.Time `json:"joined_at"`
+}
+
+// WaitlistResponse Reply to "get the waitlist" requests, the parties in the order they are seated
+type WaitlistResponse struct {
+	Waitlist []WaitlistEntryResponse `json:"waitlist"`
+}
+
+// SeatWaitlistResponse Reply to "seat the waitlist" requests: the walk-ins seated and the parties still waiting
+type SeatWaitlistResponse struct {
+	Seated   []WalkInResponse        `json:"seated"`
+	Waitlist []WaitlistEntryResponse `json:"waitlist"`
+}
+
+// AdmissionRuleConfig Admission rule as written in rules files, only the fields of the rule's kind being used
+type AdmissionRuleConfig struct {
+	Rule       string         `json:"rule"`
+	Names      []string       `json:"names,omitempty"`       // ban_list: names of the banned guests
+	GuestIDs   []string       `json:"guest_ids,omitempty"`   // ban_list: IDs of the banned guests
+	Limits     map[string]int `json:"limits,omitempty"`      // entourage_limit: largest entourage of each category
+	Opens      *time.Time     `json:"opens,omitempty"`       // time_window: when guests are first let in
+	Closes     *time.Time     `json:"closes,omitempty"`      // time_window: when guests are last let in
+	MinimumAge int            `json:"minimum_age,omitempty"` // age_restriction: age under which guests are refused
+}
+
+// SetAdmissionRulesRequest Body of "set the admission rules" requests, also the rules of an event in rules files
+type SetAdmissionRulesRequest struct {
+	Rules []AdmissionRuleConfig `json:"rules"`
+}
+
+// AdmissionRulesFile Rules file giving the admission rules of each event, by event identifier
+type AdmissionRulesFile struct {
+	Events map[string]SetAdmissionRulesRequest `json:"events"`
+}
+
+// AdmissionRuleResponse Admission rule with what it checks
+type AdmissionRuleResponse struct {
+	Rule        string `json:"rule"`
+	Description string `json:"description"`
+}
+
+// AdmissionRulesResponse Reply to "list the admission rules" requests, the rules being checked in order
+type AdmissionRulesResponse struct {
+	Event string                  `json:"event"`
+	Rules []AdmissionRuleResponse `json:"rules"`
+}
@@ -1,13 +1,204 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"guestListChallenge/src/auth"
 	"guestListChallenge/src/database"
+	"guestListChallenge/src/grpcServer"
+	"guestListChallenge/src/guestService"
 	"guestListChallenge/src/requestRouting"
+	"guestListChallenge/src/utils"
+	"log"
+	"os"
+	"time"
 )
 
 // main App entrypoint
+//
+// Running "app migrate up|down|status" manages the database schema instead of serving requests.
+// Running "app -simulate-time 22:30" rehearses the party as if it started at the given time.
+// The REST and gRPC APIs are served together, on the addresses given by -http-address and -grpc-address,
+// while guest lifecycle events are delivered to the registered webhooks and snapshots are saved to -snapshot-dir.
+// Guests who did not arrive by -no-show-cutoff are marked as no-shows and their tables released,
+// while at most -walk-in-cap people not on the guest list are admitted at the door and at most -venue-capacity people are let in at once.
+// Who is admitted is decided by the rules of the event in the -admission-rules file, when one is given.
+// Guests are stored in MySQL unless -db-driver chooses postgres or sqlite.
 func main() {
-	database.Connect()
-	requestRouting.Setup()
-	requestRouting.ListenForRequests()
+	config := requestRouting.DefaultConfig()
+	grpcConfig := grpcServer.DefaultConfig()
+	databaseConfig := database.DefaultConfig()
+
+	simulatedTime := flag.String("simulate-time", "", "rehearse the party with a clock starting at this time (hours:minutes or RFC 3339)")
+	admissionRules := flag.String("admission-rules", "", "rules file giving the admission rules of each event, the table, entourage and venue rules applying when empty")
+	noShowCutoff := flag.String("no-show-cutoff", "", "mark guests who did not arrive by this time as no-shows and release their tables (hours:minutes or RFC 3339)")
+	flag.IntVar(&config.WalkIns.Cap, "walk-in-cap", config.WalkIns.Cap, "largest number of people admitted as walk-ins, accompanying guests included, no cap when negative")
+	flag.IntVar(&config.Venue.Capacity, "venue-capacity", config.Venue.Capacity, "largest number of people in the venue at once, accompanying guests included, no limit when zero")
+	flag.Float64Var(&config.Venue.WarningThreshold, "occupancy-warning", config.Venue.WarningThreshold, "share of the venue capacity at which the occupancy_warning event is emitted")
+	flag.Float64Var(&config.Venue.CriticalThreshold, "occupancy-critical", config.Venue.CriticalThreshold, "share of the venue capacity at which the occupancy_critical event is emitted")
+	flag.StringVar(&config.NetworkAddress, "http-address", config.NetworkAddress, "TCP network address of the REST API")
+	flag.StringVar(&grpcConfig.NetworkAddress, "grpc-address", grpcConfig.NetworkAddress, "TCP network address of the gRPC API")
+	flag.StringVar(&databaseConfig.Driver, "db-driver", databaseConfig.Driver, "database driver: mysql, postgres or sqlite")
+	flag.StringVar(&databaseConfig.ServerName, "db-server", databaseConfig.ServerName, "host name of the mysql or postgres server")
+	flag.StringVar(&databaseConfig.ServerPort, "db-port", databaseConfig.ServerPort, "port of the mysql or postgres server, the driver's default when empty")
+	flag.StringVar(&databaseConfig.Path, "db-path", databaseConfig.Path, "database file of the sqlite driver")
+	flag.StringVar(&config.Snapshots.Directory, "snapshot-dir", config.Snapshots.Directory, "directory of the automatic snapshots, disabled when empty")
+	flag.DurationVar(&config.Snapshots.Interval, "snapshot-interval", config.Snapshots.Interval, "how often an automatic snapshot is saved")
+	flag.IntVar(&config.Snapshots.Retention, "snapshot-retention", config.Snapshots.Retention, "number of automatic snapshots kept")
+	flag.BoolVar(&config.Webhooks.AllowPrivateTargets, "webhooks-allow-private", config.Webhooks.AllowPrivateTargets, "let webhooks send events to loopback, private and link-local addresses")
+	flag.Parse()
+
+	if flag.Arg(0) == "migrate" {
+		os.Exit(migrate(databaseConfig, flag.Args()[1:]))
+	}
+
+	var clock utils.Clock = utils.RealClock{}
+	if *simulatedTime != "" {
+		start, parseError := utils.ParseSimulatedTime(*simulatedTime, time.Now())
+		if parseError != nil {
+			fmt.Println(parseError.Error())
+			panic("Invalid simulated time")
+		}
+		clock = utils.NewSimulatedClock(start)
+		fmt.Println("Simulating party starting at " + start.Format(time.RFC3339))
+	}
+	if *noShowCutoff != "" {
+		cutoff, parseError := utils.ParseSimulatedTime(*noShowCutoff, clock.Now())
+		if parseError != nil {
+			fmt.Println(parseError.Error())
+			panic("Invalid no-show cutoff")
+		}
+		config.NoShows.Cutoff = cutoff
+	}
+	if *admissionRules != "" {
+		policy, loadError := guestService.LoadAdmissionRules(*admissionRules, config.EventID)
+		if loadError != nil {
+			fmt.Println(loadError.Error())
+			panic("Invalid admission rules")
+		}
+		config.Admission = policy
+	}
+
+	store, connectionError := database.Connect(databaseConfig)
+	if connectionError != nil {
+		fmt.Println(connectionError.Error())
+		panic("Failed to connect to Database")
+	}
+	defer store.Close()
+
+	config.TicketKey = []byte(os.Getenv("GUESTLIST_TICKET_KEY"))
+
+	apiKeys, parseError := auth.ParseAPIKeys(os.Getenv("GUESTLIST_API_KEYS"))
+	if parseError != nil {
+		fmt.Println(parseError.Error())
+		panic("Invalid API keys")
+	}
+	config.APIKeys = apiKeys
+	grpcConfig.APIKeys = apiKeys
+
+	adminKeys, parseError := auth.ParseAPIKeys(os.Getenv("GUESTLIST_ADMIN_KEYS"))
+	if parseError != nil {
+		fmt.Println(parseError.Error())
+		panic("Invalid admin keys")
+	}
+	config.AdminKeys = adminKeys
+
+	logger := log.New(os.Stdout, "", log.LstdFlags)
+	server := requestRouting.NewServer(store, clock, logger, config)
+	rpcServer := grpcServer.NewServer(server.GuestService(), logger, grpcConfig)
+
+	// Deliver webhook events in the background, starting with those left pending by a previous run
+	go server.Webhooks().Run(context.Background())
+
+	// Save snapshots of the party in the background
+	go server.Snapshots().Run(context.Background())
+
+	// Release the tables of the guests who did not arrive by the cutoff
+	go server.GuestService().RunNoShowDetection(context.Background(), config.NoShows.CheckInterval)
+
+	// Serve both APIs until one of them fails
+	rpcError := make(chan error, 1)
+	go func() {
+		rpcError <- rpcServer.ListenAndServe()
+	}()
+	routingError := make(chan error, 1)
+	go func() {
+		routingError <- server.ListenAndServe()
+	}()
+
+	select {
+	case serveError := <-rpcError:
+		fmt.Println(serveError.Error())
+		panic("Failed to setup gRPC server")
+	case serveError := <-routingError:
+		fmt.Println(serveError.Error())
+		panic("Failed to setup request Router")
+	}
+}
+
+// migrate Runs the "migrate" subcommand and returns the process exit code
+func migrate(databaseConfig database.Config, arguments []string) int {
+	if len(arguments) != 1 {
+		fmt.Println("Usage: app migrate up|down|status")
+		return 2
+	}
+
+	store, connectionError := database.Open(databaseConfig)
+	if connectionError != nil {
+		fmt.Println(connectionError.Error())
+		return 1
+	}
+	defer store.Close()
+
+	switch arguments[0] {
+	case "up":
+		migrated, migrationError := database.MigrateUp(store.DB())
+		for _, migration := range migrated {
+			fmt.Printf("Applied migration %04d_%s\n", migration.Version, migration.Name)
+		}
+		if migrationError != nil {
+			fmt.Println(migrationError.Error())
+			return 1
+		}
+		if len(migrated) == 0 {
+			fmt.Println("Database schema is up to date")
+		}
+
+	case "down":
+		reverted, migrationError := database.MigrateDown(store.DB())
+		if migrationError != nil {
+			fmt.Println(migrationError.Error())
+			return 1
+		}
+		if reverted == nil {
+			fmt.Println("No migration to revert")
+		} else {
+			fmt.Printf("Reverted migration %04d_%s\n", reverted.Version, reverted.Name)
+		}
+
+	case "status":
+		states, statusError := database.MigrationStatus(store.DB())
+		if statusError != nil {
+			fmt.Println(statusError.Error())
+			return 1
+		}
+		for _, state := range states {
+			if state.Applied {
+				fmt.Printf("%04d_%s\tapplied %s\n", state.Version, state.Name, state.AppliedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Printf("%04d_%s\tpending\n", state.Version, state.Name)
+			}
+		}
+		if versionError := database.CheckSchemaVersion(store.DB()); versionError != nil {
+			fmt.Println(versionError.Error())
+			return 1
+		}
+
+	default:
+		fmt.Println("Usage: app migrate up|down|status")
+		return 2
+	}
+
+	return 0
 }
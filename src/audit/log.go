@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/utils"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Audited operations
+const (
+	OperationAddGuest      = "add_guest"
+	OperationCheckInGuest  = "check_in_guest"
+	OperationCheckOutGuest = "check_out_guest"
+	OperationDeleteGuest   = "delete_guest"
+	OperationAddWebhook    = "add_webhook"
+	OperationDeleteWebhook = "delete_webhook"
+
+	OperationMarkNoShow      = "mark_no_show"
+	OperationHoldReservation = "hold_reservation"
+	OperationAdmitWalkIn     = "admit_walk_in"
+
+	OperationAddRoom          = "add_room"
+	OperationDeleteRoom       = "delete_room"
+	OperationSetRoomCapacity  = "set_room_capacity"
+	OperationAssignRoom       = "assign_room"
+	OperationGrantRoomAccess  = "grant_room_access"
+	OperationRevokeRoomAccess = "revoke_room_access"
+	OperationEnterRoom        = "enter_room"
+	OperationLeaveRoom        = "leave_room"
+
+	OperationSetCategory = "set_category"
+
+	OperationChangeSetting = "change_setting"
+
+	OperationJoinWaitlist     = "join_waitlist"
+	OperationLeaveWaitlist    = "leave_waitlist"
+	OperationSeatFromWaitlist = "seat_from_waitlist"
+
+	OperationRestoreSnapshot = "restore_snapshot"
+
+	OperationUndoAddGuest      = "undo_add_guest"
+	OperationUndoCheckInGuest  = "undo_check_in_guest"
+	OperationUndoCheckOutGuest = "undo_check_out_guest"
+	OperationUndoDeleteGuest   = "undo_delete_guest"
+	OperationUndoAssignRoom    = "undo_assign_room"
+	OperationUndoEnterRoom     = "undo_enter_room"
+	OperationUndoLeaveRoom     = "undo_leave_room"
+	OperationUndoSetCategory   = "undo_set_category"
+)
+
+// verifyBatchSize Number of records read at once while verifying the log
+const verifyBatchSize = 500
+
+// Log Append-only log of every mutating operation
+//
+// Each record is chained to the previous one by its hash. Records are appended by a single Log at a time,
+// so every process writing to the same database must share it.
+type Log struct {
+	store *database.Store
+	clock utils.Clock
+	mutex sync.Mutex
+}
+
+// Verification Outcome of the verification of the audit log
+//
+// LastHash lets the log be anchored elsewhere: records removed from its end are only detected
+// by comparing it with a previously anchored hash
+type Verification struct {
+	Valid          bool
+	Records        int64
+	LastHash       string
+	BrokenSequence int64  // first record whose hash or chaining does not match, 0 if valid
+	Problem        string // what does not match in the broken record
+}
+
+// NewLog Creates a Log appending to the given store
+func NewLog(store *database.Store, clock utils.Clock) *Log {
+	return &Log{store: store, clock: clock}
+}
+
+// Record Appends the record of an operation on a target, from its state before to its state after the operation
+//
+// States are encoded in JSON, nil standing for a target that did not or no longer exists.
+// Returns the record as appended.
+func (auditLog *Log) Record(ctx context.Context, operation string, targetID string, before interface{}, after interface{}) (database.AuditRecord, error) {
+	beforeJSON, encodeError := json.Marshal(before)
+	if encodeError != nil {
+		return database.AuditRecord{}, encodeError
+	}
+	afterJSON, encodeError := json.Marshal(after)
+	if encodeError != nil {
+		return database.AuditRecord{}, encodeError
+	}
+
+	origin := OriginFrom(ctx)
+	record := database.AuditRecord{
+		// The database keeps whole seconds, the hash covers what is stored
+		Time:      auditLog.clock.Now().UTC().Truncate(time.Second),
+		Actor:     origin.Actor,
+		RequestID: origin.RequestID,
+		Operation: operation,
+		TargetID:  targetID,
+		Before:    string(beforeJSON),
+		After:     string(afterJSON),
+	}
+
+	auditLog.mutex.Lock()
+	defer auditLog.mutex.Unlock()
+
+	lastRecord, found, queryError := auditLog.store.LastAuditRecord()
+	if queryError != nil {
+		return record, queryError
+	}
+	if found {
+		record.PreviousHash = lastRecord.Hash
+	}
+	record.Hash = Hash(record)
+	return record, auditLog.store.AppendAuditRecord(&record)
+}
+
+// Records Returns at most limit records matching the filter, newest first
+func (auditLog *Log) Records(filter database.AuditFilter, limit int) ([]database.AuditRecord, error) {
+	return auditLog.store.AuditRecords(filter, limit)
+}
+
+// Verify Checks that every record is chained to the previous one and matches its hash
+func (auditLog *Log) Verify() (Verification, error) {
+	verification := Verification{Valid: true}
+	var lastSequence int64
+
+	for {
+		records, queryError := auditLog.store.AuditRecordsAfter(lastSequence, verifyBatchSize)
+		if queryError != nil {
+			return verification, queryError
+		}
+
+		for _, record := range records {
+			switch {
+			case record.PreviousHash != verification.LastHash:
+				verification.Problem = "previous hash does not match the previous record, a record was removed or changed"
+			case record.Hash != Hash(record):
+				verification.Problem = "hash does not match the record, the record was changed"
+			}
+			if verification.Problem != "" {
+				verification.Valid = false
+				verification.BrokenSequence = record.Sequence
+				return verification, nil
+			}
+			verification.Records++
+			verification.LastHash = record.Hash
+			lastSequence = record.Sequence
+		}
+
+		if len(records) < verifyBatchSize {
+			return verification, nil
+		}
+	}
+}
+
+// Hash Returns the hash of a record, covering every field but its sequence number and its own hash
+//
+// Fields are length-prefixed so that moving text from one field to the next changes the hash
+func Hash(record database.AuditRecord) string {
+	hash := sha256.New()
+	for _, field := range []string{
+		record.Time.UTC().Format(time.RFC3339),
+		record.Actor,
+		record.RequestID,
+		record.Operation,
+		record.TargetID,
+		record.Before,
+		record.After,
+		record.PreviousHash,
+	} {
+		hash.Write([]byte(strconv.Itoa(len(field)) + ":" + field))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"context"
+	"guestListChallenge/src/utils"
+)
+
+// System Actor of the operations that were not requested through an API
+const System = "system"
+
+// originKey Context key of the origin of a request
+type originKey struct{}
+
+// Origin Who sent the request causing an operation, and under which request ID
+type Origin struct {
+	Actor     string
+	RequestID string
+}
+
+// WithOrigin Returns a context carrying the origin of a request
+func WithOrigin(ctx context.Context, origin Origin) context.Context {
+	return context.WithValue(ctx, originKey{}, origin)
+}
+
+// OriginFrom Returns the origin carried by a context, System if there is none
+func OriginFrom(ctx context.Context) Origin {
+	if origin, given := ctx.Value(originKey{}).(Origin); given {
+		return origin
+	}
+	return Origin{Actor: System}
+}
+
+// maxRequestIDLength Longest request ID accepted from clients, the size of its column
+const maxRequestIDLength = 64
+
+// RequestID Returns the request ID chosen by a client if it is usable, or a new one otherwise
+//
+// Client request IDs are kept to letters, digits, dots, underscores and hyphens so that they can be logged safely
+func RequestID(clientRequestID string) string {
+	if clientRequestID == "" || len(clientRequestID) > maxRequestIDLength {
+		return utils.UUIDGenerator{}.NewID()
+	}
+	for _, character := range clientRequestID {
+		isAllowed := (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z') ||
+			(character >= '0' && character <= '9') || character == '.' || character == '_' || character == '-'
+		if !isAllowed {
+			return utils.UUIDGenerator{}.NewID()
+		}
+	}
+	return clientRequestID
+}
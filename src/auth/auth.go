@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+)
+
+// Anonymous Actor of the requests served while no API key is configured
+const Anonymous = "anonymous"
+
+// bearerPrefix Prefix of the Authorization header values carrying an API key
+const bearerPrefix = "Bearer "
+
+// Authenticator Tells which actor sent a request from the API key of its Authorization header
+//
+// When no API key is configured every request is accepted as sent by Anonymous.
+// Admin keys are accepted as well, and are the only ones giving access to the administration of the server.
+type Authenticator struct {
+	actorsByKey map[string]string
+	adminsByKey map[string]string
+}
+
+// NewAuthenticator Creates an Authenticator accepting the given API keys and admin keys, each mapped to the actor using it
+func NewAuthenticator(actorsByKey map[string]string, adminsByKey map[string]string) *Authenticator {
+	return &Authenticator{actorsByKey: actorsByKey, adminsByKey: adminsByKey}
+}
+
+// Enabled Checks if requests have to carry an API key
+func (authenticator *Authenticator) Enabled() bool {
+	return len(authenticator.actorsByKey) > 0
+}
+
+// Actor Returns the actor using the API key of an Authorization header value
+//
+// ok is false if API keys are configured and the header does not carry one of them
+func (authenticator *Authenticator) Actor(authorization string) (actor string, ok bool) {
+	if actor, ok = authenticator.Admin(authorization); ok {
+		return actor, true
+	}
+	if !authenticator.Enabled() {
+		return Anonymous, true
+	}
+	return findActor(authenticator.actorsByKey, authorization)
+}
+
+// Admin Returns the actor using the admin key of an Authorization header value
+//
+// ok is false if the header does not carry one of the admin keys, always when none is configured
+func (authenticator *Authenticator) Admin(authorization string) (actor string, ok bool) {
+	return findActor(authenticator.adminsByKey, authorization)
+}
+
+// findActor Returns the actor using the API key carried by an Authorization header value, ok is false if none does
+func findActor(actorsByKey map[string]string, authorization string) (actor string, ok bool) {
+	if !strings.HasPrefix(authorization, bearerPrefix) {
+		return "", false
+	}
+
+	// Compare every key in constant time so that response times do not leak them
+	presentedKey := []byte(strings.TrimPrefix(authorization, bearerPrefix))
+	for key, keyActor := range actorsByKey {
+		if subtle.ConstantTimeCompare(presentedKey, []byte(key)) == 1 {
+			actor, ok = keyActor, true
+		}
+	}
+	return actor, ok
+}
+
+// ParseAPIKeys Parses API keys configured as comma separated "actor:key" pairs
+func ParseAPIKeys(value string) (map[string]string, error) {
+	actorsByKey := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		separatorIndex := strings.Index(pair, ":")
+		if separatorIndex <= 0 || separatorIndex == len(pair)-1 {
+			return nil, errors.New("API keys must be given as actor:key pairs")
+		}
+		actorsByKey[pair[separatorIndex+1:]] = pair[:separatorIndex]
+	}
+	return actorsByKey, nil
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/client"
+	"text/tabwriter"
+)
+
+// newAPIClient Creates a client for the server selected by the profile and flags
+func newAPIClient(settings *options) (*client.Client, error) {
+	connection, profileError := loadProfile(settings.profileName)
+	if profileError != nil {
+		return nil, profileError
+	}
+	if settings.serverURL != "" {
+		connection.ServerURL = settings.serverURL
+	}
+	if settings.apiKey != "" {
+		connection.APIKey = settings.apiKey
+	}
+
+	config := client.DefaultConfig()
+	config.APIKey = connection.APIKey
+	return client.NewClient(connection.ServerURL, config), nil
+}
+
+// exitCode Returns the exit code matching an error of the client
+func exitCode(requestError error) int {
+	var replyError *client.Error
+	if !errors.As(requestError, &replyError) {
+		return ExitUnavailable
+	}
+
+	switch replyError.Code {
+	case "":
+		if replyError.Status >= 500 {
+			return ExitUnavailable
+		}
+		return ExitInvalidRequest
+	case api.ErrorCodeGuestNotFound, api.ErrorCodeOperationNotFound:
+		return ExitNotFound
+	case api.ErrorCodeAmbiguousGuest:
+		return ExitAmbiguous
+	case api.ErrorCodeInvalidRequest, api.ErrorCodeRequestTooLarge, api.ErrorCodeUnsupportedMediaType:
+		return ExitInvalidRequest
+	case api.ErrorCodeInternal:
+		return ExitUnavailable
+	default:
+		return ExitRefused
+	}
+}
+
+// reportFailure Prints why a request failed and returns the matching exit code
+func reportFailure(requestError error, settings *options) int {
+	var replyError *client.Error
+	if !errors.As(requestError, &replyError) {
+		fmt.Fprintln(settings.stderr, requestError.Error())
+		return exitCode(requestError)
+	}
+
+	if settings.json {
+		printJSON(settings, replyError)
+		return exitCode(requestError)
+	}
+
+	switch {
+	case len(replyError.Violations) > 0:
+		fmt.Fprintln(settings.stderr, replyError.Message+":")
+		for _, violation := range replyError.Violations {
+			fmt.Fprintln(settings.stderr, "  "+violation)
+		}
+
+	case len(replyError.Candidates) > 0:
+		fmt.Fprintln(settings.stderr, "Several guests share this name, choose one with --id:")
+		table := tabwriter.NewWriter(settings.stderr, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "ID\tNAME\tTABLE")
+		for _, candidate := range replyError.Candidates {
+			fmt.Fprintf(table, "%s\t%s\t%d\n", candidate.ID, candidate.Name, candidate.Table)
+		}
+		table.Flush()
+
+	case len(replyError.Conflicts) > 0:
+		fmt.Fprintln(settings.stderr, replyError.Message+":")
+		printOperations(settings.stderr, replyError.Conflicts)
+
+	default:
+		fmt.Fprintln(settings.stderr, replyError.Message)
+	}
+	return exitCode(requestError)
+}
+
+// printJSON Prints a reply as JSON
+func printJSON(settings *options, reply interface{}) {
+	encoder := json.NewEncoder(settings.stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(reply)
+}
@@ -0,0 +1,135 @@
+// Package cli Command-line client of the guest list server, sending requests to its REST API for the door staff and organisers
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Exit codes of the command-line client
+const (
+	ExitOK             = 0 // the operation succeeded
+	ExitRefused        = 1 // the server refused the operation: table too small, entourage too big, already checked in, not arrived
+	ExitUsage          = 2 // the command line is wrong
+	ExitInvalidRequest = 3 // the server rejected the request as malformed
+	ExitNotFound       = 4 // the guest is not in the guest list, or the operation to undo does not exist
+	ExitAmbiguous      = 5 // several guests share the name, one must be chosen by ID
+	ExitUnavailable    = 6 // the server could not be reached or failed
+)
+
+// options Settings shared by every command
+type options struct {
+	profileName string
+	serverURL   string
+	apiKey      string
+	json        bool
+	stdout      io.Writer
+	stderr      io.Writer
+}
+
+// command A subcommand of the client
+type command struct {
+	usage       string
+	description string
+	run         func(arguments []string, settings *options) int
+}
+
+// commands Every subcommand of the client, by name
+var commands map[string]command
+
+// init Registers the subcommands, which refer to commands for their usage
+func init() {
+	commands = map[string]command{
+		"add":      {"add <name> --table <seats> [--accompanying <guests>]", "Add a guest to the guest list", runAdd},
+		"list":     {"list", "Show the guest list", runList},
+		"checkin":  {"checkin (<name> | --id <id>) [--accompanying <guests>]", "Check a guest in when arriving", runCheckIn},
+		"checkout": {"checkout (<name> | --id <id>)", "Check a guest out when leaving", runCheckOut},
+		"arrived":  {"arrived", "Show the guests that are at the party", runArrived},
+		"seats":    {"seats", "Show the number of empty seats", runSeats},
+		"recent":   {"recent [--limit <operations>]", "Show the latest operations on guests, to find one to undo", runRecent},
+		"undo":     {"undo <operation-id>", "Undo an operation on a guest shown by recent", runUndo},
+		"import":   {"import <file.csv>", "Add every guest of a CSV file with name, table and accompanying_guests columns", runImport},
+		"export":   {"export [file.csv]", "Write the guest list as CSV, to standard output if no file is given", runExport},
+	}
+}
+
+// Run Runs the subcommand named by the first argument, writing its output to stdout and stderr, and returns the process exit code
+func Run(arguments []string, stdout io.Writer, stderr io.Writer) int {
+	return run(arguments, &options{stdout: stdout, stderr: stderr})
+}
+
+// run Runs a subcommand and returns the process exit code
+func run(arguments []string, settings *options) int {
+	if len(arguments) == 0 {
+		printUsage(settings.stderr)
+		return ExitUsage
+	}
+
+	selectedCommand, exists := commands[arguments[0]]
+	if !exists {
+		if arguments[0] == "help" || arguments[0] == "-h" || arguments[0] == "--help" {
+			printUsage(settings.stdout)
+			return ExitOK
+		}
+		fmt.Fprintf(settings.stderr, "Unknown command %q\n\n", arguments[0])
+		printUsage(settings.stderr)
+		return ExitUsage
+	}
+
+	return selectedCommand.run(arguments[1:], settings)
+}
+
+// newFlagSet Creates the flag set of a subcommand with the flags shared by every command
+func newFlagSet(name string, settings *options) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(settings.stderr)
+	flags.StringVar(&settings.profileName, "profile", os.Getenv("GUESTLIST_PROFILE"), "profile of the profile file to use")
+	flags.StringVar(&settings.serverURL, "server", os.Getenv("GUESTLIST_SERVER_URL"), "server URL, overriding the profile's")
+	flags.StringVar(&settings.apiKey, "api-key", os.Getenv("GUESTLIST_API_KEY"), "API key, overriding the profile's")
+	flags.BoolVar(&settings.json, "json", false, "print the server's JSON replies")
+	flags.Usage = func() {
+		fmt.Fprintf(settings.stderr, "Usage: guestlist %s\n\n%s\n\nFlags:\n", commands[name].usage, commands[name].description)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags Parses the flags of a subcommand, flags and arguments may be interleaved
+//
+// Returns the positional arguments, ok is false if the command line is wrong
+func parseFlags(flags *flag.FlagSet, arguments []string) (positional []string, ok bool) {
+	for {
+		if parseError := flags.Parse(arguments); parseError != nil {
+			return nil, false
+		}
+		arguments = flags.Args()
+		if len(arguments) == 0 {
+			return positional, true
+		}
+		positional = append(positional, arguments[0])
+		arguments = arguments[1:]
+	}
+}
+
+// printUsage Prints the list of subcommands
+func printUsage(output io.Writer) {
+	fmt.Fprintln(output, "Usage: guestlist <command> [flags] [arguments]")
+	fmt.Fprintln(output)
+	fmt.Fprintln(output, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(output, "  %-9s %s\n", name, commands[name].description)
+	}
+
+	fmt.Fprintln(output)
+	fmt.Fprintln(output, "Every command accepts --profile, --server, --api-key and --json.")
+	fmt.Fprintln(output, "Run \"guestlist <command> -h\" for the flags of a command.")
+}
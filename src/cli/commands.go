@@ -0,0 +1,369 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"guestListChallenge/src/api"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// importColumns Columns of the CSV files read by import and written by export
+var importColumns = []string{"name", "table", "accompanying_guests"}
+
+// runAdd Adds a guest to the guest list
+func runAdd(arguments []string, settings *options) int {
+	flags := newFlagSet("add", settings)
+	table := flags.Int("table", 0, "number of seats at the guest's table")
+	accompanyingGuests := flags.Int("accompanying", 0, "number of accompanying guests")
+	positional, ok := parseFlags(flags, arguments)
+	if !ok || len(positional) != 1 || *table <= 0 {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	connection, clientError := newAPIClient(settings)
+	if clientError != nil {
+		fmt.Fprintln(settings.stderr, clientError.Error())
+		return ExitUsage
+	}
+	added, requestError := connection.AddGuest(context.Background(), positional[0], *table, *accompanyingGuests)
+	return printReply(settings, added, requestError, func() {
+		fmt.Fprintf(settings.stdout, "Added %s (ID %s)\n", added.Name, added.ID)
+	})
+}
+
+// runList Prints the guest list
+func runList(arguments []string, settings *options) int {
+	flags := newFlagSet("list", settings)
+	if positional, ok := parseFlags(flags, arguments); !ok || len(positional) != 0 {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	connection, clientError := newAPIClient(settings)
+	if clientError != nil {
+		fmt.Fprintln(settings.stderr, clientError.Error())
+		return ExitUsage
+	}
+	guestList, requestError := connection.GuestList(context.Background())
+	return printReply(settings, api.GuestListResponse{Guests: guestList}, requestError, func() {
+		table := tabwriter.NewWriter(settings.stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "NAME\tTABLE\tACCOMPANYING\tID")
+		for _, guest := range guestList {
+			fmt.Fprintf(table, "%s\t%d\t%d\t%s\n", guest.Name, guest.Table, guest.AccompanyingGuests, guest.ID)
+		}
+		table.Flush()
+	})
+}
+
+// runCheckIn Checks a guest in, chosen by name or by ID
+func runCheckIn(arguments []string, settings *options) int {
+	flags := newFlagSet("checkin", settings)
+	guestID := flags.String("id", "", "ID of the guest, for guests sharing their name")
+	accompanyingGuests := flags.Int("accompanying", 0, "number of accompanying guests arriving with the guest")
+	positional, ok := parseFlags(flags, arguments)
+	if !ok || !validGuestChoice(positional, *guestID) {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	connection, clientError := newAPIClient(settings)
+	if clientError != nil {
+		fmt.Fprintln(settings.stderr, clientError.Error())
+		return ExitUsage
+	}
+	var arrived api.GuestResponse
+	var requestError error
+	if *guestID != "" {
+		arrived, requestError = connection.CheckInGuestByID(context.Background(), *guestID, *accompanyingGuests)
+	} else {
+		arrived, requestError = connection.CheckInGuest(context.Background(), positional[0], *accompanyingGuests)
+	}
+	return printReply(settings, arrived, requestError, func() {
+		fmt.Fprintf(settings.stdout, "Checked in %s (ID %s)\n", arrived.Name, arrived.ID)
+	})
+}
+
+// runCheckOut Checks a guest out, chosen by name or by ID
+func runCheckOut(arguments []string, settings *options) int {
+	flags := newFlagSet("checkout", settings)
+	guestID := flags.String("id", "", "ID of the guest, for guests sharing their name")
+	positional, ok := parseFlags(flags, arguments)
+	if !ok || !validGuestChoice(positional, *guestID) {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	connection, clientError := newAPIClient(settings)
+	if clientError != nil {
+		fmt.Fprintln(settings.stderr, clientError.Error())
+		return ExitUsage
+	}
+	var message string
+	var requestError error
+	if *guestID != "" {
+		message, requestError = connection.CheckOutGuestByID(context.Background(), *guestID)
+	} else {
+		message, requestError = connection.CheckOutGuest(context.Background(), positional[0])
+	}
+	return printReply(settings, message, requestError, func() {
+		fmt.Fprintln(settings.stdout, message)
+	})
+}
+
+// runArrived Prints the guests that are at the party
+func runArrived(arguments []string, settings *options) int {
+	flags := newFlagSet("arrived", settings)
+	if positional, ok := parseFlags(flags, arguments); !ok || len(positional) != 0 {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	connection, clientError := newAPIClient(settings)
+	if clientError != nil {
+		fmt.Fprintln(settings.stderr, clientError.Error())
+		return ExitUsage
+	}
+	arrivedGuests, requestError := connection.ArrivedGuests(context.Background())
+	return printReply(settings, api.ArrivedGuestsResponse{Guests: arrivedGuests}, requestError, func() {
+		table := tabwriter.NewWriter(settings.stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "NAME\tACCOMPANYING\tARRIVED\tID")
+		for _, guest := range arrivedGuests {
+			fmt.Fprintf(table, "%s\t%d\t%s\t%s\n", guest.Name, guest.AccompanyingGuests, guest.TimeArrived, guest.ID)
+		}
+		table.Flush()
+	})
+}
+
+// runSeats Prints the number of empty seats
+func runSeats(arguments []string, settings *options) int {
+	flags := newFlagSet("seats", settings)
+	if positional, ok := parseFlags(flags, arguments); !ok || len(positional) != 0 {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	connection, clientError := newAPIClient(settings)
+	if clientError != nil {
+		fmt.Fprintln(settings.stderr, clientError.Error())
+		return ExitUsage
+	}
+	seatsEmpty, requestError := connection.EmptySeats(context.Background())
+	return printReply(settings, api.EmptySeatsResponse{SeatsEmpty: seatsEmpty}, requestError, func() {
+		fmt.Fprintf(settings.stdout, "%d empty seats\n", seatsEmpty)
+	})
+}
+
+// runRecent Prints the latest operations on guests, to find the ID of one to undo
+func runRecent(arguments []string, settings *options) int {
+	flags := newFlagSet("recent", settings)
+	limit := flags.Int("limit", 20, "number of operations to show, at most 100")
+	if positional, ok := parseFlags(flags, arguments); !ok || len(positional) != 0 || *limit < 1 {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	connection, clientError := newAPIClient(settings)
+	if clientError != nil {
+		fmt.Fprintln(settings.stderr, clientError.Error())
+		return ExitUsage
+	}
+	operations, requestError := connection.RecentOperations(context.Background(), *limit)
+	return printReply(settings, api.OperationsResponse{Operations: operations}, requestError, func() {
+		printOperations(settings.stdout, operations)
+	})
+}
+
+// runUndo Reverts an operation on a guest
+func runUndo(arguments []string, settings *options) int {
+	flags := newFlagSet("undo", settings)
+	positional, ok := parseFlags(flags, arguments)
+	if !ok || len(positional) != 1 {
+		flags.Usage()
+		return ExitUsage
+	}
+	operationID, parseError := strconv.ParseInt(positional[0], 10, 64)
+	if parseError != nil {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	connection, clientError := newAPIClient(settings)
+	if clientError != nil {
+		fmt.Fprintln(settings.stderr, clientError.Error())
+		return ExitUsage
+	}
+	undoing, requestError := connection.UndoOperation(context.Background(), operationID)
+	return printReply(settings, api.UndoResponse{Undone: operationID, Operation: undoing}, requestError, func() {
+		fmt.Fprintf(settings.stdout, "Undid operation %d on %s (ID %s)\n", operationID, undoing.GuestName, undoing.GuestID)
+	})
+}
+
+// runImport Adds every guest of a CSV file to the guest list
+//
+// Every row is attempted, the exit code is the one of the first row that failed
+func runImport(arguments []string, settings *options) int {
+	flags := newFlagSet("import", settings)
+	positional, ok := parseFlags(flags, arguments)
+	if !ok || len(positional) != 1 {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	file, openError := os.Open(positional[0])
+	if openError != nil {
+		fmt.Fprintln(settings.stderr, openError.Error())
+		return ExitUsage
+	}
+	defer file.Close()
+
+	rows, readError := readGuestRows(file)
+	if readError != nil {
+		fmt.Fprintf(settings.stderr, "%s: %v\n", positional[0], readError)
+		return ExitUsage
+	}
+
+	connection, clientError := newAPIClient(settings)
+	if clientError != nil {
+		fmt.Fprintln(settings.stderr, clientError.Error())
+		return ExitUsage
+	}
+
+	exitCode, imported := ExitOK, 0
+	for rowIndex, row := range rows {
+		_, requestError := connection.AddGuest(context.Background(), row.Name, row.Table, row.AccompanyingGuests)
+		if requestError != nil {
+			fmt.Fprintf(settings.stderr, "Row %d (%s): ", rowIndex+2, row.Name)
+			rowExitCode := reportFailure(requestError, &options{stdout: settings.stderr, stderr: settings.stderr})
+			if rowExitCode == ExitUnavailable {
+				return rowExitCode
+			}
+			if exitCode == ExitOK {
+				exitCode = rowExitCode
+			}
+			continue
+		}
+		imported++
+	}
+
+	fmt.Fprintf(settings.stdout, "Imported %d of %d guests\n", imported, len(rows))
+	return exitCode
+}
+
+// runExport Writes the guest list as CSV
+func runExport(arguments []string, settings *options) int {
+	flags := newFlagSet("export", settings)
+	positional, ok := parseFlags(flags, arguments)
+	if !ok || len(positional) > 1 {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	connection, clientError := newAPIClient(settings)
+	if clientError != nil {
+		fmt.Fprintln(settings.stderr, clientError.Error())
+		return ExitUsage
+	}
+	guestList, requestError := connection.GuestList(context.Background())
+	if requestError != nil {
+		return reportFailure(requestError, settings)
+	}
+
+	output := settings.stdout
+	if len(positional) == 1 {
+		file, createError := os.Create(positional[0])
+		if createError != nil {
+			fmt.Fprintln(settings.stderr, createError.Error())
+			return ExitUsage
+		}
+		defer file.Close()
+		output = file
+	}
+
+	writer := csv.NewWriter(output)
+	writer.Write(append(append([]string{}, importColumns...), "id"))
+	for _, guest := range guestList {
+		writer.Write([]string{guest.Name, strconv.Itoa(guest.Table), strconv.Itoa(guest.AccompanyingGuests), guest.ID})
+	}
+	writer.Flush()
+	if writeError := writer.Error(); writeError != nil {
+		fmt.Fprintln(settings.stderr, writeError.Error())
+		return ExitUnavailable
+	}
+	return ExitOK
+}
+
+// printReply Prints the reply to a request, in JSON if asked or with printHuman otherwise
+func printReply(settings *options, reply interface{}, requestError error, printHuman func()) int {
+	if requestError != nil {
+		return reportFailure(requestError, settings)
+	}
+	if settings.json {
+		printJSON(settings, reply)
+	} else {
+		printHuman()
+	}
+	return ExitOK
+}
+
+// printOperations Prints operations on guests as a table
+func printOperations(output io.Writer, operations []api.Operation) {
+	table := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tTIME\tACTOR\tOPERATION\tGUEST\tUNDOABLE")
+	for _, operation := range operations {
+		undoable := "no"
+		if operation.Undoable {
+			undoable = "yes"
+		}
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\t%s\n", operation.ID, operation.Time.Local().Format("15:04:05"),
+			operation.Actor, operation.Operation, operation.GuestName, undoable)
+	}
+	table.Flush()
+}
+
+// validGuestChoice Checks that a guest is chosen either by name or by ID
+func validGuestChoice(positional []string, guestID string) bool {
+	return (guestID != "" && len(positional) == 0) || (guestID == "" && len(positional) == 1)
+}
+
+// readGuestRows Reads the guests of a CSV file whose header names the importColumns
+func readGuestRows(input io.Reader) ([]api.ListedGuest, error) {
+	records, readError := csv.NewReader(input).ReadAll()
+	if readError != nil {
+		return nil, readError
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing header row")
+	}
+
+	// Locate columns
+	columnIndexes := map[string]int{}
+	for index, column := range records[0] {
+		columnIndexes[strings.ToLower(strings.TrimSpace(column))] = index
+	}
+	for _, column := range importColumns {
+		if _, exists := columnIndexes[column]; !exists {
+			return nil, fmt.Errorf("missing %q column", column)
+		}
+	}
+
+	rows := make([]api.ListedGuest, 0, len(records)-1)
+	for recordIndex, record := range records[1:] {
+		table, tableError := strconv.Atoi(strings.TrimSpace(record[columnIndexes["table"]]))
+		accompanyingGuests, accompanyingError := strconv.Atoi(strings.TrimSpace(record[columnIndexes["accompanying_guests"]]))
+		if tableError != nil || accompanyingError != nil {
+			return nil, fmt.Errorf("row %d: table and accompanying_guests must be numbers", recordIndex+2)
+		}
+		rows = append(rows, api.ListedGuest{
+			Name:               strings.TrimSpace(record[columnIndexes["name"]]),
+			Table:              table,
+			AccompanyingGuests: accompanyingGuests,
+		})
+	}
+	return rows, nil
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// defaultServerURL Server used when no profile or flag sets one
+const defaultServerURL = "http://localhost:4242"
+
+// profile Connection settings of a guest list server
+type profile struct {
+	ServerURL string `json:"server_url"`
+	APIKey    string `json:"api_key"`
+}
+
+// profileFile Contents of the profile file
+//
+//	{
+//	    "default_profile": "venue",
+//	    "profiles": {
+//	        "venue": {"server_url": "http://10.0.0.5:4242", "api_key": "..."}
+//	    }
+//	}
+type profileFile struct {
+	DefaultProfile string             `json:"default_profile"`
+	Profiles       map[string]profile `json:"profiles"`
+}
+
+// profileFilePath Returns the path of the profile file
+//
+// GUESTLIST_CONFIG overrides the default location in the user's configuration directory
+func profileFilePath() (string, error) {
+	if path := os.Getenv("GUESTLIST_CONFIG"); path != "" {
+		return path, nil
+	}
+	configDirectory, directoryError := os.UserConfigDir()
+	if directoryError != nil {
+		return "", directoryError
+	}
+	return filepath.Join(configDirectory, "guestlist", "profiles.json"), nil
+}
+
+// loadProfile Returns the connection settings of a profile
+//
+// When no profile is named the file's default profile is used, and a missing file means default settings
+func loadProfile(name string) (profile, error) {
+	settings := profile{ServerURL: defaultServerURL}
+
+	path, pathError := profileFilePath()
+	if pathError != nil {
+		return settings, pathError
+	}
+
+	contents, readError := ioutil.ReadFile(path)
+	if errors.Is(readError, os.ErrNotExist) && name == "" {
+		return settings, nil
+	}
+	if readError != nil {
+		return settings, readError
+	}
+
+	var file profileFile
+	if decodeError := json.Unmarshal(contents, &file); decodeError != nil {
+		return settings, fmt.Errorf("%s: %v", path, decodeError)
+	}
+
+	if name == "" {
+		name = file.DefaultProfile
+		if name == "" {
+			return settings, nil
+		}
+	}
+
+	namedProfile, exists := file.Profiles[name]
+	if !exists {
+		return settings, fmt.Errorf("%s: no profile named %q", path, name)
+	}
+	if namedProfile.ServerURL != "" {
+		settings.ServerURL = namedProfile.ServerURL
+	}
+	settings.APIKey = namedProfile.APIKey
+	return settings, nil
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config Client configuration
+type Config struct {
+	APIKey     string        // sent as a bearer token when not empty
+	HTTPClient *http.Client  // client sending the requests
+	MaxRetries int           // number of times a request is sent again when the server is unavailable
+	RetryDelay time.Duration // wait before the first retry, doubled on every retry unless the server asks otherwise
+}
+
+// DefaultConfig Returns the default client configuration
+func DefaultConfig() Config {
+	return Config{
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		MaxRetries: 3,
+		RetryDelay: 500 * time.Millisecond,
+	}
+}
+
+// Client Sends requests to a guest list server
+type Client struct {
+	serverURL string
+	config    Config
+}
+
+// NewClient Creates a client for the guest list server at serverURL, e.g. "http://localhost:4242"
+func NewClient(serverURL string, config Config) *Client {
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &Client{serverURL: strings.TrimSuffix(serverURL, "/"), config: config}
+}
+
+// send Sends a request with an optional JSON body and returns the body of a successful reply
+//
+// Requests are sent again while the server replies 503, up to MaxRetries times.
+// Replies with an error code or an error status are returned as *Error.
+func (client *Client) send(ctx context.Context, method string, path string, requestData interface{}) ([]byte, error) {
+	var requestBody []byte
+	if requestData != nil {
+		var encodeError error
+		if requestBody, encodeError = json.Marshal(requestData); encodeError != nil {
+			return nil, encodeError
+		}
+	}
+
+	retryDelay := client.config.RetryDelay
+	for attempt := 0; ; attempt++ {
+		request, requestError := http.NewRequestWithContext(ctx, method, client.serverURL+path, bytes.NewReader(requestBody))
+		if requestError != nil {
+			return nil, requestError
+		}
+		if requestData != nil {
+			request.Header.Set("Content-Type", "application/json")
+		}
+		if client.config.APIKey != "" {
+			request.Header.Set("Authorization", "Bearer "+client.config.APIKey)
+		}
+
+		response, sendError := client.config.HTTPClient.Do(request)
+		if sendError != nil {
+			return nil, sendError
+		}
+		responseBody, readError := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		if readError != nil {
+			return nil, readError
+		}
+
+		// Wait and retry while the server is unavailable
+		if response.StatusCode == http.StatusServiceUnavailable && attempt < client.config.MaxRetries {
+			wait := retryDelay
+			if seconds, parseError := strconv.Atoi(response.Header.Get("Retry-After")); parseError == nil && seconds >= 0 {
+				wait = time.Duration(seconds) * time.Second
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+			retryDelay *= 2
+			continue
+		}
+
+		errorCode := response.Header.Get(api.ErrorCodeHeader)
+		if errorCode != "" || response.StatusCode >= http.StatusMultipleChoices {
+			return nil, newError(response.StatusCode, errorCode, responseBody)
+		}
+		return responseBody, nil
+	}
+}
+
+// sendJSON Sends a request and decodes the JSON body of a successful reply into replyData
+func (client *Client) sendJSON(ctx context.Context, method string, path string, requestData interface{}, replyData interface{}) error {
+	responseBody, sendError := client.send(ctx, method, path, requestData)
+	if sendError != nil {
+		return sendError
+	}
+	return json.Unmarshal(responseBody, replyData)
+}
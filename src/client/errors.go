@@ -0,0 +1,103 @@
+package client
+
+import (
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Error Error reply of the guest list server
+//
+// Errors can be told apart with errors.Is and the Err* values, which match on the error code
+// or, for replies without an error code, on the HTTP status.
+type Error struct {
+	Status     int                           `json:"status"`               // HTTP status of the reply
+	Code       string                        `json:"code"`                 // error code of the reply, one of the api.ErrorCode* constants
+	Message    string                        `json:"message"`              // human readable message of the reply
+	Violations []string                      `json:"violations,omitempty"` // problems found in an invalid request
+	Candidates []api.AmbiguousGuestCandidate `json:"candidates,omitempty"` // guests sharing the name of an ambiguous request
+	Conflicts  []api.Operation               `json:"conflicts,omitempty"`  // later operations preventing an undo
+}
+
+// Errors matching the error codes of the server
+var (
+	ErrInvalidRequest       = &Error{Code: api.ErrorCodeInvalidRequest}
+	ErrRequestTooLarge      = &Error{Code: api.ErrorCodeRequestTooLarge}
+	ErrUnsupportedMediaType = &Error{Code: api.ErrorCodeUnsupportedMediaType}
+	ErrGuestNotFound        = &Error{Code: api.ErrorCodeGuestNotFound}
+	ErrAmbiguousGuest       = &Error{Code: api.ErrorCodeAmbiguousGuest}
+	ErrTableTooSmall        = &Error{Code: api.ErrorCodeTableTooSmall}
+	ErrEntourageTooBig      = &Error{Code: api.ErrorCodeEntourageTooBig}
+	ErrAlreadyCheckedIn     = &Error{Code: api.ErrorCodeAlreadyCheckedIn}
+	ErrNotArrived           = &Error{Code: api.ErrorCodeNotArrived}
+	ErrTicketRejected       = &Error{Code: api.ErrorCodeTicketRejected}
+	ErrTicketUsed           = &Error{Code: api.ErrorCodeTicketUsed}
+	ErrWebhookNotFound      = &Error{Code: api.ErrorCodeWebhookNotFound}
+	ErrUnauthorized         = &Error{Code: api.ErrorCodeUnauthorized}
+	ErrOperationNotFound    = &Error{Code: api.ErrorCodeOperationNotFound}
+	ErrNotUndoable          = &Error{Code: api.ErrorCodeNotUndoable}
+	ErrUndoConflict         = &Error{Code: api.ErrorCodeUndoConflict}
+	ErrInternal             = &Error{Code: api.ErrorCodeInternal}
+)
+
+// ErrUnavailable Reported when the server stayed unavailable after every retry
+var ErrUnavailable = &Error{Status: http.StatusServiceUnavailable}
+
+// Error Returns the message of the reply
+func (replyError *Error) Error() string {
+	message := replyError.Message
+	if message == "" {
+		message = http.StatusText(replyError.Status)
+	}
+	if len(replyError.Violations) > 0 {
+		message += ": " + strings.Join(replyError.Violations, "; ")
+	}
+	if replyError.Code != "" {
+		return "guest list server: " + replyError.Code + ": " + message
+	}
+	return "guest list server: " + strconv.Itoa(replyError.Status) + ": " + message
+}
+
+// Is Checks if the error has the code of target, or its status when target has no code
+func (replyError *Error) Is(target error) bool {
+	targetError, isError := target.(*Error)
+	if !isError {
+		return false
+	}
+	if targetError.Code != "" {
+		return replyError.Code == targetError.Code
+	}
+	return replyError.Code == "" && replyError.Status == targetError.Status
+}
+
+// newError Creates the error of a reply that carries an error code or an error status
+//
+// The body is either a JSON string or one of the structured error replies
+func newError(status int, errorCode string, body []byte) *Error {
+	replyError := &Error{Status: status, Code: errorCode}
+
+	var message string
+	if json.Unmarshal(body, &message) == nil {
+		replyError.Message = message
+		return replyError
+	}
+
+	var details struct {
+		Error      string                        `json:"error"`
+		Violations []string                      `json:"violations"`
+		Candidates []api.AmbiguousGuestCandidate `json:"candidates"`
+		Conflicts  []api.Operation               `json:"conflicts"`
+	}
+	if json.Unmarshal(body, &details) == nil {
+		replyError.Message = details.Error
+		replyError.Violations = details.Violations
+		replyError.Candidates = details.Candidates
+		replyError.Conflicts = details.Conflicts
+		return replyError
+	}
+
+	replyError.Message = strings.TrimSpace(string(body))
+	return replyError
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"guestListChallenge/src/api"
+	"net/http"
+	"net/url"
+)
+
+// AddGuest Adds a guest to the guest list
+//
+// ErrTableTooSmall is reported if the accompanying guests do not fit at the table
+func (client *Client) AddGuest(ctx context.Context, name string, table int, accompanyingGuests int) (api.GuestResponse, error) {
+	var reply api.GuestResponse
+	requestError := client.sendJSON(ctx, http.MethodPost, "/guest_list/"+url.PathEscape(name),
+		api.AddGuestRequest{Table: table, AccompanyingGuests: accompanyingGuests}, &reply)
+	return reply, requestError
+}
+
+// GuestList Returns the guest list
+func (client *Client) GuestList(ctx context.Context) ([]api.ListedGuest, error) {
+	var reply api.GuestListResponse
+	requestError := client.sendJSON(ctx, http.MethodGet, "/guest_list", nil, &reply)
+	return reply.Guests, requestError
+}
+
+// SearchGuests Searches the guest list for guests whose name is close to the query
+func (client *Client) SearchGuests(ctx context.Context, query string) (api.SearchGuestsResponse, error) {
+	var reply api.SearchGuestsResponse
+	requestError := client.sendJSON(ctx, http.MethodGet, "/guest_list/search?q="+url.QueryEscape(query), nil, &reply)
+	return reply, requestError
+}
+
+// CheckInGuest Checks in a guest chosen by name
+//
+// ErrAmbiguousGuest is reported if several guests share the name, CheckInGuestByID must then be used
+func (client *Client) CheckInGuest(ctx context.Context, name string, accompanyingGuests int) (api.GuestResponse, error) {
+	return client.checkIn(ctx, "/guests/"+url.PathEscape(name), accompanyingGuests)
+}
+
+// CheckInGuestByID Checks in a guest chosen by ID
+func (client *Client) CheckInGuestByID(ctx context.Context, guestID string, accompanyingGuests int) (api.GuestResponse, error) {
+	return client.checkIn(ctx, "/guests/id/"+url.PathEscape(guestID), accompanyingGuests)
+}
+
+// checkIn Sends a check in request to the path of a guest
+func (client *Client) checkIn(ctx context.Context, path string, accompanyingGuests int) (api.GuestResponse, error) {
+	var reply api.GuestResponse
+	requestError := client.sendJSON(ctx, http.MethodPut, path, api.CheckInGuestRequest{AccompanyingGuests: accompanyingGuests}, &reply)
+	return reply, requestError
+}
+
+// CheckOutGuest Checks out a guest chosen by name and returns the server's message
+func (client *Client) CheckOutGuest(ctx context.Context, name string) (string, error) {
+	var message string
+	requestError := client.sendJSON(ctx, http.MethodDelete, "/guests/"+url.PathEscape(name), nil, &message)
+	return message, requestError
+}
+
+// CheckOutGuestByID Checks out a guest chosen by ID and returns the server's message
+func (client *Client) CheckOutGuestByID(ctx context.Context, guestID string) (string, error) {
+	var message string
+	requestError := client.sendJSON(ctx, http.MethodDelete, "/guests/id/"+url.PathEscape(guestID), nil, &message)
+	return message, requestError
+}
+
+// DeleteGuest Removes a guest chosen by name from the guest list and returns the server's message
+func (client *Client) DeleteGuest(ctx context.Context, name string) (string, error) {
+	var message string
+	requestError := client.sendJSON(ctx, http.MethodDelete, "/guest_list/"+url.PathEscape(name), nil, &message)
+	return message, requestError
+}
+
+// DeleteGuestByID Removes a guest chosen by ID from the guest list and returns the server's message
+func (client *Client) DeleteGuestByID(ctx context.Context, guestID string) (string, error) {
+	var message string
+	requestError := client.sendJSON(ctx, http.MethodDelete, "/guest_list/id/"+url.PathEscape(guestID), nil, &message)
+	return message, requestError
+}
+
+// ArrivedGuests Returns the guests that are at the party
+func (client *Client) ArrivedGuests(ctx context.Context) ([]api.ArrivedGuest, error) {
+	var reply api.ArrivedGuestsResponse
+	requestError := client.sendJSON(ctx, http.MethodGet, "/guests", nil, &reply)
+	return reply.Guests, requestError
+}
+
+// EmptySeats Returns the number of empty seats
+func (client *Client) EmptySeats(ctx context.Context) (int, error) {
+	var reply api.EmptySeatsResponse
+	requestError := client.sendJSON(ctx, http.MethodGet, "/seats_empty", nil, &reply)
+	return reply.SeatsEmpty, requestError
+}
+
+// GuestTicket Returns the PNG image of the ticket of a guest chosen by name
+func (client *Client) GuestTicket(ctx context.Context, name string) ([]byte, error) {
+	return client.send(ctx, http.MethodGet, "/guest_list/"+url.PathEscape(name)+"/ticket.png", nil)
+}
+
+// GuestTicketByID Returns the PNG image of the ticket of a guest chosen by ID
+func (client *Client) GuestTicketByID(ctx context.Context, guestID string) ([]byte, error) {
+	return client.send(ctx, http.MethodGet, "/guest_list/id/"+url.PathEscape(guestID)+"/ticket.png", nil)
+}
+
+// ScanTicket Checks in the guest holding a ticket
+//
+// ErrTicketRejected is reported for forged or expired tickets and ErrTicketUsed for tickets already scanned
+func (client *Client) ScanTicket(ctx context.Context, token string, accompanyingGuests int) (api.GuestResponse, error) {
+	var reply api.GuestResponse
+	requestError := client.sendJSON(ctx, http.MethodPost, "/checkin/scan",
+		api.ScanTicketRequest{Token: token, AccompanyingGuests: accompanyingGuests}, &reply)
+	return reply, requestError
+}
+
+// OpenAPIDocument Returns the OpenAPI document describing the server's API
+func (client *Client) OpenAPIDocument(ctx context.Context) ([]byte, error) {
+	return client.send(ctx, http.MethodGet, "/openapi.json", nil)
+}
@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+	"guestListChallenge/src/api"
+	"net/http"
+	"strconv"
+)
+
+// RecentOperations Returns at most limit of the latest operations on guests, newest first
+func (client *Client) RecentOperations(ctx context.Context, limit int) ([]api.Operation, error) {
+	var reply api.OperationsResponse
+	requestError := client.sendJSON(ctx, http.MethodGet, "/operations?limit="+strconv.Itoa(limit), nil, &reply)
+	return reply.Operations, requestError
+}
+
+// UndoOperation Reverts an operation on a guest and returns the operation recording its undoing
+//
+// ErrUndoConflict is reported if the guest changed since the operation, Error.Conflicts then lists the later operations
+func (client *Client) UndoOperation(ctx context.Context, operationID int64) (api.Operation, error) {
+	var reply api.UndoResponse
+	requestError := client.sendJSON(ctx, http.MethodPost, "/operations/"+strconv.FormatInt(operationID, 10)+"/undo", nil, &reply)
+	return reply.Operation, requestError
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"guestListChallenge/src/cli"
+	"os"
+)
+
+// main Command-line client entrypoint
+func main() {
+	os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,22 @@
+package database
+
+import (
+	"time"
+)
+
+// AuditRecord Structure representation of the audit_records sql table
+//
+// Records are only ever appended, the database rejects updates and deletes. Each record carries
+// the hash of the previous one, so that records changed or removed behind the database's back are detected.
+type AuditRecord struct {
+	Sequence     int64     `gorm:"primary_key;auto_increment"`
+	Time         time.Time `gorm:"not null"`
+	Actor        string    `gorm:"size:255;not null"`
+	RequestID    string    `gorm:"size:64;not null"`
+	Operation    string    `gorm:"size:32;not null"`
+	TargetID     string    `gorm:"size:64;not null"`
+	Before       string    `gorm:"type:text;not null"` // JSON state of the target before the operation, "null" if it did not exist
+	After        string    `gorm:"type:text;not null"` // JSON state of the target after the operation, "null" if it no longer exists
+	PreviousHash string    `gorm:"type:char(64);not null"`
+	Hash         string    `gorm:"type:char(64);not null"`
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"errors"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// ErrAuditRecordNotFound Reported when no audit record has the sequence number of a lookup
+var ErrAuditRecordNotFound = errors.New("audit record not found")
+
+// AuditFilter Criteria selecting audit records, empty criteria match every record
+type AuditFilter struct {
+	Actor     string
+	RequestID string
+	Operation string
+	TargetID  string
+	Since     time.Time // records at or after this time
+	Until     time.Time // records before this time
+
+	Operations    []string // records of any of these operations
+	AfterSequence int64    // records following this sequence number
+}
+
+// AppendAuditRecord Appends a record to the audit log, its sequence number is set by the database
+func (store *Store) AppendAuditRecord(record *AuditRecord) error {
+	return store.db.Create(record).Error
+}
+
+// LastAuditRecord Returns the latest record of the audit log, found is false if the log is empty
+func (store *Store) LastAuditRecord() (record AuditRecord, found bool, queryError error) {
+	var records []AuditRecord
+	queryError = store.db.Order("sequence DESC").Limit(1).Find(&records).Error
+	if queryError != nil || len(records) == 0 {
+		return record, false, queryError
+	}
+	return records[0], true, nil
+}
+
+// AuditRecordBySequence Returns the audit record with the given sequence number
+//
+// ErrAuditRecordNotFound is reported if there is no such record
+func (store *Store) AuditRecordBySequence(sequence int64) (AuditRecord, error) {
+	var record AuditRecord
+	queryError := store.db.Where("sequence = ?", sequence).First(&record).Error
+	if gorm.IsRecordNotFoundError(queryError) {
+		return record, ErrAuditRecordNotFound
+	}
+	return record, queryError
+}
+
+// AuditRecords Returns at most limit records matching the filter, newest first
+func (store *Store) AuditRecords(filter AuditFilter, limit int) ([]AuditRecord, error) {
+	query := store.db
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.Operation != "" {
+		query = query.Where("operation = ?", filter.Operation)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if len(filter.Operations) > 0 {
+		query = query.Where("operation IN (?)", filter.Operations)
+	}
+	if filter.AfterSequence > 0 {
+		query = query.Where("sequence > ?", filter.AfterSequence)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("time >= ?", filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("time < ?", filter.Until.UTC())
+	}
+
+	var records []AuditRecord
+	queryError := query.Order("sequence DESC").Limit(limit).Find(&records).Error
+	return records, queryError
+}
+
+// AuditRecordsAfter Returns at most limit records following the given sequence number, oldest first
+func (store *Store) AuditRecordsAfter(sequence int64, limit int) ([]AuditRecord, error) {
+	var records []AuditRecord
+	queryError := store.db.Where("sequence > ?", sequence).Order("sequence").Limit(limit).Find(&records).Error
+	return records, queryError
+}
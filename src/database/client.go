@@ -2,27 +2,85 @@ package database
 
 import (
 	"fmt"
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"guestListChallenge/src/utils"
 )
 
-//Connector Database connection  for CRUD operation's
-var Connector *gorm.DB
+// Store Database connection for CRUD operation's
+type Store struct {
+	db  *gorm.DB
+	ids utils.IDGenerator
+}
 
-//Connect Creates MySQL connection and performs database migration for GuestList
-func Connect() {
+// NewStore Creates a Store on top of an already established database connection
+//
+// New guests are identified with IDs taken from the given generator
+func NewStore(db *gorm.DB, ids utils.IDGenerator) *Store {
+	return &Store{db: db, ids: ids}
+}
 
-	var connectionError error
+// Open Creates a database connection with the configured driver without touching the database schema
+func Open(config Config) (*Store, error) {
+	if !config.knownDriver() {
+		return nil, fmt.Errorf("unknown database driver %q, expected mysql, postgres or sqlite", config.Driver)
+	}
 
 	// Establish connection to the database
-	Connector, connectionError = gorm.Open(dbConnectionConfig.ServerName, getConnectionString())
+	db, connectionError := gorm.Open(config.dialect(), config.ConnectionString())
 	if connectionError != nil {
-		fmt.Println(connectionError.Error())
-		panic("Failed to connect to Database")
+		return nil, connectionError
+	}
+
+	// SQLite locks the whole database to write, a single connection avoids failing on a lock held by another one
+	if config.Driver == DriverSQLite {
+		db.DB().SetMaxOpenConns(1)
 	}
 
 	fmt.Println("Connection to database was successful")
 
-	// Auto migrate to keep tables reflecting structs
-	Connector.AutoMigrate(&GuestList{})
+	return NewStore(db, utils.UUIDGenerator{}), nil
+}
+
+// Connect Creates a database connection and applies the pending schema migrations
+//
+// An error is reported if the database schema is newer than the one known by the binary
+func Connect(config Config) (*Store, error) {
+
+	store, connectionError := Open(config)
+	if connectionError != nil {
+		return nil, connectionError
+	}
+
+	migrated, migrationError := MigrateUp(store.DB())
+	if migrationError != nil {
+		store.Close()
+		return nil, migrationError
+	}
+
+	for _, migration := range migrated {
+		fmt.Printf("Applied migration %04d_%s\n", migration.Version, migration.Name)
+	}
+
+	return store, nil
+}
+
+// driverOf Returns the driver of a database connection, one of the Driver* constants
+func driverOf(db *gorm.DB) string {
+	if db.Dialect().GetName() == "sqlite3" {
+		return DriverSQLite
+	}
+	return db.Dialect().GetName()
+}
+
+// DB Returns the underlying database connection
+func (store *Store) DB() *gorm.DB {
+	return store.db
+}
+
+// Close Closes the database connection
+func (store *Store) Close() error {
+	return store.db.Close()
 }
@@ -1,35 +1,108 @@
 package database
 
-// dbConnectionConfig Database connection configuration
-var dbConnectionConfig = struct {
+import (
+	"net/url"
+)
+
+// Database drivers the store can work with
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Drivers Every database driver the store can work with
+var Drivers = []string{DriverMySQL, DriverPostgres, DriverSQLite}
+
+// defaultPorts Port of the database server of each driver, used when none is configured
+var defaultPorts = map[string]string{
+	DriverMySQL:    "3306",
+	DriverPostgres: "5432",
+}
+
+// Config Database connection configuration
+//
+// SQLite databases are files given by Path, the other drivers connect to a server
+type Config struct {
+	Driver         string // one of the Driver* constants
 	User           string
 	Password       string
 	ServerProtocol string
 	ServerName     string
-	ServerPort     string
+	ServerPort     string // the driver's default port when empty
 	DBName         string
-}{
-	User:           "francisco",
-	Password:       "password",
-	ServerProtocol: "tcp",
-	ServerName:     "mysql",
-	ServerPort:     "3306",
-	DBName:         "getground",
+	Path           string // database file, for DriverSQLite
+}
+
+// DefaultConfig Returns the database connection configuration used by the docker setup
+func DefaultConfig() Config {
+	return Config{
+		Driver:         DriverMySQL,
+		User:           "francisco",
+		Password:       "password",
+		ServerProtocol: "tcp",
+		ServerName:     "mysql",
+		DBName:         "getground",
+		Path:           "guestlist.db",
+	}
 }
 
-// getConnectionString Returns the connection string for the current database setup
-func getConnectionString() string {
+// knownDriver Checks if the configured driver is one of Drivers
+func (config Config) knownDriver() bool {
+	for _, driver := range Drivers {
+		if config.Driver == driver {
+			return true
+		}
+	}
+	return false
+}
+
+// dialect Returns the name of the gorm dialect of the configured driver
+func (config Config) dialect() string {
+	if config.Driver == DriverSQLite {
+		return "sqlite3"
+	}
+	return config.Driver
+}
+
+// port Returns the port of the database server
+func (config Config) port() string {
+	if config.ServerPort == "" {
+		return defaultPorts[config.Driver]
+	}
+	return config.ServerPort
+}
+
+// ConnectionString Returns the connection string for the database setup
+func (config Config) ConnectionString() string {
+	switch config.Driver {
+	case DriverPostgres:
+		connectionURL := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(config.User, config.Password),
+			Host:     config.ServerName + ":" + config.port(),
+			Path:     "/" + config.DBName,
+			RawQuery: "sslmode=disable",
+		}
+		return connectionURL.String()
+
+	case DriverSQLite:
+		// Wait for the lock held by another connection instead of failing
+		return config.Path + "?_busy_timeout=5000"
+	}
+
 	const leftParenthesis = "("
 	const rightParenthesis = ")"
 	const atSymbol = "@"
 	const colon = ":"
 	const slash = "/"
+	const options = "?parseTime=true"
 
-	return dbConnectionConfig.User + colon +
-		dbConnectionConfig.Password + atSymbol +
-		dbConnectionConfig.ServerProtocol + leftParenthesis +
-		dbConnectionConfig.ServerName + colon +
-		dbConnectionConfig.ServerPort + rightParenthesis + slash +
-		dbConnectionConfig.DBName
+	return config.User + colon +
+		config.Password + atSymbol +
+		config.ServerProtocol + leftParenthesis +
+		config.ServerName + colon +
+		config.port() + rightParenthesis + slash +
+		config.DBName + options
 
 }
@@ -1,9 +1,47 @@
 package database
 
+import (
+	"time"
+)
+
 // GuestList Structure representation of the guestlist sql table used in the database
+//
+// Guests are identified by a generated ID so that several guests can share the same name.
+// AddedAt is nil for guests added before the time of additions was recorded.
+// NoShowAt is set when a guest who did not arrive in time is marked as a no-show and their table released,
+// ReservationHeld keeps a guest from being marked automatically.
+// Walk-ins are admitted at the door without being on the guest list beforehand: they have no table of their own
+// and sit with their accompanying guests at the table of the guest whose ID is SeatedWith.
+// RoomID is the room the guest's table is in, that of their host's table for walk-ins, and InRoom the room the guest
+// is in with their accompanying guests, both empty when there is none.
+// Category is the category of guests the guest is in, which sets the rules applying to them.
 type GuestList struct {
-	Name               string `json:"name" gorm:"primary_key"`
-	Table              int    `json:"table"`
-	AccompanyingGuests int    `json:"accompanying_guests"`
-	TimeArrived        string `json:"time_arrived"`
+	ID                 string     `json:"id" gorm:"primary_key;type:char(36)"`
+	Name               string     `json:"name" gorm:"index"`
+	Table              int        `json:"table"`
+	AccompanyingGuests int        `json:"accompanying_guests"`
+	TimeArrived        string     `json:"time_arrived"`
+	AddedAt            *time.Time `json:"added_at"`
+	NoShowAt           *time.Time `json:"no_show_at"`
+	ReservationHeld    bool       `json:"reservation_held"`
+	WalkIn             bool       `json:"walk_in"`
+	SeatedWith         string     `json:"seated_with" gorm:"type:char(36)"`
+	RoomID             string     `json:"room_id" gorm:"type:char(36)"`
+	InRoom             string     `json:"in_room" gorm:"type:char(36)"`
+	Category           string     `json:"category" gorm:"type:varchar(16);default:'guest'"`
+}
+
+// SameAs Checks if two guests hold the same data, times being compared as instants whatever their time zone
+func (guest GuestList) SameAs(other GuestList) bool {
+	if !sameTime(guest.AddedAt, other.AddedAt) || !sameTime(guest.NoShowAt, other.NoShowAt) {
+		return false
+	}
+	guest.AddedAt, other.AddedAt = nil, nil
+	guest.NoShowAt, other.NoShowAt = nil, nil
+	return guest == other
+}
+
+// sameTime Checks if two optional times are both missing or the same instant
+func sameTime(first *time.Time, second *time.Time) bool {
+	return (first == nil) == (second == nil) && (first == nil || first.Equal(*second))
 }
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"errors"
+	"github.com/jinzhu/gorm"
+)
+
+// ErrGuestNotFound Reported when no guest matches a lookup
+var ErrGuestNotFound = errors.New("guest not found")
+
+// AddGuest Adds a guest to the guest list under a newly generated ID
+func (store *Store) AddGuest(guest *GuestList) error {
+	guest.ID = store.ids.NewID()
+	return store.db.Create(guest).Error
+}
+
+// Guests Returns every guest in the guest list
+func (store *Store) Guests() ([]GuestList, error) {
+	var guestList []GuestList
+	queryError := store.db.Order("name, id").Find(&guestList).Error
+	return guestList, queryError
+}
+
+// GuestByID Returns the guest with the given ID
+//
+// ErrGuestNotFound is reported if there is no such guest
+func (store *Store) GuestByID(id string) (GuestList, error) {
+	var guest GuestList
+	queryError := store.db.Where("id = ?", id).First(&guest).Error
+	if gorm.IsRecordNotFoundError(queryError) {
+		return guest, ErrGuestNotFound
+	}
+	return guest, queryError
+}
+
+// GuestsByIDs Returns the guests with the given IDs in a single query, IDs without a guest are ignored
+func (store *Store) GuestsByIDs(ids []string) ([]GuestList, error) {
+	var guestList []GuestList
+	if len(ids) == 0 {
+		return guestList, nil
+	}
+	queryError := store.db.Where("id IN (?)", ids).Find(&guestList).Error
+	return guestList, queryError
+}
+
+// GuestsByName Returns every guest with the given name
+func (store *Store) GuestsByName(name string) ([]GuestList, error) {
+	var guestList []GuestList
+	queryError := store.db.Where("name = ?", name).Order("id").Find(&guestList).Error
+	return guestList, queryError
+}
+
+// SaveGuest Updates every field of a guest already in the guest list
+func (store *Store) SaveGuest(guest *GuestList) error {
+	return store.db.Save(guest).Error
+}
+
+// DeleteGuest Removes a guest from the guest list
+func (store *Store) DeleteGuest(guest GuestList) error {
+	return store.db.Where("id = ?", guest.ID).Delete(&GuestList{}).Error
+}
+
+// RestoreGuest Adds back a guest removed from the guest list, under their former ID
+func (store *Store) RestoreGuest(guest *GuestList) error {
+	return store.db.Create(guest).Error
+}
+
+// SetCategory Puts a guest in a category of guests
+func (store *Store) SetCategory(guestID string, category string) error {
+	return store.db.Model(&GuestList{}).Where("id = ?", guestID).Update("category", category).Error
+}
@@ -0,0 +1,413 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles Versioned SQL migrations embedded in the binary, in a directory per driver
+//
+//go:embed migrations/*/*.sql
+var migrationFiles embed.FS
+
+// migrationsDirectory Directory of migrationFiles holding the directories of SQL migrations
+const migrationsDirectory = "migrations"
+
+// SchemaMigration Structure representation of the schema_migrations sql table
+//
+// Each row records one migration that has been applied to the database
+type SchemaMigration struct {
+	Version   int       `gorm:"primary_key;auto_increment:false"`
+	Name      string    `gorm:"size:255"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName Name of the sql table holding the applied migrations
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migration A versioned schema change with the SQL needed to apply and revert it
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState A known migration and whether it has been applied to the database
+type MigrationState struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrations Returns the migrations of a driver embedded in the binary ordered by version
+//
+// Files are expected to be named <version>_<name>.up.sql and <version>_<name>.down.sql. Every driver
+// has the same versions, each written in the SQL dialect of the driver.
+func Migrations(driver string) ([]Migration, error) {
+	driverDirectory := path.Join(migrationsDirectory, driver)
+	entries, readError := fs.ReadDir(migrationFiles, driverDirectory)
+	if readError != nil {
+		return nil, fmt.Errorf("no migrations for database driver %q", driver)
+	}
+
+	migrationsByVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s: unexpected file name", fileName)
+		}
+
+		baseName := strings.TrimSuffix(fileName, "."+direction+".sql")
+		separatorIndex := strings.Index(baseName, "_")
+		if separatorIndex <= 0 {
+			return nil, fmt.Errorf("migration %s: missing version prefix", fileName)
+		}
+		version, conversionError := strconv.Atoi(baseName[:separatorIndex])
+		if conversionError != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version prefix", fileName)
+		}
+
+		contents, readError := fs.ReadFile(migrationFiles, path.Join(driverDirectory, fileName))
+		if readError != nil {
+			return nil, readError
+		}
+
+		migration, exists := migrationsByVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: baseName[separatorIndex+1:]}
+			migrationsByVersion[version] = migration
+		} else if migration.Name != baseName[separatorIndex+1:] {
+			return nil, fmt.Errorf("migration %d: conflicting names %q and %q", version, migration.Name, baseName[separatorIndex+1:])
+		}
+
+		if direction == "up" {
+			migration.Up = string(contents)
+		} else {
+			migration.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(migrationsByVersion))
+	for _, migration := range migrationsByVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d: both up and down files are required", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// LatestSchemaVersion Returns the newest schema version known by the binary for a driver
+func LatestSchemaVersion(driver string) (int, error) {
+	migrations, loadError := Migrations(driver)
+	if loadError != nil {
+		return 0, loadError
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// ensureMigrationsTable Creates the schema_migrations table if it does not exist yet
+func ensureMigrationsTable(db *gorm.DB) error {
+	if db.HasTable(&SchemaMigration{}) {
+		return nil
+	}
+	return db.CreateTable(&SchemaMigration{}).Error
+}
+
+// appliedMigrations Returns the migrations recorded in schema_migrations indexed by version
+func appliedMigrations(db *gorm.DB) (map[int]SchemaMigration, error) {
+	if migrationsTableError := ensureMigrationsTable(db); migrationsTableError != nil {
+		return nil, migrationsTableError
+	}
+
+	var records []SchemaMigration
+	if queryError := db.Order("version").Find(&records).Error; queryError != nil {
+		return nil, queryError
+	}
+
+	applied := make(map[int]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// SchemaVersion Returns the newest migration version applied to the database, 0 if none was applied
+func SchemaVersion(db *gorm.DB) (int, error) {
+	applied, queryError := appliedMigrations(db)
+	if queryError != nil {
+		return 0, queryError
+	}
+
+	version := 0
+	for appliedVersion := range applied {
+		if appliedVersion > version {
+			version = appliedVersion
+		}
+	}
+	return version, nil
+}
+
+// CheckSchemaVersion Reports an error if the database schema is newer than the binary knows
+func CheckSchemaVersion(db *gorm.DB) error {
+	databaseVersion, queryError := SchemaVersion(db)
+	if queryError != nil {
+		return queryError
+	}
+
+	latestVersion, loadError := LatestSchemaVersion(driverOf(db))
+	if loadError != nil {
+		return loadError
+	}
+
+	if databaseVersion > latestVersion {
+		return fmt.Errorf("database schema version %d is newer than the latest version %d known by this binary", databaseVersion, latestVersion)
+	}
+	return nil
+}
+
+// MigrationStatus Returns every known migration along with whether it has been applied
+func MigrationStatus(db *gorm.DB) ([]MigrationState, error) {
+	migrations, loadError := Migrations(driverOf(db))
+	if loadError != nil {
+		return nil, loadError
+	}
+
+	applied, queryError := appliedMigrations(db)
+	if queryError != nil {
+		return nil, queryError
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	for _, migration := range migrations {
+		record, isApplied := applied[migration.Version]
+		states = append(states, MigrationState{Migration: migration, Applied: isApplied, AppliedAt: record.AppliedAt})
+	}
+	return states, nil
+}
+
+// MigrateUp Applies every pending migration in version order
+//
+// Returns the migrations that were applied
+func MigrateUp(db *gorm.DB) ([]Migration, error) {
+	if versionError := CheckSchemaVersion(db); versionError != nil {
+		return nil, versionError
+	}
+
+	states, statusError := MigrationStatus(db)
+	if statusError != nil {
+		return nil, statusError
+	}
+
+	var migrated []Migration
+	for _, state := range states {
+		if state.Applied {
+			continue
+		}
+
+		record := SchemaMigration{Version: state.Version, Name: state.Name, AppliedAt: time.Now().UTC()}
+		migrationError := runMigration(db, state.Up, func(tx *gorm.DB) error {
+			return tx.Create(&record).Error
+		})
+		if migrationError != nil {
+			return migrated, fmt.Errorf("migration %d_%s up: %v", state.Version, state.Name, migrationError)
+		}
+
+		migrated = append(migrated, state.Migration)
+	}
+	return migrated, nil
+}
+
+// MigrateDown Reverts the newest applied migration
+//
+// Returns the reverted migration, nil if no migration was applied
+func MigrateDown(db *gorm.DB) (*Migration, error) {
+	if versionError := CheckSchemaVersion(db); versionError != nil {
+		return nil, versionError
+	}
+
+	states, statusError := MigrationStatus(db)
+	if statusError != nil {
+		return nil, statusError
+	}
+
+	for index := len(states) - 1; index >= 0; index-- {
+		state := states[index]
+		if !state.Applied {
+			continue
+		}
+
+		if check, checked := downChecks[state.Version]; checked {
+			if checkError := check(db); checkError != nil {
+				return nil, fmt.Errorf("migration %d_%s down: %v", state.Version, state.Name, checkError)
+			}
+		}
+
+		migrationError := runMigration(db, state.Down, func(tx *gorm.DB) error {
+			return tx.Where("version = ?", state.Version).Delete(&SchemaMigration{}).Error
+		})
+		if migrationError != nil {
+			return nil, fmt.Errorf("migration %d_%s down: %v", state.Version, state.Name, migrationError)
+		}
+
+		return &state.Migration, nil
+	}
+	return nil, nil
+}
+
+// downChecks Checks refusing to revert a migration whose down script cannot succeed on the data in the database,
+// by version
+var downChecks = map[int]func(db *gorm.DB) error{
+	2: checkNamesUnique,
+}
+
+// checkNamesUnique Refuses to give the guest list its name primary key back while guests share a name
+//
+// Migration 0002_add_guest_ids is irreversible once guests sharing a name were added
+func checkNamesUnique(db *gorm.DB) error {
+	var sharedNames []string
+	if queryError := db.Table("guest_lists").Group("name").Having("COUNT(*) > 1").Pluck("name", &sharedNames).Error; queryError != nil {
+		return queryError
+	}
+	if len(sharedNames) > 0 {
+		return fmt.Errorf("guests share the names %s, the guest list cannot be keyed by name again: remove or rename them first", strings.Join(sharedNames, ", "))
+	}
+	return nil
+}
+
+// runMigration Executes a migration script and records the change of schema version with recordVersion
+//
+// On postgres and sqlite, where DDL is transactional, both happen in one transaction so that a script failing part-way,
+// like a table rebuild, leaves the database as it was. MySQL commits every DDL statement on its own.
+func runMigration(db *gorm.DB, script string, recordVersion func(tx *gorm.DB) error) error {
+	if driverOf(db) == DriverMySQL {
+		if execError := execStatements(db, script); execError != nil {
+			return execError
+		}
+		return recordVersion(db)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if execError := execStatements(tx, script); execError != nil {
+			return execError
+		}
+		return recordVersion(tx)
+	})
+}
+
+// execStatements Executes every ";" terminated statement of a migration script
+//
+// Statements are run one by one since not every driver accepts multi-statement queries
+func execStatements(db *gorm.DB, script string) error {
+	for _, statement := range SplitStatements(script) {
+		if execError := db.Exec(statement).Error; execError != nil {
+			return execError
+		}
+	}
+	return nil
+}
+
+// triggerBodyPattern Matches the start of statements creating a trigger whose body is a BEGIN ... END block
+var triggerBodyPattern = regexp.MustCompile(`(?is)^CREATE\s+TRIGGER\b.*\bBEGIN\b`)
+
+// triggerBodyEndPattern Matches the end of a trigger body
+var triggerBodyEndPattern = regexp.MustCompile(`(?is)\bEND$`)
+
+// SplitStatements Splits a migration script into its ";" terminated statements
+//
+// Semicolons inside quoted text, comments, dollar-quoted function bodies and BEGIN ... END trigger bodies
+// do not end a statement
+func SplitStatements(script string) []string {
+	var statements []string
+	var statement strings.Builder
+	var quote string
+
+	for index := 0; index < len(script); index++ {
+		character := script[index]
+
+		switch {
+		case quote != "":
+			// Inside quoted text, only its closing quote matters
+			if strings.HasPrefix(script[index:], quote) {
+				statement.WriteString(quote)
+				index += len(quote) - 1
+				quote = ""
+				continue
+			}
+
+		case character == '\'' || character == '"' || character == '`':
+			quote = string(character)
+
+		case strings.HasPrefix(script[index:], "--"):
+			// Comments run to the end of the line
+			lineEnd := strings.IndexByte(script[index:], '\n')
+			if lineEnd < 0 {
+				lineEnd = len(script) - index
+			}
+			statement.WriteString(script[index : index+lineEnd])
+			index += lineEnd - 1
+			continue
+
+		case character == '$':
+			// Dollar quotes are $$ or $tag$
+			if closingIndex := strings.IndexByte(script[index+1:], '$'); closingIndex >= 0 && isDollarTag(script[index+1:index+1+closingIndex]) {
+				quote = script[index : index+closingIndex+2]
+				statement.WriteString(quote)
+				index += len(quote) - 1
+				continue
+			}
+
+		case character == ';':
+			trimmed := strings.TrimSpace(statement.String())
+			if triggerBodyPattern.MatchString(trimmed) && !triggerBodyEndPattern.MatchString(trimmed) {
+				break
+			}
+			if trimmed != "" {
+				statements = append(statements, trimmed)
+			}
+			statement.Reset()
+			continue
+		}
+
+		statement.WriteByte(character)
+	}
+
+	if trimmed := strings.TrimSpace(statement.String()); trimmed != "" {
+		statements = append(statements, trimmed)
+	}
+	return statements
+}
+
+// isDollarTag Checks if text can be the tag of a dollar quote
+func isDollarTag(text string) bool {
+	for _, character := range text {
+		isAllowed := character == '_' || (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z') || (character >= '0' && character <= '9')
+		if !isAllowed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,4 @@
+-- Irreversible once guests share a name: migrate down refuses to run it until they are removed or renamed
+DROP INDEX idx_guest_lists_name ON guest_lists;
+ALTER TABLE guest_lists DROP PRIMARY KEY, ADD PRIMARY KEY (name);
+ALTER TABLE guest_lists DROP COLUMN id;
@@ -0,0 +1,4 @@
+ALTER TABLE guest_lists ADD COLUMN id CHAR(36) NULL;
+UPDATE guest_lists SET id = UUID();
+ALTER TABLE guest_lists DROP PRIMARY KEY, ADD PRIMARY KEY (id);
+CREATE INDEX idx_guest_lists_name ON guest_lists (name);
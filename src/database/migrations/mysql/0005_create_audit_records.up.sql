@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS audit_records (
+    sequence BIGINT NOT NULL AUTO_INCREMENT,
+    time DATETIME NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(64) NOT NULL,
+    operation VARCHAR(32) NOT NULL,
+    target_id VARCHAR(64) NOT NULL,
+    `before` TEXT NOT NULL,
+    `after` TEXT NOT NULL,
+    previous_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    PRIMARY KEY (sequence),
+    INDEX idx_audit_records_actor (actor),
+    INDEX idx_audit_records_target (target_id),
+    INDEX idx_audit_records_request (request_id)
+);
+
+CREATE TRIGGER audit_records_no_update BEFORE UPDATE ON audit_records
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit records are append-only';
+
+CREATE TRIGGER audit_records_no_delete BEFORE DELETE ON audit_records
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit records are append-only';
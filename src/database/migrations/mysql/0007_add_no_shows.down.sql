@@ -0,0 +1,2 @@
+ALTER TABLE guest_lists DROP COLUMN reservation_held;
+ALTER TABLE guest_lists DROP COLUMN no_show_at;
@@ -0,0 +1,4 @@
+ALTER TABLE visits DROP COLUMN seated_with;
+ALTER TABLE visits DROP COLUMN walk_in;
+ALTER TABLE guest_lists DROP COLUMN seated_with;
+ALTER TABLE guest_lists DROP COLUMN walk_in;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS settings (
+    name VARCHAR(64) NOT NULL,
+    value TEXT NOT NULL,
+    changed_at DATETIME NOT NULL,
+    PRIMARY KEY (name)
+);
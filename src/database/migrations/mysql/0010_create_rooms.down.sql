@@ -0,0 +1,5 @@
+ALTER TABLE visits DROP COLUMN room_id;
+ALTER TABLE guest_lists DROP COLUMN in_room;
+ALTER TABLE guest_lists DROP COLUMN room_id;
+DROP TABLE IF EXISTS room_accesses;
+DROP TABLE IF EXISTS rooms;
@@ -0,0 +1,2 @@
+ALTER TABLE visits DROP COLUMN category;
+ALTER TABLE guest_lists DROP COLUMN category;
@@ -0,0 +1,4 @@
+-- Irreversible once guests share a name: migrate down refuses to run it until they are removed or renamed
+DROP INDEX idx_guest_lists_name;
+ALTER TABLE guest_lists DROP CONSTRAINT guest_lists_pkey, ADD PRIMARY KEY (name);
+ALTER TABLE guest_lists DROP COLUMN id;
@@ -0,0 +1,4 @@
+ALTER TABLE guest_lists ADD COLUMN id VARCHAR(36) NULL;
+UPDATE guest_lists SET id = md5(random()::text || clock_timestamp()::text || name)::uuid::text;
+ALTER TABLE guest_lists DROP CONSTRAINT guest_lists_pkey, ADD PRIMARY KEY (id);
+CREATE INDEX idx_guest_lists_name ON guest_lists (name);
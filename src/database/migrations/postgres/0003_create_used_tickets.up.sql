@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS used_tickets (
+    id VARCHAR(64) NOT NULL,
+    guest_id VARCHAR(36) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (id)
+);
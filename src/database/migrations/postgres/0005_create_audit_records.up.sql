@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS audit_records (
+    sequence BIGSERIAL NOT NULL,
+    time TIMESTAMP WITH TIME ZONE NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(64) NOT NULL,
+    operation VARCHAR(32) NOT NULL,
+    target_id VARCHAR(64) NOT NULL,
+    before TEXT NOT NULL,
+    after TEXT NOT NULL,
+    previous_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    PRIMARY KEY (sequence)
+);
+
+CREATE INDEX idx_audit_records_actor ON audit_records (actor);
+CREATE INDEX idx_audit_records_target ON audit_records (target_id);
+CREATE INDEX idx_audit_records_request ON audit_records (request_id);
+
+CREATE FUNCTION audit_records_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit records are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_records_no_update BEFORE UPDATE ON audit_records
+FOR EACH ROW EXECUTE PROCEDURE audit_records_append_only();
+
+CREATE TRIGGER audit_records_no_delete BEFORE DELETE ON audit_records
+FOR EACH ROW EXECUTE PROCEDURE audit_records_append_only();
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS visits;
+ALTER TABLE guest_lists DROP COLUMN added_at;
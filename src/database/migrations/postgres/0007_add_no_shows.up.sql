@@ -0,0 +1,2 @@
+ALTER TABLE guest_lists ADD COLUMN no_show_at TIMESTAMP WITH TIME ZONE NULL;
+ALTER TABLE guest_lists ADD COLUMN reservation_held BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS rooms (
+    id VARCHAR(36) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    capacity INT NOT NULL DEFAULT 0,
+    restricted BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX idx_rooms_name ON rooms (name);
+
+CREATE TABLE IF NOT EXISTS room_accesses (
+    room_id VARCHAR(36) NOT NULL,
+    guest_id VARCHAR(36) NOT NULL,
+    PRIMARY KEY (room_id, guest_id)
+);
+
+CREATE INDEX idx_room_accesses_guest ON room_accesses (guest_id);
+
+ALTER TABLE guest_lists ADD COLUMN room_id VARCHAR(36) NOT NULL DEFAULT '';
+ALTER TABLE guest_lists ADD COLUMN in_room VARCHAR(36) NOT NULL DEFAULT '';
+ALTER TABLE visits ADD COLUMN room_id VARCHAR(36) NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id VARCHAR(36) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    accompanying_guests INT NOT NULL,
+    category VARCHAR(16) NOT NULL DEFAULT 'guest',
+    joined_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1 @@
+ALTER TABLE waitlist_entries ADD COLUMN age INT NULL;
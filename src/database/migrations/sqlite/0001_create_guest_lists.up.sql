@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS guest_lists (
+    name VARCHAR(255) NOT NULL,
+    "table" INT,
+    accompanying_guests INT,
+    time_arrived VARCHAR(255),
+    PRIMARY KEY (name)
+);
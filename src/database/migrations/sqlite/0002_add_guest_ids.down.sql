@@ -0,0 +1,12 @@
+-- Irreversible once guests share a name: migrate down refuses to run it until they are removed or renamed
+CREATE TABLE guest_lists_without_ids (
+    name VARCHAR(255) NOT NULL,
+    "table" INT,
+    accompanying_guests INT,
+    time_arrived VARCHAR(255),
+    PRIMARY KEY (name)
+);
+INSERT INTO guest_lists_without_ids (name, "table", accompanying_guests, time_arrived)
+SELECT name, "table", accompanying_guests, time_arrived FROM guest_lists;
+DROP TABLE guest_lists;
+ALTER TABLE guest_lists_without_ids RENAME TO guest_lists;
@@ -0,0 +1,16 @@
+CREATE TABLE guest_lists_with_ids (
+    id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    "table" INT,
+    accompanying_guests INT,
+    time_arrived VARCHAR(255),
+    PRIMARY KEY (id)
+);
+INSERT INTO guest_lists_with_ids (id, name, "table", accompanying_guests, time_arrived)
+SELECT lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+             substr('89ab', 1 + abs(random()) % 4, 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6))),
+       name, "table", accompanying_guests, time_arrived
+FROM guest_lists;
+DROP TABLE guest_lists;
+ALTER TABLE guest_lists_with_ids RENAME TO guest_lists;
+CREATE INDEX idx_guest_lists_name ON guest_lists (name);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS audit_records (
+    sequence INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    time DATETIME NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(64) NOT NULL,
+    operation VARCHAR(32) NOT NULL,
+    target_id VARCHAR(64) NOT NULL,
+    "before" TEXT NOT NULL,
+    "after" TEXT NOT NULL,
+    previous_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL
+);
+
+CREATE INDEX idx_audit_records_actor ON audit_records (actor);
+CREATE INDEX idx_audit_records_target ON audit_records (target_id);
+CREATE INDEX idx_audit_records_request ON audit_records (request_id);
+
+CREATE TRIGGER audit_records_no_update BEFORE UPDATE ON audit_records
+BEGIN
+    SELECT RAISE(ABORT, 'audit records are append-only');
+END;
+
+CREATE TRIGGER audit_records_no_delete BEFORE DELETE ON audit_records
+BEGIN
+    SELECT RAISE(ABORT, 'audit records are append-only');
+END;
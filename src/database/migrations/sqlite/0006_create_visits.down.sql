@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS visits;
+
+-- SQLite cannot drop columns, the guest list is rebuilt without added_at
+CREATE TABLE guest_lists_without_added_at (
+    id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    "table" INT,
+    accompanying_guests INT,
+    time_arrived VARCHAR(255),
+    PRIMARY KEY (id)
+);
+INSERT INTO guest_lists_without_added_at (id, name, "table", accompanying_guests, time_arrived)
+SELECT id, name, "table", accompanying_guests, time_arrived FROM guest_lists;
+DROP TABLE guest_lists;
+ALTER TABLE guest_lists_without_added_at RENAME TO guest_lists;
+CREATE INDEX idx_guest_lists_name ON guest_lists (name);
@@ -0,0 +1,17 @@
+ALTER TABLE guest_lists ADD COLUMN added_at DATETIME NULL;
+
+CREATE TABLE IF NOT EXISTS visits (
+    id CHAR(36) NOT NULL,
+    guest_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    "table" INT NOT NULL,
+    accompanying_guests INT NOT NULL,
+    time_arrived VARCHAR(255) NOT NULL,
+    guest_added_at DATETIME NULL,
+    arrived_at DATETIME NOT NULL,
+    left_at DATETIME NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_visits_guest ON visits (guest_id);
+CREATE INDEX idx_visits_arrived_at ON visits (arrived_at);
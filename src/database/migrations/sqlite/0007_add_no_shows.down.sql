@@ -0,0 +1,15 @@
+-- SQLite cannot drop columns, the guest list is rebuilt without the no-show columns
+CREATE TABLE guest_lists_without_no_shows (
+    id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    "table" INT,
+    accompanying_guests INT,
+    time_arrived VARCHAR(255),
+    added_at DATETIME NULL,
+    PRIMARY KEY (id)
+);
+INSERT INTO guest_lists_without_no_shows (id, name, "table", accompanying_guests, time_arrived, added_at)
+SELECT id, name, "table", accompanying_guests, time_arrived, added_at FROM guest_lists;
+DROP TABLE guest_lists;
+ALTER TABLE guest_lists_without_no_shows RENAME TO guest_lists;
+CREATE INDEX idx_guest_lists_name ON guest_lists (name);
@@ -0,0 +1,36 @@
+-- SQLite cannot drop columns, the guest list and the visits are rebuilt without the walk-in columns
+CREATE TABLE guest_lists_without_walk_ins (
+    id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    "table" INT,
+    accompanying_guests INT,
+    time_arrived VARCHAR(255),
+    added_at DATETIME NULL,
+    no_show_at DATETIME NULL,
+    reservation_held BOOLEAN NOT NULL DEFAULT 0,
+    PRIMARY KEY (id)
+);
+INSERT INTO guest_lists_without_walk_ins (id, name, "table", accompanying_guests, time_arrived, added_at, no_show_at, reservation_held)
+SELECT id, name, "table", accompanying_guests, time_arrived, added_at, no_show_at, reservation_held FROM guest_lists;
+DROP TABLE guest_lists;
+ALTER TABLE guest_lists_without_walk_ins RENAME TO guest_lists;
+CREATE INDEX idx_guest_lists_name ON guest_lists (name);
+
+CREATE TABLE visits_without_walk_ins (
+    id CHAR(36) NOT NULL,
+    guest_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    "table" INT NOT NULL,
+    accompanying_guests INT NOT NULL,
+    time_arrived VARCHAR(255) NOT NULL,
+    guest_added_at DATETIME NULL,
+    arrived_at DATETIME NOT NULL,
+    left_at DATETIME NULL,
+    PRIMARY KEY (id)
+);
+INSERT INTO visits_without_walk_ins (id, guest_id, name, "table", accompanying_guests, time_arrived, guest_added_at, arrived_at, left_at)
+SELECT id, guest_id, name, "table", accompanying_guests, time_arrived, guest_added_at, arrived_at, left_at FROM visits;
+DROP TABLE visits;
+ALTER TABLE visits_without_walk_ins RENAME TO visits;
+CREATE INDEX idx_visits_guest ON visits (guest_id);
+CREATE INDEX idx_visits_arrived_at ON visits (arrived_at);
@@ -0,0 +1,4 @@
+ALTER TABLE guest_lists ADD COLUMN walk_in BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE guest_lists ADD COLUMN seated_with CHAR(36) NOT NULL DEFAULT '';
+ALTER TABLE visits ADD COLUMN walk_in BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE visits ADD COLUMN seated_with CHAR(36) NOT NULL DEFAULT '';
@@ -0,0 +1,43 @@
+-- SQLite cannot drop columns, the guest list and the visits are rebuilt without the category
+CREATE TABLE guest_lists_without_categories (
+    id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    "table" INT,
+    accompanying_guests INT,
+    time_arrived VARCHAR(255),
+    added_at DATETIME NULL,
+    no_show_at DATETIME NULL,
+    reservation_held BOOLEAN NOT NULL DEFAULT 0,
+    walk_in BOOLEAN NOT NULL DEFAULT 0,
+    seated_with CHAR(36) NOT NULL DEFAULT '',
+    room_id CHAR(36) NOT NULL DEFAULT '',
+    in_room CHAR(36) NOT NULL DEFAULT '',
+    PRIMARY KEY (id)
+);
+INSERT INTO guest_lists_without_categories (id, name, "table", accompanying_guests, time_arrived, added_at, no_show_at, reservation_held, walk_in, seated_with, room_id, in_room)
+SELECT id, name, "table", accompanying_guests, time_arrived, added_at, no_show_at, reservation_held, walk_in, seated_with, room_id, in_room FROM guest_lists;
+DROP TABLE guest_lists;
+ALTER TABLE guest_lists_without_categories RENAME TO guest_lists;
+CREATE INDEX idx_guest_lists_name ON guest_lists (name);
+
+CREATE TABLE visits_without_categories (
+    id CHAR(36) NOT NULL,
+    guest_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    "table" INT NOT NULL,
+    accompanying_guests INT NOT NULL,
+    time_arrived VARCHAR(255) NOT NULL,
+    guest_added_at DATETIME NULL,
+    arrived_at DATETIME NOT NULL,
+    left_at DATETIME NULL,
+    walk_in BOOLEAN NOT NULL DEFAULT 0,
+    seated_with CHAR(36) NOT NULL DEFAULT '',
+    room_id CHAR(36) NOT NULL DEFAULT '',
+    PRIMARY KEY (id)
+);
+INSERT INTO visits_without_categories (id, guest_id, name, "table", accompanying_guests, time_arrived, guest_added_at, arrived_at, left_at, walk_in, seated_with, room_id)
+SELECT id, guest_id, name, "table", accompanying_guests, time_arrived, guest_added_at, arrived_at, left_at, walk_in, seated_with, room_id FROM visits;
+DROP TABLE visits;
+ALTER TABLE visits_without_categories RENAME TO visits;
+CREATE INDEX idx_visits_guest ON visits (guest_id);
+CREATE INDEX idx_visits_arrived_at ON visits (arrived_at);
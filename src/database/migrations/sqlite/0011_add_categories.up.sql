@@ -0,0 +1,2 @@
+ALTER TABLE guest_lists ADD COLUMN category VARCHAR(16) NOT NULL DEFAULT 'guest';
+ALTER TABLE visits ADD COLUMN category VARCHAR(16) NOT NULL DEFAULT 'guest';
@@ -0,0 +1,13 @@
+-- SQLite cannot drop columns, the waitlist is rebuilt without the age
+CREATE TABLE waitlist_entries_without_ages (
+    id CHAR(36) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    accompanying_guests INT NOT NULL,
+    category VARCHAR(16) NOT NULL DEFAULT 'guest',
+    joined_at DATETIME NOT NULL,
+    PRIMARY KEY (id)
+);
+INSERT INTO waitlist_entries_without_ages (id, name, accompanying_guests, category, joined_at)
+SELECT id, name, accompanying_guests, category, joined_at FROM waitlist_entries;
+DROP TABLE waitlist_entries;
+ALTER TABLE waitlist_entries_without_ages RENAME TO waitlist_entries;
@@ -0,0 +1,29 @@
+package database
+
+import (
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// notArrived Condition matching the guests who did not check in
+const notArrived = "(time_arrived = '' OR time_arrived IS NULL)"
+
+// MarkNoShow Marks a guest as a no-show at a given time, unless they checked in, were already marked or had their reservation held
+//
+// Returns whether the guest was marked. The guest is checked and marked in a single statement, so that a guest
+// checking in meanwhile is never marked.
+func (store *Store) MarkNoShow(guestID string, noShowAt time.Time) (bool, error) {
+	update := store.db.Model(&GuestList{}).
+		Where("id = ? AND "+notArrived+" AND no_show_at IS NULL AND reservation_held = ?", guestID, false).
+		Update("no_show_at", noShowAt.UTC())
+	return update.RowsAffected > 0, update.Error
+}
+
+// SetNoShow Marks a guest as a no-show at a given time, or holds their reservation when the time is nil, unless they checked in
+func (store *Store) SetNoShow(guestID string, noShowAt *time.Time) error {
+	changes := map[string]interface{}{"no_show_at": gorm.Expr("NULL"), "reservation_held": true}
+	if noShowAt != nil {
+		changes = map[string]interface{}{"no_show_at": noShowAt.UTC(), "reservation_held": false}
+	}
+	return store.db.Model(&GuestList{}).Where("id = ? AND "+notArrived, guestID).Updates(changes).Error
+}
@@ -0,0 +1,26 @@
+package database
+
+import (
+	"time"
+)
+
+// Room Structure representation of the rooms sql table
+//
+// A venue is split into rooms, like a main hall, a terrace or a lounge, each owning the tables of the guests assigned to it.
+// Capacity is the largest number of people in the room at once, no limit when zero. Restricted rooms are only entered
+// by the guests whose table is in them and by those granted access to them.
+type Room struct {
+	ID         string    `json:"id" gorm:"primary_key;type:char(36)"`
+	Name       string    `json:"name" gorm:"size:64;not null"`
+	Capacity   int       `json:"capacity" gorm:"not null"`
+	Restricted bool      `json:"restricted" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"not null"`
+}
+
+// RoomAccess Structure representation of the room_accesses sql table
+//
+// Grants a guest the right to enter a restricted room their table is not in
+type RoomAccess struct {
+	RoomID  string `json:"room_id" gorm:"primary_key;type:char(36)"`
+	GuestID string `json:"guest_id" gorm:"primary_key;type:char(36)"`
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"errors"
+	"github.com/jinzhu/gorm"
+)
+
+// ErrRoomNotFound Reported when no room matches a lookup
+var ErrRoomNotFound = errors.New("room not found")
+
+// AddRoom Adds a room to the venue under a newly generated ID
+func (store *Store) AddRoom(room *Room) error {
+	room.ID = store.ids.NewID()
+	room.CreatedAt = room.CreatedAt.UTC()
+	return store.db.Create(room).Error
+}
+
+// Rooms Returns every room of the venue, by name
+func (store *Store) Rooms() ([]Room, error) {
+	var rooms []Room
+	queryError := store.db.Order("name, id").Find(&rooms).Error
+	return rooms, queryError
+}
+
+// RoomByName Returns the room with the given name
+//
+// ErrRoomNotFound is reported if there is no such room
+func (store *Store) RoomByName(name string) (Room, error) {
+	var room Room
+	queryError := store.db.Where("name = ?", name).First(&room).Error
+	if gorm.IsRecordNotFoundError(queryError) {
+		return room, ErrRoomNotFound
+	}
+	return room, queryError
+}
+
+// RoomByID Returns the room with the given ID
+//
+// ErrRoomNotFound is reported if there is no such room
+func (store *Store) RoomByID(id string) (Room, error) {
+	var room Room
+	queryError := store.db.Where("id = ?", id).First(&room).Error
+	if gorm.IsRecordNotFoundError(queryError) {
+		return room, ErrRoomNotFound
+	}
+	return room, queryError
+}
+
+// SetRoomCapacity Sets the largest number of people in a room at once, no limit when zero
+func (store *Store) SetRoomCapacity(roomID string, capacity int) error {
+	return store.db.Model(&Room{}).Where("id = ?", roomID).Update("capacity", capacity).Error
+}
+
+// DeleteRoom Removes a room along with the access granted to it, in a single transaction
+func (store *Store) DeleteRoom(roomID string) error {
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		if deleteError := tx.Where("room_id = ?", roomID).Delete(&RoomAccess{}).Error; deleteError != nil {
+			return deleteError
+		}
+		return tx.Where("id = ?", roomID).Delete(&Room{}).Error
+	})
+}
+
+// AssignRoom Puts a guest's table in a room, or in none when roomID is empty
+//
+// The walk-ins sitting at the guest's table follow it to the room
+func (store *Store) AssignRoom(guestID string, roomID string) error {
+	return store.db.Model(&GuestList{}).Where("id = ? OR (walk_in = ? AND seated_with = ?)", guestID, true, guestID).Update("room_id", roomID).Error
+}
+
+// SetInRoom Records the room a guest is in with their accompanying guests, none when roomID is empty
+func (store *Store) SetInRoom(guestID string, roomID string) error {
+	return store.db.Model(&GuestList{}).Where("id = ?", guestID).Update("in_room", roomID).Error
+}
+
+// GrantRoomAccess Grants a guest the right to enter a restricted room, granting it again changes nothing
+func (store *Store) GrantRoomAccess(roomID string, guestID string) error {
+	return store.db.FirstOrCreate(&RoomAccess{}, RoomAccess{RoomID: roomID, GuestID: guestID}).Error
+}
+
+// RevokeRoomAccess Takes back the right of a guest to enter a restricted room
+func (store *Store) RevokeRoomAccess(roomID string, guestID string) error {
+	return store.db.Where("room_id = ? AND guest_id = ?", roomID, guestID).Delete(&RoomAccess{}).Error
+}
+
+// HasRoomAccess Checks if a guest was granted the right to enter a restricted room
+func (store *Store) HasRoomAccess(roomID string, guestID string) (bool, error) {
+	var count int
+	queryError := store.db.Model(&RoomAccess{}).Where("room_id = ? AND guest_id = ?", roomID, guestID).Count(&count).Error
+	return count > 0, queryError
+}
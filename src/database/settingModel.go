@@ -0,0 +1,16 @@
+package database
+
+import (
+	"time"
+)
+
+// Setting Structure representation of the settings sql table
+//
+// Holds a setting of the party changed while the server runs, like the venue capacity or the admission rules,
+// so that it outlives a restart. Value is the JSON encoding of the setting.
+// ChangedAt is not named UpdatedAt so that gorm keeps the time of the change instead of setting its own.
+type Setting struct {
+	Name      string    `json:"name" gorm:"primary_key;size:64"`
+	Value     string    `json:"value" gorm:"type:text;not null"`
+	ChangedAt time.Time `json:"changed_at" gorm:"not null"`
+}
@@ -0,0 +1,14 @@
+package database
+
+// Settings Returns the settings changed while the server ran, ordered by name
+func (store *Store) Settings() ([]Setting, error) {
+	var settings []Setting
+	queryError := store.db.Order("name").Find(&settings).Error
+	return settings, queryError
+}
+
+// SaveSetting Records the value of a setting, replacing the one recorded before
+func (store *Store) SaveSetting(setting *Setting) error {
+	setting.ChangedAt = setting.ChangedAt.UTC()
+	return store.db.Save(setting).Error
+}
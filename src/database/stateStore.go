@@ -0,0 +1,154 @@
+package database
+
+import (
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// State Everything that makes up a party: the guests with their tables and arrivals, the visits of the guests,
+// the tickets scanned at the door, the registered webhooks, the rooms with the access granted to them, the settings
+// and the parties on the waitlist
+//
+// The audit log and the webhook delivery log are history rather than state, they are not part of it
+type State struct {
+	Guests      []GuestList     `json:"guests"`
+	Visits      []Visit         `json:"visits"`
+	UsedTickets []UsedTicket    `json:"used_tickets"`
+	Webhooks    []Webhook       `json:"webhooks"`
+	Rooms       []Room          `json:"rooms"`
+	RoomAccess  []RoomAccess    `json:"room_access"`
+	Settings    []Setting       `json:"settings"`
+	Waitlist    []WaitlistEntry `json:"waitlist"`
+}
+
+// State Returns the whole state of the party, read in a single transaction so that it is consistent
+func (store *Store) State() (State, error) {
+	var state State
+	transactionError := store.db.Transaction(func(tx *gorm.DB) error {
+		if queryError := tx.Order("id").Find(&state.Guests).Error; queryError != nil {
+			return queryError
+		}
+		if queryError := tx.Order("id").Find(&state.Visits).Error; queryError != nil {
+			return queryError
+		}
+		if queryError := tx.Order("id").Find(&state.UsedTickets).Error; queryError != nil {
+			return queryError
+		}
+		if queryError := tx.Order("id").Find(&state.Webhooks).Error; queryError != nil {
+			return queryError
+		}
+		if queryError := tx.Order("id").Find(&state.Rooms).Error; queryError != nil {
+			return queryError
+		}
+		if queryError := tx.Order("room_id, guest_id").Find(&state.RoomAccess).Error; queryError != nil {
+			return queryError
+		}
+		if queryError := tx.Order("name").Find(&state.Settings).Error; queryError != nil {
+			return queryError
+		}
+		return tx.Order("id").Find(&state.Waitlist).Error
+	})
+	return state, transactionError
+}
+
+// ReplaceState Replaces the whole state of the party in a single transaction
+//
+// Deliveries to webhooks that are not part of the new state are dropped, those to the other webhooks are kept
+func (store *Store) ReplaceState(state State) error {
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		if deleteError := tx.Delete(&GuestList{}).Error; deleteError != nil {
+			return deleteError
+		}
+		if deleteError := tx.Delete(&Visit{}).Error; deleteError != nil {
+			return deleteError
+		}
+		if deleteError := tx.Delete(&UsedTicket{}).Error; deleteError != nil {
+			return deleteError
+		}
+		if deleteError := tx.Delete(&RoomAccess{}).Error; deleteError != nil {
+			return deleteError
+		}
+		if deleteError := tx.Delete(&Room{}).Error; deleteError != nil {
+			return deleteError
+		}
+		if deleteError := tx.Delete(&Setting{}).Error; deleteError != nil {
+			return deleteError
+		}
+		if deleteError := tx.Delete(&WaitlistEntry{}).Error; deleteError != nil {
+			return deleteError
+		}
+
+		webhookIDs := make([]string, 0, len(state.Webhooks))
+		for _, webhook := range state.Webhooks {
+			webhookIDs = append(webhookIDs, webhook.ID)
+		}
+		deliveries := tx
+		if len(webhookIDs) > 0 {
+			deliveries = tx.Where("webhook_id NOT IN (?)", webhookIDs)
+		}
+		if deleteError := deliveries.Delete(&WebhookDelivery{}).Error; deleteError != nil {
+			return deleteError
+		}
+		if deleteError := tx.Delete(&Webhook{}).Error; deleteError != nil {
+			return deleteError
+		}
+
+		for _, guest := range state.Guests {
+			guest.AddedAt, guest.NoShowAt = optionalUTC(guest.AddedAt), optionalUTC(guest.NoShowAt)
+			if createError := tx.Create(&guest).Error; createError != nil {
+				return createError
+			}
+		}
+		for _, visit := range state.Visits {
+			visit.GuestAddedAt, visit.ArrivedAt, visit.LeftAt = optionalUTC(visit.GuestAddedAt), visit.ArrivedAt.UTC(), optionalUTC(visit.LeftAt)
+			if createError := tx.Create(&visit).Error; createError != nil {
+				return createError
+			}
+		}
+		for _, usedTicket := range state.UsedTickets {
+			usedTicket.UsedAt = usedTicket.UsedAt.UTC()
+			if createError := tx.Create(&usedTicket).Error; createError != nil {
+				return createError
+			}
+		}
+		for _, webhook := range state.Webhooks {
+			webhook.CreatedAt = webhook.CreatedAt.UTC()
+			if createError := tx.Create(&webhook).Error; createError != nil {
+				return createError
+			}
+		}
+		for _, room := range state.Rooms {
+			room.CreatedAt = room.CreatedAt.UTC()
+			if createError := tx.Create(&room).Error; createError != nil {
+				return createError
+			}
+		}
+		for _, roomAccess := range state.RoomAccess {
+			if createError := tx.Create(&roomAccess).Error; createError != nil {
+				return createError
+			}
+		}
+		for _, setting := range state.Settings {
+			setting.ChangedAt = setting.ChangedAt.UTC()
+			if createError := tx.Create(&setting).Error; createError != nil {
+				return createError
+			}
+		}
+		for _, entry := range state.Waitlist {
+			entry.JoinedAt = entry.JoinedAt.UTC()
+			if createError := tx.Create(&entry).Error; createError != nil {
+				return createError
+			}
+		}
+		return nil
+	})
+}
+
+// optionalUTC Returns an optional time in UTC
+func optionalUTC(optionalTime *time.Time) *time.Time {
+	if optionalTime == nil {
+		return nil
+	}
+	utcTime := optionalTime.UTC()
+	return &utcTime
+}
@@ -0,0 +1,14 @@
+package database
+
+import (
+	"time"
+)
+
+// UsedTicket Structure representation of the used_tickets sql table
+//
+// Records every ticket token that was scanned at the door so that it cannot be used twice
+type UsedTicket struct {
+	ID      string    `json:"id" gorm:"primary_key;size:64"`
+	GuestID string    `json:"guest_id" gorm:"type:char(36)"`
+	UsedAt  time.Time `json:"used_at" gorm:"not null"`
+}
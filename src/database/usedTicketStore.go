@@ -0,0 +1,43 @@
+package database
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTicketUsed Reported when a ticket to mark as used was already scanned
+var ErrTicketUsed = errors.New("ticket already used")
+
+// TicketUsed Checks if a ticket has already been scanned
+func (store *Store) TicketUsed(ticketID string) (bool, error) {
+	var count int
+	queryError := store.db.Model(&UsedTicket{}).Where("id = ?", ticketID).Count(&count).Error
+	return count > 0, queryError
+}
+
+// MarkTicketUsed Records that a ticket has been scanned, ErrTicketUsed being reported if it already was
+//
+// The ticket ID is the primary key of the record, so of two scans of the same ticket only one can record it
+func (store *Store) MarkTicketUsed(ticketID string, guestID string, usedAt time.Time) error {
+	ticketUsed, queryError := store.TicketUsed(ticketID)
+	if queryError != nil {
+		return queryError
+	}
+	if ticketUsed {
+		return ErrTicketUsed
+	}
+	createError := store.db.Create(&UsedTicket{ID: ticketID, GuestID: guestID, UsedAt: usedAt.UTC()}).Error
+	if createError == nil {
+		return nil
+	}
+	// Another scan recorded the ticket meanwhile, drivers report duplicate keys differently so the record is looked for
+	if ticketUsed, queryError := store.TicketUsed(ticketID); queryError == nil && ticketUsed {
+		return ErrTicketUsed
+	}
+	return createError
+}
+
+// ReleaseTicket Forgets that a ticket has been scanned, for scans whose check-in was refused
+func (store *Store) ReleaseTicket(ticketID string) error {
+	return store.db.Where("id = ?", ticketID).Delete(&UsedTicket{}).Error
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"time"
+)
+
+// Visit Structure representation of the visits sql table
+//
+// Records the stay of a guest at the party, from check-in to check-out, so that attendance at a past time
+// can be told although checked out guests are removed from the guest list. The guest's data is copied as it was
+// at check-in. LeftAt is nil while the guest is at the party.
+type Visit struct {
+	ID                 string     `json:"id" gorm:"primary_key;type:char(36)"`
+	GuestID            string     `json:"guest_id" gorm:"type:char(36);not null"`
+	Name               string     `json:"name" gorm:"not null"`
+	Table              int        `json:"table" gorm:"not null"`
+	AccompanyingGuests int        `json:"accompanying_guests" gorm:"not null"`
+	TimeArrived        string     `json:"time_arrived" gorm:"not null"`
+	GuestAddedAt       *time.Time `json:"guest_added_at"`
+	ArrivedAt          time.Time  `json:"arrived_at" gorm:"not null"`
+	LeftAt             *time.Time `json:"left_at"`
+	WalkIn             bool       `json:"walk_in"`
+	SeatedWith         string     `json:"seated_with" gorm:"type:char(36)"`
+	RoomID             string     `json:"room_id" gorm:"type:char(36)"`
+	Category           string     `json:"category" gorm:"type:varchar(16);default:'guest'"`
+}
+
+// PresentAt Checks if the guest was at the party at a given time
+func (visit Visit) PresentAt(at time.Time) bool {
+	return !visit.ArrivedAt.After(at) && (visit.LeftAt == nil || visit.LeftAt.After(at))
+}
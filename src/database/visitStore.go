@@ -0,0 +1,84 @@
+package database
+
+import (
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// newVisit Returns the visit of a guest arriving to the party, under a newly generated ID
+func (store *Store) newVisit(guest GuestList, arrivedAt time.Time) Visit {
+	return Visit{
+		ID:                 store.ids.NewID(),
+		GuestID:            guest.ID,
+		Name:               guest.Name,
+		Table:              guest.Table,
+		AccompanyingGuests: guest.AccompanyingGuests,
+		TimeArrived:        guest.TimeArrived,
+		GuestAddedAt:       guest.AddedAt,
+		ArrivedAt:          arrivedAt.UTC(),
+		WalkIn:             guest.WalkIn,
+		SeatedWith:         guest.SeatedWith,
+		RoomID:             guest.RoomID,
+		Category:           guest.Category,
+	}
+}
+
+// CheckInGuest Updates a guest arriving to the party and opens their visit, in a single transaction
+func (store *Store) CheckInGuest(guest *GuestList, arrivedAt time.Time) error {
+	visit := store.newVisit(*guest, arrivedAt)
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		if saveError := tx.Save(guest).Error; saveError != nil {
+			return saveError
+		}
+		return tx.Create(&visit).Error
+	})
+}
+
+// CheckOutGuest Removes a guest leaving the party from the guest list and closes their visit, in a single transaction
+func (store *Store) CheckOutGuest(guest GuestList, leftAt time.Time) error {
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		if deleteError := tx.Where("id = ?", guest.ID).Delete(&GuestList{}).Error; deleteError != nil {
+			return deleteError
+		}
+		return tx.Model(&Visit{}).Where("guest_id = ? AND left_at IS NULL", guest.ID).Update("left_at", leftAt.UTC()).Error
+	})
+}
+
+// UndoCheckIn Puts back a guest as they were before checking in and forgets their visit, in a single transaction
+func (store *Store) UndoCheckIn(guest *GuestList) error {
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		if saveError := tx.Save(guest).Error; saveError != nil {
+			return saveError
+		}
+		return tx.Where("guest_id = ? AND left_at IS NULL", guest.ID).Delete(&Visit{}).Error
+	})
+}
+
+// UndoCheckOut Adds back a guest removed from the guest list when checking out and reopens their visit, in a single transaction
+func (store *Store) UndoCheckOut(guest *GuestList) error {
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		if createError := tx.Create(guest).Error; createError != nil {
+			return createError
+		}
+
+		var visits []Visit
+		if queryError := tx.Where("guest_id = ? AND left_at IS NOT NULL", guest.ID).Order("left_at DESC").Limit(1).Find(&visits).Error; queryError != nil || len(visits) == 0 {
+			return queryError
+		}
+		return tx.Model(&Visit{}).Where("id = ?", visits[0].ID).Update("left_at", gorm.Expr("NULL")).Error
+	})
+}
+
+// VisitsNotEndedBy Returns the visits of the guests still at the party at a given time or who arrived later, by arrival
+func (store *Store) VisitsNotEndedBy(at time.Time) ([]Visit, error) {
+	var visits []Visit
+	queryError := store.db.Where("left_at IS NULL OR left_at > ?", at.UTC()).Order("arrived_at, id").Find(&visits).Error
+	return visits, queryError
+}
+
+// VisitsStartedBefore Returns the visits of the guests who arrived before a given time, by arrival
+func (store *Store) VisitsStartedBefore(until time.Time) ([]Visit, error) {
+	var visits []Visit
+	queryError := store.db.Where("arrived_at < ?", until.UTC()).Order("arrived_at, id").Find(&visits).Error
+	return visits, queryError
+}
@@ -0,0 +1,18 @@
+package database
+
+import (
+	"time"
+)
+
+// WaitlistEntry Structure representation of the waitlist_entries sql table
+//
+// A party waiting at the door for seats to free up, seated as walk-ins when the tables of no-shows are released.
+// Age is the age of the person who joined if it was checked at the door.
+type WaitlistEntry struct {
+	ID                 string    `json:"id" gorm:"primary_key;type:char(36)"`
+	Name               string    `json:"name" gorm:"size:64;not null"`
+	AccompanyingGuests int       `json:"accompanying_guests" gorm:"not null"`
+	Category           string    `json:"category" gorm:"size:16;not null"`
+	Age                *int      `json:"age"`
+	JoinedAt           time.Time `json:"joined_at" gorm:"not null"`
+}
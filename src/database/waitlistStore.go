@@ -0,0 +1,54 @@
+package database
+
+import (
+	"errors"
+	"github.com/jinzhu/gorm"
+)
+
+// ErrWaitlistEntryNotFound Reported when no waitlist entry matches a lookup
+var ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+
+// AddWaitlistEntry Adds a party to the waitlist under a newly generated ID
+func (store *Store) AddWaitlistEntry(entry *WaitlistEntry) error {
+	entry.ID = store.ids.NewID()
+	entry.JoinedAt = entry.JoinedAt.UTC()
+	return store.db.Create(entry).Error
+}
+
+// Waitlist Returns every party on the waitlist, first come first
+func (store *Store) Waitlist() ([]WaitlistEntry, error) {
+	var entries []WaitlistEntry
+	queryError := store.db.Order("joined_at, id").Find(&entries).Error
+	return entries, queryError
+}
+
+// WaitlistEntryByID Returns the waitlist entry with the given ID
+//
+// ErrWaitlistEntryNotFound is reported if there is no such entry
+func (store *Store) WaitlistEntryByID(id string) (WaitlistEntry, error) {
+	var entry WaitlistEntry
+	queryError := store.db.Where("id = ?", id).First(&entry).Error
+	if gorm.IsRecordNotFoundError(queryError) {
+		return entry, ErrWaitlistEntryNotFound
+	}
+	return entry, queryError
+}
+
+// DeleteWaitlistEntry Removes a party from the waitlist
+func (store *Store) DeleteWaitlistEntry(id string) error {
+	return store.db.Where("id = ?", id).Delete(&WaitlistEntry{}).Error
+}
+
+// ClaimWaitlistEntry Removes a party from the waitlist to seat it
+//
+// Returns whether the party was still on the waitlist. The party is checked and removed in a single statement, so that
+// a party seated or leaving meanwhile is never seated twice.
+func (store *Store) ClaimWaitlistEntry(id string) (bool, error) {
+	deletion := store.db.Where("id = ?", id).Delete(&WaitlistEntry{})
+	return deletion.RowsAffected > 0, deletion.Error
+}
+
+// RestoreWaitlistEntry Puts back on the waitlist a party claimed but not seated, under its former ID and at its former place
+func (store *Store) RestoreWaitlistEntry(entry *WaitlistEntry) error {
+	return store.db.Create(entry).Error
+}
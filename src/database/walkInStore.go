@@ -0,0 +1,36 @@
+package database
+
+import (
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// AdmitWalkIn Adds a walk-in to the guest list under a newly generated ID and opens their visit, in a single transaction
+func (store *Store) AdmitWalkIn(walkIn *GuestList, arrivedAt time.Time) error {
+	walkIn.ID = store.ids.NewID()
+	visit := store.newVisit(*walkIn, arrivedAt)
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		if createError := tx.Create(walkIn).Error; createError != nil {
+			return createError
+		}
+		return tx.Create(&visit).Error
+	})
+}
+
+// WalkInsSeatedWith Returns the walk-ins at the party sitting at a guest's table
+func (store *Store) WalkInsSeatedWith(guestID string) ([]GuestList, error) {
+	var walkIns []GuestList
+	queryError := store.db.Where("walk_in = ? AND seated_with = ?", true, guestID).Order("id").Find(&walkIns).Error
+	return walkIns, queryError
+}
+
+// WalkInsAdmitted Returns the number of people admitted as walk-ins so far, the walk-ins who left included
+func (store *Store) WalkInsAdmitted() (int, error) {
+	var walkInVisits []Visit
+	queryError := store.db.Where("walk_in = ?", true).Find(&walkInVisits).Error
+	people := 0
+	for _, visit := range walkInVisits {
+		people += 1 + visit.AccompanyingGuests
+	}
+	return people, queryError
+}
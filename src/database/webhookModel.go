@@ -0,0 +1,42 @@
+package database
+
+import (
+	"time"
+)
+
+// Statuses of a webhook delivery
+const (
+	DeliveryPending   = "pending"   // waiting for its first or next attempt
+	DeliveryDelivered = "delivered" // accepted by the receiver
+	DeliveryFailed    = "failed"    // given up after too many attempts
+)
+
+// Webhook Structure representation of the webhooks sql table
+//
+// Events holds the comma separated types of the events delivered to the webhook.
+// Secret is never encoded to JSON, so that snapshots of the party do not give away the keys signing the deliveries.
+type Webhook struct {
+	ID        string    `json:"id" gorm:"primary_key;type:char(36)"`
+	URL       string    `json:"url" gorm:"size:2048;not null"`
+	Events    string    `json:"events" gorm:"size:255;not null"`
+	Secret    string    `json:"-" gorm:"size:255;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null"`
+}
+
+// WebhookDelivery Structure representation of the webhook_deliveries sql table
+//
+// The table is the outbox of the webhooks: events are recorded in it before being sent,
+// so that deliveries pending or being retried survive restarts
+type WebhookDelivery struct {
+	ID             string    `gorm:"primary_key;type:char(36)"`
+	WebhookID      string    `gorm:"type:char(36);not null"`
+	Event          string    `gorm:"size:32;not null"`
+	Payload        string    `gorm:"type:text;not null"`
+	Status         string    `gorm:"size:16;not null"`
+	Attempts       int       `gorm:"not null"`
+	LastStatusCode int       `gorm:"not null"`
+	LastError      string    `gorm:"size:1024;not null"`
+	CreatedAt      time.Time `gorm:"not null"`
+	NextAttemptAt  time.Time `gorm:"not null"`
+	DeliveredAt    *time.Time
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"errors"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+// ErrWebhookNotFound Reported when no webhook matches a lookup
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// AddWebhook Registers a webhook under a newly generated ID
+func (store *Store) AddWebhook(webhook *Webhook) error {
+	webhook.ID = store.ids.NewID()
+	webhook.CreatedAt = webhook.CreatedAt.UTC()
+	return store.db.Create(webhook).Error
+}
+
+// Webhooks Returns every registered webhook, oldest first
+func (store *Store) Webhooks() ([]Webhook, error) {
+	var webhooks []Webhook
+	queryError := store.db.Order("created_at, id").Find(&webhooks).Error
+	return webhooks, queryError
+}
+
+// WebhookByID Returns the webhook with the given ID
+//
+// ErrWebhookNotFound is reported if there is no such webhook
+func (store *Store) WebhookByID(id string) (Webhook, error) {
+	var webhook Webhook
+	queryError := store.db.Where("id = ?", id).First(&webhook).Error
+	if gorm.IsRecordNotFoundError(queryError) {
+		return webhook, ErrWebhookNotFound
+	}
+	return webhook, queryError
+}
+
+// DeleteWebhook Removes a webhook along with its deliveries
+func (store *Store) DeleteWebhook(id string) error {
+	if deleteError := store.db.Where("webhook_id = ?", id).Delete(&WebhookDelivery{}).Error; deleteError != nil {
+		return deleteError
+	}
+	return store.db.Where("id = ?", id).Delete(&Webhook{}).Error
+}
+
+// AddWebhookDelivery Records a delivery to be sent under a newly generated ID
+func (store *Store) AddWebhookDelivery(delivery *WebhookDelivery) error {
+	delivery.ID = store.ids.NewID()
+	inUTC(delivery)
+	return store.db.Create(delivery).Error
+}
+
+// SaveWebhookDelivery Updates a delivery after an attempt
+func (store *Store) SaveWebhookDelivery(delivery *WebhookDelivery) error {
+	inUTC(delivery)
+	return store.db.Save(delivery).Error
+}
+
+// inUTC Expresses the times of a delivery in UTC
+//
+// SQLite stores times as text, they only compare in order when written in the same time zone
+func inUTC(delivery *WebhookDelivery) {
+	delivery.CreatedAt = delivery.CreatedAt.UTC()
+	delivery.NextAttemptAt = delivery.NextAttemptAt.UTC()
+	delivery.DeliveredAt = optionalUTC(delivery.DeliveredAt)
+}
+
+// DueWebhookDeliveries Returns at most limit pending deliveries whose next attempt is due at the given time, oldest first
+func (store *Store) DueWebhookDeliveries(now time.Time, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	queryError := store.db.Where("status = ? AND next_attempt_at <= ?", DeliveryPending, now.UTC()).
+		Order("next_attempt_at, created_at").Limit(limit).Find(&deliveries).Error
+	return deliveries, queryError
+}
+
+// WebhookDeliveries Returns the latest deliveries of a webhook, at most limit of them, newest first
+func (store *Store) WebhookDeliveries(webhookID string, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	queryError := store.db.Where("webhook_id = ?", webhookID).
+		Order("created_at DESC, id DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, queryError
+}
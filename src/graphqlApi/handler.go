@@ -0,0 +1,201 @@
+package graphqlApi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"guestListChallenge/src/guestService"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// eventStreamMediaType Media type clients accept to receive subscription results as server-sent events
+const eventStreamMediaType = "text/event-stream"
+
+// subscribedKey Context key of the function a subscription calls once it receives updates
+type subscribedKey struct{}
+
+// notifySubscribed Tells the handler streaming a subscription that updates are being received
+func notifySubscribed(ctx context.Context) {
+	if notify, streaming := ctx.Value(subscribedKey{}).(func()); streaming {
+		notify()
+	}
+}
+
+// Request GraphQL request, sent as a JSON body or as query string parameters
+type Request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// Handler Serves GraphQL requests on the guest list
+type Handler struct {
+	guests *guestService.Service
+	schema graphql.Schema
+	logger *log.Logger
+}
+
+// NewHandler Creates the GraphQL handler of the guest list of an event
+func NewHandler(guests *guestService.Service, eventID string, logger *log.Logger) *Handler {
+	schema, schemaError := newSchema(guests, eventID)
+	if schemaError != nil {
+		logger.Println(schemaError.Error())
+		panic("Failed to build GraphQL schema")
+	}
+	return &Handler{guests: guests, schema: schema, logger: logger}
+}
+
+// ServeHTTP Executes a GraphQL request
+//
+// Queries and mutations are answered with a single JSON result. Subscriptions are answered with
+// server-sent events, one per result, when the client accepts text/event-stream.
+// Mutations must be sent with POST, so that links and prefetching browsers cannot change the guest list.
+func (handler *Handler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	graphqlRequest, decodeError := decodeRequest(request)
+	if decodeError != nil {
+		writeRequestError(response, http.StatusBadRequest, decodeError.Error())
+		return
+	}
+	if request.Method == http.MethodGet && operationType(graphqlRequest) == ast.OperationTypeMutation {
+		response.Header().Set("Allow", http.MethodPost)
+		writeRequestError(response, http.StatusMethodNotAllowed, "mutations must be sent with POST")
+		return
+	}
+
+	params := graphql.Params{
+		Schema:         handler.schema,
+		RequestString:  graphqlRequest.Query,
+		VariableValues: graphqlRequest.Variables,
+		OperationName:  graphqlRequest.OperationName,
+		Context:        withLoader(request.Context(), handler.guests),
+	}
+
+	if strings.Contains(request.Header.Get("Accept"), eventStreamMediaType) {
+		handler.streamResults(response, request, params)
+		return
+	}
+
+	result := graphql.Do(params)
+	handler.logFailures(result)
+	response.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(response).Encode(result)
+}
+
+// writeRequestError Replies to a request that cannot be executed with a GraphQL result holding the error
+func writeRequestError(response http.ResponseWriter, status int, message string) {
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(status)
+	json.NewEncoder(response).Encode(graphql.Result{Errors: []gqlerrors.FormattedError{{Message: message}}})
+}
+
+// operationType Returns the type of the operation a request executes, empty when the document cannot be parsed
+// or holds no such operation, in which case executing it reports the error
+func operationType(graphqlRequest Request) string {
+	document, parseError := parser.Parse(parser.ParseParams{Source: graphqlRequest.Query})
+	if parseError != nil {
+		return ""
+	}
+	operations := []*ast.OperationDefinition{}
+	for _, definition := range document.Definitions {
+		if operation, isOperation := definition.(*ast.OperationDefinition); isOperation {
+			operations = append(operations, operation)
+		}
+	}
+	for _, operation := range operations {
+		if graphqlRequest.OperationName == "" && len(operations) == 1 ||
+			operation.Name != nil && operation.Name.Value == graphqlRequest.OperationName {
+			return operation.Operation
+		}
+	}
+	return ""
+}
+
+// logFailures Logs the database failures behind the internal errors of a result
+func (handler *Handler) logFailures(result *graphql.Result) {
+	for _, formattedError := range result.Errors {
+		located, isLocated := formattedError.OriginalError().(*gqlerrors.Error)
+		if !isLocated {
+			continue
+		}
+		if refusal, isRefusal := located.OriginalError.(refusalError); isRefusal && refusal.cause != nil {
+			handler.logger.Println(refusal.cause.Error())
+		}
+	}
+}
+
+// streamResults Sends every result of a subscription as a server-sent event until the client goes away
+func (handler *Handler) streamResults(response http.ResponseWriter, request *http.Request, params graphql.Params) {
+	flusher, canFlush := response.(http.Flusher)
+	if !canFlush {
+		http.Error(response, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Headers are sent once subscribed, so clients waiting for them receive every later result
+	var headersSent sync.Once
+	sendHeaders := func() {
+		headersSent.Do(func() {
+			response.Header().Set("Content-Type", eventStreamMediaType)
+			response.Header().Set("Cache-Control", "no-cache")
+			response.WriteHeader(http.StatusOK)
+			flusher.Flush()
+		})
+	}
+	params.Context = context.WithValue(params.Context, subscribedKey{}, sendHeaders)
+
+	results := graphql.Subscribe(params)
+	for result := range results {
+		sendHeaders()
+		handler.logFailures(result)
+		payload, encodeError := json.Marshal(result)
+		if encodeError != nil {
+			handler.logger.Println(encodeError.Error())
+			continue
+		}
+		if _, writeError := fmt.Fprintf(response, "event: next\ndata: %s\n\n", payload); writeError != nil {
+			break
+		}
+		flusher.Flush()
+	}
+
+	// The results channel is closed once the request context is done, drain it so that nothing blocks on it
+	go func() {
+		for range results {
+		}
+	}()
+	if request.Context().Err() == nil {
+		sendHeaders()
+		fmt.Fprint(response, "event: complete\ndata: \n\n")
+		flusher.Flush()
+	}
+}
+
+// decodeRequest Reads a GraphQL request from the body of a POST request or the query string of a GET request
+func decodeRequest(request *http.Request) (Request, error) {
+	var graphqlRequest Request
+
+	if request.Method == http.MethodGet {
+		queryValues := request.URL.Query()
+		graphqlRequest.Query = queryValues.Get("query")
+		graphqlRequest.OperationName = queryValues.Get("operationName")
+		if variables := queryValues.Get("variables"); variables != "" {
+			if decodeError := json.Unmarshal([]byte(variables), &graphqlRequest.Variables); decodeError != nil {
+				return graphqlRequest, fmt.Errorf("malformed variables: %v", decodeError)
+			}
+		}
+	} else if decodeError := json.NewDecoder(request.Body).Decode(&graphqlRequest); decodeError != nil {
+		return graphqlRequest, fmt.Errorf("malformed request: %v", decodeError)
+	}
+
+	if strings.TrimSpace(graphqlRequest.Query) == "" {
+		return graphqlRequest, fmt.Errorf("query is required")
+	}
+	return graphqlRequest, nil
+}
@@ -0,0 +1,135 @@
+package graphqlApi
+
+import (
+	"context"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/guestService"
+	"sync"
+)
+
+// loaderKey Context key of the request's loader
+type loaderKey struct{}
+
+// loader Loads the guests needed by one GraphQL request with as few database queries as possible
+//
+// The whole guest list is loaded at most once per request. Guests looked up by ID are collected
+// while a level of the query is resolved and loaded together when the first of them is needed,
+// so that listing tables or visits along with their guest does not query the database once per row.
+type loader struct {
+	guests *guestService.Service
+
+	mutex      sync.Mutex
+	guestList  []database.GuestList
+	listLoaded bool
+	guestsByID map[string]database.GuestList
+	pendingIDs map[string]struct{}
+	loadError  error
+
+	// admissions Decisions of the admission policy on the guests added or checked in by the request, by guest ID
+	admissions map[string]guestService.AdmissionDecision
+}
+
+// newLoader Creates an empty loader
+func newLoader(guests *guestService.Service) *loader {
+	return &loader{
+		guests:     guests,
+		guestsByID: map[string]database.GuestList{},
+		pendingIDs: map[string]struct{}{},
+		admissions: map[string]guestService.AdmissionDecision{},
+	}
+}
+
+// withLoader Returns a context carrying a new loader
+func withLoader(ctx context.Context, guests *guestService.Service) context.Context {
+	return context.WithValue(ctx, loaderKey{}, newLoader(guests))
+}
+
+// loaderFrom Returns the loader of a request
+func loaderFrom(ctx context.Context) *loader {
+	return ctx.Value(loaderKey{}).(*loader)
+}
+
+// allGuests Returns the guest list, loading it on first use
+func (guestLoader *loader) allGuests() ([]database.GuestList, error) {
+	guestLoader.mutex.Lock()
+	defer guestLoader.mutex.Unlock()
+
+	if !guestLoader.listLoaded {
+		guestList, queryError := guestLoader.guests.Guests()
+		if queryError != nil {
+			return nil, queryError
+		}
+		guestLoader.guestList = guestList
+		guestLoader.listLoaded = true
+		for _, guest := range guestList {
+			guestLoader.guestsByID[guest.ID] = guest
+		}
+	}
+	return guestLoader.guestList, nil
+}
+
+// guest Returns a thunk resolving to the guest with the given ID, or nil if there is none
+//
+// The ID is only queued until a thunk is called, which loads every queued ID at once
+func (guestLoader *loader) guest(guestID string) func() (interface{}, error) {
+	guestLoader.mutex.Lock()
+	if _, loaded := guestLoader.guestsByID[guestID]; !loaded && !guestLoader.listLoaded {
+		guestLoader.pendingIDs[guestID] = struct{}{}
+	}
+	guestLoader.mutex.Unlock()
+
+	return func() (interface{}, error) {
+		guestLoader.mutex.Lock()
+		defer guestLoader.mutex.Unlock()
+
+		if len(guestLoader.pendingIDs) > 0 {
+			guestIDs := make([]string, 0, len(guestLoader.pendingIDs))
+			for pendingID := range guestLoader.pendingIDs {
+				guestIDs = append(guestIDs, pendingID)
+			}
+			guestLoader.pendingIDs = map[string]struct{}{}
+
+			guestList, queryError := guestLoader.guests.GuestsByIDs(guestIDs)
+			if queryError != nil {
+				guestLoader.loadError = queryError
+			}
+			for _, guest := range guestList {
+				guestLoader.guestsByID[guest.ID] = guest
+			}
+		}
+
+		if guestLoader.loadError != nil {
+			return nil, guestLoader.loadError
+		}
+		guest, found := guestLoader.guestsByID[guestID]
+		if !found {
+			return nil, nil
+		}
+		return guest, nil
+	}
+}
+
+// remember Records a guest loaded or modified while resolving the request
+func (guestLoader *loader) remember(guest database.GuestList) {
+	guestLoader.mutex.Lock()
+	defer guestLoader.mutex.Unlock()
+	guestLoader.guestsByID[guest.ID] = guest
+}
+
+// rememberAdmission Records the decision of the admission policy on a guest added or checked in by the request
+func (guestLoader *loader) rememberAdmission(guestID string, decision guestService.AdmissionDecision) {
+	guestLoader.mutex.Lock()
+	defer guestLoader.mutex.Unlock()
+	guestLoader.admissions[guestID] = decision
+}
+
+// admission Returns the decision of the admission policy on a guest added or checked in by the request, nil if there is none
+func (guestLoader *loader) admission(guestID string) *guestService.AdmissionDecision {
+	guestLoader.mutex.Lock()
+	defer guestLoader.mutex.Unlock()
+	decision, decided := guestLoader.admissions[guestID]
+	if !decided {
+		return nil
+	}
+	return &decision
+}
@@ -0,0 +1,558 @@
+package graphqlApi
+
+import (
+	"errors"
+	"github.com/graphql-go/graphql"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/utils"
+	"strings"
+)
+
+// tableData Table of a guest, the guest is resolved through the request's loader
+type tableData struct {
+	guestID    string
+	seats      int
+	seatsTaken int
+}
+
+// visitData Arrival of a guest to the party, the guest is resolved through the request's loader
+type visitData struct {
+	guestID            string
+	timeArrived        string
+	accompanyingGuests int
+}
+
+// statsData Attendance figures of the party
+type statsData struct {
+	Guests        int `json:"guests"`
+	ArrivedGuests int `json:"arrivedGuests"`
+	PeopleAtParty int `json:"peopleAtParty"`
+	Seats         int `json:"seats"`
+	SeatsTaken    int `json:"seatsTaken"`
+	SeatsEmpty    int `json:"seatsEmpty"`
+
+	Categories []categoryStatsData `json:"categories"`
+}
+
+// categoryStatsData Attendance figures of a category of guests
+type categoryStatsData struct {
+	Category      string `json:"category"`
+	Guests        int    `json:"guests"`
+	ArrivedGuests int    `json:"arrivedGuests"`
+	PeopleAtParty int    `json:"peopleAtParty"`
+}
+
+// refusalError Error reported to GraphQL clients with its error code in the error extensions
+type refusalError struct {
+	code         string
+	message      string
+	candidateIDs []string
+	rule         string
+
+	// cause Database failure behind an internal error, logged by the handler instead of being shown to clients
+	cause error
+}
+
+// Error Returns the message of the refusal
+func (refusal refusalError) Error() string {
+	return refusal.message
+}
+
+// Extensions Returns the error code of the refusal, for ambiguous guests the IDs of the candidates
+// and for guests turned away the admission rule that refused them
+func (refusal refusalError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": refusal.code}
+	if len(refusal.candidateIDs) > 0 {
+		extensions["candidateIds"] = refusal.candidateIDs
+	}
+	if refusal.rule != "" {
+		extensions["rule"] = refusal.rule
+	}
+	return extensions
+}
+
+// resolverError Converts an error of the guest service into the error returned by a resolver
+func resolverError(serviceError error) error {
+	var refusal *guestService.Error
+	if !errors.As(serviceError, &refusal) {
+		return refusalError{code: api.ErrorCodeInternal, message: "Database unreachable", cause: serviceError}
+	}
+
+	var candidateIDs []string
+	for _, candidate := range refusal.Candidates {
+		candidateIDs = append(candidateIDs, candidate.ID)
+	}
+	return refusalError{code: refusal.Code, message: refusal.Message, candidateIDs: candidateIDs, rule: refusal.Rule}
+}
+
+// newTable Returns the table of a guest
+func newTable(guest database.GuestList) tableData {
+	return tableData{guestID: guest.ID, seats: guest.Table, seatsTaken: guestService.SeatsTaken(guest, nil)}
+}
+
+// newVisit Returns the visit of a guest, nil if the guest has not arrived
+func newVisit(guest database.GuestList) *visitData {
+	if guest.TimeArrived == "" {
+		return nil
+	}
+	return &visitData{guestID: guest.ID, timeArrived: guest.TimeArrived, accompanyingGuests: guest.AccompanyingGuests}
+}
+
+// newStats Returns the attendance figures of a guest list
+func newStats(guestList []database.GuestList) statsData {
+	stats := statsData{Guests: len(guestList), SeatsEmpty: guestService.CountEmptySeats(guestList)}
+	for _, guest := range guestList {
+		stats.Seats += guest.Table
+		stats.SeatsTaken += guestService.PartySeats(guest)
+		if guest.TimeArrived != "" {
+			stats.ArrivedGuests++
+			stats.PeopleAtParty += 1 + guest.AccompanyingGuests
+		}
+	}
+	for _, count := range guestService.CountCategories(guestList) {
+		stats.Categories = append(stats.Categories, categoryStatsData(count))
+	}
+	return stats
+}
+
+// guestFilter Criteria selecting guests, every criterion given must match
+type guestFilter struct {
+	name     string
+	arrived  *bool
+	minSeats *int
+	maxSeats *int
+	category string
+}
+
+// newGuestFilter Reads the "filter" argument of a field
+func newGuestFilter(arguments map[string]interface{}) guestFilter {
+	var filter guestFilter
+	input, given := arguments["filter"].(map[string]interface{})
+	if !given {
+		return filter
+	}
+	if name, given := input["name"].(string); given {
+		filter.name = utils.NormalizeName(name)
+	}
+	if arrived, given := input["arrived"].(bool); given {
+		filter.arrived = &arrived
+	}
+	if minSeats, given := input["minSeats"].(int); given {
+		filter.minSeats = &minSeats
+	}
+	if maxSeats, given := input["maxSeats"].(int); given {
+		filter.maxSeats = &maxSeats
+	}
+	if category, given := input["category"].(string); given {
+		filter.category = category
+	}
+	return filter
+}
+
+// matches Checks if a guest matches the filter
+//
+// Names match when the guest's name contains the filter's, ignoring case and accents
+func (filter guestFilter) matches(guest database.GuestList) bool {
+	if filter.name != "" && !strings.Contains(utils.NormalizeName(guest.Name), filter.name) {
+		return false
+	}
+	if filter.arrived != nil && *filter.arrived != (guest.TimeArrived != "") {
+		return false
+	}
+	if filter.minSeats != nil && guest.Table < *filter.minSeats {
+		return false
+	}
+	if filter.maxSeats != nil && guest.Table > *filter.maxSeats {
+		return false
+	}
+	if filter.category != "" && guest.Category != filter.category {
+		return false
+	}
+	return true
+}
+
+// filteredGuests Returns the guests of the request's guest list matching the "filter" argument of a field
+func filteredGuests(resolveParams graphql.ResolveParams) ([]database.GuestList, error) {
+	guestList, queryError := loaderFrom(resolveParams.Context).allGuests()
+	if queryError != nil {
+		return nil, resolverError(queryError)
+	}
+
+	filter := newGuestFilter(resolveParams.Args)
+	matchingGuests := make([]database.GuestList, 0, len(guestList))
+	for _, guest := range guestList {
+		if filter.matches(guest) {
+			matchingGuests = append(matchingGuests, guest)
+		}
+	}
+	return matchingGuests, nil
+}
+
+// newSchema Creates the GraphQL schema of the guest list of an event
+func newSchema(guests *guestService.Service, eventID string) (graphql.Schema, error) {
+	var guestType, tableType, visitType *graphql.Object
+
+	admissionType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Admission",
+		Description: "Decision of the admission policy letting a guest in, for display at the door",
+		Fields: graphql.Fields{
+			"rule": {Type: graphql.NewNonNull(graphql.String), Description: "Rule that let the guest in, policy when every rule did", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*guestService.AdmissionDecision).Rule, nil
+			}},
+			"reason": {Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*guestService.AdmissionDecision).Reason, nil
+			}},
+		},
+	})
+
+	// resolveAdmission Resolves the decision of the admission policy on a guest added or checked in by the request
+	resolveAdmission := func(guestID string, resolveParams graphql.ResolveParams) (interface{}, error) {
+		if decision := loaderFrom(resolveParams.Context).admission(guestID); decision != nil {
+			return decision, nil
+		}
+		return nil, nil
+	}
+
+	// resolveGuestRef Resolves the guest of a table or a visit through the request's loader
+	resolveGuestRef := func(guestID string, resolveParams graphql.ResolveParams) (interface{}, error) {
+		return loaderFrom(resolveParams.Context).guest(guestID), nil
+	}
+
+	guestType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Guest",
+		Description: "Guest of the guest list",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": {Type: graphql.NewNonNull(graphql.ID), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(database.GuestList).ID, nil
+				}},
+				"name": {Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(database.GuestList).Name, nil
+				}},
+				"accompanyingGuests": {Type: graphql.NewNonNull(graphql.Int), Description: "Accompanying guests registered, or arrived with the guest once checked in", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(database.GuestList).AccompanyingGuests, nil
+				}},
+				"arrived": {Type: graphql.NewNonNull(graphql.Boolean), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(database.GuestList).TimeArrived != "", nil
+				}},
+				"category": {Type: graphql.NewNonNull(graphql.String), Description: "Category of guests the guest is in", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(database.GuestList).Category, nil
+				}},
+				"table": {Type: graphql.NewNonNull(tableType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return newTable(p.Source.(database.GuestList)), nil
+				}},
+				"visit": {Type: visitType, Description: "Arrival of the guest, null if the guest has not arrived", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if visit := newVisit(p.Source.(database.GuestList)); visit != nil {
+						return *visit, nil
+					}
+					return nil, nil
+				}},
+				"admission": {Type: admissionType, Description: "Decision admitting the guest when this request added or checked them in, null otherwise", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return resolveAdmission(p.Source.(database.GuestList).ID, p)
+				}},
+			}
+		}),
+	})
+
+	tableType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Table",
+		Description: "Table reserved for a guest and their accompanying guests",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"guest": {Type: guestType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return resolveGuestRef(p.Source.(tableData).guestID, p)
+				}},
+				"seats": {Type: graphql.NewNonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(tableData).seats, nil
+				}},
+				"seatsTaken": {Type: graphql.NewNonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(tableData).seatsTaken, nil
+				}},
+				"seatsEmpty": {Type: graphql.NewNonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					table := p.Source.(tableData)
+					return table.seats - table.seatsTaken, nil
+				}},
+			}
+		}),
+	})
+
+	visitType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Visit",
+		Description: "Arrival of a guest to the party",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"guest": {Type: guestType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return resolveGuestRef(p.Source.(visitData).guestID, p)
+				}},
+				"timeArrived": {Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(visitData).timeArrived, nil
+				}},
+				"accompanyingGuests": {Type: graphql.NewNonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(visitData).accompanyingGuests, nil
+				}},
+				"partySize": {Type: graphql.NewNonNull(graphql.Int), Description: "The guest and their accompanying guests", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return 1 + p.Source.(visitData).accompanyingGuests, nil
+				}},
+				"admission": {Type: admissionType, Description: "Decision admitting the guest when this request checked them in, null otherwise", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return resolveAdmission(p.Source.(visitData).guestID, p)
+				}},
+			}
+		}),
+	})
+
+	categoryStatsType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "CategoryStats",
+		Description: "Attendance figures of a category of guests",
+		Fields: graphql.Fields{
+			"category":      {Type: graphql.NewNonNull(graphql.String)},
+			"guests":        {Type: graphql.NewNonNull(graphql.Int), Description: "Guests of the category in the guest list"},
+			"arrivedGuests": {Type: graphql.NewNonNull(graphql.Int), Description: "Guests of the category that checked in"},
+			"peopleAtParty": {Type: graphql.NewNonNull(graphql.Int), Description: "Arrived guests of the category and their accompanying guests"},
+		},
+	})
+
+	statsType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Stats",
+		Description: "Attendance figures of the party",
+		Fields: graphql.Fields{
+			"guests":        {Type: graphql.NewNonNull(graphql.Int), Description: "Guests in the guest list"},
+			"arrivedGuests": {Type: graphql.NewNonNull(graphql.Int), Description: "Guests that checked in"},
+			"peopleAtParty": {Type: graphql.NewNonNull(graphql.Int), Description: "Arrived guests and their accompanying guests"},
+			"seats":         {Type: graphql.NewNonNull(graphql.Int)},
+			"seatsTaken":    {Type: graphql.NewNonNull(graphql.Int)},
+			"seatsEmpty":    {Type: graphql.NewNonNull(graphql.Int)},
+			"categories":    {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryStatsType))), Description: "Figures of every category of guests"},
+		},
+	})
+
+	guestFilterType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "GuestFilter",
+		Description: "Criteria selecting guests, every criterion given must match",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":     {Type: graphql.String, Description: "Part of the guest's name, ignoring case and accents"},
+			"arrived":  {Type: graphql.Boolean},
+			"minSeats": {Type: graphql.Int, Description: "Smallest number of seats at the guest's table"},
+			"maxSeats": {Type: graphql.Int, Description: "Largest number of seats at the guest's table"},
+			"category": {Type: graphql.String, Description: "Category of guests the guest is in"},
+		},
+	})
+	filterArguments := graphql.FieldConfigArgument{"filter": {Type: guestFilterType}}
+
+	// Fields shared by the query root and the Event type
+	eventFields := graphql.Fields{
+		"guests": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(guestType))),
+			Args: filterArguments,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return filteredGuests(p)
+			},
+		},
+		"tables": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tableType))),
+			Args: filterArguments,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				guestList, queryError := filteredGuests(p)
+				if queryError != nil {
+					return nil, queryError
+				}
+				tables := make([]tableData, 0, len(guestList))
+				for _, guest := range guestList {
+					tables = append(tables, newTable(guest))
+				}
+				return tables, nil
+			},
+		},
+		"visits": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(visitType))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				guestList, queryError := loaderFrom(p.Context).allGuests()
+				if queryError != nil {
+					return nil, resolverError(queryError)
+				}
+				visits := make([]visitData, 0, len(guestList))
+				for _, guest := range guestList {
+					if visit := newVisit(guest); visit != nil {
+						visits = append(visits, *visit)
+					}
+				}
+				return visits, nil
+			},
+		},
+		"stats": {
+			Type: graphql.NewNonNull(statsType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				guestList, queryError := loaderFrom(p.Context).allGuests()
+				if queryError != nil {
+					return nil, resolverError(queryError)
+				}
+				return newStats(guestList), nil
+			},
+		},
+	}
+
+	eventType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Event",
+		Description: "The party",
+		Fields: graphql.Fields{
+			"id":     {Type: graphql.NewNonNull(graphql.ID)},
+			"guests": eventFields["guests"],
+			"tables": eventFields["tables"],
+			"visits": eventFields["visits"],
+			"stats":  eventFields["stats"],
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"event": {
+				Type: graphql.NewNonNull(eventType),
+				Resolve: func(graphql.ResolveParams) (interface{}, error) {
+					return map[string]interface{}{"id": eventID}, nil
+				},
+			},
+			"guest": {
+				Type: guestType,
+				Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loaderFrom(p.Context).guest(p.Args["id"].(string)), nil
+				},
+			},
+			"guests": eventFields["guests"],
+			"tables": eventFields["tables"],
+			"visits": eventFields["visits"],
+			"stats":  eventFields["stats"],
+		},
+	})
+
+	// findGuest Finds the guest a mutation refers to, by ID if given or by name otherwise
+	findGuest := func(arguments map[string]interface{}) (database.GuestList, error) {
+		if guestID, byID := arguments["id"].(string); byID {
+			return guests.GuestByID(guestID)
+		}
+		if guestName, byName := arguments["name"].(string); byName {
+			return guests.GuestByName(guestName)
+		}
+		return database.GuestList{}, &guestService.Error{Code: api.ErrorCodeInvalidRequest, Message: "A guest must be chosen by id or by name"}
+	}
+	guestChoiceArguments := graphql.FieldConfigArgument{
+		"id":   {Type: graphql.ID, Description: "ID of the guest, needed when several guests share the name"},
+		"name": {Type: graphql.String},
+	}
+
+	mutationType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"addGuest": {
+				Type: graphql.NewNonNull(guestType),
+				Args: graphql.FieldConfigArgument{
+					"name":               {Type: graphql.NewNonNull(graphql.String)},
+					"table":              {Type: graphql.NewNonNull(graphql.Int), Description: "Number of seats at the guest's table"},
+					"accompanyingGuests": {Type: graphql.Int, DefaultValue: 0},
+					"category":           {Type: graphql.String, DefaultValue: guestService.CategoryGuest, Description: "Category of guests the guest is in"},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					guest, decision, addError := guests.AddGuest(p.Context, p.Args["name"].(string), p.Args["table"].(int), p.Args["accompanyingGuests"].(int), p.Args["category"].(string))
+					if addError != nil {
+						return nil, resolverError(addError)
+					}
+					loaderFrom(p.Context).remember(guest)
+					loaderFrom(p.Context).rememberAdmission(guest.ID, decision)
+					return guest, nil
+				},
+			},
+			"checkInGuest": {
+				Type: graphql.NewNonNull(visitType),
+				Args: graphql.FieldConfigArgument{
+					"id":                 guestChoiceArguments["id"],
+					"name":               guestChoiceArguments["name"],
+					"accompanyingGuests": {Type: graphql.Int, DefaultValue: 0},
+					"age":                {Type: graphql.Int, Description: "Age of the guest if it was checked at the door"},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					guest, findError := findGuest(p.Args)
+					if findError != nil {
+						return nil, resolverError(findError)
+					}
+					var age *int
+					if checkedAge, checked := p.Args["age"].(int); checked {
+						age = &checkedAge
+					}
+					guest, decision, checkInError := guests.CheckIn(p.Context, guest, p.Args["accompanyingGuests"].(int), age)
+					if checkInError != nil {
+						return nil, resolverError(checkInError)
+					}
+					loaderFrom(p.Context).remember(guest)
+					loaderFrom(p.Context).rememberAdmission(guest.ID, decision)
+					return *newVisit(guest), nil
+				},
+			},
+			"checkOutGuest": {
+				Type: graphql.NewNonNull(guestType),
+				Args: guestChoiceArguments,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					guest, findError := findGuest(p.Args)
+					if findError != nil {
+						return nil, resolverError(findError)
+					}
+					if checkOutError := guests.CheckOut(p.Context, guest); checkOutError != nil {
+						return nil, resolverError(checkOutError)
+					}
+					return guest, nil
+				},
+			},
+		},
+	})
+
+	subscriptionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"guestArrived": {
+				Type:        graphql.NewNonNull(visitType),
+				Description: "Every guest checking in from now on, whichever API they used",
+				Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+					updates, unsubscribe := guests.Subscribe()
+					guestLoader := loaderFrom(p.Context)
+					notifySubscribed(p.Context)
+
+					arrivals := make(chan interface{})
+					go func() {
+						defer close(arrivals)
+						defer unsubscribe()
+						for {
+							select {
+							case <-p.Context.Done():
+								return
+							case update, subscribed := <-updates:
+								if !subscribed {
+									return
+								}
+								if update.Kind != guestService.AttendanceArrived {
+									continue
+								}
+								guestLoader.remember(update.Guest)
+								select {
+								case arrivals <- *newVisit(update.Guest):
+								case <-p.Context.Done():
+									return
+								}
+							}
+						}
+					}()
+					return arrivals, nil
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:        queryType,
+		Mutation:     mutationType,
+		Subscription: subscriptionType,
+	})
+}
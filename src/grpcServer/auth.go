@@ -0,0 +1,65 @@
+package grpcServer
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"guestListChallenge/src/audit"
+)
+
+// requestIDKey Metadata key of the request ID, the gRPC counterpart of the X-Request-ID header
+const requestIDKey = "x-request-id"
+
+// identify Tells who sent a call and under which request ID, for the audit log
+//
+// When API keys are configured, calls not carrying one in their authorization metadata are rejected
+func (server *Server) identify(ctx context.Context) (context.Context, error) {
+	incoming, _ := metadata.FromIncomingContext(ctx)
+	requestID := audit.RequestID(firstValue(incoming, requestIDKey))
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	actor, authenticated := server.authenticator.Actor(firstValue(incoming, "authorization"))
+	if !authenticated {
+		return nil, status.Error(codes.Unauthenticated, "A valid API key must be sent as bearer token")
+	}
+	return audit.WithOrigin(ctx, audit.Origin{Actor: actor, RequestID: requestID}), nil
+}
+
+// unaryIdentify Interceptor identifying unary calls
+func (server *Server) unaryIdentify(ctx context.Context, request interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, identifyError := server.identify(ctx)
+	if identifyError != nil {
+		return nil, identifyError
+	}
+	return handler(ctx, request)
+}
+
+// streamIdentify Interceptor identifying streaming calls
+func (server *Server) streamIdentify(service interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, identifyError := server.identify(stream.Context())
+	if identifyError != nil {
+		return identifyError
+	}
+	return handler(service, identifiedStream{ServerStream: stream, ctx: ctx})
+}
+
+// identifiedStream Server stream whose context carries the origin of the call
+type identifiedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context Returns the context of the call carrying its origin
+func (stream identifiedStream) Context() context.Context {
+	return stream.ctx
+}
+
+// firstValue Returns the first value of a metadata key, empty if not given
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,229 @@
+package grpcServer
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/guestlistpb"
+	"strings"
+)
+
+// errorDomain Domain of the ErrorInfo details attached to refused requests
+const errorDomain = "guestlist"
+
+// statusCodes gRPC status code of every error code the guest service reports
+var statusCodes = map[string]codes.Code{
+	api.ErrorCodeInvalidRequest:   codes.InvalidArgument,
+	api.ErrorCodeGuestNotFound:    codes.NotFound,
+	api.ErrorCodeAmbiguousGuest:   codes.FailedPrecondition,
+	api.ErrorCodeTableTooSmall:    codes.FailedPrecondition,
+	api.ErrorCodeEntourageTooBig:  codes.FailedPrecondition,
+	api.ErrorCodeAlreadyCheckedIn: codes.AlreadyExists,
+	api.ErrorCodeNotArrived:       codes.FailedPrecondition,
+}
+
+// AddGuest Processes the request to add a guest to the guest list
+func (server *Server) AddGuest(ctx context.Context, request *guestlistpb.AddGuestRequest) (*guestlistpb.Guest, error) {
+	guest, decision, addError := server.guests.AddGuest(ctx, request.GetName(), int(request.GetTable()), int(request.GetAccompanyingGuests()), "")
+	if addError != nil {
+		return nil, server.statusError(addError)
+	}
+	return newAdmittedGuestMessage(guest, decision), nil
+}
+
+// ListGuests Processes the request to get the guest list
+func (server *Server) ListGuests(context.Context, *guestlistpb.ListGuestsRequest) (*guestlistpb.ListGuestsResponse, error) {
+	guestList, queryError := server.guests.Guests()
+	if queryError != nil {
+		return nil, server.statusError(queryError)
+	}
+	return newGuestListMessage(guestList), nil
+}
+
+// CheckInGuest Processes the request that happens when a guest arrives to the party
+func (server *Server) CheckInGuest(ctx context.Context, request *guestlistpb.CheckInGuestRequest) (*guestlistpb.Guest, error) {
+	guest, findError := server.findGuest(request.GetId(), request.GetName())
+	if findError != nil {
+		return nil, server.statusError(findError)
+	}
+
+	var age *int
+	if request.Age != nil {
+		checkedAge := int(request.GetAge())
+		age = &checkedAge
+	}
+	guest, decision, checkInError := server.guests.CheckIn(ctx, guest, int(request.GetAccompanyingGuests()), age)
+	if checkInError != nil {
+		return nil, server.statusError(checkInError)
+	}
+	return newAdmittedGuestMessage(guest, decision), nil
+}
+
+// CheckOutGuest Processes the request that happens when a guest leaves the party
+func (server *Server) CheckOutGuest(ctx context.Context, request *guestlistpb.CheckOutGuestRequest) (*guestlistpb.CheckOutGuestResponse, error) {
+	guest, findError := server.findGuest(request.GetId(), request.GetName())
+	if findError != nil {
+		return nil, server.statusError(findError)
+	}
+
+	if checkOutError := server.guests.CheckOut(ctx, guest); checkOutError != nil {
+		return nil, server.statusError(checkOutError)
+	}
+	return &guestlistpb.CheckOutGuestResponse{Guest: newGuestMessage(guest)}, nil
+}
+
+// ListArrivedGuests Processes the request to get the list of guests that have arrived to the party
+func (server *Server) ListArrivedGuests(context.Context, *guestlistpb.ListArrivedGuestsRequest) (*guestlistpb.ListGuestsResponse, error) {
+	guestList, queryError := server.guests.ArrivedGuests()
+	if queryError != nil {
+		return nil, server.statusError(queryError)
+	}
+	return newGuestListMessage(guestList), nil
+}
+
+// CountEmptySeats Processes the request to get the number of empty seats
+func (server *Server) CountEmptySeats(context.Context, *guestlistpb.CountEmptySeatsRequest) (*guestlistpb.CountEmptySeatsResponse, error) {
+	numberOfEmptySeats, queryError := server.guests.EmptySeats()
+	if queryError != nil {
+		return nil, server.statusError(queryError)
+	}
+	return &guestlistpb.CountEmptySeatsResponse{SeatsEmpty: int32(numberOfEmptySeats)}, nil
+}
+
+// WatchAttendance Streams attendance updates until the client goes away
+//
+// Response headers are sent once subscribed, so clients waiting for them receive every later update.
+// Clients falling too far behind are disconnected with an Unavailable status and have to watch again.
+func (server *Server) WatchAttendance(_ *guestlistpb.WatchAttendanceRequest, stream guestlistpb.GuestList_WatchAttendanceServer) error {
+	updates, unsubscribe := server.guests.Subscribe()
+	defer unsubscribe()
+	if headerError := stream.SendHeader(metadata.MD{}); headerError != nil {
+		return headerError
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case update, subscribed := <-updates:
+			if !subscribed {
+				return status.Error(codes.Unavailable, "attendance updates were not read fast enough")
+			}
+			if sendError := stream.Send(newAttendanceUpdateMessage(update)); sendError != nil {
+				return sendError
+			}
+		}
+	}
+}
+
+// findGuest Finds the guest a request refers to, by ID if present or by name otherwise
+func (server *Server) findGuest(guestID string, guestName string) (database.GuestList, error) {
+	if guestID != "" {
+		return server.guests.GuestByID(guestID)
+	}
+	if guestName == "" {
+		return database.GuestList{}, &guestService.Error{Code: api.ErrorCodeInvalidRequest, Message: "A guest must be chosen by id or by name"}
+	}
+	return server.guests.GuestByName(guestName)
+}
+
+// statusError Converts an error of the guest service into a gRPC status error
+//
+// Refusals carry an ErrorInfo detail whose reason is their error code, along with the admission rule that refused the guest
+// if any, other errors are database failures
+func (server *Server) statusError(serviceError error) error {
+	var refusal *guestService.Error
+	if !errors.As(serviceError, &refusal) {
+		server.logger.Println(serviceError.Error())
+		return errorStatus(codes.Internal, api.ErrorCodeInternal, "Database unreachable", nil)
+	}
+
+	statusCode, known := statusCodes[refusal.Code]
+	if !known {
+		statusCode = codes.FailedPrecondition
+	}
+
+	var details map[string]string
+	message := refusal.Message
+	if len(refusal.Candidates) > 0 {
+		candidateIDs := make([]string, 0, len(refusal.Candidates))
+		for _, candidate := range refusal.Candidates {
+			candidateIDs = append(candidateIDs, candidate.ID)
+		}
+		details = map[string]string{"candidate_ids": strings.Join(candidateIDs, ",")}
+		message += ": choose one by id among " + details["candidate_ids"]
+	}
+	if refusal.Rule != "" {
+		if details == nil {
+			details = map[string]string{}
+		}
+		details["rule"] = refusal.Rule
+	}
+	return errorStatus(statusCode, refusal.Code, message, details)
+}
+
+// errorStatus Creates a gRPC status error with an ErrorInfo detail
+func errorStatus(statusCode codes.Code, errorCode string, message string, metadata map[string]string) error {
+	errorStatus, detailsError := status.New(statusCode, message).WithDetails(&errdetails.ErrorInfo{
+		Reason:   errorCode,
+		Domain:   errorDomain,
+		Metadata: metadata,
+	})
+	if detailsError != nil {
+		return status.Error(statusCode, message)
+	}
+	return errorStatus.Err()
+}
+
+// newGuestMessage Converts a guest into its protocol buffer message
+func newGuestMessage(guest database.GuestList) *guestlistpb.Guest {
+	return &guestlistpb.Guest{
+		Id:                 guest.ID,
+		Name:               guest.Name,
+		Table:              int32(guest.Table),
+		AccompanyingGuests: int32(guest.AccompanyingGuests),
+		TimeArrived:        guest.TimeArrived,
+	}
+}
+
+// newAdmittedGuestMessage Converts a guest just added or checked in into its protocol buffer message, with the decision
+// of the admission policy
+func newAdmittedGuestMessage(guest database.GuestList, decision guestService.AdmissionDecision) *guestlistpb.Guest {
+	message := newGuestMessage(guest)
+	message.Admission = &guestlistpb.AdmissionDecision{Rule: decision.Rule, Reason: decision.Reason}
+	return message
+}
+
+// newGuestListMessage Converts a list of guests into its protocol buffer message
+func newGuestListMessage(guestList []database.GuestList) *guestlistpb.ListGuestsResponse {
+	guests := make([]*guestlistpb.Guest, 0, len(guestList))
+	for _, guest := range guestList {
+		guests = append(guests, newGuestMessage(guest))
+	}
+	return &guestlistpb.ListGuestsResponse{Guests: guests}
+}
+
+// newAttendanceUpdateMessage Converts an attendance update into its protocol buffer message
+func newAttendanceUpdateMessage(update guestService.AttendanceUpdate) *guestlistpb.AttendanceUpdate {
+	kind := guestlistpb.AttendanceUpdate_KIND_UNSPECIFIED
+	switch update.Kind {
+	case guestService.AttendanceArrived:
+		kind = guestlistpb.AttendanceUpdate_KIND_ARRIVED
+	case guestService.AttendanceLeft:
+		kind = guestlistpb.AttendanceUpdate_KIND_LEFT
+	}
+
+	return &guestlistpb.AttendanceUpdate{
+		Kind:       kind,
+		Guest:      newGuestMessage(update.Guest),
+		SeatsEmpty: int32(update.SeatsEmpty),
+		Time:       timestamppb.New(update.Time),
+	}
+}
@@ -0,0 +1,73 @@
+package grpcServer
+
+import (
+	"google.golang.org/grpc"
+	"guestListChallenge/src/auth"
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/guestlistpb"
+	"log"
+	"net"
+)
+
+// networkAddress TCP network address to be used by the gRPC server
+const networkAddress string = ":4243"
+
+// Config gRPC server configuration
+type Config struct {
+	NetworkAddress string
+	APIKeys        map[string]string // actors by API key, calls need none when empty
+}
+
+// DefaultConfig Returns the gRPC server configuration used by the docker setup
+func DefaultConfig() Config {
+	return Config{NetworkAddress: networkAddress}
+}
+
+// Server Guest list gRPC server
+//
+// Shares its guestService.Service with the HTTP server so that both apply the same rules
+// and attendance updates report check-ins made with either protocol
+type Server struct {
+	guestlistpb.UnimplementedGuestListServer
+
+	guests        *guestService.Service
+	logger        *log.Logger
+	config        Config
+	authenticator *auth.Authenticator
+	grpcServer    *grpc.Server
+}
+
+// NewServer Creates a Server and registers the guest list service
+func NewServer(guests *guestService.Service, logger *log.Logger, config Config) *Server {
+	server := &Server{
+		guests:        guests,
+		logger:        logger,
+		config:        config,
+		authenticator: auth.NewAuthenticator(config.APIKeys, nil),
+	}
+	server.grpcServer = grpc.NewServer(
+		grpc.UnaryInterceptor(server.unaryIdentify),
+		grpc.StreamInterceptor(server.streamIdentify),
+	)
+	guestlistpb.RegisterGuestListServer(server.grpcServer, server)
+	return server
+}
+
+// Serve Accepts gRPC connections on the given listener
+func (server *Server) Serve(listener net.Listener) error {
+	return server.grpcServer.Serve(listener)
+}
+
+// ListenAndServe Listens for incoming gRPC connections on the configured network address
+func (server *Server) ListenAndServe() error {
+	listener, listenError := net.Listen("tcp", server.config.NetworkAddress)
+	if listenError != nil {
+		return listenError
+	}
+	return server.Serve(listener)
+}
+
+// Stop Closes every connection and stops serving
+func (server *Server) Stop() {
+	server.grpcServer.Stop()
+}
@@ -0,0 +1,472 @@
+package guestService
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/utils"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stages of the party at which guests are admitted
+const (
+	AdmissionStageAddition    = "addition"    // the guest is added to the guest list
+	AdmissionStageCheckIn     = "check_in"    // the guest arrives at the door, walk-ins included
+	AdmissionStageReadmission = "readmission" // the guest is put back at the party by undoing their check-out
+	AdmissionStageRoomEntry   = "room_entry"  // the guest, at the party, enters a room
+)
+
+// Names of the admission rules, as written in rules files
+const (
+	AdmissionRuleBanList        = "ban_list"
+	AdmissionRuleTableCapacity  = "table_capacity"
+	AdmissionRuleEntourageLimit = "entourage_limit"
+	AdmissionRuleVenueCapacity  = "venue_capacity"
+	AdmissionRuleTimeWindow     = "time_window"
+	AdmissionRuleAgeRestriction = "age_restriction"
+)
+
+// AdmissionRuleNames Names of the admission rules that can be written in rules files
+var AdmissionRuleNames = []string{
+	AdmissionRuleBanList, AdmissionRuleTableCapacity, AdmissionRuleEntourageLimit,
+	AdmissionRuleVenueCapacity, AdmissionRuleTimeWindow, AdmissionRuleAgeRestriction,
+}
+
+// AdmissionPolicyRule Rule named by the decisions admitting a guest, every rule of the policy having let them in
+const AdmissionPolicyRule = "policy"
+
+// AdmissionRequest Guest asking to be admitted, with everything the rules may base their decision on
+type AdmissionRequest struct {
+	Stage              string             // one of the AdmissionStage* stages
+	Guest              database.GuestList // guest asking to be admitted, as on the guest list
+	AccompanyingGuests int                // accompanying guests coming with the guest
+	Age                *int               // age of the guest checked at the door, nil when it was not
+	Time               time.Time          // when the guest asks to be admitted
+	Category           CategoryRules      // rules of the guest's category
+	Headcount          Headcount          // people in the venue before the guest comes in, counted at the door only
+	Room               database.Room      // room the guest enters, at room entry only
+	PeopleInRoom       int                // people in the room before the guest enters, at room entry only
+}
+
+// atDoor Checks if the guest of the request comes in at the door, arriving or put back at the party
+func (request AdmissionRequest) atDoor() bool {
+	return request.Stage == AdmissionStageCheckIn || request.Stage == AdmissionStageReadmission
+}
+
+// AdmissionRule Rule letting a guest in or refusing them
+//
+// Rules are pluggable: anything implementing the interface can be part of an AdmissionPolicy
+type AdmissionRule interface {
+
+	// Name Identifies the rule in the decisions it takes
+	Name() string
+
+	// Describe Returns what the rule checks, for display at the door
+	Describe() string
+
+	// Check Returns the refusal of a guest, nil when the rule lets them in or does not apply at the request's stage
+	Check(request AdmissionRequest) *Error
+
+	// Config Returns the rule as written in rules files, NewAdmissionRule creating it again
+	Config() api.AdmissionRuleConfig
+}
+
+// AdmissionDecision Outcome of an admission request, with the rule that took it for display at the door
+type AdmissionDecision struct {
+	Allowed bool
+	Rule    string // rule that refused the guest, AdmissionPolicyRule when every rule let them in
+	Reason  string
+}
+
+// AdmissionPolicy Rules deciding who is admitted to an event, checked in order
+type AdmissionPolicy struct {
+	Event string
+	Rules []AdmissionRule
+}
+
+// DefaultAdmissionPolicy Returns the admission policy used when no rules file is given
+//
+// Guests are admitted if their table can seat their accompanying guests, their category allows them and the venue has room
+func DefaultAdmissionPolicy() AdmissionPolicy {
+	return AdmissionPolicy{Rules: []AdmissionRule{TableCapacityRule{}, EntourageLimitRule{}, VenueCapacityRule{}}}
+}
+
+// Decide Checks an admission request against every rule of the policy, the first rule refusing the guest deciding
+//
+// The refusal is also returned as an *Error naming the rule
+func (policy AdmissionPolicy) Decide(request AdmissionRequest) (AdmissionDecision, error) {
+	passed := make([]string, 0, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		if refusal := rule.Check(request); refusal != nil {
+			refusal.Rule = rule.Name()
+			return AdmissionDecision{Rule: rule.Name(), Reason: refusal.Message}, refusal
+		}
+		passed = append(passed, rule.Name())
+	}
+
+	decision := AdmissionDecision{Allowed: true, Rule: AdmissionPolicyRule, Reason: "No rule applies"}
+	switch len(passed) {
+	case 0:
+	case 1:
+		decision.Reason = "Passed " + passed[0]
+	default:
+		decision.Reason = "Passed " + strings.Join(passed[:len(passed)-1], ", ") + " and " + passed[len(passed)-1]
+	}
+	return decision, nil
+}
+
+// NewAdmissionRule Creates the admission rule described by an entry of a rules file
+func NewAdmissionRule(config api.AdmissionRuleConfig) (AdmissionRule, error) {
+	switch config.Rule {
+	case AdmissionRuleBanList:
+		return BanListRule{Names: config.Names, GuestIDs: config.GuestIDs}, nil
+	case AdmissionRuleTableCapacity:
+		return TableCapacityRule{}, nil
+	case AdmissionRuleEntourageLimit:
+		for category, limit := range config.Limits {
+			if !ValidCategory(category) {
+				return nil, errors.New("entourage_limit: category " + category + " is not one of " + strings.Join(CategoryNames, ", "))
+			}
+			if limit < 0 {
+				return nil, errors.New("entourage_limit: the limit of category " + category + " cannot be negative")
+			}
+		}
+		return EntourageLimitRule{Limits: config.Limits}, nil
+	case AdmissionRuleVenueCapacity:
+		return VenueCapacityRule{}, nil
+	case AdmissionRuleTimeWindow:
+		rule := TimeWindowRule{}
+		if config.Opens != nil {
+			rule.Opens = *config.Opens
+		}
+		if config.Closes != nil {
+			rule.Closes = *config.Closes
+		}
+		if !rule.Opens.IsZero() && !rule.Closes.IsZero() && !rule.Closes.After(rule.Opens) {
+			return nil, errors.New("time_window: admission must close after it opens")
+		}
+		return rule, nil
+	case AdmissionRuleAgeRestriction:
+		if config.MinimumAge < 1 {
+			return nil, errors.New("age_restriction: the minimum age must be at least 1")
+		}
+		return AgeRestrictionRule{MinimumAge: config.MinimumAge}, nil
+	default:
+		return nil, errors.New("Admission rule " + config.Rule + " is not one of " + strings.Join(AdmissionRuleNames, ", "))
+	}
+}
+
+// Configs Returns the rules of the policy as written in rules files, in order
+func (policy AdmissionPolicy) Configs() []api.AdmissionRuleConfig {
+	configs := make([]api.AdmissionRuleConfig, 0, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		configs = append(configs, rule.Config())
+	}
+	return configs
+}
+
+// NewAdmissionPolicy Creates the admission policy of an event from the entries of a rules file, in order
+func NewAdmissionPolicy(event string, configs []api.AdmissionRuleConfig) (AdmissionPolicy, error) {
+	policy := AdmissionPolicy{Event: event, Rules: make([]AdmissionRule, 0, len(configs))}
+	for _, config := range configs {
+		rule, ruleError := NewAdmissionRule(config)
+		if ruleError != nil {
+			return AdmissionPolicy{}, ruleError
+		}
+		policy.Rules = append(policy.Rules, rule)
+	}
+	return policy, nil
+}
+
+// ParseAdmissionRules Returns the admission policy of an event from the contents of a rules file
+//
+// A rules file holds the policy of every event it configures, an error is reported if the event is not one of them
+func ParseAdmissionRules(contents []byte, event string) (AdmissionPolicy, error) {
+	var rulesFile api.AdmissionRulesFile
+	if decodeError := json.Unmarshal(contents, &rulesFile); decodeError != nil {
+		return AdmissionPolicy{}, fmt.Errorf("Invalid rules file: %w", decodeError)
+	}
+	eventRules, configured := rulesFile.Events[event]
+	if !configured {
+		return AdmissionPolicy{}, errors.New("The rules file has no admission rules for event " + event)
+	}
+	return NewAdmissionPolicy(event, eventRules.Rules)
+}
+
+// LoadAdmissionRules Returns the admission policy of an event from a rules file
+func LoadAdmissionRules(path string, event string) (AdmissionPolicy, error) {
+	contents, readError := ioutil.ReadFile(path)
+	if readError != nil {
+		return AdmissionPolicy{}, readError
+	}
+	return ParseAdmissionRules(contents, event)
+}
+
+// BanListRule Refuses banned guests, named or identified, both when they are added and at the door
+type BanListRule struct {
+	Names    []string // names of the banned guests, compared regardless of case and accents
+	GuestIDs []string // IDs of the banned guests
+}
+
+// Name Identifies the rule in the decisions it takes
+func (rule BanListRule) Name() string {
+	return AdmissionRuleBanList
+}
+
+// Config Returns the rule as written in rules files
+func (rule BanListRule) Config() api.AdmissionRuleConfig {
+	return api.AdmissionRuleConfig{Rule: AdmissionRuleBanList, Names: rule.Names, GuestIDs: rule.GuestIDs}
+}
+
+// Describe Returns what the rule checks
+func (rule BanListRule) Describe() string {
+	banned := append(append([]string{}, rule.Names...), rule.GuestIDs...)
+	if len(banned) == 0 {
+		return "Nobody is banned"
+	}
+	return "Refuses " + strings.Join(banned, ", ")
+}
+
+// Check Refuses a banned guest
+func (rule BanListRule) Check(request AdmissionRequest) *Error {
+	if request.Stage == AdmissionStageRoomEntry {
+		return nil
+	}
+	banned := false
+	for _, guestID := range rule.GuestIDs {
+		banned = banned || (request.Guest.ID != "" && request.Guest.ID == guestID)
+	}
+	for _, name := range rule.Names {
+		banned = banned || utils.NormalizeName(request.Guest.Name) == utils.NormalizeName(name)
+	}
+	if banned {
+		return newError(api.ErrorCodeGuestBanned, "Guest "+request.Guest.Name+" is banned from the event")
+	}
+	return nil
+}
+
+// TableCapacityRule Refuses guests whose table cannot seat them with their accompanying guests
+//
+// Walk-ins are seated at tables with enough empty seats instead
+type TableCapacityRule struct{}
+
+// Name Identifies the rule in the decisions it takes
+func (rule TableCapacityRule) Name() string {
+	return AdmissionRuleTableCapacity
+}
+
+// Config Returns the rule as written in rules files
+func (rule TableCapacityRule) Config() api.AdmissionRuleConfig {
+	return api.AdmissionRuleConfig{Rule: AdmissionRuleTableCapacity}
+}
+
+// Describe Returns what the rule checks
+func (rule TableCapacityRule) Describe() string {
+	return "A guest's table must have a seat for each of their accompanying guests"
+}
+
+// Check Refuses a guest whose table cannot seat their accompanying guests
+func (rule TableCapacityRule) Check(request AdmissionRequest) *Error {
+	if request.Stage == AdmissionStageRoomEntry || request.Guest.WalkIn || request.AccompanyingGuests <= request.Guest.Table {
+		return nil
+	}
+	if request.Stage == AdmissionStageAddition {
+		return newError(api.ErrorCodeTableTooSmall, "Guest will no be added to the guest list: guest's table cannot hold so many people.")
+	}
+	return newError(api.ErrorCodeEntourageTooBig, "Guest "+request.Guest.Name+" arrived with an entourage bigger than the registered one")
+}
+
+// EntourageLimitRule Refuses guests bringing more accompanying guests than allowed for their category
+type EntourageLimitRule struct {
+
+	// Limits Largest number of accompanying guests of each category, the category rules applying to those left out
+	Limits map[string]int
+}
+
+// Name Identifies the rule in the decisions it takes
+func (rule EntourageLimitRule) Name() string {
+	return AdmissionRuleEntourageLimit
+}
+
+// Config Returns the rule as written in rules files
+func (rule EntourageLimitRule) Config() api.AdmissionRuleConfig {
+	return api.AdmissionRuleConfig{Rule: AdmissionRuleEntourageLimit, Limits: rule.Limits}
+}
+
+// Describe Returns what the rule checks
+func (rule EntourageLimitRule) Describe() string {
+	description := "Guests may bring the accompanying guests their category allows"
+	for _, category := range CategoryNames {
+		if limit, limited := rule.Limits[category]; limited {
+			description += ", " + strconv.Itoa(limit) + " for category " + category
+		}
+	}
+	return description
+}
+
+// Check Refuses a guest bringing more accompanying guests than their category allows
+func (rule EntourageLimitRule) Check(request AdmissionRequest) *Error {
+	if request.Stage == AdmissionStageRoomEntry {
+		return nil
+	}
+	limit, limited := rule.Limits[request.Guest.Category]
+	if !limited {
+		limit = request.Category.MaxAccompanyingGuests
+	}
+	return entourageRefusal(request.Guest, request.AccompanyingGuests, limit)
+}
+
+// VenueCapacityRule Refuses guests arriving when the venue cannot hold them with their accompanying guests, and guests
+// entering a room that cannot hold them, unless their category bypasses the capacities
+type VenueCapacityRule struct{}
+
+// Name Identifies the rule in the decisions it takes
+func (rule VenueCapacityRule) Name() string {
+	return AdmissionRuleVenueCapacity
+}
+
+// Config Returns the rule as written in rules files
+func (rule VenueCapacityRule) Config() api.AdmissionRuleConfig {
+	return api.AdmissionRuleConfig{Rule: AdmissionRuleVenueCapacity}
+}
+
+// Describe Returns what the rule checks
+func (rule VenueCapacityRule) Describe() string {
+	return "The venue, and the room a guest enters, must have room for the guest and their accompanying guests"
+}
+
+// Check Refuses a guest arriving when the venue is full or entering a full room
+func (rule VenueCapacityRule) Check(request AdmissionRequest) *Error {
+	if request.Category.BypassCapacity {
+		return nil
+	}
+	if request.Stage == AdmissionStageRoomEntry {
+		return roomCapacityRefusal(request.Room, request.PeopleInRoom, request.Guest, request.AccompanyingGuests)
+	}
+	if !request.atDoor() {
+		return nil
+	}
+	return capacityRefusal(request.Headcount, request.Guest, request.AccompanyingGuests)
+}
+
+// TimeWindowRule Refuses guests arriving before the doors open or after they close
+type TimeWindowRule struct {
+	Opens  time.Time // when guests are first let in, no opening time when zero
+	Closes time.Time // when guests are last let in, no closing time when zero
+}
+
+// Name Identifies the rule in the decisions it takes
+func (rule TimeWindowRule) Name() string {
+	return AdmissionRuleTimeWindow
+}
+
+// Config Returns the rule as written in rules files
+func (rule TimeWindowRule) Config() api.AdmissionRuleConfig {
+	config := api.AdmissionRuleConfig{Rule: AdmissionRuleTimeWindow}
+	if !rule.Opens.IsZero() {
+		config.Opens = &rule.Opens
+	}
+	if !rule.Closes.IsZero() {
+		config.Closes = &rule.Closes
+	}
+	return config
+}
+
+// Describe Returns what the rule checks
+func (rule TimeWindowRule) Describe() string {
+	switch {
+	case rule.Opens.IsZero() && rule.Closes.IsZero():
+		return "Guests are let in at any time"
+	case rule.Closes.IsZero():
+		return "Guests are let in from " + rule.Opens.Format(time.RFC3339)
+	case rule.Opens.IsZero():
+		return "Guests are let in until " + rule.Closes.Format(time.RFC3339)
+	default:
+		return "Guests are let in from " + rule.Opens.Format(time.RFC3339) + " until " + rule.Closes.Format(time.RFC3339)
+	}
+}
+
+// Check Refuses a guest arriving outside the admission hours
+func (rule TimeWindowRule) Check(request AdmissionRequest) *Error {
+	if !request.atDoor() {
+		return nil
+	}
+	if !rule.Opens.IsZero() && request.Time.Before(rule.Opens) {
+		return newError(api.ErrorCodeOutsideAdmissionHours, "Guest "+request.Guest.Name+" is too early: the doors open at "+rule.Opens.Format(time.RFC3339))
+	}
+	if !rule.Closes.IsZero() && request.Time.After(rule.Closes) {
+		return newError(api.ErrorCodeOutsideAdmissionHours, "Guest "+request.Guest.Name+" is too late: the doors closed at "+rule.Closes.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// AgeRestrictionRule Refuses guests younger than the minimum age at the door, or whose age was not checked
+//
+// Only the guest's age is checked, the guest answers for their accompanying guests. Guests put back at the party had their
+// age checked when they first arrived.
+type AgeRestrictionRule struct {
+	MinimumAge int
+}
+
+// Name Identifies the rule in the decisions it takes
+func (rule AgeRestrictionRule) Name() string {
+	return AdmissionRuleAgeRestriction
+}
+
+// Config Returns the rule as written in rules files
+func (rule AgeRestrictionRule) Config() api.AdmissionRuleConfig {
+	return api.AdmissionRuleConfig{Rule: AdmissionRuleAgeRestriction, MinimumAge: rule.MinimumAge}
+}
+
+// Describe Returns what the rule checks
+func (rule AgeRestrictionRule) Describe() string {
+	return "Guests must be at least " + strconv.Itoa(rule.MinimumAge) + " years old"
+}
+
+// Check Refuses a guest under the minimum age or whose age was not checked
+func (rule AgeRestrictionRule) Check(request AdmissionRequest) *Error {
+	if request.Stage != AdmissionStageCheckIn {
+		return nil
+	}
+	if request.Age == nil {
+		return newError(api.ErrorCodeAgeRestricted, "The age of guest "+request.Guest.Name+" must be checked: guests under "+
+			strconv.Itoa(rule.MinimumAge)+" are not admitted")
+	}
+	if *request.Age < rule.MinimumAge {
+		return newError(api.ErrorCodeAgeRestricted, "Guest "+request.Guest.Name+" is "+strconv.Itoa(*request.Age)+
+			", under the minimum age of "+strconv.Itoa(rule.MinimumAge))
+	}
+	return nil
+}
+
+// AdmissionPolicy Returns the rules deciding who is admitted
+func (service *Service) AdmissionPolicy() AdmissionPolicy {
+	service.admissionMutex.Lock()
+	defer service.admissionMutex.Unlock()
+	return service.admission
+}
+
+// SetAdmissionPolicy Sets the rules deciding who is admitted
+//
+// Guests already admitted stay even if they break the new rules
+func (service *Service) SetAdmissionPolicy(policy AdmissionPolicy) {
+	policy.Rules = append([]AdmissionRule{}, policy.Rules...)
+	service.admissionMutex.Lock()
+	defer service.admissionMutex.Unlock()
+	service.admission = policy
+}
+
+// admit Decides if the guest of an admission request is admitted, at the time of the service and under the rules of the
+// guest's category
+//
+// Every admission goes through it, whatever the stage, so that the admission policy decides them all
+func (service *Service) admit(request AdmissionRequest) (AdmissionDecision, error) {
+	request.Time = service.now()
+	request.Category = service.Categories().Rules(request.Guest.Category)
+	return service.AdmissionPolicy().Decide(request)
+}
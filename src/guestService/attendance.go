@@ -0,0 +1,67 @@
+package guestService
+
+import (
+	"guestListChallenge/src/database"
+	"time"
+)
+
+// Kinds of attendance updates
+const (
+	AttendanceArrived = "arrived" // a guest checked in
+	AttendanceLeft    = "left"    // a guest checked out
+)
+
+// attendanceBufferSize Number of updates kept for a subscriber that has not read them yet
+const attendanceBufferSize = 64
+
+// AttendanceUpdate A guest arriving to or leaving the party
+type AttendanceUpdate struct {
+	Kind       string
+	Guest      database.GuestList
+	SeatsEmpty int // empty seats once the guest arrived or left, -1 if they could not be counted
+	Time       time.Time
+}
+
+// Subscribe Returns a channel receiving every attendance update from now on and a function ending the subscription
+//
+// Subscribers falling more than attendanceBufferSize updates behind are dropped and their channel is closed,
+// so that a slow subscriber never holds up check-ins
+func (service *Service) Subscribe() (<-chan AttendanceUpdate, func()) {
+	updates := make(chan AttendanceUpdate, attendanceBufferSize)
+
+	service.subscribersMutex.Lock()
+	service.subscribers[updates] = struct{}{}
+	service.subscribersMutex.Unlock()
+
+	unsubscribe := func() {
+		service.subscribersMutex.Lock()
+		defer service.subscribersMutex.Unlock()
+		if _, subscribed := service.subscribers[updates]; subscribed {
+			delete(service.subscribers, updates)
+			close(updates)
+		}
+	}
+	return updates, unsubscribe
+}
+
+// publish Sends an attendance update to every subscriber
+func (service *Service) publish(kind string, guest database.GuestList) {
+	seatsEmpty, countError := service.EmptySeats()
+	if countError != nil {
+		service.logger.Println(countError.Error())
+		seatsEmpty = -1
+	}
+	update := AttendanceUpdate{Kind: kind, Guest: guest, SeatsEmpty: seatsEmpty, Time: service.clock.Now()}
+
+	service.subscribersMutex.Lock()
+	defer service.subscribersMutex.Unlock()
+	for subscriber := range service.subscribers {
+		select {
+		case subscriber <- update:
+		default:
+			service.logger.Println("Dropping attendance subscriber that fell behind")
+			delete(service.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
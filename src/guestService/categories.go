@@ -0,0 +1,223 @@
+package guestService
+
+import (
+	"context"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/database"
+	"strconv"
+	"strings"
+)
+
+// Categories of guests, every guest is in one
+const (
+	CategoryGuest   = "guest"
+	CategoryVIP     = "vip"
+	CategoryStaff   = "staff"
+	CategorySpeaker = "speaker"
+	CategoryPlusOne = "plus_one"
+	CategoryVendor  = "vendor"
+)
+
+// CategoryNames Categories of guests, in the order they are listed
+var CategoryNames = []string{CategoryGuest, CategoryVIP, CategoryStaff, CategorySpeaker, CategoryPlusOne, CategoryVendor}
+
+// NoEntourageLimit Entourage limit letting a guest bring any number of accompanying guests their table can seat
+const NoEntourageLimit = -1
+
+// CategoryRules Rules applying to the guests of a category
+type CategoryRules struct {
+
+	// ReservedTable Keeps the empty seats at the tables of the category's guests from being given to walk-ins,
+	// even once the guest arrived or was marked as a no-show
+	ReservedTable bool `json:"reserved_table"`
+
+	// BypassCapacity Lets the category's guests in with their accompanying guests even when the venue or the room
+	// they enter is full, they still count towards the headcount
+	BypassCapacity bool `json:"bypass_capacity"`
+
+	// MaxAccompanyingGuests Largest number of accompanying guests a guest of the category may bring, NoEntourageLimit for no limit
+	MaxAccompanyingGuests int `json:"max_accompanying_guests"`
+
+	// WaitlistPriority Seats parties of the category from the waitlist before those of categories with a lower priority
+	WaitlistPriority int `json:"waitlist_priority"`
+}
+
+// CategoryConfig Rules of each category of guests, by category
+//
+// Categories left out follow no rule besides those of every guest
+type CategoryConfig map[string]CategoryRules
+
+// DefaultCategoryConfig Returns the category rules used by the docker setup
+//
+// The tables of VIPs and speakers are kept for them, staff get in when the venue is full but come alone like plus-ones,
+// and VIPs then speakers are seated first from the waitlist
+func DefaultCategoryConfig() CategoryConfig {
+	return CategoryConfig{
+		CategoryGuest:   {MaxAccompanyingGuests: NoEntourageLimit},
+		CategoryVIP:     {ReservedTable: true, MaxAccompanyingGuests: NoEntourageLimit, WaitlistPriority: 2},
+		CategoryStaff:   {BypassCapacity: true, MaxAccompanyingGuests: 0},
+		CategorySpeaker: {ReservedTable: true, MaxAccompanyingGuests: NoEntourageLimit, WaitlistPriority: 1},
+		CategoryPlusOne: {MaxAccompanyingGuests: 0},
+		CategoryVendor:  {MaxAccompanyingGuests: NoEntourageLimit},
+	}
+}
+
+// Rules Returns the rules applying to the guests of a category
+func (categories CategoryConfig) Rules(category string) CategoryRules {
+	rules, configured := categories[category]
+	if !configured {
+		return CategoryRules{MaxAccompanyingGuests: NoEntourageLimit}
+	}
+	return rules
+}
+
+// TableReserved Checks if the empty seats at a guest's table are kept from walk-ins by the guest's category
+func (categories CategoryConfig) TableReserved(guest database.GuestList) bool {
+	return !guest.WalkIn && categories.Rules(guest.Category).ReservedTable
+}
+
+// ValidCategory Checks if a category is one of CategoryNames
+func ValidCategory(category string) bool {
+	for _, name := range CategoryNames {
+		if category == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCategory Refuses a category that is not one of CategoryNames
+func checkCategory(category string) error {
+	if !ValidCategory(category) {
+		return newError(api.ErrorCodeInvalidRequest, "Category "+category+" is not one of "+strings.Join(CategoryNames, ", "))
+	}
+	return nil
+}
+
+// GuestsInCategory Returns the given guests that are in a category
+func GuestsInCategory(guestList []database.GuestList, category string) []database.GuestList {
+	guestsInCategory := make([]database.GuestList, 0, len(guestList))
+	for _, guest := range guestList {
+		if guest.Category == category {
+			guestsInCategory = append(guestsInCategory, guest)
+		}
+	}
+	return guestsInCategory
+}
+
+// CategoryCount Attendance figures of a category of guests
+type CategoryCount struct {
+	Category      string
+	Guests        int // guests of the category in the guest list, walk-ins included
+	ArrivedGuests int // guests of the category at the party
+	PeopleAtParty int // arrived guests of the category and their accompanying guests
+}
+
+// CountCategories Returns the attendance figures of every category among the given guests, in the order of CategoryNames
+func CountCategories(guestList []database.GuestList) []CategoryCount {
+	counts := make([]CategoryCount, 0, len(CategoryNames))
+	for _, category := range CategoryNames {
+		guestsInCategory := GuestsInCategory(guestList, category)
+		count := CategoryCount{Category: category, Guests: len(guestsInCategory), PeopleAtParty: CountPeople(guestsInCategory)}
+		for _, guest := range guestsInCategory {
+			if guest.TimeArrived != "" {
+				count.ArrivedGuests++
+			}
+		}
+		counts = append(counts, count)
+	}
+	return counts
+}
+
+// Categories Returns the rules of each category of guests
+func (service *Service) Categories() CategoryConfig {
+	service.categoriesMutex.Lock()
+	defer service.categoriesMutex.Unlock()
+	categories := make(CategoryConfig, len(service.categories))
+	for category, rules := range service.categories {
+		categories[category] = rules
+	}
+	return categories
+}
+
+// SetCategories Sets the rules of each category of guests
+//
+// Guests already admitted stay even if they break the new rules
+func (service *Service) SetCategories(categories CategoryConfig) {
+	copied := make(CategoryConfig, len(categories))
+	for category, rules := range categories {
+		copied[category] = rules
+	}
+	service.categoriesMutex.Lock()
+	defer service.categoriesMutex.Unlock()
+	service.categories = copied
+}
+
+// SetCategoryRules Sets the rules of a category of guests, a negative entourage limit removing it
+//
+// Guests already admitted stay even if they break the new rules
+func (service *Service) SetCategoryRules(category string, rules CategoryRules) error {
+	if !ValidCategory(category) {
+		return newError(api.ErrorCodeCategoryNotFound, "Category "+category+" is not one of "+strings.Join(CategoryNames, ", "))
+	}
+	if rules.MaxAccompanyingGuests < 0 {
+		rules.MaxAccompanyingGuests = NoEntourageLimit
+	}
+	service.categoriesMutex.Lock()
+	defer service.categoriesMutex.Unlock()
+	service.categories[category] = rules
+	return nil
+}
+
+// CategoryCounts Returns the attendance figures of every category of guests, in the order of CategoryNames
+func (service *Service) CategoryCounts() ([]CategoryCount, error) {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return nil, queryError
+	}
+	return CountCategories(guestList), nil
+}
+
+// SetCategory Puts a guest in a category of guests
+//
+// An error is reported for walk-ins, who are always regular guests, and if the guest's accompanying guests,
+// the registered ones or those who came with them, are more than the category allows. Returns the guest as updated.
+func (service *Service) SetCategory(ctx context.Context, guest database.GuestList, category string) (database.GuestList, error) {
+	if categoryError := checkCategory(category); categoryError != nil {
+		return guest, categoryError
+	}
+	if guest.WalkIn {
+		return guest, newError(api.ErrorCodeInvalidRequest, "Walk-in "+guest.Name+" is a regular guest and cannot be put in a category")
+	}
+
+	previousGuest := guest
+	guest.Category = category
+	if entourageError := service.checkEntourage(guest, guest.AccompanyingGuests); entourageError != nil {
+		return previousGuest, entourageError
+	}
+
+	if storeError := service.store.SetCategory(guest.ID, category); storeError != nil {
+		return previousGuest, storeError
+	}
+
+	service.record(ctx, audit.OperationSetCategory, guest.ID, previousGuest, guest)
+	return guest, nil
+}
+
+// checkEntourage Refuses a guest coming with more accompanying guests than their category allows
+func (service *Service) checkEntourage(guest database.GuestList, accompanyingGuests int) error {
+	if refusal := entourageRefusal(guest, accompanyingGuests, service.Categories().Rules(guest.Category).MaxAccompanyingGuests); refusal != nil {
+		return refusal
+	}
+	return nil
+}
+
+// entourageRefusal Returns the refusal of a guest coming with more accompanying guests than a limit, nil if they do not
+func entourageRefusal(guest database.GuestList, accompanyingGuests int, limit int) *Error {
+	if limit != NoEntourageLimit && accompanyingGuests > limit {
+		return newError(api.ErrorCodeEntourageTooBig, "Guests in category "+guest.Category+" may bring at most "+strconv.Itoa(limit)+
+			" accompanying guests: "+guest.Name+" cannot come with "+strconv.Itoa(accompanyingGuests))
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package guestService
+
+import (
+	"guestListChallenge/src/database"
+)
+
+// Error Business rule violation reported by the Service
+//
+// Code is one of the api.ErrorCode* constants so that every protocol reports the same codes
+type Error struct {
+	Code    string
+	Message string
+
+	// Candidates Guests sharing the name of a request, set for api.ErrorCodeAmbiguousGuest
+	Candidates []database.GuestList
+
+	// Conflicts Later operations on the guest of an operation to undo, set for api.ErrorCodeUndoConflict
+	Conflicts []Operation
+
+	// Rule Admission rule that refused the guest, set for refusals of the AdmissionPolicy
+	Rule string
+}
+
+// Error Returns the human readable message of the error
+func (serviceError *Error) Error() string {
+	return serviceError.Message
+}
+
+// newError Creates an Error with the given code and message
+func newError(code string, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
@@ -0,0 +1,54 @@
+package guestService
+
+import (
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/database"
+	"time"
+)
+
+// Event Something that happened to a guest, one of the api.Event* types
+type Event struct {
+	Type  string
+	Guest database.GuestList
+	Time  time.Time
+
+	// Headcount People in the venue once the guest came in, only set for occupancy events
+	Headcount *Headcount
+}
+
+// AddEventHandler Registers a function called with every event from now on
+//
+// Handlers are called synchronously, before the operation that caused the event returns,
+// so that they can record the event durably. They must not call back into the service.
+func (service *Service) AddEventHandler(handler func(Event)) {
+	service.eventHandlersMutex.Lock()
+	defer service.eventHandlersMutex.Unlock()
+	service.eventHandlers = append(service.eventHandlers, handler)
+}
+
+// emit Calls every event handler with an event that just happened to a guest
+func (service *Service) emit(eventType string, guest database.GuestList) {
+	service.handle(Event{Type: eventType, Guest: guest, Time: service.clock.Now()})
+}
+
+// emitHeadcount Calls every event handler with an occupancy event reached by a guest coming in
+func (service *Service) emitHeadcount(eventType string, guest database.GuestList, headcount Headcount) {
+	service.handle(Event{Type: eventType, Guest: guest, Time: service.clock.Now(), Headcount: &headcount})
+}
+
+// handle Calls every event handler with an event
+func (service *Service) handle(event Event) {
+	service.eventHandlersMutex.RLock()
+	defer service.eventHandlersMutex.RUnlock()
+	for _, handler := range service.eventHandlers {
+		handler(event)
+	}
+}
+
+// emitCheckIn Emits the events of a guest checking in, walkIns being the walk-ins seated at their table
+func (service *Service) emitCheckIn(guest database.GuestList, walkIns []database.GuestList) {
+	service.emit(api.EventGuestArrived, guest)
+	if SeatsTaken(guest, walkIns) >= guest.Table {
+		service.emit(api.EventTableFull, guest)
+	}
+}
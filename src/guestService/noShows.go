@@ -0,0 +1,167 @@
+package guestService
+
+import (
+	"context"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/database"
+	"strconv"
+	"time"
+)
+
+// NoShowConfig No-show detection configuration
+type NoShowConfig struct {
+
+	// Cutoff Time after which guests who did not arrive are marked as no-shows, detection is disabled when zero
+	Cutoff time.Time
+
+	// CheckInterval How often guests who did not arrive are looked for once the cutoff passed
+	CheckInterval time.Duration
+}
+
+// DefaultNoShowConfig Returns the no-show detection configuration used by the docker setup
+//
+// No cutoff is set, guests are only marked as no-shows by hand until one is
+func DefaultNoShowConfig() NoShowConfig {
+	return NoShowConfig{CheckInterval: time.Minute}
+}
+
+// NoShowCutoff Returns the time after which guests who did not arrive are marked as no-shows, zero if none is set
+func (service *Service) NoShowCutoff() time.Time {
+	service.noShowMutex.Lock()
+	defer service.noShowMutex.Unlock()
+	return service.noShowCutoff
+}
+
+// SetNoShowCutoff Sets the time after which guests who did not arrive are marked as no-shows, zero to disable detection
+func (service *Service) SetNoShowCutoff(cutoff time.Time) {
+	service.noShowMutex.Lock()
+	defer service.noShowMutex.Unlock()
+	service.noShowCutoff = cutoff
+}
+
+// NoShows Returns the guests marked as no-shows, whose tables are released
+func (service *Service) NoShows() ([]database.GuestList, error) {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return nil, queryError
+	}
+
+	noShows := make([]database.GuestList, 0, len(guestList))
+	for _, guest := range guestList {
+		if guest.NoShowAt != nil {
+			noShows = append(noShows, guest)
+		}
+	}
+	return noShows, nil
+}
+
+// DetectNoShows Marks the guests who did not arrive as no-shows if the cutoff passed
+//
+// Returns the guests marked
+func (service *Service) DetectNoShows(ctx context.Context) ([]database.GuestList, error) {
+	cutoff := service.NoShowCutoff()
+	if cutoff.IsZero() || service.clock.Now().Before(cutoff) {
+		return nil, nil
+	}
+	return service.MarkNoShows(ctx)
+}
+
+// MarkNoShows Marks every guest who did not arrive as a no-show and releases their table, whatever the cutoff
+//
+// Guests whose reservation is held are left alone. Parties on the waitlist are then seated at the released tables.
+// Returns the guests marked.
+func (service *Service) MarkNoShows(ctx context.Context) ([]database.GuestList, error) {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return nil, queryError
+	}
+
+	noShowAt := service.now()
+	var marked []database.GuestList
+	for _, guest := range guestList {
+		if guest.TimeArrived != "" || guest.NoShowAt != nil || guest.ReservationHeld {
+			continue
+		}
+		// The guest may have checked in since the guest list was read, they are then left alone
+		wasMarked, storeError := service.store.MarkNoShow(guest.ID, noShowAt)
+		if storeError != nil {
+			return marked, storeError
+		}
+		if !wasMarked {
+			continue
+		}
+
+		previousGuest := guest
+		guest.NoShowAt = &noShowAt
+		service.record(ctx, audit.OperationMarkNoShow, guest.ID, previousGuest, guest)
+		service.emit(api.EventGuestNoShow, guest)
+		marked = append(marked, guest)
+	}
+	if len(marked) > 0 {
+		service.seatReleasedSeats(ctx)
+	}
+	return marked, nil
+}
+
+// SetNoShow Marks a guest who did not arrive as a no-show and releases their table to the parties on the waitlist,
+// or holds their reservation so that they are not marked automatically
+//
+// An error is reported if the guest already checked in. Returns the guest as updated.
+func (service *Service) SetNoShow(ctx context.Context, guest database.GuestList, noShow bool) (database.GuestList, error) {
+	if guest.TimeArrived != "" {
+		return guest, newError(api.ErrorCodeAlreadyCheckedIn, "Guest "+guest.Name+" already checked in")
+	}
+
+	var noShowAt *time.Time
+	operation := audit.OperationHoldReservation
+	if noShow {
+		now := service.now()
+		noShowAt = &now
+		operation = audit.OperationMarkNoShow
+	}
+	if storeError := service.store.SetNoShow(guest.ID, noShowAt); storeError != nil {
+		return guest, storeError
+	}
+
+	updatedGuest, queryError := service.store.GuestByID(guest.ID)
+	if queryError != nil {
+		return guest, queryError
+	}
+	if updatedGuest.TimeArrived != "" {
+		return updatedGuest, newError(api.ErrorCodeAlreadyCheckedIn, "Guest "+guest.Name+" already checked in")
+	}
+
+	service.record(ctx, operation, guest.ID, guest, updatedGuest)
+	if noShow {
+		service.emit(api.EventGuestNoShow, updatedGuest)
+		service.seatReleasedSeats(ctx)
+	}
+	return updatedGuest, nil
+}
+
+// RunNoShowDetection Marks the guests who did not arrive as no-shows once the cutoff passed, checking at every interval
+// until the context is done
+func (service *Service) RunNoShowDetection(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		marked, detectError := service.DetectNoShows(ctx)
+		if detectError != nil {
+			service.logger.Println("No-shows not detected: " + detectError.Error())
+		} else if len(marked) > 0 {
+			service.logger.Println(strconv.Itoa(len(marked)) + " guests marked as no-shows, their tables are released")
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package guestService
+
+import (
+	"guestListChallenge/src/reports"
+	"time"
+)
+
+// Occupancy Returns the occupancy report of the party until a given time, by intervals of the given length
+//
+// Without a start time the report starts with the interval of the first check-in,
+// at most reports.MaxBins intervals before its end
+func (service *Service) Occupancy(from *time.Time, until time.Time, interval time.Duration) (reports.Occupancy, error) {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return reports.Occupancy{}, queryError
+	}
+	visits, queryError := service.store.VisitsStartedBefore(until)
+	if queryError != nil {
+		return reports.Occupancy{}, queryError
+	}
+
+	window := reports.Window{Until: until.UTC(), Interval: interval}
+	if from != nil {
+		window.From = from.UTC()
+	} else {
+		window.From = window.Until.Add(-interval).Truncate(interval)
+		if len(visits) > 0 && visits[0].ArrivedAt.Before(window.From) {
+			window.From = visits[0].ArrivedAt.Truncate(interval)
+		}
+		if earliest := window.Until.Add(-reports.MaxBins * interval); window.From.Before(earliest) {
+			window.From = earliest
+		}
+	}
+	return reports.NewOccupancy(guestList, visits, window), nil
+}
@@ -0,0 +1,347 @@
+package guestService
+
+import (
+	"context"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/database"
+	"strconv"
+	"time"
+)
+
+// RoomOccupancy Room of the venue with the people in it and the seats at its tables
+type RoomOccupancy struct {
+	Room   database.Room
+	People int   // guests in the room and their accompanying guests
+	Tables int   // tables in the room, one for each guest who is not a walk-in
+	Seats  Seats // seats at the tables in the room
+}
+
+// Remaining Returns the number of people that can still come in the room, -1 if the room has no capacity
+func (occupancy RoomOccupancy) Remaining() int {
+	if occupancy.Room.Capacity <= 0 {
+		return -1
+	}
+	if occupancy.People >= occupancy.Room.Capacity {
+		return 0
+	}
+	return occupancy.Room.Capacity - occupancy.People
+}
+
+// TablesInRoom Returns the guests whose table is in a room, walk-ins sitting at those tables included
+func TablesInRoom(guestList []database.GuestList, roomID string) []database.GuestList {
+	tables := make([]database.GuestList, 0, len(guestList))
+	for _, guest := range guestList {
+		if guest.RoomID == roomID {
+			tables = append(tables, guest)
+		}
+	}
+	return tables
+}
+
+// CountPeopleInRoom Returns the number of people in a room among the given guests, accompanying guests included
+func CountPeopleInRoom(guestList []database.GuestList, roomID string) int {
+	people := 0
+	for _, guest := range guestList {
+		if guest.TimeArrived != "" && guest.InRoom == roomID {
+			people += 1 + guest.AccompanyingGuests
+		}
+	}
+	return people
+}
+
+// Rooms Returns the rooms of the venue with the people in them and the seats at their tables, by name
+func (service *Service) Rooms() ([]RoomOccupancy, error) {
+	rooms, queryError := service.store.Rooms()
+	if queryError != nil {
+		return nil, queryError
+	}
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return nil, queryError
+	}
+
+	occupancies := make([]RoomOccupancy, 0, len(rooms))
+	categories := service.Categories()
+	for _, room := range rooms {
+		occupancies = append(occupancies, roomOccupancy(room, guestList, categories))
+	}
+	return occupancies, nil
+}
+
+// RoomOccupancy Returns a room with the people in it and the seats at its tables
+func (service *Service) RoomOccupancy(room database.Room) (RoomOccupancy, error) {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return RoomOccupancy{}, queryError
+	}
+	return roomOccupancy(room, guestList, service.Categories()), nil
+}
+
+// roomOccupancy Returns a room with the people in it and the seats at its tables among the given guests
+func roomOccupancy(room database.Room, guestList []database.GuestList, categories CategoryConfig) RoomOccupancy {
+	occupancy := RoomOccupancy{Room: room, People: CountPeopleInRoom(guestList, room.ID)}
+	tables := TablesInRoom(guestList, room.ID)
+	for _, guest := range tables {
+		if !guest.WalkIn {
+			occupancy.Tables++
+		}
+	}
+	occupancy.Seats = Seats{Empty: CountEmptySeats(tables), Available: CountAvailableSeats(tables, categories)}
+	return occupancy
+}
+
+// RoomByName Returns the room with the given name
+func (service *Service) RoomByName(name string) (database.Room, error) {
+	room, queryError := service.store.RoomByName(name)
+	if queryError == database.ErrRoomNotFound {
+		return room, newError(api.ErrorCodeRoomNotFound, "Room "+name+" is not in the venue")
+	}
+	return room, queryError
+}
+
+// AddRoom Adds a room to the venue
+//
+// A capacity of zero lets any number of people in the room. Restricted rooms are only entered by the guests
+// whose table is in them and by those granted access to them. Room names are unique.
+func (service *Service) AddRoom(ctx context.Context, name string, capacity int, restricted bool) (database.Room, error) {
+	room := database.Room{Name: name, Capacity: capacity, Restricted: restricted}
+	if name == "" || capacity < 0 {
+		return room, newError(api.ErrorCodeInvalidRequest, "A room needs a name and a non-negative capacity")
+	}
+
+	if _, queryError := service.store.RoomByName(name); queryError == nil {
+		return room, newError(api.ErrorCodeRoomExists, "Room "+name+" is already in the venue")
+	} else if queryError != database.ErrRoomNotFound {
+		return room, queryError
+	}
+
+	room.CreatedAt = service.now()
+	if storeError := service.store.AddRoom(&room); storeError != nil {
+		return room, storeError
+	}
+
+	service.record(ctx, audit.OperationAddRoom, room.ID, nil, room)
+	return room, nil
+}
+
+// DeleteRoom Removes a room from the venue along with the access granted to it
+//
+// An error is reported while tables are in the room or people are in it
+func (service *Service) DeleteRoom(ctx context.Context, room database.Room) error {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return queryError
+	}
+	for _, guest := range guestList {
+		if guest.RoomID == room.ID || guest.InRoom == room.ID {
+			return newError(api.ErrorCodeRoomInUse, "Room "+room.Name+" cannot be removed: the table of "+guest.Name+" is in it or they are in it")
+		}
+	}
+
+	if storeError := service.store.DeleteRoom(room.ID); storeError != nil {
+		return storeError
+	}
+
+	service.record(ctx, audit.OperationDeleteRoom, room.ID, room, nil)
+	return nil
+}
+
+// SetRoomCapacity Sets the largest number of people in a room at once, zero to let any number in
+//
+// People already in the room stay even if they are more than the new capacity. Returns the room as updated.
+func (service *Service) SetRoomCapacity(ctx context.Context, room database.Room, capacity int) (database.Room, error) {
+	if capacity < 0 {
+		return room, newError(api.ErrorCodeInvalidRequest, "The capacity of a room cannot be negative")
+	}
+
+	service.roomMutex.Lock()
+	defer service.roomMutex.Unlock()
+	if storeError := service.store.SetRoomCapacity(room.ID, capacity); storeError != nil {
+		return room, storeError
+	}
+
+	previousRoom := room
+	room.Capacity = capacity
+	service.record(ctx, audit.OperationSetRoomCapacity, room.ID, previousRoom, room)
+	return room, nil
+}
+
+// AssignRoom Puts a guest's table in a room, or in none when room is nil
+//
+// The walk-ins sitting at the guest's table follow it, each move being recorded in the audit log.
+// Walk-ins have no table of their own to put in a room. Returns the guest as updated.
+func (service *Service) AssignRoom(ctx context.Context, guest database.GuestList, room *database.Room) (database.GuestList, error) {
+	if guest.WalkIn {
+		return guest, newError(api.ErrorCodeInvalidRequest, guest.Name+" is a walk-in and sits at the table of the guest they came with")
+	}
+
+	roomID := ""
+	if room != nil {
+		roomID = room.ID
+	}
+
+	// No walk-in is seated at the table while it moves
+	service.walkInMutex.Lock()
+	defer service.walkInMutex.Unlock()
+	walkIns, queryError := service.store.WalkInsSeatedWith(guest.ID)
+	if queryError != nil {
+		return guest, queryError
+	}
+	if storeError := service.store.AssignRoom(guest.ID, roomID); storeError != nil {
+		return guest, storeError
+	}
+
+	previousGuest := guest
+	guest.RoomID = roomID
+	service.record(ctx, audit.OperationAssignRoom, guest.ID, previousGuest, guest)
+	for _, walkIn := range walkIns {
+		previousWalkIn := walkIn
+		walkIn.RoomID = roomID
+		service.record(ctx, audit.OperationAssignRoom, walkIn.ID, previousWalkIn, walkIn)
+	}
+	return guest, nil
+}
+
+// GrantRoomAccess Grants a guest the right to enter a restricted room their table is not in
+func (service *Service) GrantRoomAccess(ctx context.Context, room database.Room, guest database.GuestList) error {
+	if storeError := service.store.GrantRoomAccess(room.ID, guest.ID); storeError != nil {
+		return storeError
+	}
+
+	service.record(ctx, audit.OperationGrantRoomAccess, room.ID, nil, database.RoomAccess{RoomID: room.ID, GuestID: guest.ID})
+	return nil
+}
+
+// RevokeRoomAccess Takes back the right of a guest to enter a restricted room
+//
+// A guest in the room when their access is revoked stays until they leave it
+func (service *Service) RevokeRoomAccess(ctx context.Context, room database.Room, guest database.GuestList) error {
+	if storeError := service.store.RevokeRoomAccess(room.ID, guest.ID); storeError != nil {
+		return storeError
+	}
+
+	service.record(ctx, audit.OperationRevokeRoomAccess, room.ID, database.RoomAccess{RoomID: room.ID, GuestID: guest.ID}, nil)
+	return nil
+}
+
+// CanEnterRoom Checks if a guest may enter a room
+//
+// Any guest may enter a room that is not restricted, restricted rooms only let in the guests whose table is in them
+// and those granted access to them
+func (service *Service) CanEnterRoom(room database.Room, guest database.GuestList) (bool, error) {
+	if !room.Restricted || guest.RoomID == room.ID {
+		return true, nil
+	}
+	return service.store.HasRoomAccess(room.ID, guest.ID)
+}
+
+// EnterRoom Checks a guest at the party in a room with their accompanying guests, leaving the room they were in
+//
+// An error is reported if the guest has not arrived or may not enter the room. The admission policy then decides if the
+// guest enters, by default an error is reported if the room would hold more than its capacity, unless the guest's
+// category bypasses it. Returns the guest as updated and the decision of the admission policy.
+func (service *Service) EnterRoom(ctx context.Context, room database.Room, guest database.GuestList) (database.GuestList, AdmissionDecision, error) {
+	if guest.TimeArrived == "" {
+		return guest, AdmissionDecision{}, newError(api.ErrorCodeNotArrived, "Guest "+guest.Name+" has not arrived yet")
+	}
+	if guest.InRoom == room.ID {
+		return guest, AdmissionDecision{}, newError(api.ErrorCodeAlreadyInRoom, "Guest "+guest.Name+" is already in room "+room.Name)
+	}
+
+	canEnter, queryError := service.CanEnterRoom(room, guest)
+	if queryError != nil {
+		return guest, AdmissionDecision{}, queryError
+	}
+	if !canEnter {
+		return guest, AdmissionDecision{}, newError(api.ErrorCodeRoomAccessDenied, "Guest "+guest.Name+" has no access to room "+room.Name)
+	}
+
+	// Entries are serialised so that a room never holds more than its capacity
+	service.roomMutex.Lock()
+	defer service.roomMutex.Unlock()
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return guest, AdmissionDecision{}, queryError
+	}
+	decision, refusal := service.admit(AdmissionRequest{Stage: AdmissionStageRoomEntry, Guest: guest, AccompanyingGuests: guest.AccompanyingGuests,
+		Room: room, PeopleInRoom: CountPeopleInRoom(guestList, room.ID)})
+	if refusal != nil {
+		return guest, decision, refusal
+	}
+
+	if storeError := service.store.SetInRoom(guest.ID, room.ID); storeError != nil {
+		return guest, decision, storeError
+	}
+
+	previousGuest := guest
+	guest.InRoom = room.ID
+	service.record(ctx, audit.OperationEnterRoom, guest.ID, previousGuest, guest)
+	return guest, decision, nil
+}
+
+// roomCapacityRefusal Returns the refusal of a guest entering a room with their accompanying guests if the room would hold
+// more than its capacity, nil if it would not
+func roomCapacityRefusal(room database.Room, peopleInRoom int, guest database.GuestList, accompanyingGuests int) *Error {
+	if room.Capacity > 0 && peopleInRoom+1+accompanyingGuests > room.Capacity {
+		return newError(api.ErrorCodeRoomFull, "Room "+room.Name+" holds "+strconv.Itoa(peopleInRoom)+" people of its capacity of "+
+			strconv.Itoa(room.Capacity)+": "+guest.Name+" cannot come in with "+strconv.Itoa(accompanyingGuests)+" accompanying guests")
+	}
+	return nil
+}
+
+// LeaveRoom Checks a guest out of a room with their accompanying guests, they stay at the party
+//
+// An error is reported if the guest is not in the room. Returns the guest as updated.
+func (service *Service) LeaveRoom(ctx context.Context, room database.Room, guest database.GuestList) (database.GuestList, error) {
+	if guest.InRoom != room.ID {
+		return guest, newError(api.ErrorCodeNotInRoom, "Guest "+guest.Name+" is not in room "+room.Name)
+	}
+
+	if storeError := service.store.SetInRoom(guest.ID, ""); storeError != nil {
+		return guest, storeError
+	}
+
+	previousGuest := guest
+	guest.InRoom = ""
+	service.record(ctx, audit.OperationLeaveRoom, guest.ID, previousGuest, guest)
+	return guest, nil
+}
+
+// GuestsInRoom Returns the guests in a room
+func (service *Service) GuestsInRoom(roomID string) ([]database.GuestList, error) {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return nil, queryError
+	}
+
+	guestsInRoom := make([]database.GuestList, 0, len(guestList))
+	for _, guest := range guestList {
+		if guest.TimeArrived != "" && guest.InRoom == roomID {
+			guestsInRoom = append(guestsInRoom, guest)
+		}
+	}
+	return guestsInRoom, nil
+}
+
+// SeatsInRoom Returns the numbers of empty seats at the tables in a room
+func (service *Service) SeatsInRoom(roomID string) (Seats, error) {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return Seats{}, queryError
+	}
+	tables := TablesInRoom(guestList, roomID)
+	return Seats{Empty: CountEmptySeats(tables), Available: CountAvailableSeats(tables, service.Categories())}, nil
+}
+
+// SeatsInRoomAt Returns the numbers of empty seats at the tables in a room at a given past time
+//
+// Tables are taken in the room they were in when their guest checked in, or are in now for guests who had not checked in
+func (service *Service) SeatsInRoomAt(roomID string, at time.Time) (Seats, error) {
+	guestList, queryError := service.guestListAt(at)
+	if queryError != nil {
+		return Seats{}, queryError
+	}
+	tables := TablesInRoom(guestList, roomID)
+	return Seats{Empty: CountEmptySeats(tables), Available: CountAvailableSeats(tables, service.Categories())}, nil
+}
@@ -0,0 +1,501 @@
+package guestService
+
+import (
+	"context"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/utils"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxNameLength Longest name of a guest or walk-in, in characters
+const MaxNameLength = 64
+
+// namePattern Names start with a letter and go on with letters, combining marks, spaces, dots, apostrophes and hyphens
+var namePattern = regexp.MustCompile(`^\p{L}[\p{L}\p{M} .'-]*$`)
+
+// checkName Reports a name that breaks the naming rules, every protocol sharing them
+func checkName(name string) *Error {
+	if name == "" || utf8.RuneCountInString(name) > MaxNameLength || !namePattern.MatchString(name) {
+		return newError(api.ErrorCodeInvalidRequest, "Name "+strconv.Quote(name)+" must be 1 to "+strconv.Itoa(MaxNameLength)+
+			" characters long, start with a letter and hold only letters, spaces, dots, apostrophes and hyphens")
+	}
+	return nil
+}
+
+// Service Guest list business logic shared by the REST and gRPC servers
+//
+// Rules violations are reported as *Error, any other error comes from the database
+type Service struct {
+	store  *database.Store
+	clock  utils.Clock
+	logger *log.Logger
+	audit  *audit.Log
+
+	subscribersMutex sync.Mutex
+	subscribers      map[chan AttendanceUpdate]struct{}
+
+	eventHandlersMutex sync.RWMutex
+	eventHandlers      []func(Event)
+
+	undoMutex sync.Mutex
+
+	noShowMutex  sync.Mutex
+	noShowCutoff time.Time
+
+	walkInMutex sync.Mutex
+	walkInCap   int
+
+	venueMutex sync.Mutex
+	venue      VenueConfig
+
+	roomMutex sync.Mutex
+
+	categoriesMutex sync.Mutex
+	categories      CategoryConfig
+
+	admissionMutex sync.Mutex
+	admission      AdmissionPolicy
+
+	settingsMutex sync.Mutex
+
+	waitlistMutex sync.Mutex
+}
+
+// NewService Creates a Service working on the given store
+func NewService(store *database.Store, clock utils.Clock, logger *log.Logger) *Service {
+	return &Service{
+		store:       store,
+		clock:       clock,
+		logger:      logger,
+		audit:       audit.NewLog(store, clock),
+		subscribers: map[chan AttendanceUpdate]struct{}{},
+		walkInCap:   NoWalkInCap,
+		venue:       DefaultVenueConfig(),
+		categories:  DefaultCategoryConfig(),
+		admission:   DefaultAdmissionPolicy(),
+	}
+}
+
+// AuditLog Returns the audit log the service records its mutations in, to record other mutations in the same log
+func (service *Service) AuditLog() *audit.Log {
+	return service.audit
+}
+
+// AddGuest Adds a guest to the guest list in a category of guests, CategoryGuest when empty
+//
+// The admission policy decides if the guest is added, by default an error is reported if the number of accompanying
+// guests is larger than the table capacity or than the category allows. Guests sharing a name are added as different guests.
+// Names are checked against the same rules whatever the protocol, see MaxNameLength.
+// Returns the guest as added and the decision of the admission policy.
+func (service *Service) AddGuest(ctx context.Context, name string, table int, accompanyingGuests int, category string) (database.GuestList, AdmissionDecision, error) {
+	if category == "" {
+		category = CategoryGuest
+	}
+	guest := database.GuestList{Name: name, Table: table, AccompanyingGuests: accompanyingGuests, Category: category}
+
+	if name == "" || table < 1 || accompanyingGuests < 0 {
+		return guest, AdmissionDecision{}, newError(api.ErrorCodeInvalidRequest, "A guest needs a name, a table of at least 1 seat and a non-negative number of accompanying guests")
+	}
+	if nameError := checkName(name); nameError != nil {
+		return guest, AdmissionDecision{}, nameError
+	}
+	if categoryError := checkCategory(category); categoryError != nil {
+		return guest, AdmissionDecision{}, categoryError
+	}
+
+	decision, refusal := service.admit(AdmissionRequest{Stage: AdmissionStageAddition, Guest: guest, AccompanyingGuests: accompanyingGuests})
+	if refusal != nil {
+		return guest, decision, refusal
+	}
+
+	addedAt := service.now()
+	guest.AddedAt = &addedAt
+	if storeError := service.store.AddGuest(&guest); storeError != nil {
+		return guest, decision, storeError
+	}
+
+	service.record(ctx, audit.OperationAddGuest, guest.ID, nil, guest)
+	service.emit(api.EventGuestAdded, guest)
+	return guest, decision, nil
+}
+
+// Guests Returns the guest list
+func (service *Service) Guests() ([]database.GuestList, error) {
+	return service.store.Guests()
+}
+
+// GuestByID Returns the guest with the given ID
+func (service *Service) GuestByID(guestID string) (database.GuestList, error) {
+	guest, queryError := service.store.GuestByID(guestID)
+	if queryError == database.ErrGuestNotFound {
+		return guest, newError(api.ErrorCodeGuestNotFound, "Guest with ID "+guestID+" is not in the guest list")
+	}
+	return guest, queryError
+}
+
+// GuestsByIDs Returns the guests with the given IDs, IDs without a guest are ignored
+func (service *Service) GuestsByIDs(guestIDs []string) ([]database.GuestList, error) {
+	return service.store.GuestsByIDs(guestIDs)
+}
+
+// GuestByName Returns the only guest with the given name
+//
+// An error listing the candidates is reported when several guests share the name
+func (service *Service) GuestByName(name string) (database.GuestList, error) {
+	guests, queryError := service.store.GuestsByName(name)
+	if queryError != nil {
+		return database.GuestList{}, queryError
+	}
+
+	switch len(guests) {
+	case 0:
+		return database.GuestList{}, newError(api.ErrorCodeGuestNotFound, "Guest "+name+" is not in the guest list")
+	case 1:
+		return guests[0], nil
+	default:
+		return database.GuestList{}, &Error{
+			Code:       api.ErrorCodeAmbiguousGuest,
+			Message:    "Several guests are called " + name,
+			Candidates: guests,
+		}
+	}
+}
+
+// CheckIn Checks in a guest arriving to the party with the given number of accompanying guests, age being the guest's age
+// if it was checked at the door
+//
+// The admission policy decides if the guest comes in, by default an error is reported if the number of accompanying
+// guests is larger than the table capacity or than the guest's category allows, or if the venue is full. An error is also
+// reported if the guest already checked in. Returns the guest as updated and the decision of the admission policy.
+func (service *Service) CheckIn(ctx context.Context, guest database.GuestList, accompanyingGuests int, age *int) (database.GuestList, AdmissionDecision, error) {
+	if accompanyingGuests < 0 {
+		return guest, AdmissionDecision{}, newError(api.ErrorCodeInvalidRequest, "The number of accompanying guests cannot be negative")
+	}
+
+	// Check if guest already checked in
+	if guest.TimeArrived != "" {
+		return guest, AdmissionDecision{}, newError(api.ErrorCodeAlreadyCheckedIn, "Guest "+guest.Name+" already checked in")
+	}
+
+	// The table of the guest may have been given to walk-ins meanwhile, while they were a no-show or before their
+	// check-in was undone
+	service.walkInMutex.Lock()
+	defer service.walkInMutex.Unlock()
+	walkIns, queryError := service.store.WalkInsSeatedWith(guest.ID)
+	if queryError != nil {
+		return guest, AdmissionDecision{}, queryError
+	}
+	seatsLeft := guest.Table - SeatsTaken(guest, walkIns)
+	if len(walkIns) > 0 && accompanyingGuests > seatsLeft {
+		return guest, AdmissionDecision{}, newError(api.ErrorCodeEntourageTooBig, "The table of guest "+guest.Name+" was given to walk-ins, only "+strconv.Itoa(seatsLeft)+" seats are left")
+	}
+
+	// Arrivals are serialised so that the venue never holds more than its capacity
+	service.venueMutex.Lock()
+	defer service.venueMutex.Unlock()
+	headcount, queryError := service.lockedHeadcount()
+	if queryError != nil {
+		return guest, AdmissionDecision{}, queryError
+	}
+	decision, refusal := service.admit(AdmissionRequest{Stage: AdmissionStageCheckIn, Guest: guest, AccompanyingGuests: accompanyingGuests, Age: age, Headcount: headcount})
+	if refusal != nil {
+		return guest, decision, refusal
+	}
+
+	// Update guest data
+	previousGuest := guest
+	guest.AccompanyingGuests = accompanyingGuests
+	guest.TimeArrived = utils.GetHoursAndMinutesString(service.clock)
+	// A guest arriving late is no longer a no-show
+	guest.NoShowAt = nil
+
+	// Update guest in the database and record their visit
+	if storeError := service.store.CheckInGuest(&guest, service.now()); storeError != nil {
+		return guest, decision, storeError
+	}
+
+	service.record(ctx, audit.OperationCheckInGuest, guest.ID, previousGuest, guest)
+	service.publish(AttendanceArrived, guest)
+	service.emitCheckIn(guest, walkIns)
+	service.emitOccupancy(headcount, guest)
+	return guest, decision, nil
+}
+
+// CheckOut Checks out a guest leaving the party
+//
+// When a guest leaves, all their accompanying guests leave as well.
+func (service *Service) CheckOut(ctx context.Context, guest database.GuestList) error {
+
+	// Check if guest checked in
+	if guest.TimeArrived == "" {
+		return newError(api.ErrorCodeNotArrived, "Guest "+guest.Name+" has not arrived yet")
+	}
+
+	// Delete checked in guest from database and end their visit
+	if storeError := service.store.CheckOutGuest(guest, service.now()); storeError != nil {
+		return storeError
+	}
+
+	service.record(ctx, audit.OperationCheckOutGuest, guest.ID, guest, nil)
+	service.publish(AttendanceLeft, guest)
+	service.emit(api.EventGuestLeft, guest)
+	return nil
+}
+
+// DeleteGuest Removes a guest who has not arrived from the guest list
+//
+// Guests at the party are checked out instead. An error is also reported while walk-ins sit at the guest's table.
+func (service *Service) DeleteGuest(ctx context.Context, guest database.GuestList) error {
+	if guest.TimeArrived != "" {
+		return newError(api.ErrorCodeAlreadyCheckedIn, "Guest "+guest.Name+" is at the party and has to be checked out instead")
+	}
+
+	// Walk-ins seated at the table of the guest would be left without the guest who gave them their seats
+	service.walkInMutex.Lock()
+	defer service.walkInMutex.Unlock()
+	walkIns, queryError := service.store.WalkInsSeatedWith(guest.ID)
+	if queryError != nil {
+		return queryError
+	}
+	if len(walkIns) > 0 {
+		return newError(api.ErrorCodeWalkInsSeated, "Guest "+guest.Name+" cannot be removed: walk-ins are seated at their table")
+	}
+
+	if storeError := service.store.DeleteGuest(guest); storeError != nil {
+		return storeError
+	}
+
+	service.record(ctx, audit.OperationDeleteGuest, guest.ID, guest, nil)
+	return nil
+}
+
+// record Appends the record of a mutation to the audit log
+//
+// The mutation already happened, a record that cannot be appended is logged instead. Returns the record as appended.
+func (service *Service) record(ctx context.Context, operation string, targetID string, before interface{}, after interface{}) database.AuditRecord {
+	record, recordError := service.audit.Record(ctx, operation, targetID, before, after)
+	if recordError != nil {
+		service.logger.Println("Audit record of " + operation + " on " + targetID + " lost: " + recordError.Error())
+	}
+	return record
+}
+
+// now Returns the current time as stored in the database, in UTC to the second
+func (service *Service) now() time.Time {
+	return service.clock.Now().UTC().Truncate(time.Second)
+}
+
+// ArrivedGuests Returns the guests that are at the party
+func (service *Service) ArrivedGuests() ([]database.GuestList, error) {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return nil, queryError
+	}
+
+	//Only account for guests that checked in
+	guestListIndex := 0
+	for _, guest := range guestList {
+		if guest.TimeArrived != "" {
+			//Copy data and increment index
+			guestList[guestListIndex] = guest
+			guestListIndex++
+		}
+	}
+	// "Truncate" slice
+	return guestList[:guestListIndex], nil
+}
+
+// EmptySeats Returns the number of empty seats
+func (service *Service) EmptySeats() (int, error) {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return 0, queryError
+	}
+	return CountEmptySeats(guestList), nil
+}
+
+// GuestsAt Returns the guests that were at the party at a given past time
+//
+// Guests checked in before visits were recorded are taken as arrived since they were added
+func (service *Service) GuestsAt(at time.Time) ([]database.GuestList, error) {
+	guestList, queryError := service.guestListAt(at)
+	if queryError != nil {
+		return nil, queryError
+	}
+
+	arrivedGuests := make([]database.GuestList, 0, len(guestList))
+	for _, guest := range guestList {
+		if guest.TimeArrived != "" {
+			arrivedGuests = append(arrivedGuests, guest)
+		}
+	}
+	return arrivedGuests, nil
+}
+
+// Seats Numbers of empty seats at the party
+type Seats struct {
+	Empty     int // seats not taken, at the tables of guests who did not arrive included
+	Available int // empty seats that can be given away: at the tables of arrived guests and of no-shows, unless reserved by their category
+}
+
+// Seats Returns the numbers of empty seats
+func (service *Service) Seats() (Seats, error) {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return Seats{}, queryError
+	}
+	return Seats{Empty: CountEmptySeats(guestList), Available: CountAvailableSeats(guestList, service.Categories())}, nil
+}
+
+// SeatsAt Returns the numbers of empty seats at a given past time
+func (service *Service) SeatsAt(at time.Time) (Seats, error) {
+	guestList, queryError := service.guestListAt(at)
+	if queryError != nil {
+		return Seats{}, queryError
+	}
+	return Seats{Empty: CountEmptySeats(guestList), Available: CountAvailableSeats(guestList, service.Categories())}, nil
+}
+
+// guestListAt Reconstructs the guest list as it was at a given past time, by name
+//
+// The current guest list is taken back to that time with the visits that had not ended by then:
+// guests added later are left out, guests who checked out later are put back, guests who checked in later
+// are taken as not arrived yet and guests marked as no-shows later are taken as expected
+func (service *Service) guestListAt(at time.Time) ([]database.GuestList, error) {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return nil, queryError
+	}
+	visits, queryError := service.store.VisitsNotEndedBy(at)
+	if queryError != nil {
+		return nil, queryError
+	}
+
+	guestsByID := make(map[string]database.GuestList, len(guestList))
+	for _, guest := range guestList {
+		if guest.AddedAt == nil || !guest.AddedAt.After(at) {
+			if guest.NoShowAt != nil && guest.NoShowAt.After(at) {
+				// Marked as a no-show later
+				guest.NoShowAt = nil
+			}
+			guestsByID[guest.ID] = guest
+		}
+	}
+	for _, visit := range visits {
+		if visit.GuestAddedAt != nil && visit.GuestAddedAt.After(at) {
+			continue
+		}
+		guest := database.GuestList{
+			ID:                 visit.GuestID,
+			Name:               visit.Name,
+			Table:              visit.Table,
+			AccompanyingGuests: visit.AccompanyingGuests,
+			TimeArrived:        visit.TimeArrived,
+			AddedAt:            visit.GuestAddedAt,
+			WalkIn:             visit.WalkIn,
+			SeatedWith:         visit.SeatedWith,
+			RoomID:             visit.RoomID,
+			Category:           visit.Category,
+		}
+		if !visit.PresentAt(at) {
+			// Checked in later
+			guest.TimeArrived = ""
+		}
+		guestsByID[guest.ID] = guest
+	}
+
+	guestListAt := make([]database.GuestList, 0, len(guestsByID))
+	for _, guest := range guestsByID {
+		guestListAt = append(guestListAt, guest)
+	}
+	sort.Slice(guestListAt, func(i, j int) bool {
+		if guestListAt[i].Name != guestListAt[j].Name {
+			return guestListAt[i].Name < guestListAt[j].Name
+		}
+		return guestListAt[i].ID < guestListAt[j].ID
+	})
+	return guestListAt, nil
+}
+
+// CountAvailableSeats Returns the number of empty seats that can be given away at the tables of the given guests
+//
+// The tables of guests who did not arrive stay reserved for them, unless they were marked as no-shows,
+// and the tables of guests whose category reserves them are never given away
+func CountAvailableSeats(guestList []database.GuestList, categories CategoryConfig) int {
+	emptySeats := EmptySeatsByTable(guestList)
+	numberOfAvailableSeats := 0
+	for _, guest := range guestList {
+		if TableAvailable(guest) && !categories.TableReserved(guest) {
+			numberOfAvailableSeats += emptySeats[guest.ID]
+		}
+	}
+	return numberOfAvailableSeats
+}
+
+// CountEmptySeats Returns the number of empty seats at the tables of the given guests
+func CountEmptySeats(guestList []database.GuestList) int {
+	numberOfEmptySeats := 0
+	for _, emptySeats := range EmptySeatsByTable(guestList) {
+		numberOfEmptySeats += emptySeats
+	}
+	return numberOfEmptySeats
+}
+
+// EmptySeatsByTable Returns the number of empty seats at the table of each of the given guests, by guest ID
+//
+// Walk-ins have no table of their own, the seats they take are taken at the table they sit at
+func EmptySeatsByTable(guestList []database.GuestList) map[string]int {
+	emptySeats := make(map[string]int, len(guestList))
+	for _, guest := range guestList {
+		if !guest.WalkIn {
+			emptySeats[guest.ID] = guest.Table - PartySeats(guest)
+		}
+	}
+	for _, guest := range guestList {
+		if _, tableListed := emptySeats[guest.SeatedWith]; guest.WalkIn && guest.TimeArrived != "" && tableListed {
+			emptySeats[guest.SeatedWith] -= PartySeats(guest)
+		}
+	}
+	return emptySeats
+}
+
+// TableAvailable Checks if the empty seats at a guest's table can be given away,
+// that is if the guest arrived or was marked as a no-show
+func TableAvailable(guest database.GuestList) bool {
+	return !guest.WalkIn && (guest.TimeArrived != "" || guest.NoShowAt != nil)
+}
+
+// PartySeats Returns the number of seats a party takes at the table it sits at, none until it arrives
+//
+// A guest's table seats their accompanying guests. Walk-ins have no table of their own: at the table they sit at, they
+// take a seat for themselves and one for each of their accompanying guests.
+func PartySeats(guest database.GuestList) int {
+	if guest.TimeArrived == "" {
+		return 0
+	}
+	if guest.WalkIn {
+		return 1 + guest.AccompanyingGuests
+	}
+	return guest.AccompanyingGuests
+}
+
+// SeatsTaken Returns the number of seats taken at a guest's table, by their party and by the walk-ins seated with them
+func SeatsTaken(guest database.GuestList, walkIns []database.GuestList) int {
+	if guest.WalkIn {
+		// Walk-ins have no table of their own
+		return 0
+	}
+	seatsTaken := PartySeats(guest)
+	for _, walkIn := range walkIns {
+		seatsTaken += PartySeats(walkIn)
+	}
+	return seatsTaken
+}
@@ -0,0 +1,234 @@
+package guestService
+
+import (
+	"context"
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/database"
+	"sort"
+	"time"
+)
+
+// Names of the settings changed while the server runs, also the targets of the audit records of their changes
+const (
+	SettingVenueCapacity  = "venue_capacity"
+	SettingWalkInCap      = "walk_in_cap"
+	SettingNoShowCutoff   = "no_show_cutoff"
+	SettingCategoryRules  = "category_rules"
+	SettingAdmissionRules = "admission_rules"
+)
+
+// Settings Settings of the party that can be changed while the server runs
+type Settings struct {
+	VenueCapacity  int                       `json:"venue_capacity"`  // largest number of people in the venue, no limit when zero
+	WalkInCap      int                       `json:"walk_in_cap"`     // largest number of people admitted as walk-ins, NoWalkInCap for no cap
+	NoShowCutoff   *time.Time                `json:"no_show_cutoff"`  // time after which guests who did not arrive are no-shows, nil if none
+	CategoryRules  CategoryConfig            `json:"category_rules"`  // rules of each category of guests
+	AdmissionRules []api.AdmissionRuleConfig `json:"admission_rules"` // rules deciding who is admitted, in order
+}
+
+// Settings Returns the settings in effect
+func (service *Service) Settings() Settings {
+	settings := Settings{
+		VenueCapacity:  service.Venue().Capacity,
+		WalkInCap:      service.WalkInCap(),
+		CategoryRules:  service.Categories(),
+		AdmissionRules: service.AdmissionPolicy().Configs(),
+	}
+	if cutoff := service.NoShowCutoff(); !cutoff.IsZero() {
+		settings.NoShowCutoff = &cutoff
+	}
+	return settings
+}
+
+// ApplySettings Puts settings in effect without recording them, like the configuration read at startup
+//
+// An error is reported if the admission rules are invalid, nothing being changed then
+func (service *Service) ApplySettings(settings Settings) error {
+	policy, policyError := NewAdmissionPolicy(service.AdmissionPolicy().Event, settings.AdmissionRules)
+	if policyError != nil {
+		return policyError
+	}
+
+	venue := service.Venue()
+	venue.Capacity = settings.VenueCapacity
+	service.SetVenue(venue)
+	service.SetWalkInCap(settings.WalkInCap)
+	cutoff := time.Time{}
+	if settings.NoShowCutoff != nil {
+		cutoff = *settings.NoShowCutoff
+	}
+	service.SetNoShowCutoff(cutoff)
+	service.SetCategories(settings.CategoryRules)
+	service.SetAdmissionPolicy(policy)
+	return nil
+}
+
+// LoadSettings Puts in effect the settings changed while the server ran before, on top of the configuration
+func (service *Service) LoadSettings() error {
+	stored, queryError := service.store.Settings()
+	if queryError != nil {
+		return queryError
+	}
+
+	settings := service.Settings()
+	values := settings.values()
+	for _, setting := range stored {
+		value, known := values[setting.Name]
+		if !known {
+			service.logger.Println("Unknown setting " + setting.Name + " ignored")
+			continue
+		}
+		if decodeError := json.Unmarshal([]byte(setting.Value), value); decodeError != nil {
+			return decodeError
+		}
+	}
+	return service.ApplySettings(settings)
+}
+
+// SettingRecords Returns the settings in effect encoded as they are stored, ordered by name
+//
+// Settings never changed while the server ran, whose values come from the configuration, have a zero change time
+func (service *Service) SettingRecords() ([]database.Setting, error) {
+	stored, queryError := service.store.Settings()
+	if queryError != nil {
+		return nil, queryError
+	}
+	storedByName := make(map[string]database.Setting, len(stored))
+	for _, setting := range stored {
+		storedByName[setting.Name] = setting
+	}
+
+	settings := service.Settings()
+	values := settings.values()
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	records := make([]database.Setting, 0, len(names))
+	for _, name := range names {
+		value, encodeError := json.Marshal(values[name])
+		if encodeError != nil {
+			return nil, encodeError
+		}
+		record := database.Setting{Name: name, Value: string(value)}
+		if storedSetting, found := storedByName[name]; found && storedSetting.Value == record.Value {
+			record.ChangedAt = storedSetting.ChangedAt
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// ChangeVenueCapacity Sets and records the largest number of people in the venue at once, no limit when zero or negative
+//
+// People already in the venue stay even if they are more than the new capacity
+func (service *Service) ChangeVenueCapacity(ctx context.Context, capacity int) error {
+	service.settingsMutex.Lock()
+	defer service.settingsMutex.Unlock()
+
+	venue := service.Venue()
+	previousVenue := venue
+	venue.Capacity = capacity
+	service.SetVenue(venue)
+	if saveError := service.saveSetting(ctx, SettingVenueCapacity, previousVenue.Capacity, service.Venue().Capacity); saveError != nil {
+		service.SetVenue(previousVenue)
+		return saveError
+	}
+	return nil
+}
+
+// ChangeWalkInCap Sets and records the largest number of people admitted as walk-ins, a negative cap removing it
+//
+// Walk-ins already admitted stay even if they are more than the new cap
+func (service *Service) ChangeWalkInCap(ctx context.Context, cap int) error {
+	service.settingsMutex.Lock()
+	defer service.settingsMutex.Unlock()
+
+	previousCap := service.WalkInCap()
+	service.SetWalkInCap(cap)
+	if saveError := service.saveSetting(ctx, SettingWalkInCap, previousCap, service.WalkInCap()); saveError != nil {
+		service.SetWalkInCap(previousCap)
+		return saveError
+	}
+	return nil
+}
+
+// ChangeNoShowCutoff Sets and records the time after which guests who did not arrive are marked as no-shows,
+// zero to disable detection
+func (service *Service) ChangeNoShowCutoff(ctx context.Context, cutoff time.Time) error {
+	service.settingsMutex.Lock()
+	defer service.settingsMutex.Unlock()
+
+	previousSettings := service.Settings()
+	service.SetNoShowCutoff(cutoff)
+	if saveError := service.saveSetting(ctx, SettingNoShowCutoff, previousSettings.NoShowCutoff, service.Settings().NoShowCutoff); saveError != nil {
+		service.SetNoShowCutoff(time.Time{})
+		if previousSettings.NoShowCutoff != nil {
+			service.SetNoShowCutoff(*previousSettings.NoShowCutoff)
+		}
+		return saveError
+	}
+	return nil
+}
+
+// ChangeCategoryRules Sets and records the rules of a category of guests, a negative entourage limit removing it
+//
+// Guests already admitted stay even if they break the new rules
+func (service *Service) ChangeCategoryRules(ctx context.Context, category string, rules CategoryRules) error {
+	service.settingsMutex.Lock()
+	defer service.settingsMutex.Unlock()
+
+	previousCategories := service.Categories()
+	if setError := service.SetCategoryRules(category, rules); setError != nil {
+		return setError
+	}
+	if saveError := service.saveSetting(ctx, SettingCategoryRules, previousCategories, service.Categories()); saveError != nil {
+		service.SetCategories(previousCategories)
+		return saveError
+	}
+	return nil
+}
+
+// ChangeAdmissionPolicy Sets and records the rules deciding who is admitted
+//
+// Guests already admitted stay even if they break the new rules
+func (service *Service) ChangeAdmissionPolicy(ctx context.Context, policy AdmissionPolicy) error {
+	service.settingsMutex.Lock()
+	defer service.settingsMutex.Unlock()
+
+	previousPolicy := service.AdmissionPolicy()
+	service.SetAdmissionPolicy(policy)
+	if saveError := service.saveSetting(ctx, SettingAdmissionRules, previousPolicy.Configs(), policy.Configs()); saveError != nil {
+		service.SetAdmissionPolicy(previousPolicy)
+		return saveError
+	}
+	return nil
+}
+
+// values Returns the fields of the settings by setting name
+func (settings *Settings) values() map[string]interface{} {
+	return map[string]interface{}{
+		SettingVenueCapacity:  &settings.VenueCapacity,
+		SettingWalkInCap:      &settings.WalkInCap,
+		SettingNoShowCutoff:   &settings.NoShowCutoff,
+		SettingCategoryRules:  &settings.CategoryRules,
+		SettingAdmissionRules: &settings.AdmissionRules,
+	}
+}
+
+// saveSetting Stores the new value of a setting so that it outlives a restart, and records its change in the audit log
+func (service *Service) saveSetting(ctx context.Context, name string, before interface{}, after interface{}) error {
+	value, encodeError := json.Marshal(after)
+	if encodeError != nil {
+		return encodeError
+	}
+	if storeError := service.store.SaveSetting(&database.Setting{Name: name, Value: string(value), ChangedAt: service.now()}); storeError != nil {
+		return storeError
+	}
+	service.record(ctx, audit.OperationChangeSetting, name, before, after)
+	return nil
+}
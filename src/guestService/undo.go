@@ -0,0 +1,345 @@
+package guestService
+
+import (
+	"context"
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/database"
+	"strconv"
+)
+
+// maxUndoConflicts Largest number of later operations reported when an undo conflicts with them
+const maxUndoConflicts = 10
+
+// undoOperations Operations that can be undone, with the operation recording their undoing
+var undoOperations = map[string]string{
+	audit.OperationAddGuest:      audit.OperationUndoAddGuest,
+	audit.OperationCheckInGuest:  audit.OperationUndoCheckInGuest,
+	audit.OperationCheckOutGuest: audit.OperationUndoCheckOutGuest,
+	audit.OperationDeleteGuest:   audit.OperationUndoDeleteGuest,
+	audit.OperationAssignRoom:    audit.OperationUndoAssignRoom,
+	audit.OperationEnterRoom:     audit.OperationUndoEnterRoom,
+	audit.OperationLeaveRoom:     audit.OperationUndoLeaveRoom,
+	audit.OperationSetCategory:   audit.OperationUndoSetCategory,
+}
+
+// guestOperations Audited operations on guests
+var guestOperations = []string{
+	audit.OperationAddGuest,
+	audit.OperationCheckInGuest,
+	audit.OperationCheckOutGuest,
+	audit.OperationDeleteGuest,
+	audit.OperationAssignRoom,
+	audit.OperationEnterRoom,
+	audit.OperationLeaveRoom,
+	audit.OperationSetCategory,
+	audit.OperationUndoAddGuest,
+	audit.OperationUndoCheckInGuest,
+	audit.OperationUndoCheckOutGuest,
+	audit.OperationUndoDeleteGuest,
+	audit.OperationUndoAssignRoom,
+	audit.OperationUndoEnterRoom,
+	audit.OperationUndoLeaveRoom,
+	audit.OperationUndoSetCategory,
+}
+
+// Operation Audited operation on a guest
+type Operation struct {
+	Record   database.AuditRecord
+	Guest    database.GuestList // guest as left by the operation, or as it was when the operation removed it
+	Undoable bool               // no later operation changed the guest
+}
+
+// newOperation Returns the operation on a guest recorded in the audit log
+func newOperation(record database.AuditRecord) Operation {
+	operation := Operation{Record: record}
+	if record.After != "null" {
+		json.Unmarshal([]byte(record.After), &operation.Guest)
+	} else {
+		json.Unmarshal([]byte(record.Before), &operation.Guest)
+	}
+	return operation
+}
+
+// RecentOperations Returns at most limit of the latest operations on guests, newest first
+//
+// An operation listed as undoable can still conflict with changes made outside the audit log when undone
+func (service *Service) RecentOperations(limit int) ([]Operation, error) {
+	records, queryError := service.store.AuditRecords(database.AuditFilter{Operations: guestOperations}, limit)
+	if queryError != nil {
+		return nil, queryError
+	}
+
+	// Records are newest first, so a guest seen before was changed by a later operation
+	changedLater := map[string]bool{}
+	operations := make([]Operation, 0, len(records))
+	for _, record := range records {
+		operation := newOperation(record)
+		_, supported := undoOperations[record.Operation]
+		operation.Undoable = supported && !changedLater[record.TargetID] && !followedTable(record.Operation, operation.Guest)
+		changedLater[record.TargetID] = true
+		operations = append(operations, operation)
+	}
+	return operations, nil
+}
+
+// Undo Reverts an operation on a guest and returns the operation recording its undoing
+//
+// Adding, checking in, checking out and removing a guest, moving their table to another room, moving them between rooms
+// and changing their category can be undone, as long as the guest is still as the operation left it and, for additions
+// and check-ins, no walk-in sits at their table. Otherwise an error listing the later operations on the guest, or the
+// admissions of the walk-ins, is reported. A guest put back on the guest list, at the party or in a room by the undoing
+// must also be admitted by the admission policy, and by the rules of their former category when it is put back.
+func (service *Service) Undo(ctx context.Context, operationID int64) (Operation, error) {
+	service.undoMutex.Lock()
+	defer service.undoMutex.Unlock()
+
+	record, queryError := service.store.AuditRecordBySequence(operationID)
+	if queryError == database.ErrAuditRecordNotFound {
+		return Operation{}, newError(api.ErrorCodeOperationNotFound, "Operation "+strconv.FormatInt(operationID, 10)+" is not in the audit log")
+	}
+	if queryError != nil {
+		return Operation{}, queryError
+	}
+
+	undoOperation, undoable := undoOperations[record.Operation]
+	if !undoable {
+		return Operation{}, newError(api.ErrorCodeNotUndoable, "Operations of type "+record.Operation+" cannot be undone")
+	}
+	if followedTable(record.Operation, newOperation(record).Guest) {
+		return Operation{}, newError(api.ErrorCodeNotUndoable, "Operation "+strconv.FormatInt(operationID, 10)+
+			" moved a walk-in along with the table they sit at: the move of the guest they came with has to be undone instead")
+	}
+
+	// Check that no later operation changed the guest
+	laterRecords, queryError := service.store.AuditRecords(database.AuditFilter{TargetID: record.TargetID, AfterSequence: record.Sequence}, maxUndoConflicts)
+	if queryError != nil {
+		return Operation{}, queryError
+	}
+	if len(laterRecords) > 0 {
+		conflicts := make([]Operation, 0, len(laterRecords))
+		for _, laterRecord := range laterRecords {
+			conflicts = append(conflicts, newOperation(laterRecord))
+		}
+		return Operation{}, &Error{
+			Code:      api.ErrorCodeUndoConflict,
+			Message:   "Operation " + strconv.FormatInt(operationID, 10) + " cannot be undone: the guest was changed by later operations",
+			Conflicts: conflicts,
+		}
+	}
+
+	// Check that the guest is still as the operation left it, it could have been changed outside the audit log
+	var before, after *database.GuestList
+	if decodeError := json.Unmarshal([]byte(record.Before), &before); decodeError != nil {
+		return Operation{}, decodeError
+	}
+	if decodeError := json.Unmarshal([]byte(record.After), &after); decodeError != nil {
+		return Operation{}, decodeError
+	}
+	var current *database.GuestList
+	guest, queryError := service.store.GuestByID(record.TargetID)
+	if queryError == nil {
+		current = &guest
+	} else if queryError != database.ErrGuestNotFound {
+		return Operation{}, queryError
+	}
+	if (current == nil) != (after == nil) || (current != nil && !current.SameAs(*after)) {
+		return Operation{}, &Error{
+			Code:      api.ErrorCodeUndoConflict,
+			Message:   "Operation " + strconv.FormatInt(operationID, 10) + " cannot be undone: the guest was changed outside the audit log",
+			Conflicts: []Operation{},
+		}
+	}
+
+	// Walk-ins seated at the table of the guest would be left without the guest who gave them their seats
+	if record.Operation == audit.OperationAddGuest || record.Operation == audit.OperationCheckInGuest {
+		conflicts, queryError := service.seatedWalkInOperations(record.TargetID)
+		if queryError != nil {
+			return Operation{}, queryError
+		}
+		if len(conflicts) > 0 {
+			return Operation{}, &Error{
+				Code:      api.ErrorCodeUndoConflict,
+				Message:   "Operation " + strconv.FormatInt(operationID, 10) + " cannot be undone: walk-ins are seated at the table of the guest",
+				Conflicts: conflicts,
+			}
+		}
+	}
+
+	// A guest put back at the party is admitted like any guest coming in, their age having been checked when they first arrived
+	var headcount Headcount
+	switch record.Operation {
+	case audit.OperationCheckOutGuest:
+		service.venueMutex.Lock()
+		defer service.venueMutex.Unlock()
+		if headcount, queryError = service.lockedHeadcount(); queryError != nil {
+			return Operation{}, queryError
+		}
+		if _, refusal := service.admit(AdmissionRequest{Stage: AdmissionStageReadmission, Guest: *before, AccompanyingGuests: before.AccompanyingGuests, Headcount: headcount}); refusal != nil {
+			return Operation{}, refusal
+		}
+	case audit.OperationDeleteGuest:
+		if _, refusal := service.admit(AdmissionRequest{Stage: AdmissionStageAddition, Guest: *before, AccompanyingGuests: before.AccompanyingGuests}); refusal != nil {
+			return Operation{}, refusal
+		}
+	case audit.OperationEnterRoom, audit.OperationLeaveRoom:
+		if before.InRoom != "" {
+			service.roomMutex.Lock()
+			defer service.roomMutex.Unlock()
+			if refusal := service.readmitToRoom(operationID, *before); refusal != nil {
+				return Operation{}, refusal
+			}
+		}
+	case audit.OperationSetCategory:
+		if refusal := service.checkEntourage(*before, before.AccompanyingGuests); refusal != nil {
+			return Operation{}, refusal
+		}
+	}
+
+	// The walk-ins at the table of a guest follow it back to the room it was in
+	var walkIns []database.GuestList
+	if record.Operation == audit.OperationAssignRoom {
+		if walkIns, queryError = service.movedBackWalkIns(operationID, *before, *current); queryError != nil {
+			return Operation{}, queryError
+		}
+	}
+
+	// Restore the guest as it was before the operation
+	var storeError error
+	switch record.Operation {
+	case audit.OperationAddGuest:
+		storeError = service.store.DeleteGuest(*current)
+	case audit.OperationCheckInGuest:
+		storeError = service.store.UndoCheckIn(before)
+	case audit.OperationCheckOutGuest:
+		// The guest is put back at the party, not in the room they were in when they left
+		before.InRoom = ""
+		storeError = service.store.UndoCheckOut(before)
+	case audit.OperationDeleteGuest:
+		storeError = service.store.RestoreGuest(before)
+	case audit.OperationAssignRoom:
+		storeError = service.store.AssignRoom(before.ID, before.RoomID)
+	case audit.OperationEnterRoom, audit.OperationLeaveRoom:
+		storeError = service.store.SetInRoom(before.ID, before.InRoom)
+	case audit.OperationSetCategory:
+		storeError = service.store.SetCategory(before.ID, before.Category)
+	}
+	if storeError != nil {
+		return Operation{}, storeError
+	}
+
+	undoRecord := service.record(ctx, undoOperation, record.TargetID, current, before)
+	switch record.Operation {
+	case audit.OperationCheckInGuest:
+		service.publish(AttendanceLeft, *current)
+		service.emit(api.EventGuestLeft, *current)
+	case audit.OperationCheckOutGuest:
+		service.publish(AttendanceArrived, *before)
+		service.emit(api.EventGuestArrived, *before)
+		service.emitOccupancy(headcount, *before)
+	case audit.OperationAssignRoom:
+		for _, walkIn := range walkIns {
+			previousWalkIn := walkIn
+			walkIn.RoomID = before.RoomID
+			service.record(ctx, undoOperation, walkIn.ID, previousWalkIn, walkIn)
+		}
+	}
+	return newOperation(undoRecord), nil
+}
+
+// followedTable Checks if an operation moved a walk-in along with the table of the guest they came with,
+// which is undone with the move of that guest
+func followedTable(operation string, guest database.GuestList) bool {
+	return operation == audit.OperationAssignRoom && guest.WalkIn
+}
+
+// movedBackWalkIns Returns the walk-ins seated at the table of a guest whose move to another room is undone
+//
+// The room the table goes back to must still exist, and the walk-ins must still be in the room the move left them in
+func (service *Service) movedBackWalkIns(operationID int64, before database.GuestList, current database.GuestList) ([]database.GuestList, error) {
+	if before.RoomID != "" {
+		if _, queryError := service.store.RoomByID(before.RoomID); queryError == database.ErrRoomNotFound {
+			return nil, &Error{
+				Code:      api.ErrorCodeUndoConflict,
+				Message:   "Operation " + strconv.FormatInt(operationID, 10) + " cannot be undone: the room the table was in was removed",
+				Conflicts: []Operation{},
+			}
+		} else if queryError != nil {
+			return nil, queryError
+		}
+	}
+
+	walkIns, queryError := service.store.WalkInsSeatedWith(current.ID)
+	if queryError != nil {
+		return nil, queryError
+	}
+	for _, walkIn := range walkIns {
+		if walkIn.RoomID != current.RoomID {
+			return nil, &Error{
+				Code:      api.ErrorCodeUndoConflict,
+				Message:   "Operation " + strconv.FormatInt(operationID, 10) + " cannot be undone: walk-in " + walkIn.Name + " is no longer in the room of the table",
+				Conflicts: []Operation{},
+			}
+		}
+	}
+	return walkIns, nil
+}
+
+// readmitToRoom Returns the refusal of a guest going back to the room they were in before an operation, nil if they may
+//
+// The room must still exist, let the guest in and have room for them with their accompanying guests
+func (service *Service) readmitToRoom(operationID int64, guest database.GuestList) error {
+	room, queryError := service.store.RoomByID(guest.InRoom)
+	if queryError == database.ErrRoomNotFound {
+		return &Error{
+			Code:      api.ErrorCodeUndoConflict,
+			Message:   "Operation " + strconv.FormatInt(operationID, 10) + " cannot be undone: the room the guest was in was removed",
+			Conflicts: []Operation{},
+		}
+	}
+	if queryError != nil {
+		return queryError
+	}
+
+	canEnter, queryError := service.CanEnterRoom(room, guest)
+	if queryError != nil {
+		return queryError
+	}
+	if !canEnter {
+		return newError(api.ErrorCodeRoomAccessDenied, "Guest "+guest.Name+" no longer has access to room "+room.Name)
+	}
+
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return queryError
+	}
+	if _, refusal := service.admit(AdmissionRequest{Stage: AdmissionStageRoomEntry, Guest: guest, AccompanyingGuests: guest.AccompanyingGuests,
+		Room: room, PeopleInRoom: CountPeopleInRoom(guestList, room.ID)}); refusal != nil {
+		return refusal
+	}
+	return nil
+}
+
+// seatedWalkInOperations Returns the admissions of the walk-ins seated at the table of a guest
+func (service *Service) seatedWalkInOperations(guestID string) ([]Operation, error) {
+	walkIns, queryError := service.store.WalkInsSeatedWith(guestID)
+	if queryError != nil {
+		return nil, queryError
+	}
+	operations := []Operation{}
+	for _, walkIn := range walkIns {
+		records, queryError := service.store.AuditRecords(database.AuditFilter{TargetID: walkIn.ID, Operation: audit.OperationAdmitWalkIn}, 1)
+		if queryError != nil {
+			return nil, queryError
+		}
+		for _, record := range records {
+			operations = append(operations, newOperation(record))
+		}
+		if len(records) == 0 {
+			// Admitted before the audit log was kept
+			operations = append(operations, Operation{Guest: walkIn})
+		}
+	}
+	return operations, nil
+}
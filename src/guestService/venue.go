@@ -0,0 +1,157 @@
+package guestService
+
+import (
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/database"
+	"strconv"
+)
+
+// VenueConfig Venue-wide occupancy limit, whatever the seats at the tables
+type VenueConfig struct {
+
+	// Capacity Largest number of people in the venue at once, guests and accompanying guests, no limit when zero
+	Capacity int
+
+	// WarningThreshold Share of the capacity at which the occupancy_warning event is emitted
+	WarningThreshold float64
+
+	// CriticalThreshold Share of the capacity at which the occupancy_critical event is emitted
+	CriticalThreshold float64
+}
+
+// DefaultVenueConfig Returns the venue configuration used by the docker setup
+//
+// No capacity is set, the venue holds as many people as there are seats until one is
+func DefaultVenueConfig() VenueConfig {
+	return VenueConfig{WarningThreshold: 0.8, CriticalThreshold: 0.95}
+}
+
+// Occupancy levels of the venue
+const (
+	OccupancyNormal   = "normal"
+	OccupancyWarning  = "warning"
+	OccupancyCritical = "critical"
+)
+
+// Headcount People in the venue
+type Headcount struct {
+	People int         // guests at the party and their accompanying guests
+	Venue  VenueConfig // limit the people are counted against
+}
+
+// Remaining Returns the number of people that can still come in, -1 if the venue has no capacity
+func (headcount Headcount) Remaining() int {
+	if headcount.Venue.Capacity <= 0 {
+		return -1
+	}
+	if headcount.People >= headcount.Venue.Capacity {
+		return 0
+	}
+	return headcount.Venue.Capacity - headcount.People
+}
+
+// Share Returns the share of the capacity taken, 0 if the venue has no capacity
+func (headcount Headcount) Share() float64 {
+	if headcount.Venue.Capacity <= 0 {
+		return 0
+	}
+	return float64(headcount.People) / float64(headcount.Venue.Capacity)
+}
+
+// Level Returns the occupancy level reached, one of the Occupancy* levels
+func (headcount Headcount) Level() string {
+	switch {
+	case headcount.Venue.Capacity <= 0:
+		return OccupancyNormal
+	case headcount.Share() >= headcount.Venue.CriticalThreshold:
+		return OccupancyCritical
+	case headcount.Share() >= headcount.Venue.WarningThreshold:
+		return OccupancyWarning
+	default:
+		return OccupancyNormal
+	}
+}
+
+// CountPeople Returns the number of people at the party among the given guests, accompanying guests included
+func CountPeople(guestList []database.GuestList) int {
+	people := 0
+	for _, guest := range guestList {
+		if guest.TimeArrived != "" {
+			people += 1 + guest.AccompanyingGuests
+		}
+	}
+	return people
+}
+
+// Venue Returns the venue-wide occupancy limit
+func (service *Service) Venue() VenueConfig {
+	service.venueMutex.Lock()
+	defer service.venueMutex.Unlock()
+	return service.venue
+}
+
+// SetVenue Sets the venue-wide occupancy limit
+//
+// People already in the venue stay even if they are more than the new capacity
+func (service *Service) SetVenue(venue VenueConfig) {
+	if venue.Capacity < 0 {
+		venue.Capacity = 0
+	}
+	service.venueMutex.Lock()
+	defer service.venueMutex.Unlock()
+	service.venue = venue
+}
+
+// Headcount Returns the number of people in the venue and the limit they are counted against
+func (service *Service) Headcount() (Headcount, error) {
+	venue := service.Venue()
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return Headcount{}, queryError
+	}
+	return Headcount{People: CountPeople(guestList), Venue: venue}, nil
+}
+
+// lockedHeadcount Returns the people in the venue and the limit they are counted against,
+// the people being only counted when the venue has a capacity
+//
+// Must be called with venueMutex locked
+func (service *Service) lockedHeadcount() (Headcount, error) {
+	headcount := Headcount{Venue: service.venue}
+	if headcount.Venue.Capacity <= 0 {
+		return headcount, nil
+	}
+
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return headcount, queryError
+	}
+	headcount.People = CountPeople(guestList)
+	return headcount, nil
+}
+
+// capacityRefusal Returns the refusal of a guest coming in with their accompanying guests if the venue would hold
+// more than its capacity, nil if it would not
+func capacityRefusal(headcount Headcount, guest database.GuestList, accompanyingGuests int) *Error {
+	if headcount.Venue.Capacity > 0 && headcount.People+1+accompanyingGuests > headcount.Venue.Capacity {
+		return newError(api.ErrorCodeVenueFull, "The venue holds "+strconv.Itoa(headcount.People)+" people of its capacity of "+
+			strconv.Itoa(headcount.Venue.Capacity)+": "+guest.Name+" cannot come in with "+strconv.Itoa(accompanyingGuests)+" accompanying guests")
+	}
+	return nil
+}
+
+// emitOccupancy Emits the occupancy events of the thresholds reached by a guest coming in
+func (service *Service) emitOccupancy(before Headcount, guest database.GuestList) {
+	after := before
+	after.People += 1 + guest.AccompanyingGuests
+	if before.Level() == after.Level() || after.Level() == OccupancyNormal {
+		return
+	}
+
+	if before.Level() == OccupancyNormal {
+		service.emitHeadcount(api.EventOccupancyWarning, guest, after)
+	}
+	if after.Level() == OccupancyCritical {
+		service.emitHeadcount(api.EventOccupancyCritical, guest, after)
+	}
+}
@@ -0,0 +1,131 @@
+package guestService
+
+import (
+	"context"
+	"errors"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/database"
+	"sort"
+	"strconv"
+)
+
+// Waitlist Returns the parties on the waitlist in the order they are seated: by the waitlist priority of their category,
+// then first come first
+func (service *Service) Waitlist() ([]database.WaitlistEntry, error) {
+	entries, queryError := service.store.Waitlist()
+	if queryError != nil {
+		return nil, queryError
+	}
+
+	categories := service.Categories()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return categories.Rules(entries[i].Category).WaitlistPriority > categories.Rules(entries[j].Category).WaitlistPriority
+	})
+	return entries, nil
+}
+
+// JoinWaitlist Puts a party that cannot be seated yet on the waitlist, age being the age of the person joining if it was
+// checked at the door
+//
+// The party is seated as walk-ins of its category once no-shows release seats, see SeatWaitlist.
+// An error is reported if the name, the number of accompanying guests or the category is invalid.
+func (service *Service) JoinWaitlist(ctx context.Context, name string, accompanyingGuests int, category string, age *int) (database.WaitlistEntry, error) {
+	if category == "" {
+		category = CategoryGuest
+	}
+	entry := database.WaitlistEntry{Name: name, AccompanyingGuests: accompanyingGuests, Category: category, Age: age, JoinedAt: service.now()}
+	if name == "" || accompanyingGuests < 0 {
+		return entry, newError(api.ErrorCodeInvalidRequest, "A party on the waitlist needs a name and a non-negative number of accompanying guests")
+	}
+	if nameError := checkName(name); nameError != nil {
+		return entry, nameError
+	}
+	if categoryError := checkCategory(category); categoryError != nil {
+		return entry, categoryError
+	}
+
+	service.waitlistMutex.Lock()
+	defer service.waitlistMutex.Unlock()
+
+	if storeError := service.store.AddWaitlistEntry(&entry); storeError != nil {
+		return entry, storeError
+	}
+	service.record(ctx, audit.OperationJoinWaitlist, entry.ID, nil, entry)
+	return entry, nil
+}
+
+// LeaveWaitlist Removes a party from the waitlist
+//
+// An error is reported if the party is not on the waitlist, seated parties leaving it. Returns the party as it was.
+func (service *Service) LeaveWaitlist(ctx context.Context, entryID string) (database.WaitlistEntry, error) {
+	service.waitlistMutex.Lock()
+	defer service.waitlistMutex.Unlock()
+
+	entry, queryError := service.store.WaitlistEntryByID(entryID)
+	if queryError == database.ErrWaitlistEntryNotFound {
+		return entry, newError(api.ErrorCodeWaitlistEntryNotFound, "Party "+entryID+" is not on the waitlist")
+	}
+	if queryError != nil {
+		return entry, queryError
+	}
+	if storeError := service.store.DeleteWaitlistEntry(entry.ID); storeError != nil {
+		return entry, storeError
+	}
+	service.record(ctx, audit.OperationLeaveWaitlist, entry.ID, entry, nil)
+	return entry, nil
+}
+
+// SeatWaitlist Admits the parties on the waitlist as walk-ins of their category at the free tables, in the order of Waitlist
+//
+// A party that cannot be seated, because no table has enough empty seats, the walk-in cap is reached or the admission
+// policy refuses it, keeps its place and the next parties are tried. Each party is taken off the waitlist before it is
+// admitted and put back if it is not. Returns the walk-ins seated.
+func (service *Service) SeatWaitlist(ctx context.Context) ([]database.GuestList, error) {
+	service.waitlistMutex.Lock()
+	defer service.waitlistMutex.Unlock()
+
+	entries, queryError := service.Waitlist()
+	if queryError != nil {
+		return nil, queryError
+	}
+
+	seated := []database.GuestList{}
+	for _, entry := range entries {
+		// The party leaves the waitlist before it is admitted, so that it cannot be seated twice
+		claimed, storeError := service.store.ClaimWaitlistEntry(entry.ID)
+		if storeError != nil {
+			return seated, storeError
+		}
+		if !claimed {
+			continue
+		}
+
+		walkIn, _, admitError := service.admitWalkIn(ctx, entry.Name, entry.AccompanyingGuests, entry.Category, "", entry.Age)
+		if admitError != nil {
+			if storeError := service.store.RestoreWaitlistEntry(&entry); storeError != nil {
+				service.logger.Println("Party " + entry.ID + " lost its place on the waitlist: " + storeError.Error())
+			}
+			var refusal *Error
+			if errors.As(admitError, &refusal) {
+				continue
+			}
+			return seated, admitError
+		}
+		service.record(ctx, audit.OperationSeatFromWaitlist, entry.ID, entry, walkIn)
+		seated = append(seated, walkIn)
+	}
+	return seated, nil
+}
+
+// seatReleasedSeats Seats parties of the waitlist at the seats just released by no-shows
+//
+// Failures are only logged, the release itself having succeeded
+func (service *Service) seatReleasedSeats(ctx context.Context) {
+	seated, seatError := service.SeatWaitlist(ctx)
+	if seatError != nil {
+		service.logger.Println("Waitlist not seated: " + seatError.Error())
+	} else if len(seated) > 0 {
+		service.logger.Println(strconv.Itoa(len(seated)) + " parties seated from the waitlist")
+	}
+}
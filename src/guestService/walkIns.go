@@ -0,0 +1,206 @@
+package guestService
+
+import (
+	"context"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/utils"
+	"sort"
+	"strconv"
+)
+
+// NoWalkInCap Walk-in cap letting any number of people in as walk-ins, as long as there are free seats
+const NoWalkInCap = -1
+
+// WalkInConfig Walk-in admission configuration
+type WalkInConfig struct {
+
+	// Cap Largest number of people admitted as walk-ins over the party, their accompanying guests included,
+	// NoWalkInCap for no cap
+	Cap int
+}
+
+// DefaultWalkInConfig Returns the walk-in admission configuration used by the docker setup
+func DefaultWalkInConfig() WalkInConfig {
+	return WalkInConfig{Cap: NoWalkInCap}
+}
+
+// FreeTable Table with empty seats that can be given to walk-ins
+type FreeTable struct {
+	Guest database.GuestList // guest whose table it is
+	Seats int                // empty seats at the table
+}
+
+// WalkIns Walk-ins of the party
+type WalkIns struct {
+	Cap        int                  // largest number of people admitted as walk-ins, NoWalkInCap for no cap
+	Admitted   int                  // people admitted as walk-ins so far, those who left included
+	Guests     []database.GuestList // walk-ins at the party
+	FreeTables []FreeTable          // tables walk-ins can be seated at
+}
+
+// FreeTables Returns the tables of the given guests that walk-ins can be seated at, fullest first
+//
+// Only the tables of guests who arrived or were marked as no-shows are given away, unless their category reserves them
+func FreeTables(guestList []database.GuestList, categories CategoryConfig) []FreeTable {
+	emptySeats := EmptySeatsByTable(guestList)
+	freeTables := []FreeTable{}
+	for _, guest := range guestList {
+		if TableAvailable(guest) && !categories.TableReserved(guest) && emptySeats[guest.ID] > 0 {
+			freeTables = append(freeTables, FreeTable{Guest: guest, Seats: emptySeats[guest.ID]})
+		}
+	}
+	sort.SliceStable(freeTables, func(i, j int) bool {
+		return freeTables[i].Seats < freeTables[j].Seats
+	})
+	return freeTables
+}
+
+// WalkInCap Returns the largest number of people admitted as walk-ins, NoWalkInCap for no cap
+func (service *Service) WalkInCap() int {
+	service.walkInMutex.Lock()
+	defer service.walkInMutex.Unlock()
+	return service.walkInCap
+}
+
+// SetWalkInCap Sets the largest number of people admitted as walk-ins, a negative cap removing it
+//
+// Walk-ins already admitted stay even if they are more than the new cap
+func (service *Service) SetWalkInCap(cap int) {
+	if cap < 0 {
+		cap = NoWalkInCap
+	}
+	service.walkInMutex.Lock()
+	defer service.walkInMutex.Unlock()
+	service.walkInCap = cap
+}
+
+// WalkIns Returns the walk-ins at the party, the number of people admitted as walk-ins and the tables they can be seated at
+func (service *Service) WalkIns() (WalkIns, error) {
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return WalkIns{}, queryError
+	}
+	admitted, queryError := service.store.WalkInsAdmitted()
+	if queryError != nil {
+		return WalkIns{}, queryError
+	}
+
+	walkIns := WalkIns{Cap: service.WalkInCap(), Admitted: admitted, Guests: []database.GuestList{}, FreeTables: FreeTables(guestList, service.Categories())}
+	for _, guest := range guestList {
+		if guest.WalkIn {
+			walkIns.Guests = append(walkIns.Guests, guest)
+		}
+	}
+	return walkIns, nil
+}
+
+// AdmitWalkIn Admits a person who is not on the guest list with their accompanying guests, seating them at a table with
+// enough empty seats
+//
+// The table is the one of the guest whose ID is seatedWith, or when empty the free table that fits the party best.
+// An error is reported if no table can seat the party or if the walk-in cap would be exceeded, and the admission policy
+// decides if the walk-in comes in like any guest arriving, age being their age if it was checked at the door.
+// Returns the walk-in as added and the decision of the admission policy.
+func (service *Service) AdmitWalkIn(ctx context.Context, name string, accompanyingGuests int, seatedWith string, age *int) (database.GuestList, AdmissionDecision, error) {
+	return service.admitWalkIn(ctx, name, accompanyingGuests, CategoryGuest, seatedWith, age)
+}
+
+// admitWalkIn Does the work of AdmitWalkIn, the walk-in being in the given category
+func (service *Service) admitWalkIn(ctx context.Context, name string, accompanyingGuests int, category string, seatedWith string, age *int) (database.GuestList, AdmissionDecision, error) {
+	walkIn := database.GuestList{Name: name, AccompanyingGuests: accompanyingGuests, WalkIn: true, Category: category}
+	if name == "" || accompanyingGuests < 0 {
+		return walkIn, AdmissionDecision{}, newError(api.ErrorCodeInvalidRequest, "A walk-in needs a name and a non-negative number of accompanying guests")
+	}
+	if nameError := checkName(name); nameError != nil {
+		return walkIn, AdmissionDecision{}, nameError
+	}
+	seatsNeeded := 1 + accompanyingGuests
+
+	// Admissions are serialised so that two parties are never given the same seats
+	service.walkInMutex.Lock()
+	defer service.walkInMutex.Unlock()
+
+	admitted, queryError := service.store.WalkInsAdmitted()
+	if queryError != nil {
+		return walkIn, AdmissionDecision{}, queryError
+	}
+	if service.walkInCap != NoWalkInCap && admitted+seatsNeeded > service.walkInCap {
+		return walkIn, AdmissionDecision{}, newError(api.ErrorCodeWalkInCapReached, "Walk-ins are capped at "+strconv.Itoa(service.walkInCap)+
+			" people and "+strconv.Itoa(admitted)+" were admitted already: "+name+" cannot come in with "+strconv.Itoa(accompanyingGuests)+" accompanying guests")
+	}
+
+	service.venueMutex.Lock()
+	defer service.venueMutex.Unlock()
+	headcount, queryError := service.lockedHeadcount()
+	if queryError != nil {
+		return walkIn, AdmissionDecision{}, queryError
+	}
+	decision, refusal := service.admit(AdmissionRequest{Stage: AdmissionStageCheckIn, Guest: walkIn, AccompanyingGuests: accompanyingGuests, Age: age, Headcount: headcount})
+	if refusal != nil {
+		return walkIn, decision, refusal
+	}
+
+	guestList, queryError := service.store.Guests()
+	if queryError != nil {
+		return walkIn, decision, queryError
+	}
+	table, tableError := chooseTable(guestList, service.Categories(), seatsNeeded, seatedWith)
+	if tableError != nil {
+		return walkIn, decision, tableError
+	}
+
+	addedAt := service.now()
+	walkIn.AddedAt = &addedAt
+	walkIn.TimeArrived = utils.GetHoursAndMinutesString(service.clock)
+	walkIn.SeatedWith = table.Guest.ID
+	walkIn.RoomID = table.Guest.RoomID
+	if storeError := service.store.AdmitWalkIn(&walkIn, addedAt); storeError != nil {
+		return walkIn, decision, storeError
+	}
+
+	service.record(ctx, audit.OperationAdmitWalkIn, walkIn.ID, nil, walkIn)
+	service.publish(AttendanceArrived, walkIn)
+	service.emit(api.EventGuestArrived, walkIn)
+	if table.Seats <= seatsNeeded {
+		// The walk-ins took the last seats at the table
+		service.emit(api.EventTableFull, table.Guest)
+	}
+	service.emitOccupancy(headcount, walkIn)
+	return walkIn, decision, nil
+}
+
+// chooseTable Returns the table a party of walk-ins is seated at: the one of the guest whose ID is seatedWith,
+// or when empty the free table with the fewest empty seats that can seat the party
+func chooseTable(guestList []database.GuestList, categories CategoryConfig, seatsNeeded int, seatedWith string) (FreeTable, error) {
+	if seatedWith == "" {
+		for _, table := range FreeTables(guestList, categories) {
+			if table.Seats >= seatsNeeded {
+				return table, nil
+			}
+		}
+		return FreeTable{}, newError(api.ErrorCodeNoFreeTable, "No table has "+strconv.Itoa(seatsNeeded)+" empty seats")
+	}
+
+	for _, guest := range guestList {
+		if guest.ID != seatedWith {
+			continue
+		}
+		if guest.WalkIn {
+			return FreeTable{}, newError(api.ErrorCodeNoFreeTable, guest.Name+" is a walk-in and has no table of their own")
+		}
+		if !TableAvailable(guest) {
+			return FreeTable{}, newError(api.ErrorCodeNoFreeTable, "The table of "+guest.Name+" is reserved until they arrive or are marked as a no-show")
+		}
+		if categories.TableReserved(guest) {
+			return FreeTable{}, newError(api.ErrorCodeNoFreeTable, "The table of "+guest.Name+" is reserved for guests in category "+guest.Category)
+		}
+		emptySeats := EmptySeatsByTable(guestList)[guest.ID]
+		if emptySeats < seatsNeeded {
+			return FreeTable{}, newError(api.ErrorCodeNoFreeTable, "The table of "+guest.Name+" only has "+strconv.Itoa(emptySeats)+" empty seats")
+		}
+		return FreeTable{Guest: guest, Seats: emptySeats}, nil
+	}
+	return FreeTable{}, newError(api.ErrorCodeGuestNotFound, "Guest with ID "+seatedWith+" is not in the guest list")
+}
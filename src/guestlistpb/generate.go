@@ -0,0 +1,4 @@
+// Package guestlistpb Protocol buffer messages and gRPC service of the guest list, generated from guestlist.proto
+package guestlistpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative guestlist.proto
@@ -0,0 +1,1201 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
+// source: guestlist.proto
+
+package guestlistpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AttendanceUpdate_Kind int32
+
+const (
+	AttendanceUpdate_KIND_UNSPECIFIED AttendanceUpdate_Kind = 0
+	AttendanceUpdate_KIND_ARRIVED     AttendanceUpdate_Kind = 1
+	AttendanceUpdate_KIND_LEFT        AttendanceUpdate_Kind = 2
+)
+
+// Enum value maps for AttendanceUpdate_Kind.
+var (
+	AttendanceUpdate_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "KIND_ARRIVED",
+		2: "KIND_LEFT",
+	}
+	AttendanceUpdate_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED": 0,
+		"KIND_ARRIVED":     1,
+		"KIND_LEFT":        2,
+	}
+)
+
+func (x AttendanceUpdate_Kind) Enum() *AttendanceUpdate_Kind {
+	p := new(AttendanceUpdate_Kind)
+	*p = x
+	return p
+}
+
+func (x AttendanceUpdate_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AttendanceUpdate_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_guestlist_proto_enumTypes[0].Descriptor()
+}
+
+func (AttendanceUpdate_Kind) Type() protoreflect.EnumType {
+	return &file_guestlist_proto_enumTypes[0]
+}
+
+func (x AttendanceUpdate_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AttendanceUpdate_Kind.Descriptor instead.
+func (AttendanceUpdate_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{12, 0}
+}
+
+// Guest Guest of the guest list
+type Guest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id                 string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name               string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Table              int32  `protobuf:"varint,3,opt,name=table,proto3" json:"table,omitempty"`
+	AccompanyingGuests int32  `protobuf:"varint,4,opt,name=accompanying_guests,json=accompanyingGuests,proto3" json:"accompanying_guests,omitempty"`
+	// time_arrived Hours and minutes of arrival, empty if the guest has not arrived
+	TimeArrived string `protobuf:"bytes,5,opt,name=time_arrived,json=timeArrived,proto3" json:"time_arrived,omitempty"`
+	// admission Decision of the admission policy, only set in the replies adding or checking in the guest
+	Admission *AdmissionDecision `protobuf:"bytes,6,opt,name=admission,proto3" json:"admission,omitempty"`
+}
+
+func (x *Guest) Reset() {
+	*x = Guest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Guest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Guest) ProtoMessage() {}
+
+func (x *Guest) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Guest.ProtoReflect.Descriptor instead.
+func (*Guest) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Guest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Guest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Guest) GetTable() int32 {
+	if x != nil {
+		return x.Table
+	}
+	return 0
+}
+
+func (x *Guest) GetAccompanyingGuests() int32 {
+	if x != nil {
+		return x.AccompanyingGuests
+	}
+	return 0
+}
+
+func (x *Guest) GetTimeArrived() string {
+	if x != nil {
+		return x.TimeArrived
+	}
+	return ""
+}
+
+func (x *Guest) GetAdmission() *AdmissionDecision {
+	if x != nil {
+		return x.Admission
+	}
+	return nil
+}
+
+// AdmissionDecision Admission rule that let a guest in, for display at the door
+type AdmissionDecision struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// rule Name of the rule, "policy" when every rule of the admission policy let the guest in
+	Rule   string `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *AdmissionDecision) Reset() {
+	*x = AdmissionDecision{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdmissionDecision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdmissionDecision) ProtoMessage() {}
+
+func (x *AdmissionDecision) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdmissionDecision.ProtoReflect.Descriptor instead.
+func (*AdmissionDecision) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AdmissionDecision) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *AdmissionDecision) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type AddGuestRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name               string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Table              int32  `protobuf:"varint,2,opt,name=table,proto3" json:"table,omitempty"`
+	AccompanyingGuests int32  `protobuf:"varint,3,opt,name=accompanying_guests,json=accompanyingGuests,proto3" json:"accompanying_guests,omitempty"`
+}
+
+func (x *AddGuestRequest) Reset() {
+	*x = AddGuestRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddGuestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddGuestRequest) ProtoMessage() {}
+
+func (x *AddGuestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddGuestRequest.ProtoReflect.Descriptor instead.
+func (*AddGuestRequest) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AddGuestRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AddGuestRequest) GetTable() int32 {
+	if x != nil {
+		return x.Table
+	}
+	return 0
+}
+
+func (x *AddGuestRequest) GetAccompanyingGuests() int32 {
+	if x != nil {
+		return x.AccompanyingGuests
+	}
+	return 0
+}
+
+type ListGuestsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListGuestsRequest) Reset() {
+	*x = ListGuestsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListGuestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGuestsRequest) ProtoMessage() {}
+
+func (x *ListGuestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGuestsRequest.ProtoReflect.Descriptor instead.
+func (*ListGuestsRequest) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{3}
+}
+
+type ListGuestsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Guests []*Guest `protobuf:"bytes,1,rep,name=guests,proto3" json:"guests,omitempty"`
+}
+
+func (x *ListGuestsResponse) Reset() {
+	*x = ListGuestsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListGuestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGuestsResponse) ProtoMessage() {}
+
+func (x *ListGuestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGuestsResponse.ProtoReflect.Descriptor instead.
+func (*ListGuestsResponse) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListGuestsResponse) GetGuests() []*Guest {
+	if x != nil {
+		return x.Guests
+	}
+	return nil
+}
+
+// CheckInGuestRequest Chooses the arriving guest by ID or by name, the ID is needed when several guests share the name
+type CheckInGuestRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Guest:
+	//	*CheckInGuestRequest_Id
+	//	*CheckInGuestRequest_Name
+	Guest              isCheckInGuestRequest_Guest `protobuf_oneof:"guest"`
+	AccompanyingGuests int32                       `protobuf:"varint,3,opt,name=accompanying_guests,json=accompanyingGuests,proto3" json:"accompanying_guests,omitempty"`
+	// age Age of the guest if it was checked at the door, required by the age_restriction rule
+	Age *int32 `protobuf:"varint,4,opt,name=age,proto3,oneof" json:"age,omitempty"`
+}
+
+func (x *CheckInGuestRequest) Reset() {
+	*x = CheckInGuestRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckInGuestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckInGuestRequest) ProtoMessage() {}
+
+func (x *CheckInGuestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckInGuestRequest.ProtoReflect.Descriptor instead.
+func (*CheckInGuestRequest) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{5}
+}
+
+func (m *CheckInGuestRequest) GetGuest() isCheckInGuestRequest_Guest {
+	if m != nil {
+		return m.Guest
+	}
+	return nil
+}
+
+func (x *CheckInGuestRequest) GetId() string {
+	if x, ok := x.GetGuest().(*CheckInGuestRequest_Id); ok {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CheckInGuestRequest) GetName() string {
+	if x, ok := x.GetGuest().(*CheckInGuestRequest_Name); ok {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CheckInGuestRequest) GetAccompanyingGuests() int32 {
+	if x != nil {
+		return x.AccompanyingGuests
+	}
+	return 0
+}
+
+func (x *CheckInGuestRequest) GetAge() int32 {
+	if x != nil && x.Age != nil {
+		return *x.Age
+	}
+	return 0
+}
+
+type isCheckInGuestRequest_Guest interface {
+	isCheckInGuestRequest_Guest()
+}
+
+type CheckInGuestRequest_Id struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3,oneof"`
+}
+
+type CheckInGuestRequest_Name struct {
+	Name string `protobuf:"bytes,2,opt,name=name,proto3,oneof"`
+}
+
+func (*CheckInGuestRequest_Id) isCheckInGuestRequest_Guest() {}
+
+func (*CheckInGuestRequest_Name) isCheckInGuestRequest_Guest() {}
+
+// CheckOutGuestRequest Chooses the leaving guest by ID or by name, the ID is needed when several guests share the name
+type CheckOutGuestRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Guest:
+	//	*CheckOutGuestRequest_Id
+	//	*CheckOutGuestRequest_Name
+	Guest isCheckOutGuestRequest_Guest `protobuf_oneof:"guest"`
+}
+
+func (x *CheckOutGuestRequest) Reset() {
+	*x = CheckOutGuestRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckOutGuestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckOutGuestRequest) ProtoMessage() {}
+
+func (x *CheckOutGuestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckOutGuestRequest.ProtoReflect.Descriptor instead.
+func (*CheckOutGuestRequest) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{6}
+}
+
+func (m *CheckOutGuestRequest) GetGuest() isCheckOutGuestRequest_Guest {
+	if m != nil {
+		return m.Guest
+	}
+	return nil
+}
+
+func (x *CheckOutGuestRequest) GetId() string {
+	if x, ok := x.GetGuest().(*CheckOutGuestRequest_Id); ok {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CheckOutGuestRequest) GetName() string {
+	if x, ok := x.GetGuest().(*CheckOutGuestRequest_Name); ok {
+		return x.Name
+	}
+	return ""
+}
+
+type isCheckOutGuestRequest_Guest interface {
+	isCheckOutGuestRequest_Guest()
+}
+
+type CheckOutGuestRequest_Id struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3,oneof"`
+}
+
+type CheckOutGuestRequest_Name struct {
+	Name string `protobuf:"bytes,2,opt,name=name,proto3,oneof"`
+}
+
+func (*CheckOutGuestRequest_Id) isCheckOutGuestRequest_Guest() {}
+
+func (*CheckOutGuestRequest_Name) isCheckOutGuestRequest_Guest() {}
+
+type CheckOutGuestResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Guest *Guest `protobuf:"bytes,1,opt,name=guest,proto3" json:"guest,omitempty"`
+}
+
+func (x *CheckOutGuestResponse) Reset() {
+	*x = CheckOutGuestResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckOutGuestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckOutGuestResponse) ProtoMessage() {}
+
+func (x *CheckOutGuestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckOutGuestResponse.ProtoReflect.Descriptor instead.
+func (*CheckOutGuestResponse) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CheckOutGuestResponse) GetGuest() *Guest {
+	if x != nil {
+		return x.Guest
+	}
+	return nil
+}
+
+type ListArrivedGuestsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListArrivedGuestsRequest) Reset() {
+	*x = ListArrivedGuestsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListArrivedGuestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListArrivedGuestsRequest) ProtoMessage() {}
+
+func (x *ListArrivedGuestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListArrivedGuestsRequest.ProtoReflect.Descriptor instead.
+func (*ListArrivedGuestsRequest) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{8}
+}
+
+type CountEmptySeatsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CountEmptySeatsRequest) Reset() {
+	*x = CountEmptySeatsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountEmptySeatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountEmptySeatsRequest) ProtoMessage() {}
+
+func (x *CountEmptySeatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountEmptySeatsRequest.ProtoReflect.Descriptor instead.
+func (*CountEmptySeatsRequest) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{9}
+}
+
+type CountEmptySeatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SeatsEmpty int32 `protobuf:"varint,1,opt,name=seats_empty,json=seatsEmpty,proto3" json:"seats_empty,omitempty"`
+}
+
+func (x *CountEmptySeatsResponse) Reset() {
+	*x = CountEmptySeatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountEmptySeatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountEmptySeatsResponse) ProtoMessage() {}
+
+func (x *CountEmptySeatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountEmptySeatsResponse.ProtoReflect.Descriptor instead.
+func (*CountEmptySeatsResponse) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CountEmptySeatsResponse) GetSeatsEmpty() int32 {
+	if x != nil {
+		return x.SeatsEmpty
+	}
+	return 0
+}
+
+type WatchAttendanceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WatchAttendanceRequest) Reset() {
+	*x = WatchAttendanceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchAttendanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAttendanceRequest) ProtoMessage() {}
+
+func (x *WatchAttendanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAttendanceRequest.ProtoReflect.Descriptor instead.
+func (*WatchAttendanceRequest) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{11}
+}
+
+// AttendanceUpdate A guest arriving to or leaving the party
+type AttendanceUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind  AttendanceUpdate_Kind `protobuf:"varint,1,opt,name=kind,proto3,enum=guestlist.v1.AttendanceUpdate_Kind" json:"kind,omitempty"`
+	Guest *Guest                `protobuf:"bytes,2,opt,name=guest,proto3" json:"guest,omitempty"`
+	// seats_empty Empty seats once the guest arrived or left, -1 if they could not be counted
+	SeatsEmpty int32                  `protobuf:"varint,3,opt,name=seats_empty,json=seatsEmpty,proto3" json:"seats_empty,omitempty"`
+	Time       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+}
+
+func (x *AttendanceUpdate) Reset() {
+	*x = AttendanceUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_guestlist_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AttendanceUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttendanceUpdate) ProtoMessage() {}
+
+func (x *AttendanceUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_guestlist_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttendanceUpdate.ProtoReflect.Descriptor instead.
+func (*AttendanceUpdate) Descriptor() ([]byte, []int) {
+	return file_guestlist_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AttendanceUpdate) GetKind() AttendanceUpdate_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return AttendanceUpdate_KIND_UNSPECIFIED
+}
+
+func (x *AttendanceUpdate) GetGuest() *Guest {
+	if x != nil {
+		return x.Guest
+	}
+	return nil
+}
+
+func (x *AttendanceUpdate) GetSeatsEmpty() int32 {
+	if x != nil {
+		return x.SeatsEmpty
+	}
+	return 0
+}
+
+func (x *AttendanceUpdate) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+var File_guestlist_proto protoreflect.FileDescriptor
+
+var file_guestlist_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x0c, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x1a,
+	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0xd4, 0x01, 0x0a, 0x05, 0x47, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x12, 0x2f, 0x0a, 0x13, 0x61, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x6e,
+	0x79, 0x69, 0x6e, 0x67, 0x5f, 0x67, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x12, 0x61, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x79, 0x69, 0x6e, 0x67, 0x47,
+	0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x61, 0x72,
+	0x72, 0x69, 0x76, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x69, 0x6d,
+	0x65, 0x41, 0x72, 0x72, 0x69, 0x76, 0x65, 0x64, 0x12, 0x3d, 0x0a, 0x09, 0x61, 0x64, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x75,
+	0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x61, 0x64,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x3f, 0x0a, 0x11, 0x41, 0x64, 0x6d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04,
+	0x72, 0x75, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x75, 0x6c, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x6c, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x47,
+	0x75, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x2f, 0x0a, 0x13, 0x61, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x61,
+	0x6e, 0x79, 0x69, 0x6e, 0x67, 0x5f, 0x67, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x12, 0x61, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x79, 0x69, 0x6e, 0x67,
+	0x47, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0x13, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x75,
+	0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x41, 0x0a, 0x12, 0x4c,
+	0x69, 0x73, 0x74, 0x47, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x2b, 0x0a, 0x06, 0x67, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x75, 0x65, 0x73, 0x74, 0x52, 0x06, 0x67, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0x96,
+	0x01, 0x0a, 0x13, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x47, 0x75, 0x65, 0x73, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2f,
+	0x0a, 0x13, 0x61, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x79, 0x69, 0x6e, 0x67, 0x5f, 0x67,
+	0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x12, 0x61, 0x63, 0x63,
+	0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x79, 0x69, 0x6e, 0x67, 0x47, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12,
+	0x15, 0x0a, 0x03, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x03,
+	0x61, 0x67, 0x65, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x67, 0x75, 0x65, 0x73, 0x74, 0x42,
+	0x06, 0x0a, 0x04, 0x5f, 0x61, 0x67, 0x65, 0x22, 0x47, 0x0a, 0x14, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x4f, 0x75, 0x74, 0x47, 0x75, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x10, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x14, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x67, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x42, 0x0a, 0x15, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x4f, 0x75, 0x74, 0x47, 0x75, 0x65, 0x73,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x67, 0x75, 0x65,
+	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74,
+	0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x75, 0x65, 0x73, 0x74, 0x52, 0x05, 0x67,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x1a, 0x0a, 0x18, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x72, 0x72, 0x69,
+	0x76, 0x65, 0x64, 0x47, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x18, 0x0a, 0x16, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x53, 0x65,
+	0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3a, 0x0a, 0x17, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x53, 0x65, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x61, 0x74, 0x73, 0x5f, 0x65,
+	0x6d, 0x70, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x65, 0x61, 0x74,
+	0x73, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x18, 0x0a, 0x16, 0x57, 0x61, 0x74, 0x63, 0x68, 0x41,
+	0x74, 0x74, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x86, 0x02, 0x0a, 0x10, 0x41, 0x74, 0x74, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x63, 0x65, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x63, 0x65, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x2e, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x29,
+	0x0a, 0x05, 0x67, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x75, 0x65,
+	0x73, 0x74, 0x52, 0x05, 0x67, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x61,
+	0x74, 0x73, 0x5f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a,
+	0x73, 0x65, 0x61, 0x74, 0x73, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x22, 0x3d, 0x0a, 0x04, 0x4b, 0x69,
+	0x6e, 0x64, 0x12, 0x14, 0x0a, 0x10, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x4b, 0x49, 0x4e, 0x44,
+	0x5f, 0x41, 0x52, 0x52, 0x49, 0x56, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x4b, 0x49,
+	0x4e, 0x44, 0x5f, 0x4c, 0x45, 0x46, 0x54, 0x10, 0x02, 0x32, 0xd8, 0x04, 0x0a, 0x09, 0x47, 0x75,
+	0x65, 0x73, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x3e, 0x0a, 0x08, 0x41, 0x64, 0x64, 0x47, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1d, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x47, 0x75, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x13, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x75, 0x65, 0x73, 0x74, 0x12, 0x4f, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x47,
+	0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x1f, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69,
+	0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x75, 0x65, 0x73, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0c, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x49, 0x6e, 0x47, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74,
+	0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x47,
+	0x75, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x67, 0x75,
+	0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x58, 0x0a, 0x0d, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x4f, 0x75, 0x74, 0x47, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x22, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x4f, 0x75, 0x74, 0x47, 0x75, 0x65, 0x73, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x4f, 0x75, 0x74, 0x47, 0x75, 0x65,
+	0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5d, 0x0a, 0x11, 0x4c, 0x69,
+	0x73, 0x74, 0x41, 0x72, 0x72, 0x69, 0x76, 0x65, 0x64, 0x47, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12,
+	0x26, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x41, 0x72, 0x72, 0x69, 0x76, 0x65, 0x64, 0x47, 0x75, 0x65, 0x73, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c,
+	0x69, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x75, 0x65, 0x73, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5e, 0x0a, 0x0f, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x53, 0x65, 0x61, 0x74, 0x73, 0x12, 0x24, 0x2e, 0x67,
+	0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x53, 0x65, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x25, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x53, 0x65, 0x61, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x59, 0x0a, 0x0f, 0x57, 0x61, 0x74,
+	0x63, 0x68, 0x41, 0x74, 0x74, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x24, 0x2e, 0x67,
+	0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x41, 0x74, 0x74, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x67, 0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x63, 0x65, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x30, 0x01, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x75, 0x65, 0x73, 0x74, 0x4c, 0x69, 0x73,
+	0x74, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x2f, 0x73, 0x72, 0x63, 0x2f, 0x67,
+	0x75, 0x65, 0x73, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_guestlist_proto_rawDescOnce sync.Once
+	file_guestlist_proto_rawDescData = file_guestlist_proto_rawDesc
+)
+
+func file_guestlist_proto_rawDescGZIP() []byte {
+	file_guestlist_proto_rawDescOnce.Do(func() {
+		file_guestlist_proto_rawDescData = protoimpl.X.CompressGZIP(file_guestlist_proto_rawDescData)
+	})
+	return file_guestlist_proto_rawDescData
+}
+
+var file_guestlist_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_guestlist_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_guestlist_proto_goTypes = []interface{}{
+	(AttendanceUpdate_Kind)(0),       // 0: guestlist.v1.AttendanceUpdate.Kind
+	(*Guest)(nil),                    // 1: guestlist.v1.Guest
+	(*AdmissionDecision)(nil),        // 2: guestlist.v1.AdmissionDecision
+	(*AddGuestRequest)(nil),          // 3: guestlist.v1.AddGuestRequest
+	(*ListGuestsRequest)(nil),        // 4: guestlist.v1.ListGuestsRequest
+	(*ListGuestsResponse)(nil),       // 5: guestlist.v1.ListGuestsResponse
+	(*CheckInGuestRequest)(nil),      // 6: guestlist.v1.CheckInGuestRequest
+	(*CheckOutGuestRequest)(nil),     // 7: guestlist.v1.CheckOutGuestRequest
+	(*CheckOutGuestResponse)(nil),    // 8: guestlist.v1.CheckOutGuestResponse
+	(*ListArrivedGuestsRequest)(nil), // 9: guestlist.v1.ListArrivedGuestsRequest
+	(*CountEmptySeatsRequest)(nil),   // 10: guestlist.v1.CountEmptySeatsRequest
+	(*CountEmptySeatsResponse)(nil),  // 11: guestlist.v1.CountEmptySeatsResponse
+	(*WatchAttendanceRequest)(nil),   // 12: guestlist.v1.WatchAttendanceRequest
+	(*AttendanceUpdate)(nil),         // 13: guestlist.v1.AttendanceUpdate
+	(*timestamppb.Timestamp)(nil),    // 14: google.protobuf.Timestamp
+}
+var file_guestlist_proto_depIdxs = []int32{
+	2,  // 0: guestlist.v1.Guest.admission:type_name -> guestlist.v1.AdmissionDecision
+	1,  // 1: guestlist.v1.ListGuestsResponse.guests:type_name -> guestlist.v1.Guest
+	1,  // 2: guestlist.v1.CheckOutGuestResponse.guest:type_name -> guestlist.v1.Guest
+	0,  // 3: guestlist.v1.AttendanceUpdate.kind:type_name -> guestlist.v1.AttendanceUpdate.Kind
+	1,  // 4: guestlist.v1.AttendanceUpdate.guest:type_name -> guestlist.v1.Guest
+	14, // 5: guestlist.v1.AttendanceUpdate.time:type_name -> google.protobuf.Timestamp
+	3,  // 6: guestlist.v1.GuestList.AddGuest:input_type -> guestlist.v1.AddGuestRequest
+	4,  // 7: guestlist.v1.GuestList.ListGuests:input_type -> guestlist.v1.ListGuestsRequest
+	6,  // 8: guestlist.v1.GuestList.CheckInGuest:input_type -> guestlist.v1.CheckInGuestRequest
+	7,  // 9: guestlist.v1.GuestList.CheckOutGuest:input_type -> guestlist.v1.CheckOutGuestRequest
+	9,  // 10: guestlist.v1.GuestList.ListArrivedGuests:input_type -> guestlist.v1.ListArrivedGuestsRequest
+	10, // 11: guestlist.v1.GuestList.CountEmptySeats:input_type -> guestlist.v1.CountEmptySeatsRequest
+	12, // 12: guestlist.v1.GuestList.WatchAttendance:input_type -> guestlist.v1.WatchAttendanceRequest
+	1,  // 13: guestlist.v1.GuestList.AddGuest:output_type -> guestlist.v1.Guest
+	5,  // 14: guestlist.v1.GuestList.ListGuests:output_type -> guestlist.v1.ListGuestsResponse
+	1,  // 15: guestlist.v1.GuestList.CheckInGuest:output_type -> guestlist.v1.Guest
+	8,  // 16: guestlist.v1.GuestList.CheckOutGuest:output_type -> guestlist.v1.CheckOutGuestResponse
+	5,  // 17: guestlist.v1.GuestList.ListArrivedGuests:output_type -> guestlist.v1.ListGuestsResponse
+	11, // 18: guestlist.v1.GuestList.CountEmptySeats:output_type -> guestlist.v1.CountEmptySeatsResponse
+	13, // 19: guestlist.v1.GuestList.WatchAttendance:output_type -> guestlist.v1.AttendanceUpdate
+	13, // [13:20] is the sub-list for method output_type
+	6,  // [6:13] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_guestlist_proto_init() }
+func file_guestlist_proto_init() {
+	if File_guestlist_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_guestlist_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Guest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_guestlist_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdmissionDecision); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_guestlist_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddGuestRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_guestlist_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListGuestsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_guestlist_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListGuestsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_guestlist_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckInGuestRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_guestlist_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckOutGuestRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_guestlist_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckOutGuestResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_guestlist_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListArrivedGuestsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_guestlist_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CountEmptySeatsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_guestlist_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CountEmptySeatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_guestlist_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchAttendanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_guestlist_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AttendanceUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_guestlist_proto_msgTypes[5].OneofWrappers = []interface{}{
+		(*CheckInGuestRequest_Id)(nil),
+		(*CheckInGuestRequest_Name)(nil),
+	}
+	file_guestlist_proto_msgTypes[6].OneofWrappers = []interface{}{
+		(*CheckOutGuestRequest_Id)(nil),
+		(*CheckOutGuestRequest_Name)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_guestlist_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_guestlist_proto_goTypes,
+		DependencyIndexes: file_guestlist_proto_depIdxs,
+		EnumInfos:         file_guestlist_proto_enumTypes,
+		MessageInfos:      file_guestlist_proto_msgTypes,
+	}.Build()
+	File_guestlist_proto = out.File
+	file_guestlist_proto_rawDesc = nil
+	file_guestlist_proto_goTypes = nil
+	file_guestlist_proto_depIdxs = nil
+}
@@ -0,0 +1,363 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: guestlist.proto
+
+package guestlistpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// GuestListClient is the client API for GuestList service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GuestListClient interface {
+	// AddGuest Adds a guest to the guest list
+	AddGuest(ctx context.Context, in *AddGuestRequest, opts ...grpc.CallOption) (*Guest, error)
+	// ListGuests Returns the guest list
+	ListGuests(ctx context.Context, in *ListGuestsRequest, opts ...grpc.CallOption) (*ListGuestsResponse, error)
+	// CheckInGuest Checks in a guest arriving to the party
+	CheckInGuest(ctx context.Context, in *CheckInGuestRequest, opts ...grpc.CallOption) (*Guest, error)
+	// CheckOutGuest Checks out a guest leaving the party along with their accompanying guests
+	CheckOutGuest(ctx context.Context, in *CheckOutGuestRequest, opts ...grpc.CallOption) (*CheckOutGuestResponse, error)
+	// ListArrivedGuests Returns the guests that are at the party
+	ListArrivedGuests(ctx context.Context, in *ListArrivedGuestsRequest, opts ...grpc.CallOption) (*ListGuestsResponse, error)
+	// CountEmptySeats Returns the number of empty seats
+	CountEmptySeats(ctx context.Context, in *CountEmptySeatsRequest, opts ...grpc.CallOption) (*CountEmptySeatsResponse, error)
+	// WatchAttendance Streams every guest arriving to or leaving the party from now on
+	WatchAttendance(ctx context.Context, in *WatchAttendanceRequest, opts ...grpc.CallOption) (GuestList_WatchAttendanceClient, error)
+}
+
+type guestListClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGuestListClient(cc grpc.ClientConnInterface) GuestListClient {
+	return &guestListClient{cc}
+}
+
+func (c *guestListClient) AddGuest(ctx context.Context, in *AddGuestRequest, opts ...grpc.CallOption) (*Guest, error) {
+	out := new(Guest)
+	err := c.cc.Invoke(ctx, "/guestlist.v1.GuestList/AddGuest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *guestListClient) ListGuests(ctx context.Context, in *ListGuestsRequest, opts ...grpc.CallOption) (*ListGuestsResponse, error) {
+	out := new(ListGuestsResponse)
+	err := c.cc.Invoke(ctx, "/guestlist.v1.GuestList/ListGuests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *guestListClient) CheckInGuest(ctx context.Context, in *CheckInGuestRequest, opts ...grpc.CallOption) (*Guest, error) {
+	out := new(Guest)
+	err := c.cc.Invoke(ctx, "/guestlist.v1.GuestList/CheckInGuest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *guestListClient) CheckOutGuest(ctx context.Context, in *CheckOutGuestRequest, opts ...grpc.CallOption) (*CheckOutGuestResponse, error) {
+	out := new(CheckOutGuestResponse)
+	err := c.cc.Invoke(ctx, "/guestlist.v1.GuestList/CheckOutGuest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *guestListClient) ListArrivedGuests(ctx context.Context, in *ListArrivedGuestsRequest, opts ...grpc.CallOption) (*ListGuestsResponse, error) {
+	out := new(ListGuestsResponse)
+	err := c.cc.Invoke(ctx, "/guestlist.v1.GuestList/ListArrivedGuests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *guestListClient) CountEmptySeats(ctx context.Context, in *CountEmptySeatsRequest, opts ...grpc.CallOption) (*CountEmptySeatsResponse, error) {
+	out := new(CountEmptySeatsResponse)
+	err := c.cc.Invoke(ctx, "/guestlist.v1.GuestList/CountEmptySeats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *guestListClient) WatchAttendance(ctx context.Context, in *WatchAttendanceRequest, opts ...grpc.CallOption) (GuestList_WatchAttendanceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GuestList_ServiceDesc.Streams[0], "/guestlist.v1.GuestList/WatchAttendance", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &guestListWatchAttendanceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GuestList_WatchAttendanceClient interface {
+	Recv() (*AttendanceUpdate, error)
+	grpc.ClientStream
+}
+
+type guestListWatchAttendanceClient struct {
+	grpc.ClientStream
+}
+
+func (x *guestListWatchAttendanceClient) Recv() (*AttendanceUpdate, error) {
+	m := new(AttendanceUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GuestListServer is the server API for GuestList service.
+// All implementations must embed UnimplementedGuestListServer
+// for forward compatibility
+type GuestListServer interface {
+	// AddGuest Adds a guest to the guest list
+	AddGuest(context.Context, *AddGuestRequest) (*Guest, error)
+	// ListGuests Returns the guest list
+	ListGuests(context.Context, *ListGuestsRequest) (*ListGuestsResponse, error)
+	// CheckInGuest Checks in a guest arriving to the party
+	CheckInGuest(context.Context, *CheckInGuestRequest) (*Guest, error)
+	// CheckOutGuest Checks out a guest leaving the party along with their accompanying guests
+	CheckOutGuest(context.Context, *CheckOutGuestRequest) (*CheckOutGuestResponse, error)
+	// ListArrivedGuests Returns the guests that are at the party
+	ListArrivedGuests(context.Context, *ListArrivedGuestsRequest) (*ListGuestsResponse, error)
+	// CountEmptySeats Returns the number of empty seats
+	CountEmptySeats(context.Context, *CountEmptySeatsRequest) (*CountEmptySeatsResponse, error)
+	// WatchAttendance Streams every guest arriving to or leaving the party from now on
+	WatchAttendance(*WatchAttendanceRequest, GuestList_WatchAttendanceServer) error
+	mustEmbedUnimplementedGuestListServer()
+}
+
+// UnimplementedGuestListServer must be embedded to have forward compatible implementations.
+type UnimplementedGuestListServer struct {
+}
+
+func (UnimplementedGuestListServer) AddGuest(context.Context, *AddGuestRequest) (*Guest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddGuest not implemented")
+}
+func (UnimplementedGuestListServer) ListGuests(context.Context, *ListGuestsRequest) (*ListGuestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGuests not implemented")
+}
+func (UnimplementedGuestListServer) CheckInGuest(context.Context, *CheckInGuestRequest) (*Guest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckInGuest not implemented")
+}
+func (UnimplementedGuestListServer) CheckOutGuest(context.Context, *CheckOutGuestRequest) (*CheckOutGuestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckOutGuest not implemented")
+}
+func (UnimplementedGuestListServer) ListArrivedGuests(context.Context, *ListArrivedGuestsRequest) (*ListGuestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListArrivedGuests not implemented")
+}
+func (UnimplementedGuestListServer) CountEmptySeats(context.Context, *CountEmptySeatsRequest) (*CountEmptySeatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountEmptySeats not implemented")
+}
+func (UnimplementedGuestListServer) WatchAttendance(*WatchAttendanceRequest, GuestList_WatchAttendanceServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAttendance not implemented")
+}
+func (UnimplementedGuestListServer) mustEmbedUnimplementedGuestListServer() {}
+
+// UnsafeGuestListServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GuestListServer will
+// result in compilation errors.
+type UnsafeGuestListServer interface {
+	mustEmbedUnimplementedGuestListServer()
+}
+
+func RegisterGuestListServer(s grpc.ServiceRegistrar, srv GuestListServer) {
+	s.RegisterService(&GuestList_ServiceDesc, srv)
+}
+
+func _GuestList_AddGuest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddGuestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GuestListServer).AddGuest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/guestlist.v1.GuestList/AddGuest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GuestListServer).AddGuest(ctx, req.(*AddGuestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GuestList_ListGuests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGuestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GuestListServer).ListGuests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/guestlist.v1.GuestList/ListGuests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GuestListServer).ListGuests(ctx, req.(*ListGuestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GuestList_CheckInGuest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckInGuestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GuestListServer).CheckInGuest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/guestlist.v1.GuestList/CheckInGuest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GuestListServer).CheckInGuest(ctx, req.(*CheckInGuestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GuestList_CheckOutGuest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckOutGuestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GuestListServer).CheckOutGuest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/guestlist.v1.GuestList/CheckOutGuest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GuestListServer).CheckOutGuest(ctx, req.(*CheckOutGuestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GuestList_ListArrivedGuests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListArrivedGuestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GuestListServer).ListArrivedGuests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/guestlist.v1.GuestList/ListArrivedGuests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GuestListServer).ListArrivedGuests(ctx, req.(*ListArrivedGuestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GuestList_CountEmptySeats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountEmptySeatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GuestListServer).CountEmptySeats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/guestlist.v1.GuestList/CountEmptySeats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GuestListServer).CountEmptySeats(ctx, req.(*CountEmptySeatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GuestList_WatchAttendance_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAttendanceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GuestListServer).WatchAttendance(m, &guestListWatchAttendanceServer{stream})
+}
+
+type GuestList_WatchAttendanceServer interface {
+	Send(*AttendanceUpdate) error
+	grpc.ServerStream
+}
+
+type guestListWatchAttendanceServer struct {
+	grpc.ServerStream
+}
+
+func (x *guestListWatchAttendanceServer) Send(m *AttendanceUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// GuestList_ServiceDesc is the grpc.ServiceDesc for GuestList service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GuestList_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "guestlist.v1.GuestList",
+	HandlerType: (*GuestListServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddGuest",
+			Handler:    _GuestList_AddGuest_Handler,
+		},
+		{
+			MethodName: "ListGuests",
+			Handler:    _GuestList_ListGuests_Handler,
+		},
+		{
+			MethodName: "CheckInGuest",
+			Handler:    _GuestList_CheckInGuest_Handler,
+		},
+		{
+			MethodName: "CheckOutGuest",
+			Handler:    _GuestList_CheckOutGuest_Handler,
+		},
+		{
+			MethodName: "ListArrivedGuests",
+			Handler:    _GuestList_ListArrivedGuests_Handler,
+		},
+		{
+			MethodName: "CountEmptySeats",
+			Handler:    _GuestList_CountEmptySeats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAttendance",
+			Handler:       _GuestList_WatchAttendance_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "guestlist.proto",
+}
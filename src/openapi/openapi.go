@@ -0,0 +1,347 @@
+package openapi
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// specification OpenAPI 3 document describing every route of the service
+//
+//go:embed openapi.json
+var specification []byte
+
+// swaggerPage HTML page rendering the OpenAPI document with Swagger UI
+//
+//go:embed swagger.html
+var swaggerPage []byte
+
+// swaggerAssets Scripts and style sheets of the documentation page, served by the service so that it works offline
+//
+// Swagger UI is vendored in the swagger-ui directory with `make swagger-ui`, until then the page renders the
+// document with docs.js
+//
+//go:embed swagger-ui/*.js swagger-ui/*.css
+var swaggerAssets embed.FS
+
+// schemaReferencePrefix Prefix of the references to the document's component schemas
+const schemaReferencePrefix = "#/components/schemas/"
+
+// parameterReferencePrefix Prefix of the references to the document's component parameters
+const parameterReferencePrefix = "#/components/parameters/"
+
+// responseReferencePrefix Prefix of the references to the document's component responses
+const responseReferencePrefix = "#/components/responses/"
+
+// Document Subset of an OpenAPI 3 document needed to validate requests and responses
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Schemas    map[string]*Schema    `json:"schemas"`
+		Parameters map[string]*Parameter `json:"parameters"`
+		Responses  map[string]*Response  `json:"responses"`
+	} `json:"components"`
+}
+
+// Operation A single method of a path
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Parameters  []*Parameter         `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter Parameter taken by an operation
+type Parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody Body expected by an operation
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response Response produced by an operation
+type Response struct {
+	Ref     string                `json:"$ref"`
+	Content map[string]*MediaType `json:"content"`
+}
+
+// MediaType Schema of a body for a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema Subset of the OpenAPI schema object supported by Validate
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Nullable             bool               `json:"nullable"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*Schema `json:"properties"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+	Enum                 []interface{}      `json:"enum"`
+	OneOf                []*Schema          `json:"oneOf"`
+	AllOf                []*Schema          `json:"allOf"`
+}
+
+// Specification Returns the raw OpenAPI document
+func Specification() []byte {
+	return specification
+}
+
+// SwaggerPage Returns the HTML page rendering the OpenAPI document with Swagger UI
+func SwaggerPage() []byte {
+	return swaggerPage
+}
+
+// SwaggerAsset Returns a script or style sheet of the documentation page, false if there is none of that name
+func SwaggerAsset(name string) ([]byte, bool) {
+	asset, readError := fs.ReadFile(swaggerAssets, "swagger-ui/"+name)
+	return asset, readError == nil
+}
+
+// Load Parses the OpenAPI document embedded in the binary
+func Load() (*Document, error) {
+	var document Document
+	if decodeError := json.Unmarshal(specification, &document); decodeError != nil {
+		return nil, decodeError
+	}
+	return &document, nil
+}
+
+// Operation Returns the operation of a path template and http method, nil if it is not documented
+func (document *Document) Operation(pathTemplate string, method string) *Operation {
+	return document.Paths[pathTemplate][strings.ToLower(method)]
+}
+
+// RequestSchema Returns the JSON request body schema of an operation, nil if it takes no body
+func (document *Document) RequestSchema(operation *Operation) (schema *Schema, required bool) {
+	if operation == nil || operation.RequestBody == nil {
+		return nil, false
+	}
+	mediaType := operation.RequestBody.Content["application/json"]
+	if mediaType == nil {
+		return nil, false
+	}
+	return mediaType.Schema, operation.RequestBody.Required
+}
+
+// ResponseSchema Returns the JSON response body schema of an operation for an http status, nil if it is not documented
+func (document *Document) ResponseSchema(operation *Operation, status int) *Schema {
+	if operation == nil {
+		return nil
+	}
+	response := operation.Responses[fmt.Sprint(status)]
+	if response != nil && strings.HasPrefix(response.Ref, responseReferencePrefix) {
+		response = document.Components.Responses[strings.TrimPrefix(response.Ref, responseReferencePrefix)]
+	}
+	if response == nil || response.Content["application/json"] == nil {
+		return nil
+	}
+	return response.Content["application/json"].Schema
+}
+
+// ValidateParameters Validates the path and query parameters of a request against the parameters of an operation
+//
+// Returns every violation found, an empty list if the parameters are valid
+func (document *Document) ValidateParameters(operation *Operation, pathValues map[string]string, queryValues url.Values) []string {
+	if operation == nil {
+		return nil
+	}
+
+	var violations []string
+	for _, parameter := range operation.Parameters {
+		if strings.HasPrefix(parameter.Ref, parameterReferencePrefix) {
+			parameter = document.Components.Parameters[strings.TrimPrefix(parameter.Ref, parameterReferencePrefix)]
+		}
+		if parameter == nil {
+			continue
+		}
+
+		location := parameter.In + "." + parameter.Name
+		switch parameter.In {
+		case "path":
+			violations = document.validate(parameter.Schema, pathValues[parameter.Name], location, violations)
+		case "query":
+			if _, present := queryValues[parameter.Name]; !present {
+				if parameter.Required {
+					violations = append(violations, location+": is required")
+				}
+				continue
+			}
+			violations = document.validate(parameter.Schema, queryValues.Get(parameter.Name), location, violations)
+		}
+	}
+	sort.Strings(violations)
+	return violations
+}
+
+// DecodeJSON Decodes a JSON body keeping numbers intact so that integers can be told apart
+func DecodeJSON(body []byte) (value interface{}, decodeError error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if decodeError = decoder.Decode(&value); decodeError != nil {
+		return nil, decodeError
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+// Validate Validates a decoded JSON value against a schema of the document
+//
+// Returns every violation found, an empty list if the value is valid
+func (document *Document) Validate(schema *Schema, value interface{}) []string {
+	violations := document.validate(schema, value, "body", nil)
+	sort.Strings(violations)
+	return violations
+}
+
+// validate Appends to violations every violation of value against schema, location being the value's path in the body
+func (document *Document) validate(schema *Schema, value interface{}, location string, violations []string) []string {
+	if schema == nil || (value == nil && schema.Nullable) {
+		return violations
+	}
+
+	if schema.Ref != "" {
+		referenced := document.Components.Schemas[strings.TrimPrefix(schema.Ref, schemaReferencePrefix)]
+		if referenced == nil {
+			return append(violations, location+": unknown schema "+schema.Ref)
+		}
+		return document.validate(referenced, value, location, violations)
+	}
+
+	for _, component := range schema.AllOf {
+		violations = document.validate(component, value, location, violations)
+	}
+
+	if len(schema.OneOf) > 0 {
+		matches := 0
+		for _, alternative := range schema.OneOf {
+			if len(document.validate(alternative, value, location, nil)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			violations = append(violations, location+": must match exactly one of the allowed schemas")
+		}
+		return violations
+	}
+
+	if schema.Type != "" && !hasType(value, schema.Type) {
+		return append(violations, location+": must be of type "+schema.Type)
+	}
+
+	if len(schema.Enum) > 0 && !isOneOf(value, schema.Enum) {
+		violations = append(violations, location+": must be one of the allowed values")
+	}
+
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for _, property := range schema.Required {
+			if _, exists := typedValue[property]; !exists {
+				violations = append(violations, location+"."+property+": is required")
+			}
+		}
+		for property, propertyValue := range typedValue {
+			propertySchema, known := schema.Properties[property]
+			if !known {
+				if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+					violations = append(violations, location+"."+property+": is not allowed")
+				}
+				continue
+			}
+			violations = document.validate(propertySchema, propertyValue, location+"."+property, violations)
+		}
+
+	case []interface{}:
+		for index, item := range typedValue {
+			violations = document.validate(schema.Items, item, fmt.Sprintf("%s[%d]", location, index), violations)
+		}
+
+	case json.Number:
+		number, _ := typedValue.Float64()
+		if schema.Minimum != nil && number < *schema.Minimum {
+			violations = append(violations, fmt.Sprintf("%s: must be at least %v", location, *schema.Minimum))
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			violations = append(violations, fmt.Sprintf("%s: must be at most %v", location, *schema.Maximum))
+		}
+
+	case string:
+		length := len([]rune(typedValue))
+		if schema.MinLength != nil && length < *schema.MinLength {
+			violations = append(violations, fmt.Sprintf("%s: must be at least %d characters long", location, *schema.MinLength))
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			violations = append(violations, fmt.Sprintf("%s: must be at most %d characters long", location, *schema.MaxLength))
+		}
+		if schema.Pattern != "" {
+			if matched, patternError := regexp.MatchString(schema.Pattern, typedValue); patternError != nil || !matched {
+				violations = append(violations, location+": has an invalid format")
+			}
+		}
+	}
+
+	return violations
+}
+
+// hasType Checks if a decoded JSON value is of the given schema type
+func hasType(value interface{}, schemaType string) bool {
+	switch schemaType {
+	case "object":
+		_, isObject := value.(map[string]interface{})
+		return isObject
+	case "array":
+		_, isArray := value.([]interface{})
+		return isArray
+	case "string":
+		_, isString := value.(string)
+		return isString
+	case "boolean":
+		_, isBoolean := value.(bool)
+		return isBoolean
+	case "number":
+		_, isNumber := value.(json.Number)
+		return isNumber
+	case "integer":
+		number, isNumber := value.(json.Number)
+		if !isNumber {
+			return false
+		}
+		floatNumber, conversionError := number.Float64()
+		return conversionError == nil && floatNumber == math.Trunc(floatNumber)
+	}
+	return false
+}
+
+// isOneOf Checks if a decoded JSON value equals one of the allowed values
+func isOneOf(value interface{}, allowed []interface{}) bool {
+	for _, allowedValue := range allowed {
+		if fmt.Sprint(allowedValue) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+// Plain rendering of the OpenAPI document, used until Swagger UI is vendored with `make swagger-ui`
+"use strict";
+
+// element Creates an element holding the given text and children
+function element(tag, className, text, children) {
+    const node = document.createElement(tag);
+    if (className) {
+        node.className = className;
+    }
+    if (text) {
+        node.textContent = text;
+    }
+    (children || []).forEach(function (child) {
+        node.appendChild(child);
+    });
+    return node;
+}
+
+// reference Returns the name of a referenced component, or the type of an inline schema
+function reference(schema) {
+    if (!schema) {
+        return "";
+    }
+    if (schema.$ref) {
+        return schema.$ref.split("/").pop();
+    }
+    return schema.type || "";
+}
+
+// renderOperation Renders the parameters, body and responses of a single method of a path
+function renderOperation(specification, path, method, operation) {
+    const parameters = (operation.parameters || []).map(function (parameter) {
+        if (parameter.$ref) {
+            parameter = specification.components.parameters[reference(parameter)];
+        }
+        return element("li", "", parameter.in + " " + parameter.name + (parameter.required ? " (required)" : "") +
+            ": " + reference(parameter.schema) + (parameter.description ? " - " + parameter.description : ""));
+    });
+    const body = operation.requestBody && operation.requestBody.content["application/json"];
+    const responses = Object.keys(operation.responses).map(function (status) {
+        const response = operation.responses[status];
+        const description = response.$ref ? reference(response) : response.description;
+        return element("li", "", status + ": " + description);
+    });
+
+    return element("div", "operation", "", [
+        element("h3", "", "", [element("span", "method " + method, method), element("code", "", path)]),
+        element("p", "", operation.summary),
+        element("p", "description", operation.description),
+        element("ul", "", "", parameters),
+        element("p", "", body ? "Body: " + reference(body.schema) : ""),
+        element("ul", "", "", responses)
+    ]);
+}
+
+// renderDocument Renders every operation of the OpenAPI document found at url in the given element
+function renderDocument(url, target) {
+    fetch(url).then(function (reply) {
+        return reply.json();
+    }).then(function (specification) {
+        target.id = "api-document";
+        target.appendChild(element("h1", "", specification.info.title + " " + specification.info.version));
+        target.appendChild(element("p", "description", specification.info.description));
+        Object.keys(specification.paths).forEach(function (path) {
+            Object.keys(specification.paths[path]).forEach(function (method) {
+                target.appendChild(renderOperation(specification, path, method, specification.paths[path][method]));
+            });
+        });
+    });
+}
@@ -0,0 +1,150 @@
+package reports
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// occupancyPage Template of the HTML page charting an occupancy report
+//
+//go:embed occupancy.html
+var occupancyPage string
+
+// occupancyTemplate Parsed occupancy page, the page is part of the binary so it is parsed once
+var occupancyTemplate = template.Must(template.New("occupancy").Parse(occupancyPage))
+
+// Size and margins of the chart, in pixels
+const (
+	chartWidth  = 800
+	chartHeight = 320
+	chartMargin = 40
+)
+
+// chartTimeFormat Format of the times labelling the chart
+const chartTimeFormat = "2006-01-02 15:04 MST"
+
+// occupancyView Values shown by the occupancy page
+type occupancyView struct {
+	EventID     string
+	From        string
+	Until       string
+	Interval    time.Duration
+	Chart       chart
+	Peak        string
+	AverageStay time.Duration
+	Visits      int
+	Invited     int
+	NoShows     int
+	NoShowRate  string
+	WalkIns     int
+	Tables      []tableView
+}
+
+// chart SVG chart of the people present in each bin, with the curve of the people arrived so far
+type chart struct {
+	Width, Height            int
+	Left, Right, Top, Bottom int
+	LabelY                   int
+	StartLabel, EndLabel     string
+	MaxPeople                int
+	Bars                     []bar
+	ArrivalCurve             string
+}
+
+// bar Bar of the chart
+type bar struct {
+	X, Y, Width, Height float64
+	Label               string
+}
+
+// tableView Utilisation of a table as shown by the occupancy page
+type tableView struct {
+	Name        string
+	Seats       int
+	Utilisation string
+}
+
+// RenderHTML Writes an HTML page charting an occupancy report as SVG
+func RenderHTML(writer io.Writer, occupancy Occupancy, eventID string) error {
+	view := occupancyView{
+		EventID:     eventID,
+		From:        occupancy.Window.From.Format(chartTimeFormat),
+		Until:       occupancy.Window.Until.Format(chartTimeFormat),
+		Interval:    occupancy.Window.Interval,
+		Chart:       newChart(occupancy),
+		Peak:        "nobody came",
+		AverageStay: occupancy.AverageStay,
+		Visits:      occupancy.Visits,
+		Invited:     occupancy.Invited,
+		NoShows:     occupancy.NoShows,
+		NoShowRate:  percentage(occupancy.NoShowRate()),
+		WalkIns:     occupancy.WalkIns,
+	}
+	if occupancy.PeakAt != nil {
+		view.Peak = fmt.Sprintf("%d people at %s", occupancy.Peak, occupancy.PeakAt.Format(chartTimeFormat))
+	}
+	for _, table := range occupancy.Tables {
+		view.Tables = append(view.Tables, tableView{Name: table.Name, Seats: table.Seats, Utilisation: percentage(table.Utilisation)})
+	}
+	return occupancyTemplate.Execute(writer, view)
+}
+
+// newChart Lays out the chart of an occupancy report
+func newChart(occupancy Occupancy) chart {
+	newChart := chart{
+		Width:      chartWidth,
+		Height:     chartHeight,
+		Left:       chartMargin,
+		Right:      chartWidth - chartMargin,
+		Top:        chartMargin / 2,
+		Bottom:     chartHeight - chartMargin,
+		LabelY:     chartHeight - chartMargin/2,
+		StartLabel: occupancy.Window.From.Format(chartTimeFormat),
+		EndLabel:   occupancy.Window.Until.Format(chartTimeFormat),
+	}
+
+	// The vertical scale fits both the people present and the people arrived so far
+	arrived := 0
+	for _, bin := range occupancy.Timeline {
+		arrived += bin.Arrivals
+	}
+	newChart.MaxPeople = occupancy.Peak
+	if arrived > newChart.MaxPeople {
+		newChart.MaxPeople = arrived
+	}
+	if newChart.MaxPeople == 0 || len(occupancy.Timeline) == 0 {
+		return newChart
+	}
+
+	plotWidth := float64(newChart.Right - newChart.Left)
+	plotHeight := float64(newChart.Bottom - newChart.Top)
+	barWidth := plotWidth / float64(len(occupancy.Timeline))
+	scale := plotHeight / float64(newChart.MaxPeople)
+
+	points := []string{fmt.Sprintf("%.1f,%d", float64(newChart.Left), newChart.Bottom)}
+	arrived = 0
+	for binIndex, bin := range occupancy.Timeline {
+		x := float64(newChart.Left) + float64(binIndex)*barWidth
+		height := float64(bin.Present) * scale
+		newChart.Bars = append(newChart.Bars, bar{
+			X:      x,
+			Y:      float64(newChart.Bottom) - height,
+			Width:  barWidth * 0.9,
+			Height: height,
+			Label:  fmt.Sprintf("%s: %d present, %d arrived, %d left", bin.Start.Format(chartTimeFormat), bin.Present, bin.Arrivals, bin.Departures),
+		})
+		arrived += bin.Arrivals
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x+barWidth, float64(newChart.Bottom)-float64(arrived)*scale))
+	}
+	newChart.ArrivalCurve = strings.Join(points, " ")
+	return newChart
+}
+
+// percentage Formats a share as a percentage
+func percentage(share float64) string {
+	return fmt.Sprintf("%.0f%%", share*100)
+}
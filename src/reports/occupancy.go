@@ -0,0 +1,279 @@
+package reports
+
+import (
+	"guestListChallenge/src/database"
+	"sort"
+	"time"
+)
+
+// MaxBins Largest number of bins a report is split into
+const MaxBins = 1000
+
+// Window Period covered by a report, split into bins of the given interval
+type Window struct {
+	From     time.Time
+	Until    time.Time
+	Interval time.Duration
+}
+
+// Bins Returns the number of bins of the window, the last one possibly shorter than the interval
+func (window Window) Bins() int {
+	if window.Interval <= 0 || !window.Until.After(window.From) {
+		return 0
+	}
+	duration := window.Until.Sub(window.From)
+	bins := int(duration / window.Interval)
+	if duration%window.Interval != 0 {
+		bins++
+	}
+	return bins
+}
+
+// Occupancy Report of the attendance of a party over a window of time
+//
+// People are counted as the guest plus their accompanying guests, while tables are taken by the accompanying guests
+// as when counting empty seats. Walk-ins are not invited and have no table, they and their accompanying guests
+// take seats at the table they sit at.
+type Occupancy struct {
+	Window Window
+
+	Timeline []Bin
+
+	Peak   int        // largest number of people present at once
+	PeakAt *time.Time // first time the peak was reached, nil when nobody came
+
+	Tables []TableUtilisation
+
+	Visits      int           // check-ins overlapping the window
+	AverageStay time.Duration // average time from check-in to check-out, or to the end of the window for guests still there
+
+	Invited int // guests on the guest list during the window, walk-ins left out
+	NoShows int // invited guests who did not check in by the end of the window
+
+	WalkIns int // people admitted as walk-ins during the window, accompanying guests included
+}
+
+// Bin Attendance during one interval of the window
+type Bin struct {
+	Start      time.Time
+	Present    int // largest number of people present during the interval
+	Arrivals   int // people checked in during the interval
+	Departures int // people checked out during the interval
+}
+
+// TableUtilisation Share of a guest's table taken over the window
+type TableUtilisation struct {
+	GuestID     string
+	Name        string
+	Seats       int
+	Utilisation float64 // seat time taken over seat time available, from 0 to 1
+}
+
+// NoShowRate Returns the share of invited guests who did not check in, 0 when nobody was invited
+func (occupancy Occupancy) NoShowRate() float64 {
+	if occupancy.Invited == 0 {
+		return 0
+	}
+	return float64(occupancy.NoShows) / float64(occupancy.Invited)
+}
+
+// stay Time a guest spent at the party
+type stay struct {
+	tableID    string // ID of the guest whose table is taken
+	people     int
+	seatsTaken int
+	arrivedAt  *time.Time // nil for guests checked in before visits were recorded, taken as there from the start
+	leftAt     *time.Time // nil while the guest is at the party
+}
+
+// event A number of people arriving or leaving
+type event struct {
+	time   time.Time
+	change int
+}
+
+// NewOccupancy Computes the occupancy report of a window from the current guest list and the visits that started before its end
+func NewOccupancy(guestList []database.GuestList, visits []database.Visit, window Window) Occupancy {
+	occupancy := Occupancy{Window: window, Timeline: []Bin{}, Tables: []TableUtilisation{}}
+
+	// Guests on the list during the window, by ID, and their stays
+	guestsByID := map[string]database.GuestList{}
+	showedUp := map[string]bool{}
+	var stays []stay
+	for _, guest := range guestList {
+		if !guest.WalkIn && (guest.AddedAt == nil || guest.AddedAt.Before(window.Until)) {
+			guestsByID[guest.ID] = guest
+		}
+	}
+	for _, visit := range visits {
+		if !visit.ArrivedAt.Before(window.Until) {
+			continue
+		}
+		arrivedAt := visit.ArrivedAt
+		if visit.WalkIn {
+			if !arrivedAt.Before(window.From) {
+				occupancy.WalkIns += 1 + visit.AccompanyingGuests
+			}
+			stays = append(stays, stay{
+				tableID:    visit.SeatedWith,
+				people:     1 + visit.AccompanyingGuests,
+				seatsTaken: 1 + visit.AccompanyingGuests,
+				arrivedAt:  &arrivedAt,
+				leftAt:     visit.LeftAt,
+			})
+			continue
+		}
+
+		showedUp[visit.GuestID] = true
+		if _, listed := guestsByID[visit.GuestID]; !listed {
+			// Checked out since, the visit holds the guest as they were
+			guestsByID[visit.GuestID] = database.GuestList{ID: visit.GuestID, Name: visit.Name, Table: visit.Table}
+		}
+		stays = append(stays, stay{
+			tableID:    visit.GuestID,
+			people:     1 + visit.AccompanyingGuests,
+			seatsTaken: visit.AccompanyingGuests,
+			arrivedAt:  &arrivedAt,
+			leftAt:     visit.LeftAt,
+		})
+	}
+	for _, guest := range guestList {
+		if _, listed := guestsByID[guest.ID]; listed && guest.TimeArrived != "" && !showedUp[guest.ID] {
+			showedUp[guest.ID] = true
+			stays = append(stays, stay{tableID: guest.ID, people: 1 + guest.AccompanyingGuests, seatsTaken: guest.AccompanyingGuests})
+		}
+	}
+
+	occupancy.Invited = len(guestsByID)
+	occupancy.NoShows = occupancy.Invited - len(showedUp)
+	occupancy.Timeline, occupancy.Peak, occupancy.PeakAt = timeline(stays, window)
+	occupancy.Visits, occupancy.AverageStay = averageStay(stays, window)
+	occupancy.Tables = tableUtilisations(guestsByID, stays, window)
+	return occupancy
+}
+
+// timeline Returns the attendance during each bin of the window, and the peak attendance with the time it was first reached
+func timeline(stays []stay, window Window) ([]Bin, int, *time.Time) {
+	var events []event
+	for _, stay := range stays {
+		arrivedAt := window.From.Add(-time.Nanosecond)
+		if stay.arrivedAt != nil {
+			arrivedAt = *stay.arrivedAt
+		}
+		events = append(events, event{time: arrivedAt, change: stay.people})
+		if stay.leftAt != nil {
+			events = append(events, event{time: *stay.leftAt, change: -stay.people})
+		}
+	}
+	// Departures first, so that the count between simultaneous events never exceeds the count after them
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].time.Equal(events[j].time) {
+			return events[i].time.Before(events[j].time)
+		}
+		return events[i].change < events[j].change
+	})
+
+	bins := make([]Bin, 0, window.Bins())
+	present, peak := 0, 0
+	var peakAt *time.Time
+	eventIndex := 0
+	for binIndex := 0; binIndex < window.Bins(); binIndex++ {
+		bin := Bin{Start: window.From.Add(time.Duration(binIndex) * window.Interval)}
+		binEnd := bin.Start.Add(window.Interval)
+		if binEnd.After(window.Until) {
+			binEnd = window.Until
+		}
+
+		// People who came before the window are there from its start
+		for ; eventIndex < len(events) && events[eventIndex].time.Before(bin.Start); eventIndex++ {
+			present += events[eventIndex].change
+		}
+		if binIndex == 0 && present > 0 {
+			peak = present
+			peakTime := window.From
+			peakAt = &peakTime
+		}
+		bin.Present = present
+
+		for ; eventIndex < len(events) && events[eventIndex].time.Before(binEnd); eventIndex++ {
+			currentEvent := events[eventIndex]
+			present += currentEvent.change
+			if currentEvent.change > 0 {
+				bin.Arrivals += currentEvent.change
+			} else {
+				bin.Departures -= currentEvent.change
+			}
+
+			// Counts are compared once every event of the same time is taken into account
+			if eventIndex+1 < len(events) && events[eventIndex+1].time.Equal(currentEvent.time) {
+				continue
+			}
+			if currentEvent.time.Equal(bin.Start) || present > bin.Present {
+				bin.Present = present
+			}
+			if present > peak {
+				peak = present
+				peakTime := currentEvent.time
+				peakAt = &peakTime
+			}
+		}
+		bins = append(bins, bin)
+	}
+	return bins, peak, peakAt
+}
+
+// averageStay Returns the number of check-ins overlapping the window and their average length,
+// guests still there being counted until the end of the window
+func averageStay(stays []stay, window Window) (int, time.Duration) {
+	visits := 0
+	var totalStay time.Duration
+	for _, stay := range stays {
+		if stay.arrivedAt == nil || (stay.leftAt != nil && !stay.leftAt.After(window.From)) {
+			continue
+		}
+		end := window.Until
+		if stay.leftAt != nil && stay.leftAt.Before(end) {
+			end = *stay.leftAt
+		}
+		visits++
+		totalStay += end.Sub(*stay.arrivedAt)
+	}
+	if visits == 0 {
+		return 0, 0
+	}
+	return visits, (totalStay / time.Duration(visits)).Truncate(time.Second)
+}
+
+// tableUtilisations Returns the share of each guest's table taken over the window, by guest name
+func tableUtilisations(guestsByID map[string]database.GuestList, stays []stay, window Window) []TableUtilisation {
+	seatTimeTaken := map[string]time.Duration{}
+	for _, stay := range stays {
+		start, end := window.From, window.Until
+		if stay.arrivedAt != nil && stay.arrivedAt.After(start) {
+			start = *stay.arrivedAt
+		}
+		if stay.leftAt != nil && stay.leftAt.Before(end) {
+			end = *stay.leftAt
+		}
+		if end.After(start) {
+			seatTimeTaken[stay.tableID] += end.Sub(start) * time.Duration(stay.seatsTaken)
+		}
+	}
+
+	tables := make([]TableUtilisation, 0, len(guestsByID))
+	windowLength := window.Until.Sub(window.From)
+	for _, guest := range guestsByID {
+		table := TableUtilisation{GuestID: guest.ID, Name: guest.Name, Seats: guest.Table}
+		if guest.Table > 0 && windowLength > 0 {
+			table.Utilisation = float64(seatTimeTaken[guest.ID]) / (float64(windowLength) * float64(guest.Table))
+		}
+		tables = append(tables, table)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].Name != tables[j].Name {
+			return tables[i].Name < tables[j].Name
+		}
+		return tables[i].GuestID < tables[j].GuestID
+	})
+	return tables
+}
@@ -0,0 +1,40 @@
+package requestRouting
+
+import (
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/guestService"
+	"net/http"
+	"strings"
+)
+
+// getAdmissionRules Processes the request to list the rules deciding who is admitted, in the order they are checked
+func (server *Server) getAdmissionRules(response http.ResponseWriter, _ *http.Request) {
+	server.encodeResponse(response, CreateAdmissionRulesResponse(server.guests.AdmissionPolicy()))
+}
+
+// setAdmissionRules Processes the request to replace the rules deciding who is admitted, written as in rules files
+func (server *Server) setAdmissionRules(response http.ResponseWriter, request *http.Request) {
+	var requestData api.SetAdmissionRulesRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	policy, policyError := guestService.NewAdmissionPolicy(server.config.EventID, requestData.Rules)
+	if policyError != nil {
+		server.encodeErrorResponse(response, http.StatusUnprocessableEntity, api.ErrorCodeInvalidRequest,
+			CreateValidationErrorResponse([]string{"body: " + policyError.Error()}))
+		return
+	}
+	if changeError := server.guests.ChangeAdmissionPolicy(request.Context(), policy); changeError != nil {
+		server.reportStoreError(response, changeError)
+		return
+	}
+
+	ruleNames := make([]string, 0, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		ruleNames = append(ruleNames, rule.Name())
+	}
+	server.logger.Println("Admission rules set: " + strings.Join(ruleNames, ", "))
+	server.encodeResponse(response, CreateAdmissionRulesResponse(policy))
+}
@@ -0,0 +1,74 @@
+package requestRouting
+
+import (
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/database"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// getAuditRecords Processes the request to get the audit log, newest first
+//
+// Records can be filtered by actor, request ID, operation, target and time range
+func (server *Server) getAuditRecords(response http.ResponseWriter, request *http.Request) {
+	queryValues := request.URL.Query()
+	filter := database.AuditFilter{
+		Actor:     queryValues.Get("actor"),
+		RequestID: queryValues.Get("request_id"),
+		Operation: queryValues.Get("operation"),
+		TargetID:  queryValues.Get("target_id"),
+	}
+
+	var violations []string
+	var parseError error
+	if since := queryValues.Get("since"); since != "" {
+		if filter.Since, parseError = time.Parse(time.RFC3339, since); parseError != nil {
+			violations = append(violations, "query.since: must be an RFC 3339 time")
+		}
+	}
+	if until := queryValues.Get("until"); until != "" {
+		if filter.Until, parseError = time.Parse(time.RFC3339, until); parseError != nil {
+			violations = append(violations, "query.until: must be an RFC 3339 time")
+		}
+	}
+	limit := defaultAuditRecords
+	if limitValue := queryValues.Get("limit"); limitValue != "" {
+		if limit, parseError = strconv.Atoi(limitValue); parseError != nil || limit < 1 || limit > maxAuditRecords {
+			violations = append(violations, "query.limit: must be between 1 and "+strconv.Itoa(maxAuditRecords))
+		}
+	}
+	if len(violations) > 0 {
+		server.encodeErrorResponse(response, http.StatusUnprocessableEntity, api.ErrorCodeInvalidRequest, CreateValidationErrorResponse(violations))
+		return
+	}
+
+	records, queryError := server.guests.AuditLog().Records(filter, limit)
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateGetAuditRecordsResponse(records))
+}
+
+// verifyAuditLog Processes the request to check that no audit record was changed or removed
+func (server *Server) verifyAuditLog(response http.ResponseWriter, _ *http.Request) {
+	verification, queryError := server.guests.AuditLog().Verify()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	if !verification.Valid {
+		server.logger.Printf("Audit log broken at record %d: %s\n", verification.BrokenSequence, verification.Problem)
+	}
+	server.encodeResponse(response, CreateVerifyAuditLogResponse(verification))
+}
+
+// recordAudit Appends the record of a mutation made by a request to the audit log
+//
+// The mutation already happened, a record that cannot be appended is logged instead
+func (server *Server) recordAudit(request *http.Request, operation string, targetID string, before interface{}, after interface{}) {
+	if _, recordError := server.guests.AuditLog().Record(request.Context(), operation, targetID, before, after); recordError != nil {
+		server.logger.Println("Audit record of " + operation + " on " + targetID + " lost: " + recordError.Error())
+	}
+}
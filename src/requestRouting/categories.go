@@ -0,0 +1,83 @@
+package requestRouting
+
+import (
+	"github.com/gorilla/mux"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/guestService"
+	"net/http"
+	"strconv"
+)
+
+// getCategories Processes the request to list the categories of guests with their rules and attendance figures
+func (server *Server) getCategories(response http.ResponseWriter, _ *http.Request) {
+	counts, queryError := server.guests.CategoryCounts()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateCategoriesResponse(counts, server.guests.Categories()))
+}
+
+// setCategoryRules Processes the request to set the rules applying to the guests of a category
+func (server *Server) setCategoryRules(response http.ResponseWriter, request *http.Request) {
+	var requestData api.SetCategoryRulesRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	category := mux.Vars(request)["category"]
+	rules := guestService.CategoryRules{
+		ReservedTable:         requestData.ReservedTable,
+		BypassCapacity:        requestData.BypassCapacity,
+		MaxAccompanyingGuests: guestService.NoEntourageLimit,
+		WaitlistPriority:      server.guests.Categories().Rules(category).WaitlistPriority,
+	}
+	if requestData.MaxAccompanyingGuests != nil {
+		rules.MaxAccompanyingGuests = *requestData.MaxAccompanyingGuests
+	}
+	if requestData.WaitlistPriority != nil {
+		rules.WaitlistPriority = *requestData.WaitlistPriority
+	}
+	if changeError := server.guests.ChangeCategoryRules(request.Context(), category, rules); changeError != nil {
+		server.reportServiceError(response, request, changeError)
+		return
+	}
+	rules = server.guests.Categories().Rules(category)
+	server.logger.Println("Rules of category " + category + " set: reserved table " + strconv.FormatBool(rules.ReservedTable) +
+		", bypass capacity " + strconv.FormatBool(rules.BypassCapacity) + ", entourage limit " + strconv.Itoa(rules.MaxAccompanyingGuests) +
+		", waitlist priority " + strconv.Itoa(rules.WaitlistPriority))
+
+	counts, queryError := server.guests.CategoryCounts()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	for _, count := range counts {
+		if count.Category == category {
+			server.encodeResponse(response, CreateCategoryResponse(count, rules))
+			return
+		}
+	}
+}
+
+// setGuestCategory Processes the request to put a guest in a category of guests
+func (server *Server) setGuestCategory(response http.ResponseWriter, request *http.Request) {
+	var requestData api.SetCategoryRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	guest, found := server.findGuest(response, request)
+	if !found {
+		return
+	}
+
+	guest, setError := server.guests.SetCategory(request.Context(), guest, requestData.Category)
+	if setError != nil {
+		server.reportServiceError(response, request, setError)
+		return
+	}
+	server.encodeResponse(response, CreateAssignRoomResponse(guest))
+}
@@ -1,4 +1,104 @@
 package requestRouting
 
+import (
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/snapshot"
+	"guestListChallenge/src/webhooks"
+	"time"
+)
+
 // networkAddress TCP network address to be used by the HTTP server
 const networkAddress string = ":4242"
+
+// maxRequestBodySize Largest request body accepted, in bytes
+const maxRequestBodySize int64 = 4096
+
+// maxSnapshotSize Largest snapshot accepted for restoration, in bytes
+const maxSnapshotSize int64 = 32 << 20
+
+// minSearchScore Lowest similarity between a search query and a guest name for the guest to be a candidate
+const minSearchScore float64 = 0.6
+
+// maxSearchCandidates Largest number of candidates returned by a guest search
+const maxSearchCandidates int = 10
+
+// maxWebhookDeliveries Largest number of deliveries returned by the delivery log of a webhook
+const maxWebhookDeliveries int = 100
+
+// defaultAuditRecords Number of records returned by the audit log when no limit is given
+const defaultAuditRecords int = 100
+
+// maxAuditRecords Largest number of records returned by the audit log
+const maxAuditRecords int = 1000
+
+// defaultRecentOperations Number of operations listed as recent when no limit is given
+const defaultRecentOperations int = 20
+
+// maxRecentOperations Largest number of operations listed as recent
+const maxRecentOperations int = 100
+
+// defaultOccupancyInterval Length of the intervals of the occupancy report when none is given
+const defaultOccupancyInterval time.Duration = 15 * time.Minute
+
+// minOccupancyInterval Shortest interval of the occupancy report
+const minOccupancyInterval time.Duration = time.Minute
+
+// ticketImageSize Width and height of the QR code ticket images, in pixels
+const ticketImageSize int = 256
+
+// Config HTTP server configuration
+type Config struct {
+	NetworkAddress string
+
+	// EventID Identifier of the party, carried by the tickets so that they cannot be used at another event
+	EventID string
+
+	// TicketKey Server-side key signing the tickets, a random one is generated when empty
+	TicketKey []byte
+
+	// TicketLifetime How long a ticket remains valid after being issued
+	TicketLifetime time.Duration
+
+	// APIKeys Actor using each API key accepted as bearer token, requests are not authenticated when empty
+	APIKeys map[string]string
+
+	// AdminKeys Actor using each admin key, the only keys giving access to the /admin routes, which are closed when empty
+	AdminKeys map[string]string
+
+	// Webhooks Delivery of the guest lifecycle events to the registered webhooks
+	Webhooks webhooks.Config
+
+	// Snapshots Automatic snapshots of the state of the party, saved to a directory
+	Snapshots snapshot.Config
+
+	// NoShows Detection of the guests who did not arrive by the cutoff, whose tables are released
+	NoShows guestService.NoShowConfig
+
+	// WalkIns Admission of people who are not on the guest list at tables with empty seats
+	WalkIns guestService.WalkInConfig
+
+	// Venue Venue-wide occupancy limit enforced at check-in, whatever the seats at the tables
+	Venue guestService.VenueConfig
+
+	// Categories Rules applying to each category of guests: reserved tables, capacity bypass and entourage limits
+	Categories guestService.CategoryConfig
+
+	// Admission Rules deciding who is added to the guest list and let in at the door, checked in order
+	Admission guestService.AdmissionPolicy
+}
+
+// DefaultConfig Returns the HTTP server configuration used by the docker setup
+func DefaultConfig() Config {
+	return Config{
+		NetworkAddress: networkAddress,
+		EventID:        "end-of-year-party",
+		TicketLifetime: 30 * 24 * time.Hour,
+		Webhooks:       webhooks.DefaultConfig(),
+		Snapshots:      snapshot.DefaultConfig(),
+		NoShows:        guestService.DefaultNoShowConfig(),
+		WalkIns:        guestService.DefaultWalkInConfig(),
+		Venue:          guestService.DefaultVenueConfig(),
+		Categories:     guestService.DefaultCategoryConfig(),
+		Admission:      guestService.DefaultAdmissionPolicy(),
+	}
+}
@@ -2,217 +2,520 @@ package requestRouting
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"github.com/gorilla/mux"
+	"github.com/skip2/go-qrcode"
+	"guestListChallenge/src/api"
 	"guestListChallenge/src/database"
-	"guestListChallenge/src/utils"
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/openapi"
+	"mime"
 	"net/http"
+	"path"
+	"strings"
+	"time"
 )
 
 // encodeResponse Encodes an http response
-func encodeResponse(response http.ResponseWriter, reply interface{}) {
+func (server *Server) encodeResponse(response http.ResponseWriter, reply interface{}) {
+	server.encodeResponseWithStatus(response, http.StatusOK, reply)
+}
+
+// encodeErrorResponse Encodes an http error response with the given http status and error code
+func (server *Server) encodeErrorResponse(response http.ResponseWriter, status int, errorCode string, reply interface{}) {
+	response.Header().Set(api.ErrorCodeHeader, errorCode)
+	server.encodeResponseWithStatus(response, status, reply)
+}
+
+// encodeResponseWithStatus Encodes an http response with the given http status
+func (server *Server) encodeResponseWithStatus(response http.ResponseWriter, status int, reply interface{}) {
 	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(status)
 	encoderError := json.NewEncoder(response).Encode(reply)
 	if encoderError != nil {
-		fmt.Println(encoderError.Error())
+		server.logger.Println(encoderError.Error())
 	}
 }
 
-// decodeRequest Decodes an http request and stores the decoded data in a database.GuestList variable
-func decodeRequest(request *http.Request) (guest database.GuestList) {
-	decoderError := json.NewDecoder(request.Body).Decode(&guest)
-	if decoderError != nil {
-		fmt.Println(decoderError.Error())
+// decodeRequest Decodes an http request body into the given request structure
+//
+// Decoding is strict: unknown fields, trailing data and bodies larger than maxRequestBodySize are reported as errors
+func (server *Server) decodeRequest(request *http.Request, requestData interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, request.Body, maxRequestBodySize))
+	decoder.DisallowUnknownFields()
+	if decoderError := decoder.Decode(requestData); decoderError != nil {
+		return decoderError
 	}
-	return
+	if decoder.More() {
+		return errors.New("unexpected data after the JSON value")
+	}
+	return nil
 }
 
-// addGuest Processes the request to add a guest to the guest list
+// reportDecodeError Replies to a request whose body could not be decoded
+func (server *Server) reportDecodeError(response http.ResponseWriter, decoderError error) {
+	server.logger.Println(decoderError.Error())
+	server.encodeErrorResponse(response, http.StatusUnprocessableEntity, api.ErrorCodeInvalidRequest,
+		CreateValidationErrorResponse([]string{"body: " + decoderError.Error()}))
+}
+
+// reportStoreError Replies to a request that failed because of the database
+func (server *Server) reportStoreError(response http.ResponseWriter, storeError error) {
+	server.logger.Println(storeError.Error())
+	server.encodeErrorResponse(response, http.StatusInternalServerError, api.ErrorCodeInternal, "Database unreachable")
+}
+
+// reportServiceError Replies to a request refused by the guest service or that failed because of the database
 //
-// An error is reported if the number of accompanying guests is larger than the table capacity
-func addGuest(response http.ResponseWriter, request *http.Request) {
-	if database.Connector == nil {
-		fmt.Println("Database unreachable")
+// Refusals keep the 200 status and plain message body they had before error codes were introduced
+func (server *Server) reportServiceError(response http.ResponseWriter, request *http.Request, serviceError error) {
+	var refusal *guestService.Error
+	if !errors.As(serviceError, &refusal) {
+		server.reportStoreError(response, serviceError)
 		return
 	}
 
-	if request == nil {
-		fmt.Println("Null request")
-		return
+	server.logger.Println(refusal.Message)
+	if refusal.Rule != "" {
+		response.Header().Set(api.AdmissionRuleHeader, refusal.Rule)
 	}
+	switch refusal.Code {
+	case api.ErrorCodeAmbiguousGuest:
+		server.encodeErrorResponse(response, http.StatusMultipleChoices, refusal.Code,
+			CreateAmbiguousGuestResponse(mux.Vars(request)["name"], refusal.Candidates))
+	case api.ErrorCodeInvalidRequest:
+		server.encodeErrorResponse(response, http.StatusUnprocessableEntity, refusal.Code,
+			CreateValidationErrorResponse([]string{"body: " + refusal.Message}))
+	default:
+		server.encodeErrorResponse(response, http.StatusOK, refusal.Code, refusal.Message)
+	}
+}
 
-	var requestReply interface{}
-
-	guest := decodeRequest(request)
+// reportAdmission Names the admission rule that let a guest in, in the AdmissionRuleHeader of the reply
+func (server *Server) reportAdmission(response http.ResponseWriter, decision guestService.AdmissionDecision) {
+	response.Header().Set(api.AdmissionRuleHeader, decision.Rule)
+}
 
-	// Check table capacity
-	if guest.AccompanyingGuests > guest.Table {
-		requestReply = "Guest will no be added to the guest list: guest's table cannot hold so many people."
-		fmt.Println(requestReply)
+// findGuest Finds the guest a request refers to, by the "id" path variable if present or by the "name" one otherwise
+//
+// When no single guest matches, ok is false and a reply has already been sent:
+// an error message if no guest matches or the list of candidates if several guests share the name
+func (server *Server) findGuest(response http.ResponseWriter, request *http.Request) (guest database.GuestList, ok bool) {
+	var findError error
+	if guestID, byID := mux.Vars(request)["id"]; byID {
+		guest, findError = server.guests.GuestByID(guestID)
 	} else {
+		guest, findError = server.guests.GuestByName(mux.Vars(request)["name"])
+	}
 
-		// Setup guest data
-		guest.Name = mux.Vars(request)["name"]
-		guest.TimeArrived = ""
+	if findError != nil {
+		server.reportServiceError(response, request, findError)
+		return guest, false
+	}
+	return guest, true
+}
 
-		// Add guest data to database
-		database.Connector.Create(&guest)
+// addGuest Processes the request to add a guest to the guest list
+//
+// The admission policy decides if the guest is added, the rule that decided being named in the AdmissionRuleHeader.
+// Guests sharing a name are added as different guests.
+func (server *Server) addGuest(response http.ResponseWriter, request *http.Request) {
+	if request == nil {
+		server.logger.Println("Null request")
+		return
+	}
 
-		requestReply = CreateAddGuestResponse(guest)
+	var requestData api.AddGuestRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
 	}
 
-	encodeResponse(response, requestReply)
+	guest, decision, addError := server.guests.AddGuest(request.Context(), mux.Vars(request)["name"], requestData.Table, requestData.AccompanyingGuests, requestData.Category)
+	if addError != nil {
+		server.reportServiceError(response, request, addError)
+		return
+	}
+
+	server.reportAdmission(response, decision)
+	server.encodeResponse(response, CreateAddGuestResponse(guest))
 }
 
 // getGuestList Processes the request to get the guest list
-func getGuestList(response http.ResponseWriter, _ *http.Request) {
-	if database.Connector == nil {
-		fmt.Println("Database unreachable")
+//
+// With category=<category> only the guests in that category are returned
+func (server *Server) getGuestList(response http.ResponseWriter, request *http.Request) {
+	category, validCategory := server.parseCategoryFilter(response, request)
+	if !validCategory {
 		return
 	}
 
-	var guestList []database.GuestList
-	database.Connector.Find(&guestList)
-	encodeResponse(response, CreateGetGuestListResponse(guestList))
+	guestList, queryError := server.guests.Guests()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	if category != "" {
+		guestList = guestService.GuestsInCategory(guestList, category)
+	}
+	server.encodeResponse(response, CreateGetGuestListResponse(guestList))
 }
 
-// checkInGuest Processes the request that happens when a guest arrives to the party
+// searchGuests Processes the request to search the guest list for a name typed by the door staff
 //
-// An error is reported if the number of accompanying guests is larger than the table capacity
-func checkInGuest(response http.ResponseWriter, request *http.Request) {
-	if database.Connector == nil {
-		fmt.Println("Database unreachable")
+// Candidates are ranked by how close their name is to the query, ignoring case and accents,
+// tolerating typos and matching names that sound alike
+func (server *Server) searchGuests(response http.ResponseWriter, request *http.Request) {
+	guestList, queryError := server.guests.Guests()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
 		return
 	}
 
+	query := request.URL.Query().Get("q")
+	server.encodeResponse(response, CreateSearchGuestsResponse(query, rankGuests(query, guestList)))
+}
+
+// checkInGuest Processes the request that happens when a guest arrives to the party
+//
+// The admission policy decides if the guest comes in, the rule that decided being named in the AdmissionRuleHeader
+func (server *Server) checkInGuest(response http.ResponseWriter, request *http.Request) {
 	if request == nil {
-		fmt.Println("Null request")
+		server.logger.Println("Null request")
 		return
 	}
 
-	var requestReply interface{}
-
-	arrivingGuest := decodeRequest(request)
-	arrivingGuestName := mux.Vars(request)["name"]
+	var arrivingGuest api.CheckInGuestRequest
+	if decoderError := server.decodeRequest(request, &arrivingGuest); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
 
 	// Get guest data from guest list
-	var guest database.GuestList
-	database.Connector.Where("name = ?", arrivingGuestName).Find(&guest)
-
-	// Check if arriving guest is in the checklist
-	if guest.Name == "" {
-		requestReply = "Guest " + arrivingGuestName + " is not in the guest list"
-	} else
-	// Check table capacity
-	if arrivingGuest.AccompanyingGuests > guest.Table {
-		requestReply = "Guest " + arrivingGuestName + " arrived with an entourage bigger than the registered one"
-	} else
-	// Check if guest already checked in
-	if guest.TimeArrived != "" {
-		requestReply = "Guest " + arrivingGuestName + " already checked in"
-	} else {
+	guest, found := server.findGuest(response, request)
+	if !found {
+		return
+	}
 
-		// Update guest data
-		guest.AccompanyingGuests = arrivingGuest.AccompanyingGuests
-		guest.TimeArrived = utils.GetHoursAndMinutesString()
+	guest, decision, checkInError := server.guests.CheckIn(request.Context(), guest, arrivingGuest.AccompanyingGuests, arrivingGuest.Age)
+	if checkInError != nil {
+		server.reportServiceError(response, request, checkInError)
+		return
+	}
+	server.reportAdmission(response, decision)
 
-		// Update guest in the database
-		database.Connector.Save(&guest)
+	server.encodeResponse(response, CreateCheckInGuestResponse(guest))
+}
+
+// getGuestTicket Processes the request to get a guest's invitation ticket
+//
+// The ticket is a QR code image of a signed token that can be scanned at the door
+func (server *Server) getGuestTicket(response http.ResponseWriter, request *http.Request) {
+	guest, found := server.findGuest(response, request)
+	if !found {
+		return
+	}
 
-		requestReply = CreateCheckInGuestResponse(guest)
+	token, issueError := server.tickets.Issue(guest.ID, server.clock.Now().Add(server.config.TicketLifetime))
+	if issueError != nil {
+		server.logger.Println(issueError.Error())
+		server.encodeErrorResponse(response, http.StatusInternalServerError, api.ErrorCodeInternal, "Ticket could not be issued")
+		return
+	}
+
+	ticketImage, encodeError := qrcode.Encode(token, qrcode.Medium, ticketImageSize)
+	if encodeError != nil {
+		server.logger.Println(encodeError.Error())
+		server.encodeErrorResponse(response, http.StatusInternalServerError, api.ErrorCodeInternal, "Ticket could not be issued")
+		return
 	}
 
-	encodeResponse(response, requestReply)
+	response.Header().Set("Content-Type", "image/png")
+	if _, writeError := response.Write(ticketImage); writeError != nil {
+		server.logger.Println(writeError.Error())
+	}
+}
+
+// scanTicket Processes the request that happens when a guest's ticket is scanned at the door
+//
+// The guest is checked in like by checkInGuest. Forged, expired and already used tickets are rejected. A ticket stays used
+// unless the admission policy or the lack of seats refused its guest.
+func (server *Server) scanTicket(response http.ResponseWriter, request *http.Request) {
+	if request == nil {
+		server.logger.Println("Null request")
+		return
+	}
+
+	var requestData api.ScanTicketRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	// Check ticket
+	claims, verifyError := server.tickets.Verify(requestData.Token, server.clock.Now())
+	if verifyError != nil {
+		server.encodeErrorResponse(response, http.StatusForbidden, api.ErrorCodeTicketRejected, "Ticket rejected: "+verifyError.Error())
+		return
+	}
+
+	// Marking the ticket used first lets only one of two scans of the same ticket check the guest in
+	storeError := server.store.MarkTicketUsed(claims.TicketID, claims.GuestID, server.clock.Now())
+	if storeError == database.ErrTicketUsed {
+		server.encodeErrorResponse(response, http.StatusConflict, api.ErrorCodeTicketUsed, "Ticket rejected: ticket was already used")
+		return
+	}
+	if storeError != nil {
+		server.reportStoreError(response, storeError)
+		return
+	}
+
+	// Get guest data from guest list and check them in
+	guest, checkInError := server.guests.GuestByID(claims.GuestID)
+	var decision guestService.AdmissionDecision
+	if checkInError == nil {
+		guest, decision, checkInError = server.guests.CheckIn(request.Context(), guest, requestData.AccompanyingGuests, requestData.Age)
+	}
+	if checkInError != nil {
+		// A ticket refused by the admission policy can be scanned again, once the guest is allowed in
+		if isAdmissionRefusal(checkInError) {
+			if releaseError := server.store.ReleaseTicket(claims.TicketID); releaseError != nil {
+				server.logger.Println("Ticket " + claims.TicketID + " stays used: " + releaseError.Error())
+			}
+		}
+		server.reportServiceError(response, request, checkInError)
+		return
+	}
+
+	server.reportAdmission(response, decision)
+	server.encodeResponse(response, CreateCheckInGuestResponse(guest))
+}
+
+// isAdmissionRefusal Tells whether a check-in was refused by the admission policy or for lack of seats, rather than for
+// the guest being unknown or already in
+func isAdmissionRefusal(checkInError error) bool {
+	var refusal *guestService.Error
+	if !errors.As(checkInError, &refusal) {
+		return false
+	}
+	switch refusal.Code {
+	case api.ErrorCodeEntourageTooBig, api.ErrorCodeTableTooSmall, api.ErrorCodeVenueFull:
+		return true
+	}
+	return refusal.Rule != ""
 }
 
 // checkOutGuest Processes the request that happens when a guest leaves the party
 //
 // When a guest leaves, all their accompanying guests leave as well.
-func checkOutGuest(response http.ResponseWriter, request *http.Request) {
-	if database.Connector == nil {
-		fmt.Println("Database unreachable")
+func (server *Server) checkOutGuest(response http.ResponseWriter, request *http.Request) {
+	if request == nil {
+		server.logger.Println("Null request")
 		return
 	}
 
-	if request == nil {
-		fmt.Println("Null request")
+	// Get guest data from guest list
+	guest, found := server.findGuest(response, request)
+	if !found {
 		return
 	}
 
-	var requestReply interface{}
+	if checkOutError := server.guests.CheckOut(request.Context(), guest); checkOutError != nil {
+		server.reportServiceError(response, request, checkOutError)
+		return
+	}
 
-	// Get guest data from guest list
-	var guest database.GuestList
-	guestName := mux.Vars(request)["name"]
-	database.Connector.Where("name = ?", guestName).Find(&guest)
-
-	// Check if guest is in the checklist
-	if guest.Name == "" {
-		requestReply = "Guest " + guestName + " is not in the guest list"
-	} else
-	// Check if guest checked in
-	if guest.TimeArrived == "" {
-		requestReply = "Guest " + guestName + " has not arrived yet"
-	} else {
+	server.encodeResponse(response, "Guest "+guest.Name+" left the party")
+}
 
-		// Delete checked in guest from database
-		database.Connector.Where("name = ?", guestName).Delete(&guest)
+// deleteGuest Processes the request to remove a guest who has not arrived from the guest list
+//
+// Guests at the party are checked out instead
+func (server *Server) deleteGuest(response http.ResponseWriter, request *http.Request) {
+	if request == nil {
+		server.logger.Println("Null request")
+		return
+	}
 
-		requestReply = "Guest " + guestName + " left the party"
+	// Get guest data from guest list
+	guest, found := server.findGuest(response, request)
+	if !found {
+		return
 	}
 
-	encodeResponse(response, requestReply)
+	if deleteError := server.guests.DeleteGuest(request.Context(), guest); deleteError != nil {
+		server.reportServiceError(response, request, deleteError)
+		return
+	}
+
+	server.encodeResponse(response, "Guest "+guest.Name+" removed from the guest list")
 }
 
 // getArrivedGuests Processes the request to get the list of guests that have arrived to the party
-func getArrivedGuests(response http.ResponseWriter, _ *http.Request) {
-	if database.Connector == nil {
-		fmt.Println("Database unreachable")
+//
+// With at=<RFC 3339 time> the guests that were at the party at that past time are returned,
+// with room=<name> only the guests in that room. Presence in rooms is not recorded over time, both cannot be combined.
+// With category=<category> only the guests in that category are returned.
+func (server *Server) getArrivedGuests(response http.ResponseWriter, request *http.Request) {
+	at, validPointInTime := server.parsePointInTime(response, request)
+	if !validPointInTime {
+		return
+	}
+	room, validRoom := server.parseRoomFilter(response, request)
+	if !validRoom {
+		return
+	}
+	category, validCategory := server.parseCategoryFilter(response, request)
+	if !validCategory {
+		return
+	}
+	if at != nil && room != nil {
+		server.encodeErrorResponse(response, http.StatusUnprocessableEntity, api.ErrorCodeInvalidRequest,
+			CreateValidationErrorResponse([]string{"query.room: cannot be combined with at, presence in rooms is not recorded over time"}))
 		return
 	}
 
-	// Get all guests from database
 	var guestList []database.GuestList
-	database.Connector.Find(&guestList)
-
-	//Only account for guests that checked in
-	guestListIndex := 0
-	for _, guest := range guestList {
-		if guest.TimeArrived != "" {
-			//Copy data and increment index
-			guestList[guestListIndex] = guest
-			guestListIndex++
-		}
+	var queryError error
+	switch {
+	case room != nil:
+		guestList, queryError = server.guests.GuestsInRoom(room.ID)
+	case at != nil:
+		guestList, queryError = server.guests.GuestsAt(*at)
+	default:
+		guestList, queryError = server.guests.ArrivedGuests()
+	}
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	if category != "" {
+		guestList = guestService.GuestsInCategory(guestList, category)
 	}
-	// "Truncate" slice
-	guestList = guestList[:guestListIndex]
 
-	encodeResponse(response, CreateGetArrivedGuestsResponse(guestList))
+	server.encodeResponse(response, CreateGetArrivedGuestsResponse(guestList))
 }
 
-// getArrivedGuests Processes the request to get the number of empty seats
-func getNumberOfEmptySeats(response http.ResponseWriter, _ *http.Request) {
-	if database.Connector == nil {
-		fmt.Println("Database unreachable")
+// getNumberOfEmptySeats Processes the request to get the number of empty seats, and of those that can be given away
+//
+// With at=<RFC 3339 time> the numbers of seats that were empty at that past time are returned,
+// with room=<name> only the seats at the tables in that room are counted
+func (server *Server) getNumberOfEmptySeats(response http.ResponseWriter, request *http.Request) {
+	at, validPointInTime := server.parsePointInTime(response, request)
+	if !validPointInTime {
+		return
+	}
+	room, validRoom := server.parseRoomFilter(response, request)
+	if !validRoom {
 		return
 	}
 
-	numberOfEmptySeats := 0
+	var seats guestService.Seats
+	var queryError error
+	switch {
+	case room != nil && at != nil:
+		seats, queryError = server.guests.SeatsInRoomAt(room.ID, *at)
+	case room != nil:
+		seats, queryError = server.guests.SeatsInRoom(room.ID)
+	case at != nil:
+		seats, queryError = server.guests.SeatsAt(*at)
+	default:
+		seats, queryError = server.guests.Seats()
+	}
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
 
-	// Get all guests from database
-	var guestList []database.GuestList
-	database.Connector.Find(&guestList)
+	server.encodeResponse(response, CreateGetNumberOfEmptySeatsResponse(seats))
+}
 
-	for _, guest := range guestList {
+// parsePointInTime Returns the past time of the at query parameter, nil without one
+//
+// An error response is sent when the parameter is not a past RFC 3339 time, the second result is then false
+func (server *Server) parsePointInTime(response http.ResponseWriter, request *http.Request) (*time.Time, bool) {
+	at, violation := server.parsePastTime(request, "at")
+	if violation != "" {
+		server.encodeErrorResponse(response, http.StatusUnprocessableEntity, api.ErrorCodeInvalidRequest, CreateValidationErrorResponse([]string{violation}))
+		return nil, false
+	}
+	return at, true
+}
 
-		if guest.TimeArrived == "" {
-			// Guest did not check int: empty table
-			numberOfEmptySeats += guest.Table
-		} else {
-			// Guest checked in: check remaining space at table
-			numberOfEmptySeats += guest.Table - guest.AccompanyingGuests
-		}
+// parseRoomFilter Returns the room named by the room query parameter, nil without one
+//
+// An error response is sent when no room has that name, the second result is then false
+func (server *Server) parseRoomFilter(response http.ResponseWriter, request *http.Request) (*database.Room, bool) {
+	name := request.URL.Query().Get("room")
+	if name == "" {
+		return nil, true
 	}
 
-	encodeResponse(response, CreateGetNumberOfEmptySeatsResponse(numberOfEmptySeats))
+	room, findError := server.guests.RoomByName(name)
+	if findError != nil {
+		server.reportServiceError(response, request, findError)
+		return nil, false
+	}
+	return &room, true
+}
+
+// parseCategoryFilter Returns the category of the category query parameter, empty without one
+//
+// An error response is sent when the parameter is not a category of guests, the second result is then false
+func (server *Server) parseCategoryFilter(response http.ResponseWriter, request *http.Request) (string, bool) {
+	category := request.URL.Query().Get("category")
+	if category != "" && !guestService.ValidCategory(category) {
+		server.encodeErrorResponse(response, http.StatusUnprocessableEntity, api.ErrorCodeInvalidRequest,
+			CreateValidationErrorResponse([]string{"query.category: must be one of " + strings.Join(guestService.CategoryNames, ", ")}))
+		return "", false
+	}
+	return category, true
+}
+
+// parsePastTime Returns the past time of a query parameter, nil without one, or the violation of a parameter
+// that is not a past RFC 3339 time
+func (server *Server) parsePastTime(request *http.Request, parameterName string) (*time.Time, string) {
+	value := request.URL.Query().Get(parameterName)
+	if value == "" {
+		return nil, ""
+	}
+
+	parsedTime, parseError := time.Parse(time.RFC3339, value)
+	if parseError != nil {
+		return nil, "query." + parameterName + ": must be an RFC 3339 time"
+	}
+	if parsedTime.After(server.clock.Now()) {
+		return nil, "query." + parameterName + ": must not be in the future"
+	}
+	return &parsedTime, ""
+}
+
+// getOpenAPIDocument Processes the request to get the OpenAPI document describing the service
+func (server *Server) getOpenAPIDocument(response http.ResponseWriter, _ *http.Request) {
+	response.Header().Set("Content-Type", "application/json")
+	if _, writeError := response.Write(openapi.Specification()); writeError != nil {
+		server.logger.Println(writeError.Error())
+	}
+}
+
+// getAPIDocumentation Processes the request to browse the API documentation with Swagger UI
+func (server *Server) getAPIDocumentation(response http.ResponseWriter, _ *http.Request) {
+	response.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, writeError := response.Write(openapi.SwaggerPage()); writeError != nil {
+		server.logger.Println(writeError.Error())
+	}
+}
+
+// getAPIDocumentationAsset Processes the request to get a script or style sheet of the API documentation page
+func (server *Server) getAPIDocumentationAsset(response http.ResponseWriter, request *http.Request) {
+	assetName := mux.Vars(request)["asset"]
+	asset, found := openapi.SwaggerAsset(assetName)
+	if !found {
+		http.NotFound(response, request)
+		return
+	}
+	response.Header().Set("Content-Type", mime.TypeByExtension(path.Ext(assetName)))
+	if _, writeError := response.Write(asset); writeError != nil {
+		server.logger.Println(writeError.Error())
+	}
 }
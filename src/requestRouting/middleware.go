@@ -0,0 +1,116 @@
+package requestRouting
+
+import (
+	"bytes"
+	"github.com/gorilla/mux"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/openapi"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// publicPaths Routes served without API key, so that the API can be discovered
+var publicPaths = map[string]bool{
+	"/openapi.json": true,
+	"/docs":         true,
+}
+
+// publicPathPrefix Prefix of the scripts and style sheets of the documentation page, served without API key too
+const publicPathPrefix = "/docs/"
+
+// adminPathPrefix Prefix of the routes administering the server, served only to requests carrying an admin key
+const adminPathPrefix = "/admin/"
+
+// identifyRequest Middleware telling who sent a request and under which request ID, for the audit log
+//
+// When API keys are configured, requests not carrying one as a bearer token are rejected with a 401 response.
+// Requests to the administration routes not carrying an admin key are rejected with a 403 response, whatever the configuration.
+// The request ID is the client's X-Request-ID if usable, or a generated one, and is sent back in the response.
+func (server *Server) identifyRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		requestID := audit.RequestID(request.Header.Get(api.RequestIDHeader))
+		response.Header().Set(api.RequestIDHeader, requestID)
+
+		actor, authenticated := server.authenticator.Actor(request.Header.Get("Authorization"))
+		if !authenticated && !publicPaths[request.URL.Path] && !strings.HasPrefix(request.URL.Path, publicPathPrefix) {
+			response.Header().Set("WWW-Authenticate", "Bearer")
+			server.encodeErrorResponse(response, http.StatusUnauthorized, api.ErrorCodeUnauthorized, "A valid API key must be sent as bearer token")
+			return
+		}
+		if _, admin := server.authenticator.Admin(request.Header.Get("Authorization")); !admin && strings.HasPrefix(request.URL.Path, adminPathPrefix) {
+			server.encodeErrorResponse(response, http.StatusForbidden, api.ErrorCodeForbidden, "An admin key must be sent as bearer token to administer the server")
+			return
+		}
+
+		ctx := audit.WithOrigin(request.Context(), audit.Origin{Actor: actor, RequestID: requestID})
+		next.ServeHTTP(response, request.WithContext(ctx))
+	})
+}
+
+// validateRequest Middleware rejecting requests whose parameters or body do not match the OpenAPI document
+//
+// Every violation found is reported together in a 422 response instead of reaching the handlers
+func (server *Server) validateRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+
+		// Find the documented operation of the matched route
+		route := mux.CurrentRoute(request)
+		if route == nil {
+			next.ServeHTTP(response, request)
+			return
+		}
+		pathTemplate, templateError := route.GetPathTemplate()
+		if templateError != nil {
+			next.ServeHTTP(response, request)
+			return
+		}
+		operation := server.document.Operation(pathTemplate, request.Method)
+		if operation == nil {
+			next.ServeHTTP(response, request)
+			return
+		}
+
+		violations := server.document.ValidateParameters(operation, mux.Vars(request), request.URL.Query())
+
+		schema, required := server.document.RequestSchema(operation)
+		if schema != nil {
+
+			// Read body and restore it for the handler
+			body, readError := ioutil.ReadAll(http.MaxBytesReader(response, request.Body, maxRequestBodySize))
+			if readError != nil {
+				server.encodeErrorResponse(response, http.StatusRequestEntityTooLarge, api.ErrorCodeRequestTooLarge, "Request body is too large")
+				return
+			}
+			request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			if len(bytes.TrimSpace(body)) == 0 {
+				if required {
+					violations = append(violations, "body: is required")
+				}
+			} else if !hasJSONContentType(request) {
+				server.encodeErrorResponse(response, http.StatusUnsupportedMediaType, api.ErrorCodeUnsupportedMediaType, "Request body must be sent as application/json")
+				return
+			} else if value, decodeError := openapi.DecodeJSON(body); decodeError != nil {
+				violations = append(violations, "body: malformed JSON: "+decodeError.Error())
+			} else {
+				violations = append(violations, server.document.Validate(schema, value)...)
+			}
+		}
+
+		if len(violations) > 0 {
+			server.encodeErrorResponse(response, http.StatusUnprocessableEntity, api.ErrorCodeInvalidRequest, CreateValidationErrorResponse(violations))
+			return
+		}
+
+		next.ServeHTTP(response, request)
+	})
+}
+
+// hasJSONContentType Checks if a request declares a JSON body
+func hasJSONContentType(request *http.Request) bool {
+	mediaType, _, parseError := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	return parseError == nil && mediaType == "application/json"
+}
@@ -0,0 +1,74 @@
+package requestRouting
+
+import (
+	"guestListChallenge/src/api"
+	"net/http"
+	"time"
+)
+
+// getNoShows Processes the request to list the guests marked as no-shows, whose tables are released
+func (server *Server) getNoShows(response http.ResponseWriter, _ *http.Request) {
+	noShows, queryError := server.guests.NoShows()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateNoShowsResponse(server.guests.NoShowCutoff(), noShows))
+}
+
+// releaseNoShows Processes the request to mark every guest who did not arrive as a no-show now, whatever the cutoff
+//
+// Only the guests marked by the request are listed in the reply
+func (server *Server) releaseNoShows(response http.ResponseWriter, request *http.Request) {
+	marked, storeError := server.guests.MarkNoShows(request.Context())
+	if storeError != nil {
+		server.reportStoreError(response, storeError)
+		return
+	}
+	server.encodeResponse(response, CreateNoShowsResponse(server.guests.NoShowCutoff(), marked))
+}
+
+// setNoShowCutoff Processes the request to set the time after which guests who did not arrive are marked as no-shows
+func (server *Server) setNoShowCutoff(response http.ResponseWriter, request *http.Request) {
+	var requestData api.SetNoShowCutoffRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	cutoff := time.Time{}
+	if requestData.Cutoff != nil {
+		cutoff = *requestData.Cutoff
+	}
+	if changeError := server.guests.ChangeNoShowCutoff(request.Context(), cutoff); changeError != nil {
+		server.reportStoreError(response, changeError)
+		return
+	}
+	if cutoff := server.guests.NoShowCutoff(); cutoff.IsZero() {
+		server.logger.Println("No-show detection disabled")
+	} else {
+		server.logger.Println("No-show cutoff set to " + cutoff.Format(time.RFC3339))
+	}
+	server.encodeResponse(response, CreateNoShowCutoffResponse(server.guests.NoShowCutoff()))
+}
+
+// setGuestNoShow Processes the request to mark a guest who did not arrive as a no-show, or to hold their reservation
+func (server *Server) setGuestNoShow(response http.ResponseWriter, request *http.Request) {
+	var requestData api.SetNoShowRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	guest, found := server.findGuest(response, request)
+	if !found {
+		return
+	}
+
+	guest, setError := server.guests.SetNoShow(request.Context(), guest, requestData.NoShow)
+	if setError != nil {
+		server.reportServiceError(response, request, setError)
+		return
+	}
+	server.encodeResponse(response, CreateNoShowGuestResponse(guest))
+}
@@ -0,0 +1,67 @@
+package requestRouting
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/guestService"
+	"net/http"
+	"strconv"
+)
+
+// getOperations Processes the request to list the latest operations on guests, so that door staff can undo a mistake
+func (server *Server) getOperations(response http.ResponseWriter, request *http.Request) {
+	limit := defaultRecentOperations
+	if limitValue := request.URL.Query().Get("limit"); limitValue != "" {
+		var parseError error
+		if limit, parseError = strconv.Atoi(limitValue); parseError != nil || limit < 1 || limit > maxRecentOperations {
+			server.encodeErrorResponse(response, http.StatusUnprocessableEntity, api.ErrorCodeInvalidRequest,
+				CreateValidationErrorResponse([]string{"query.limit: must be between 1 and " + strconv.Itoa(maxRecentOperations)}))
+			return
+		}
+	}
+
+	operations, queryError := server.guests.RecentOperations(limit)
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateGetOperationsResponse(operations))
+}
+
+// undoOperation Processes the request to revert an operation on a guest
+//
+// A conflict listing the later operations on the guest is reported if the guest changed since the operation
+func (server *Server) undoOperation(response http.ResponseWriter, request *http.Request) {
+	operationID, parseError := strconv.ParseInt(mux.Vars(request)["id"], 10, 64)
+	if parseError != nil {
+		server.encodeErrorResponse(response, http.StatusUnprocessableEntity, api.ErrorCodeInvalidRequest,
+			CreateValidationErrorResponse([]string{"path.id: must be an operation ID"}))
+		return
+	}
+
+	undoing, undoError := server.guests.Undo(request.Context(), operationID)
+	if undoError != nil {
+		var refusal *guestService.Error
+		if !errors.As(undoError, &refusal) {
+			server.reportStoreError(response, undoError)
+			return
+		}
+
+		server.logger.Println(refusal.Message)
+		if refusal.Rule != "" {
+			response.Header().Set(api.AdmissionRuleHeader, refusal.Rule)
+		}
+		switch refusal.Code {
+		case api.ErrorCodeOperationNotFound:
+			server.encodeErrorResponse(response, http.StatusNotFound, refusal.Code, refusal.Message)
+		case api.ErrorCodeUndoConflict:
+			server.encodeErrorResponse(response, http.StatusConflict, refusal.Code, CreateUndoConflictResponse(refusal.Message, refusal.Conflicts))
+		default:
+			server.encodeErrorResponse(response, http.StatusUnprocessableEntity, refusal.Code, refusal.Message)
+		}
+		return
+	}
+
+	server.encodeResponse(response, CreateUndoResponse(operationID, undoing))
+}
@@ -0,0 +1,77 @@
+package requestRouting
+
+import (
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/reports"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// getOccupancyReport Processes the request to get the occupancy report of the party
+//
+// The report covers from the from query parameter, or the first check-in, until the until query parameter, or now,
+// by intervals of the interval query parameter
+func (server *Server) getOccupancyReport(response http.ResponseWriter, request *http.Request) {
+	occupancy, ok := server.occupancyReport(response, request)
+	if !ok {
+		return
+	}
+	server.encodeResponse(response, CreateOccupancyReportResponse(occupancy))
+}
+
+// getOccupancyChart Processes the request to get the occupancy report of the party as an HTML page with an SVG chart
+//
+// Takes the same query parameters as getOccupancyReport
+func (server *Server) getOccupancyChart(response http.ResponseWriter, request *http.Request) {
+	occupancy, ok := server.occupancyReport(response, request)
+	if !ok {
+		return
+	}
+	response.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if renderError := reports.RenderHTML(response, occupancy, server.config.EventID); renderError != nil {
+		server.logger.Println(renderError.Error())
+	}
+}
+
+// occupancyReport Computes the occupancy report asked for by a request
+//
+// When the query parameters are invalid or the report cannot be computed, ok is false and a reply has already been sent
+func (server *Server) occupancyReport(response http.ResponseWriter, request *http.Request) (occupancy reports.Occupancy, ok bool) {
+	var violations []string
+	from, violation := server.parsePastTime(request, "from")
+	if violation != "" {
+		violations = append(violations, violation)
+	}
+	until := server.clock.Now()
+	if untilValue, violation := server.parsePastTime(request, "until"); violation != "" {
+		violations = append(violations, violation)
+	} else if untilValue != nil {
+		until = *untilValue
+	}
+	interval := defaultOccupancyInterval
+	if intervalValue := request.URL.Query().Get("interval"); intervalValue != "" {
+		var parseError error
+		if interval, parseError = time.ParseDuration(intervalValue); parseError != nil || interval < minOccupancyInterval {
+			violations = append(violations, "query.interval: must be a duration of at least "+minOccupancyInterval.String())
+		}
+	}
+	if len(violations) == 0 && from != nil {
+		if !from.Before(until) {
+			violations = append(violations, "query.from: must be before until")
+		} else if (reports.Window{From: *from, Until: until, Interval: interval}).Bins() > reports.MaxBins {
+			violations = append(violations, "query.interval: splits the report into more than "+strconv.Itoa(reports.MaxBins)+" intervals")
+		}
+	}
+	if len(violations) > 0 {
+		server.encodeErrorResponse(response, http.StatusUnprocessableEntity, api.ErrorCodeInvalidRequest, CreateValidationErrorResponse(violations))
+		return occupancy, false
+	}
+
+	occupancy, queryError := server.guests.Occupancy(from, until, interval)
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return occupancy, false
+	}
+	return occupancy, true
+}
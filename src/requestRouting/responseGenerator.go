@@ -1,78 +1,477 @@
 package requestRouting
 
 import (
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
 	"guestListChallenge/src/database"
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/reports"
+	"guestListChallenge/src/snapshot"
+	"guestListChallenge/src/webhooks"
+	"math"
+	"time"
 )
 
 // CreateAddGuestResponse Creates a response for "add a guest to the guest list" requests
-//
-// A struct with the appropriate fields and json tags is used
-func CreateAddGuestResponse(guest database.GuestList) interface{} {
-	return struct {
-		Name string `json:"name"`
-	}{Name: guest.Name}
+func CreateAddGuestResponse(guest database.GuestList) api.GuestResponse {
+	return api.GuestResponse{ID: guest.ID, Name: guest.Name}
 }
 
 // CreateGetGuestListResponse Creates a response for "get the guest list" requests
+func CreateGetGuestListResponse(guestList []database.GuestList) api.GuestListResponse {
+
+	// Populate guest data array
+	guestDataArray := make([]api.ListedGuest, 0, len(guestList))
+	for _, guest := range guestList {
+		guestDataArray = append(guestDataArray, api.ListedGuest{
+			ID:                 guest.ID,
+			Name:               guest.Name,
+			Table:              guest.Table,
+			AccompanyingGuests: guest.AccompanyingGuests,
+			WalkIn:             guest.WalkIn,
+			SeatedWith:         guest.SeatedWith,
+			RoomID:             guest.RoomID,
+			Category:           guest.Category,
+		})
+	}
+
+	return api.GuestListResponse{Guests: guestDataArray}
+}
+
+// CreateSearchGuestsResponse Creates a response for "search the guest list" requests
 //
-// A struct with the appropriate fields and json tags is used
-func CreateGetGuestListResponse(guestList []database.GuestList) interface{} {
+// The top match is repeated apart so that door staff can confirm it before checking the guest in.
+func CreateSearchGuestsResponse(query string, matches []GuestMatch) api.SearchGuestsResponse {
 
-	// Guest data to send in the response
-	type guestData struct {
-		Name               string `json:"name"`
-		Table              int    `json:"table"`
-		AccompanyingGuests int    `json:"accompanying_guests"`
+	// Populate candidate data array
+	candidateDataArray := make([]api.SearchCandidate, 0, len(matches))
+	for _, match := range matches {
+		candidateDataArray = append(candidateDataArray, api.SearchCandidate{
+			ID:                 match.Guest.ID,
+			Name:               match.Guest.Name,
+			Table:              match.Guest.Table,
+			AccompanyingGuests: match.Guest.AccompanyingGuests,
+			Arrived:            match.Guest.TimeArrived != "",
+			Score:              math.Round(match.Score*100) / 100,
+		})
 	}
 
-	// Populate guest data array
-	guestDataArray := make([]guestData, 0, len(guestList))
-	for _, guest := range guestList {
-		guestDataArray = append(guestDataArray, guestData{guest.Name, guest.Table, guest.AccompanyingGuests})
+	var topMatch *api.SearchCandidate
+	if len(candidateDataArray) > 0 {
+		topMatch = &candidateDataArray[0]
 	}
 
-	return struct {
-		Guests []guestData `json:"guests"`
-	}{Guests: guestDataArray}
+	return api.SearchGuestsResponse{Query: query, TopMatch: topMatch, Candidates: candidateDataArray}
 }
 
 // CreateCheckInGuestResponse Creates a response for "guest arrives to the party" requests
-//
-// A struct with the appropriate fields and json tags is used
-func CreateCheckInGuestResponse(guest database.GuestList) interface{} {
-	return struct {
-		Name string `json:"name"`
-	}{Name: guest.Name}
+func CreateCheckInGuestResponse(guest database.GuestList) api.GuestResponse {
+	return api.GuestResponse{ID: guest.ID, Name: guest.Name}
 }
 
 // CreateGetArrivedGuestsResponse Creates a response for "get list of guests that have arrived to the party" requests
-//
-// A struct with the appropriate fields and json tags is used
-func CreateGetArrivedGuestsResponse(guestList []database.GuestList) interface{} {
-
-	// Guest data to send in the response
-	type guestData struct {
-		Name               string `json:"name"`
-		AccompanyingGuests int    `json:"accompanying_guests"`
-		TimeArrived        string `json:"time_arrived"`
-	}
+func CreateGetArrivedGuestsResponse(guestList []database.GuestList) api.ArrivedGuestsResponse {
 
 	// Populate guest data array
-	guestDataArray := make([]guestData, 0, len(guestList))
+	guestDataArray := make([]api.ArrivedGuest, 0, len(guestList))
 	for _, guest := range guestList {
-		guestDataArray = append(guestDataArray, guestData{guest.Name, guest.AccompanyingGuests, guest.TimeArrived})
+		guestDataArray = append(guestDataArray, CreateRoomGuestResponse(guest))
 	}
 
-	return struct {
-		Guests []guestData `json:"guests"`
-	}{Guests: guestDataArray}
+	return api.ArrivedGuestsResponse{Guests: guestDataArray}
 }
 
 // CreateGetNumberOfEmptySeatsResponse Creates a response for "get the number of empty seats" requests
-//
-// A struct with the appropriate fields and json tags is used
-func CreateGetNumberOfEmptySeatsResponse(seatsEmpty int) interface{} {
-	return struct {
-		SeatsEmpty int `json:"seats_empty"`
-	}{SeatsEmpty: seatsEmpty}
+func CreateGetNumberOfEmptySeatsResponse(seats guestService.Seats) api.EmptySeatsResponse {
+	return api.EmptySeatsResponse{SeatsEmpty: seats.Empty, SeatsAvailable: seats.Available}
+}
+
+// CreateAmbiguousGuestResponse Creates a response for requests naming a guest when several guests share that name
+func CreateAmbiguousGuestResponse(name string, guestList []database.GuestList) api.AmbiguousGuestResponse {
+
+	// Populate candidate data array
+	candidateDataArray := make([]api.AmbiguousGuestCandidate, 0, len(guestList))
+	for _, guest := range guestList {
+		candidateDataArray = append(candidateDataArray, api.AmbiguousGuestCandidate{ID: guest.ID, Name: guest.Name, Table: guest.Table})
+	}
+
+	return api.AmbiguousGuestResponse{
+		Error:      "Several guests are called " + name + ": use /guests/id/{id} to choose one",
+		Candidates: candidateDataArray,
+	}
+}
+
+// CreateValidationErrorResponse Creates a response for requests whose body does not match the OpenAPI document
+func CreateValidationErrorResponse(violations []string) api.ValidationErrorResponse {
+	return api.ValidationErrorResponse{Error: "Invalid request body", Violations: violations}
+}
+
+// CreateWebhookResponse Creates a response for "register a webhook" requests
+func CreateWebhookResponse(webhook database.Webhook) api.WebhookResponse {
+	return api.WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    webhooks.SplitEvents(webhook.Events),
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+// CreateGetWebhooksResponse Creates a response for "list the webhooks" requests
+func CreateGetWebhooksResponse(webhookList []database.Webhook) api.WebhookListResponse {
+	webhookDataArray := make([]api.WebhookResponse, 0, len(webhookList))
+	for _, webhook := range webhookList {
+		webhookDataArray = append(webhookDataArray, CreateWebhookResponse(webhook))
+	}
+	return api.WebhookListResponse{Webhooks: webhookDataArray}
+}
+
+// CreateGetWebhookDeliveriesResponse Creates a response for "get the delivery log of a webhook" requests
+func CreateGetWebhookDeliveriesResponse(deliveries []database.WebhookDelivery) api.WebhookDeliveriesResponse {
+	deliveryDataArray := make([]api.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryData := api.WebhookDelivery{
+			ID:             delivery.ID,
+			Event:          delivery.Event,
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			LastStatusCode: delivery.LastStatusCode,
+			LastError:      delivery.LastError,
+			CreatedAt:      delivery.CreatedAt,
+			DeliveredAt:    delivery.DeliveredAt,
+		}
+		if delivery.Status == database.DeliveryPending {
+			nextAttemptAt := delivery.NextAttemptAt
+			deliveryData.NextAttemptAt = &nextAttemptAt
+		}
+		deliveryDataArray = append(deliveryDataArray, deliveryData)
+	}
+	return api.WebhookDeliveriesResponse{Deliveries: deliveryDataArray}
+}
+
+// CreateGetAuditRecordsResponse Creates a response for "get the audit log" requests
+func CreateGetAuditRecordsResponse(records []database.AuditRecord) api.AuditRecordsResponse {
+	recordDataArray := make([]api.AuditRecord, 0, len(records))
+	for _, record := range records {
+		recordDataArray = append(recordDataArray, api.AuditRecord{
+			Sequence:     record.Sequence,
+			Time:         record.Time,
+			Actor:        record.Actor,
+			RequestID:    record.RequestID,
+			Operation:    record.Operation,
+			TargetID:     record.TargetID,
+			Before:       json.RawMessage(record.Before),
+			After:        json.RawMessage(record.After),
+			PreviousHash: record.PreviousHash,
+			Hash:         record.Hash,
+		})
+	}
+	return api.AuditRecordsResponse{Records: recordDataArray}
+}
+
+// CreateVerifyAuditLogResponse Creates a response for "verify the audit log" requests
+func CreateVerifyAuditLogResponse(verification audit.Verification) api.AuditVerificationResponse {
+	return api.AuditVerificationResponse{
+		Valid:          verification.Valid,
+		Records:        verification.Records,
+		LastHash:       verification.LastHash,
+		BrokenSequence: verification.BrokenSequence,
+		Problem:        verification.Problem,
+	}
+}
+
+// CreateOperationResponse Creates the representation of an operation on a guest
+func CreateOperationResponse(operation guestService.Operation) api.Operation {
+	return api.Operation{
+		ID:        operation.Record.Sequence,
+		Time:      operation.Record.Time,
+		Actor:     operation.Record.Actor,
+		Operation: operation.Record.Operation,
+		GuestID:   operation.Record.TargetID,
+		GuestName: operation.Guest.Name,
+		Undoable:  operation.Undoable,
+	}
+}
+
+// CreateGetOperationsResponse Creates a response for "get the recent operations" requests
+func CreateGetOperationsResponse(operations []guestService.Operation) api.OperationsResponse {
+	operationDataArray := make([]api.Operation, 0, len(operations))
+	for _, operation := range operations {
+		operationDataArray = append(operationDataArray, CreateOperationResponse(operation))
+	}
+	return api.OperationsResponse{Operations: operationDataArray}
+}
+
+// CreateUndoResponse Creates a response for "undo an operation" requests
+func CreateUndoResponse(undoneID int64, undoing guestService.Operation) api.UndoResponse {
+	return api.UndoResponse{Undone: undoneID, Operation: CreateOperationResponse(undoing)}
+}
+
+// CreateUndoConflictResponse Creates a response for "undo an operation" requests conflicting with later changes
+func CreateUndoConflictResponse(message string, conflicts []guestService.Operation) api.UndoConflictResponse {
+	return api.UndoConflictResponse{Error: message, Conflicts: CreateGetOperationsResponse(conflicts).Operations}
+}
+
+// CreateRestoreSnapshotResponse Creates a response for "restore a snapshot" requests
+func CreateRestoreSnapshotResponse(restoration snapshot.Restoration) api.RestoreSnapshotResponse {
+	recordChanges := func(changes snapshot.Changes) api.RecordChanges {
+		return api.RecordChanges{Added: changes.Added, Removed: changes.Removed, Changed: changes.Changed}
+	}
+
+	return api.RestoreSnapshotResponse{
+		DryRun: restoration.DryRun,
+		Snapshot: api.Snapshot{
+			FormatVersion: restoration.Archive.FormatVersion,
+			SchemaVersion: restoration.Archive.SchemaVersion,
+			EventID:       restoration.Archive.EventID,
+			CreatedAt:     restoration.Archive.CreatedAt,
+			Checksum:      restoration.Archive.Checksum,
+			Guests:        len(restoration.State.Guests),
+			Visits:        len(restoration.State.Visits),
+			UsedTickets:   len(restoration.State.UsedTickets),
+			Webhooks:      len(restoration.State.Webhooks),
+			Rooms:         len(restoration.State.Rooms),
+			RoomAccess:    len(restoration.State.RoomAccess),
+			Settings:      len(restoration.State.Settings),
+			Waitlist:      len(restoration.State.Waitlist),
+		},
+		Changes: api.SnapshotChanges{
+			Guests:      recordChanges(restoration.Diff.Guests),
+			Visits:      recordChanges(restoration.Diff.Visits),
+			UsedTickets: recordChanges(restoration.Diff.UsedTickets),
+			Webhooks:    recordChanges(restoration.Diff.Webhooks),
+			Rooms:       recordChanges(restoration.Diff.Rooms),
+			RoomAccess:  recordChanges(restoration.Diff.RoomAccess),
+			Settings:    recordChanges(restoration.Diff.Settings),
+			Waitlist:    recordChanges(restoration.Diff.Waitlist),
+		},
+		WebhooksWithoutSecret: append([]string{}, restoration.WebhooksWithoutSecret...),
+	}
+}
+
+// CreateOccupancyReportResponse Creates a response for "get the occupancy report" requests
+func CreateOccupancyReportResponse(occupancy reports.Occupancy) api.OccupancyReportResponse {
+	reportResponse := api.OccupancyReportResponse{
+		From:               occupancy.Window.From,
+		Until:              occupancy.Window.Until,
+		IntervalSeconds:    int64(occupancy.Window.Interval.Seconds()),
+		Timeline:           make([]api.OccupancyBin, 0, len(occupancy.Timeline)),
+		Peak:               occupancy.Peak,
+		PeakAt:             occupancy.PeakAt,
+		Tables:             make([]api.TableUtilisation, 0, len(occupancy.Tables)),
+		Visits:             occupancy.Visits,
+		AverageStaySeconds: int64(occupancy.AverageStay.Seconds()),
+		Invited:            occupancy.Invited,
+		NoShows:            occupancy.NoShows,
+		NoShowRate:         math.Round(occupancy.NoShowRate()*1000) / 1000,
+		WalkIns:            occupancy.WalkIns,
+	}
+	for _, bin := range occupancy.Timeline {
+		reportResponse.Timeline = append(reportResponse.Timeline, api.OccupancyBin{Start: bin.Start, Present: bin.Present, Arrivals: bin.Arrivals, Departures: bin.Departures})
+	}
+	for _, table := range occupancy.Tables {
+		reportResponse.Tables = append(reportResponse.Tables, api.TableUtilisation{
+			GuestID:     table.GuestID,
+			Name:        table.Name,
+			Seats:       table.Seats,
+			Utilisation: math.Round(table.Utilisation*1000) / 1000,
+		})
+	}
+	return reportResponse
+}
+
+// CreateNoShowGuestResponse Creates a response for "mark a guest as a no-show" requests
+func CreateNoShowGuestResponse(guest database.GuestList) api.NoShowGuest {
+	return api.NoShowGuest{ID: guest.ID, Name: guest.Name, Table: guest.Table, NoShowAt: guest.NoShowAt, ReservationHeld: guest.ReservationHeld}
+}
+
+// CreateNoShowsResponse Creates a response for "get the no-shows" and "release the tables of the guests who did not arrive" requests
+func CreateNoShowsResponse(cutoff time.Time, noShows []database.GuestList) api.NoShowsResponse {
+	noShowsResponse := api.NoShowsResponse{Cutoff: CreateNoShowCutoffResponse(cutoff).Cutoff, Guests: make([]api.NoShowGuest, 0, len(noShows))}
+	for _, guest := range noShows {
+		noShowsResponse.SeatsReleased += guest.Table
+		noShowsResponse.Guests = append(noShowsResponse.Guests, CreateNoShowGuestResponse(guest))
+	}
+	return noShowsResponse
+}
+
+// CreateNoShowCutoffResponse Creates a response for "set the no-show cutoff" requests
+func CreateNoShowCutoffResponse(cutoff time.Time) api.NoShowCutoffResponse {
+	if cutoff.IsZero() {
+		return api.NoShowCutoffResponse{}
+	}
+	return api.NoShowCutoffResponse{Cutoff: &cutoff}
+}
+
+// CreateWalkInResponse Creates a response for "admit a walk-in" requests
+func CreateWalkInResponse(walkIn database.GuestList) api.WalkInResponse {
+	return api.WalkInResponse{
+		ID:                 walkIn.ID,
+		Name:               walkIn.Name,
+		AccompanyingGuests: walkIn.AccompanyingGuests,
+		TimeArrived:        walkIn.TimeArrived,
+		SeatedWith:         walkIn.SeatedWith,
+	}
+}
+
+// CreateWalkInsResponse Creates a response for "get the walk-ins" requests
+func CreateWalkInsResponse(walkIns guestService.WalkIns) api.WalkInsResponse {
+	walkInsResponse := api.WalkInsResponse{
+		Cap:        CreateWalkInCapResponse(walkIns.Cap).Cap,
+		Admitted:   walkIns.Admitted,
+		WalkIns:    make([]api.WalkInResponse, 0, len(walkIns.Guests)),
+		FreeTables: make([]api.FreeTable, 0, len(walkIns.FreeTables)),
+	}
+	for _, walkIn := range walkIns.Guests {
+		walkInsResponse.WalkIns = append(walkInsResponse.WalkIns, CreateWalkInResponse(walkIn))
+	}
+	for _, table := range walkIns.FreeTables {
+		walkInsResponse.FreeTables = append(walkInsResponse.FreeTables, api.FreeTable{GuestID: table.Guest.ID, Name: table.Guest.Name, EmptySeats: table.Seats})
+	}
+	return walkInsResponse
+}
+
+// CreateWalkInCapResponse Creates a response for "set the walk-in cap" requests
+func CreateWalkInCapResponse(cap int) api.WalkInCapResponse {
+	if cap == guestService.NoWalkInCap {
+		return api.WalkInCapResponse{}
+	}
+	return api.WalkInCapResponse{Cap: &cap}
+}
+
+// CreateHeadcountResponse Creates a response for "get the live headcount" and "set the venue capacity" requests
+func CreateHeadcountResponse(headcount guestService.Headcount) api.HeadcountResponse {
+	headcountResponse := api.HeadcountResponse{
+		Headcount:         headcount.People,
+		Level:             headcount.Level(),
+		WarningThreshold:  headcount.Venue.WarningThreshold,
+		CriticalThreshold: headcount.Venue.CriticalThreshold,
+	}
+	if headcount.Venue.Capacity > 0 {
+		capacity, remaining := headcount.Venue.Capacity, headcount.Remaining()
+		headcountResponse.Capacity, headcountResponse.Remaining = &capacity, &remaining
+	}
+	return headcountResponse
+}
+
+// CreateRoomGuestResponse Creates a response for "guest enters a room" and "guest leaves a room" requests
+func CreateRoomGuestResponse(guest database.GuestList) api.ArrivedGuest {
+	return api.ArrivedGuest{
+		ID:                 guest.ID,
+		Name:               guest.Name,
+		AccompanyingGuests: guest.AccompanyingGuests,
+		TimeArrived:        guest.TimeArrived,
+		WalkIn:             guest.WalkIn,
+		SeatedWith:         guest.SeatedWith,
+		RoomID:             guest.RoomID,
+		InRoom:             guest.InRoom,
+		Category:           guest.Category,
+	}
+}
+
+// CreateAssignRoomResponse Creates a response for "put a guest's table in a room" and "put a guest in a category" requests
+func CreateAssignRoomResponse(guest database.GuestList) api.ListedGuest {
+	return api.ListedGuest{
+		ID:                 guest.ID,
+		Name:               guest.Name,
+		Table:              guest.Table,
+		AccompanyingGuests: guest.AccompanyingGuests,
+		WalkIn:             guest.WalkIn,
+		SeatedWith:         guest.SeatedWith,
+		RoomID:             guest.RoomID,
+		Category:           guest.Category,
+	}
+}
+
+// CreateRoomResponse Creates a response for "add a room" and "set the capacity of a room" requests
+func CreateRoomResponse(occupancy guestService.RoomOccupancy) api.RoomResponse {
+	roomResponse := api.RoomResponse{
+		ID:             occupancy.Room.ID,
+		Name:           occupancy.Room.Name,
+		Restricted:     occupancy.Room.Restricted,
+		Headcount:      occupancy.People,
+		Tables:         occupancy.Tables,
+		SeatsEmpty:     occupancy.Seats.Empty,
+		SeatsAvailable: occupancy.Seats.Available,
+	}
+	if occupancy.Room.Capacity > 0 {
+		capacity, remaining := occupancy.Room.Capacity, occupancy.Remaining()
+		roomResponse.Capacity, roomResponse.Remaining = &capacity, &remaining
+	}
+	return roomResponse
+}
+
+// CreateRoomsResponse Creates a response for "list the rooms" requests
+func CreateRoomsResponse(occupancies []guestService.RoomOccupancy) api.RoomsResponse {
+	rooms := make([]api.RoomResponse, 0, len(occupancies))
+	for _, occupancy := range occupancies {
+		rooms = append(rooms, CreateRoomResponse(occupancy))
+	}
+	return api.RoomsResponse{Rooms: rooms}
+}
+
+// CreateCategoryResponse Creates a response for "set the rules of a category" requests
+func CreateCategoryResponse(count guestService.CategoryCount, rules guestService.CategoryRules) api.CategoryResponse {
+	categoryResponse := api.CategoryResponse{
+		Category:         count.Category,
+		ReservedTable:    rules.ReservedTable,
+		BypassCapacity:   rules.BypassCapacity,
+		WaitlistPriority: rules.WaitlistPriority,
+		Guests:           count.Guests,
+		ArrivedGuests:    count.ArrivedGuests,
+		PeopleAtParty:    count.PeopleAtParty,
+	}
+	if rules.MaxAccompanyingGuests != guestService.NoEntourageLimit {
+		maxAccompanyingGuests := rules.MaxAccompanyingGuests
+		categoryResponse.MaxAccompanyingGuests = &maxAccompanyingGuests
+	}
+	return categoryResponse
+}
+
+// CreateCategoriesResponse Creates a response for "list the categories" requests
+func CreateCategoriesResponse(counts []guestService.CategoryCount, categories guestService.CategoryConfig) api.CategoriesResponse {
+	categoryResponses := make([]api.CategoryResponse, 0, len(counts))
+	for _, count := range counts {
+		categoryResponses = append(categoryResponses, CreateCategoryResponse(count, categories.Rules(count.Category)))
+	}
+	return api.CategoriesResponse{Categories: categoryResponses}
+}
+
+// CreateWaitlistEntryResponse Creates a response for "join the waitlist" and "leave the waitlist" requests
+func CreateWaitlistEntryResponse(entry database.WaitlistEntry, categories guestService.CategoryConfig) api.WaitlistEntryResponse {
+	return api.WaitlistEntryResponse{
+		ID:                 entry.ID,
+		Name:               entry.Name,
+		AccompanyingGuests: entry.AccompanyingGuests,
+		Category:           entry.Category,
+		Priority:           categories.Rules(entry.Category).WaitlistPriority,
+		JoinedAt:           entry.JoinedAt,
+	}
+}
+
+// CreateWaitlistResponse Creates a response for "get the waitlist" requests
+func CreateWaitlistResponse(entries []database.WaitlistEntry, categories guestService.CategoryConfig) api.WaitlistResponse {
+	entryResponses := make([]api.WaitlistEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		entryResponses = append(entryResponses, CreateWaitlistEntryResponse(entry, categories))
+	}
+	return api.WaitlistResponse{Waitlist: entryResponses}
+}
+
+// CreateSeatWaitlistResponse Creates a response for "seat the waitlist" requests
+func CreateSeatWaitlistResponse(seated []database.GuestList, entries []database.WaitlistEntry, categories guestService.CategoryConfig) api.SeatWaitlistResponse {
+	seatedResponses := make([]api.WalkInResponse, 0, len(seated))
+	for _, walkIn := range seated {
+		seatedResponses = append(seatedResponses, CreateWalkInResponse(walkIn))
+	}
+	return api.SeatWaitlistResponse{Seated: seatedResponses, Waitlist: CreateWaitlistResponse(entries, categories).Waitlist}
+}
+
+// CreateAdmissionRulesResponse Creates a response for "list the admission rules" and "set the admission rules" requests
+func CreateAdmissionRulesResponse(policy guestService.AdmissionPolicy) api.AdmissionRulesResponse {
+	rules := make([]api.AdmissionRuleResponse, 0, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		rules = append(rules, api.AdmissionRuleResponse{Rule: rule.Name(), Description: rule.Describe()})
+	}
+	return api.AdmissionRulesResponse{Event: policy.Event, Rules: rules}
 }
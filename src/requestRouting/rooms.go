@@ -0,0 +1,209 @@
+package requestRouting
+
+import (
+	"github.com/gorilla/mux"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/database"
+	"net/http"
+)
+
+// findRoom Finds the room a request refers to by the "room" path variable
+//
+// When no room matches, ok is false and an error message has already been sent
+func (server *Server) findRoom(response http.ResponseWriter, request *http.Request) (room database.Room, ok bool) {
+	room, findError := server.guests.RoomByName(mux.Vars(request)["room"])
+	if findError != nil {
+		server.reportServiceError(response, request, findError)
+		return room, false
+	}
+	return room, true
+}
+
+// addRoom Processes the request to add a room to the venue
+func (server *Server) addRoom(response http.ResponseWriter, request *http.Request) {
+	var requestData api.AddRoomRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	capacity := 0
+	if requestData.Capacity != nil {
+		capacity = *requestData.Capacity
+	}
+	room, addError := server.guests.AddRoom(request.Context(), mux.Vars(request)["room"], capacity, requestData.Restricted)
+	if addError != nil {
+		server.reportServiceError(response, request, addError)
+		return
+	}
+
+	occupancy, queryError := server.guests.RoomOccupancy(room)
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateRoomResponse(occupancy))
+}
+
+// getRooms Processes the request to list the rooms of the venue with the people in them and the seats at their tables
+func (server *Server) getRooms(response http.ResponseWriter, _ *http.Request) {
+	occupancies, queryError := server.guests.Rooms()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateRoomsResponse(occupancies))
+}
+
+// deleteRoom Processes the request to remove a room from the venue
+func (server *Server) deleteRoom(response http.ResponseWriter, request *http.Request) {
+	room, found := server.findRoom(response, request)
+	if !found {
+		return
+	}
+
+	if deleteError := server.guests.DeleteRoom(request.Context(), room); deleteError != nil {
+		server.reportServiceError(response, request, deleteError)
+		return
+	}
+	server.encodeResponse(response, "Room "+room.Name+" removed")
+}
+
+// setRoomCapacity Processes the request to set the largest number of people in a room at once
+func (server *Server) setRoomCapacity(response http.ResponseWriter, request *http.Request) {
+	var requestData api.SetRoomCapacityRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	room, found := server.findRoom(response, request)
+	if !found {
+		return
+	}
+
+	capacity := 0
+	if requestData.Capacity != nil {
+		capacity = *requestData.Capacity
+	}
+	room, setError := server.guests.SetRoomCapacity(request.Context(), room, capacity)
+	if setError != nil {
+		server.reportServiceError(response, request, setError)
+		return
+	}
+
+	occupancy, queryError := server.guests.RoomOccupancy(room)
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateRoomResponse(occupancy))
+}
+
+// assignRoom Processes the request to put a guest's table in a room, or in none
+func (server *Server) assignRoom(response http.ResponseWriter, request *http.Request) {
+	var requestData api.AssignRoomRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	guest, found := server.findGuest(response, request)
+	if !found {
+		return
+	}
+
+	var room *database.Room
+	if requestData.Room != nil {
+		namedRoom, findError := server.guests.RoomByName(*requestData.Room)
+		if findError != nil {
+			server.reportServiceError(response, request, findError)
+			return
+		}
+		room = &namedRoom
+	}
+
+	guest, assignError := server.guests.AssignRoom(request.Context(), guest, room)
+	if assignError != nil {
+		server.reportServiceError(response, request, assignError)
+		return
+	}
+	server.encodeResponse(response, CreateAssignRoomResponse(guest))
+}
+
+// grantRoomAccess Processes the request to grant a guest the right to enter a restricted room
+func (server *Server) grantRoomAccess(response http.ResponseWriter, request *http.Request) {
+	room, found := server.findRoom(response, request)
+	if !found {
+		return
+	}
+	guest, found := server.findGuest(response, request)
+	if !found {
+		return
+	}
+
+	if grantError := server.guests.GrantRoomAccess(request.Context(), room, guest); grantError != nil {
+		server.reportServiceError(response, request, grantError)
+		return
+	}
+	server.encodeResponse(response, "Guest "+guest.Name+" can enter room "+room.Name)
+}
+
+// revokeRoomAccess Processes the request to take back the right of a guest to enter a restricted room
+func (server *Server) revokeRoomAccess(response http.ResponseWriter, request *http.Request) {
+	room, found := server.findRoom(response, request)
+	if !found {
+		return
+	}
+	guest, found := server.findGuest(response, request)
+	if !found {
+		return
+	}
+
+	if revokeError := server.guests.RevokeRoomAccess(request.Context(), room, guest); revokeError != nil {
+		server.reportServiceError(response, request, revokeError)
+		return
+	}
+	server.encodeResponse(response, "Guest "+guest.Name+" can no longer enter room "+room.Name)
+}
+
+// enterRoom Processes the request that happens when a guest at the party enters a room
+//
+// An error is reported if the guest may not enter the room or if the admission policy refuses them, by default when it is full
+func (server *Server) enterRoom(response http.ResponseWriter, request *http.Request) {
+	room, found := server.findRoom(response, request)
+	if !found {
+		return
+	}
+	guest, found := server.findGuest(response, request)
+	if !found {
+		return
+	}
+
+	guest, decision, enterError := server.guests.EnterRoom(request.Context(), room, guest)
+	if enterError != nil {
+		server.reportServiceError(response, request, enterError)
+		return
+	}
+	server.reportAdmission(response, decision)
+	server.encodeResponse(response, CreateRoomGuestResponse(guest))
+}
+
+// leaveRoom Processes the request that happens when a guest leaves a room, staying at the party
+func (server *Server) leaveRoom(response http.ResponseWriter, request *http.Request) {
+	room, found := server.findRoom(response, request)
+	if !found {
+		return
+	}
+	guest, found := server.findGuest(response, request)
+	if !found {
+		return
+	}
+
+	guest, leaveError := server.guests.LeaveRoom(request.Context(), room, guest)
+	if leaveError != nil {
+		server.reportServiceError(response, request, leaveError)
+		return
+	}
+	server.encodeResponse(response, CreateRoomGuestResponse(guest))
+}
@@ -1,35 +1,190 @@
 package requestRouting
 
 import (
-	"fmt"
 	"github.com/gorilla/mux"
+	"guestListChallenge/src/auth"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/graphqlApi"
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/openapi"
+	"guestListChallenge/src/snapshot"
+	"guestListChallenge/src/tickets"
+	"guestListChallenge/src/utils"
+	"guestListChallenge/src/webhooks"
+	"log"
 	"net/http"
 )
 
-// Router Http request router
-var Router *mux.Router
+// Server Guest list HTTP server
+//
+// Holds every dependency needed by the request handlers so that several instances can live in one process
+type Server struct {
+	store     *database.Store
+	clock     utils.Clock
+	guests    *guestService.Service
+	logger    *log.Logger
+	config    Config
+	document  *openapi.Document
+	tickets   *tickets.Signer
+	webhooks  *webhooks.Dispatcher
+	snapshots *snapshot.Archiver
+	router    *mux.Router
+
+	authenticator *auth.Authenticator
+}
+
+// NewServer Creates a Server and setups its http request router
+func NewServer(store *database.Store, clock utils.Clock, logger *log.Logger, config Config) *Server {
+	document, loadError := openapi.Load()
+	if loadError != nil {
+		logger.Println(loadError.Error())
+		panic("Failed to load OpenAPI document")
+	}
+
+	if len(config.TicketKey) == 0 {
+		logger.Println("No ticket key configured: tickets will not be accepted after a restart")
+		config.TicketKey = tickets.NewKey()
+	}
+
+	guests := guestService.NewService(store, clock, logger)
+	server := &Server{
+		store:     store,
+		clock:     clock,
+		guests:    guests,
+		logger:    logger,
+		config:    config,
+		document:  document,
+		tickets:   tickets.NewSigner(config.TicketKey, config.EventID),
+		webhooks:  webhooks.NewDispatcher(store, clock, logger, config.Webhooks),
+		snapshots: snapshot.NewArchiver(store, clock, logger, guests.AuditLog(), guests, config.EventID, config.Snapshots),
+
+		authenticator: auth.NewAuthenticator(config.APIKeys, config.AdminKeys),
+	}
+	if !server.authenticator.Enabled() {
+		logger.Println("No API key configured: requests are accepted from anyone and audited as " + auth.Anonymous)
+	}
+	if len(config.AdminKeys) == 0 {
+		logger.Println("No admin key configured: snapshots can neither be downloaded nor restored")
+	}
+	server.guests.AddEventHandler(server.webhooks.Enqueue)
+	server.guests.SetNoShowCutoff(config.NoShows.Cutoff)
+	server.guests.SetWalkInCap(config.WalkIns.Cap)
+	server.guests.SetVenue(config.Venue)
+	server.guests.SetCategories(config.Categories)
+	if config.Admission.Event == "" {
+		config.Admission.Event = config.EventID
+	}
+	server.guests.SetAdmissionPolicy(config.Admission)
+	if store != nil {
+		if loadError := server.guests.LoadSettings(); loadError != nil {
+			logger.Println("Settings changed while the server ran before couldn't be loaded: " + loadError.Error())
+		}
+	}
+	server.setupRouter()
+	return server
+}
 
-// Setup Setups http request Router
+// setupRouter Setups http request router
 //
 // Matches incoming requests to their respective handler
-func Setup() {
+func (server *Server) setupRouter() {
 
-	Router = mux.NewRouter().StrictSlash(true)
-	Router.HandleFunc("/guest_list/{name}", addGuest).Methods(http.MethodPost)
-	Router.HandleFunc("/guest_list", getGuestList).Methods(http.MethodGet)
-	Router.HandleFunc("/guests/{name}", checkInGuest).Methods(http.MethodPut)
-	Router.HandleFunc("/guests/{name}", checkOutGuest).Methods(http.MethodDelete)
-	Router.HandleFunc("/guests", getArrivedGuests).Methods(http.MethodGet)
-	Router.HandleFunc("/seats_empty", getNumberOfEmptySeats).Methods(http.MethodGet)
+	server.router = mux.NewRouter().StrictSlash(true)
+	server.router.HandleFunc("/guest_list/{name}", server.addGuest).Methods(http.MethodPost)
+	server.router.HandleFunc("/guest_list/{name}", server.deleteGuest).Methods(http.MethodDelete)
+	server.router.HandleFunc("/guest_list/id/{id}", server.deleteGuest).Methods(http.MethodDelete)
+	server.router.HandleFunc("/guest_list", server.getGuestList).Methods(http.MethodGet)
+	server.router.HandleFunc("/guest_list/search", server.searchGuests).Methods(http.MethodGet)
+	server.router.HandleFunc("/guest_list/{name}/ticket.png", server.getGuestTicket).Methods(http.MethodGet)
+	server.router.HandleFunc("/guest_list/id/{id}/ticket.png", server.getGuestTicket).Methods(http.MethodGet)
+	server.router.HandleFunc("/guest_list/{name}/no_show", server.setGuestNoShow).Methods(http.MethodPut)
+	server.router.HandleFunc("/guest_list/id/{id}/no_show", server.setGuestNoShow).Methods(http.MethodPut)
+	server.router.HandleFunc("/guest_list/{name}/room", server.assignRoom).Methods(http.MethodPut)
+	server.router.HandleFunc("/guest_list/id/{id}/room", server.assignRoom).Methods(http.MethodPut)
+	server.router.HandleFunc("/guest_list/{name}/category", server.setGuestCategory).Methods(http.MethodPut)
+	server.router.HandleFunc("/guest_list/id/{id}/category", server.setGuestCategory).Methods(http.MethodPut)
+	server.router.HandleFunc("/guests/{name}", server.checkInGuest).Methods(http.MethodPut)
+	server.router.HandleFunc("/guests/{name}", server.checkOutGuest).Methods(http.MethodDelete)
+	server.router.HandleFunc("/guests/id/{id}", server.checkInGuest).Methods(http.MethodPut)
+	server.router.HandleFunc("/guests/id/{id}", server.checkOutGuest).Methods(http.MethodDelete)
+	server.router.HandleFunc("/checkin/scan", server.scanTicket).Methods(http.MethodPost)
+	server.router.HandleFunc("/guests", server.getArrivedGuests).Methods(http.MethodGet)
+	server.router.HandleFunc("/seats_empty", server.getNumberOfEmptySeats).Methods(http.MethodGet)
+	server.router.HandleFunc("/no_shows", server.getNoShows).Methods(http.MethodGet)
+	server.router.HandleFunc("/no_shows/release", server.releaseNoShows).Methods(http.MethodPost)
+	server.router.HandleFunc("/no_shows/cutoff", server.setNoShowCutoff).Methods(http.MethodPut)
+	server.router.HandleFunc("/occupancy", server.getHeadcount).Methods(http.MethodGet)
+	server.router.HandleFunc("/occupancy/capacity", server.setVenueCapacity).Methods(http.MethodPut)
+	server.router.HandleFunc("/categories", server.getCategories).Methods(http.MethodGet)
+	server.router.HandleFunc("/categories/{category}", server.setCategoryRules).Methods(http.MethodPut)
+	server.router.HandleFunc("/admission/rules", server.getAdmissionRules).Methods(http.MethodGet)
+	server.router.HandleFunc("/admission/rules", server.setAdmissionRules).Methods(http.MethodPut)
+	server.router.HandleFunc("/rooms", server.getRooms).Methods(http.MethodGet)
+	server.router.HandleFunc("/rooms/{room}", server.addRoom).Methods(http.MethodPost)
+	server.router.HandleFunc("/rooms/{room}", server.deleteRoom).Methods(http.MethodDelete)
+	server.router.HandleFunc("/rooms/{room}/capacity", server.setRoomCapacity).Methods(http.MethodPut)
+	server.router.HandleFunc("/rooms/{room}/access/{name}", server.grantRoomAccess).Methods(http.MethodPut)
+	server.router.HandleFunc("/rooms/{room}/access/{name}", server.revokeRoomAccess).Methods(http.MethodDelete)
+	server.router.HandleFunc("/rooms/{room}/access/id/{id}", server.grantRoomAccess).Methods(http.MethodPut)
+	server.router.HandleFunc("/rooms/{room}/access/id/{id}", server.revokeRoomAccess).Methods(http.MethodDelete)
+	server.router.HandleFunc("/rooms/{room}/guests/{name}", server.enterRoom).Methods(http.MethodPut)
+	server.router.HandleFunc("/rooms/{room}/guests/{name}", server.leaveRoom).Methods(http.MethodDelete)
+	server.router.HandleFunc("/rooms/{room}/guests/id/{id}", server.enterRoom).Methods(http.MethodPut)
+	server.router.HandleFunc("/rooms/{room}/guests/id/{id}", server.leaveRoom).Methods(http.MethodDelete)
+	server.router.HandleFunc("/walk_ins", server.admitWalkIn).Methods(http.MethodPost)
+	server.router.HandleFunc("/walk_ins", server.getWalkIns).Methods(http.MethodGet)
+	server.router.HandleFunc("/walk_ins/cap", server.setWalkInCap).Methods(http.MethodPut)
+	server.router.HandleFunc("/waitlist", server.joinWaitlist).Methods(http.MethodPost)
+	server.router.HandleFunc("/waitlist", server.getWaitlist).Methods(http.MethodGet)
+	server.router.HandleFunc("/waitlist/seat", server.seatWaitlist).Methods(http.MethodPost)
+	server.router.HandleFunc("/waitlist/{id}", server.leaveWaitlist).Methods(http.MethodDelete)
+	server.router.HandleFunc("/webhooks", server.addWebhook).Methods(http.MethodPost)
+	server.router.HandleFunc("/webhooks", server.getWebhooks).Methods(http.MethodGet)
+	server.router.HandleFunc("/webhooks/{id}", server.deleteWebhook).Methods(http.MethodDelete)
+	server.router.HandleFunc("/webhooks/{id}/deliveries", server.getWebhookDeliveries).Methods(http.MethodGet)
+	server.router.HandleFunc("/audit", server.getAuditRecords).Methods(http.MethodGet)
+	server.router.HandleFunc("/audit/verify", server.verifyAuditLog).Methods(http.MethodGet)
+	server.router.HandleFunc("/operations", server.getOperations).Methods(http.MethodGet)
+	server.router.HandleFunc("/operations/{id}/undo", server.undoOperation).Methods(http.MethodPost)
+	server.router.HandleFunc("/reports/occupancy", server.getOccupancyReport).Methods(http.MethodGet)
+	server.router.HandleFunc("/reports/occupancy.html", server.getOccupancyChart).Methods(http.MethodGet)
+	server.router.HandleFunc("/admin/snapshot", server.getSnapshot).Methods(http.MethodGet)
+	server.router.HandleFunc("/admin/restore", server.restoreSnapshot).Methods(http.MethodPost)
+	server.router.Handle("/graphql", graphqlApi.NewHandler(server.guests, server.config.EventID, server.logger)).Methods(http.MethodGet, http.MethodPost)
+	server.router.HandleFunc("/openapi.json", server.getOpenAPIDocument).Methods(http.MethodGet)
+	server.router.HandleFunc("/docs", server.getAPIDocumentation).Methods(http.MethodGet)
+	server.router.HandleFunc("/docs/{asset}", server.getAPIDocumentationAsset).Methods(http.MethodGet)
+	server.router.Use(server.identifyRequest)
+	server.router.Use(server.validateRequest)
 
-	fmt.Println("Request Router successfully setup")
+	server.logger.Println("Request Router successfully setup")
 }
 
-// ListenForRequests Listens for incoming http requests
-func ListenForRequests() {
-	routingSetupError := http.ListenAndServe(networkAddress, Router)
-	if routingSetupError != nil {
-		fmt.Println(routingSetupError.Error())
-		panic("Failed to setup request Router")
-	}
+// GuestService Returns the guest list business logic used by the server, to share it with other servers
+func (server *Server) GuestService() *guestService.Service {
+	return server.guests
+}
+
+// Webhooks Returns the dispatcher delivering the guest lifecycle events to the registered webhooks
+//
+// Deliveries are only sent while its Run method is running
+func (server *Server) Webhooks() *webhooks.Dispatcher {
+	return server.webhooks
+}
+
+// Snapshots Returns the archiver taking and restoring snapshots of the state of the party
+//
+// Snapshots are only saved automatically while its Run method is running
+func (server *Server) Snapshots() *snapshot.Archiver {
+	return server.snapshots
+}
+
+// Handler Returns the http handler serving every route of the server
+func (server *Server) Handler() http.Handler {
+	return server.router
+}
+
+// ListenAndServe Listens for incoming http requests on the configured network address
+func (server *Server) ListenAndServe() error {
+	return http.ListenAndServe(server.config.NetworkAddress, server.Handler())
 }
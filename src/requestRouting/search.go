@@ -0,0 +1,37 @@
+package requestRouting
+
+import (
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/utils"
+	"sort"
+)
+
+// GuestMatch A guest matching a search along with how close it is to the query
+type GuestMatch struct {
+	Guest database.GuestList
+	Score float64
+}
+
+// rankGuests Returns the guests whose name is close to the query, closest first
+//
+// At most maxSearchCandidates guests scoring at least minSearchScore are returned
+func rankGuests(query string, guestList []database.GuestList) []GuestMatch {
+	matches := make([]GuestMatch, 0, len(guestList))
+	for _, guest := range guestList {
+		if score := utils.NameSimilarity(query, guest.Name); score >= minSearchScore {
+			matches = append(matches, GuestMatch{Guest: guest, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Guest.Name < matches[j].Guest.Name
+	})
+
+	if len(matches) > maxSearchCandidates {
+		matches = matches[:maxSearchCandidates]
+	}
+	return matches
+}
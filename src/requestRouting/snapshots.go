@@ -0,0 +1,71 @@
+package requestRouting
+
+import (
+	"errors"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/snapshot"
+	"io/ioutil"
+	"mime"
+	"net/http"
+)
+
+// getSnapshot Processes the request to download a snapshot of the whole state of the party
+//
+// The snapshot is a gzip compressed archive that POST /admin/restore loads back
+func (server *Server) getSnapshot(response http.ResponseWriter, _ *http.Request) {
+	archive, takeError := server.snapshots.Take()
+	if takeError != nil {
+		server.reportStoreError(response, takeError)
+		return
+	}
+
+	archiveData, encodeError := archive.Encode()
+	if encodeError != nil {
+		server.logger.Println(encodeError.Error())
+		server.encodeErrorResponse(response, http.StatusInternalServerError, api.ErrorCodeInternal, "Snapshot could not be taken")
+		return
+	}
+
+	response.Header().Set("Content-Type", "application/gzip")
+	response.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": snapshot.FileName(archive)}))
+	if _, writeError := response.Write(archiveData); writeError != nil {
+		server.logger.Println(writeError.Error())
+	}
+}
+
+// restoreSnapshot Processes the request to replace the whole state of the party with a snapshot
+//
+// With dry_run=true only the changes the restoration would make are reported.
+// Snapshots of another event are refused unless allow_other_event=true.
+func (server *Server) restoreSnapshot(response http.ResponseWriter, request *http.Request) {
+	if mediaType, _, parseError := mime.ParseMediaType(request.Header.Get("Content-Type")); parseError != nil || mediaType != "application/gzip" {
+		server.encodeErrorResponse(response, http.StatusUnsupportedMediaType, api.ErrorCodeUnsupportedMediaType, "Snapshot must be sent as application/gzip")
+		return
+	}
+
+	archiveData, readError := ioutil.ReadAll(http.MaxBytesReader(response, request.Body, maxSnapshotSize))
+	if readError != nil {
+		server.encodeErrorResponse(response, http.StatusRequestEntityTooLarge, api.ErrorCodeRequestTooLarge, "Snapshot is too large")
+		return
+	}
+
+	dryRun := request.URL.Query().Get("dry_run") == "true"
+	allowOtherEvent := request.URL.Query().Get("allow_other_event") == "true"
+	restoration, restoreError := server.snapshots.Restore(request.Context(), archiveData, dryRun, allowOtherEvent)
+	if errors.Is(restoreError, snapshot.ErrInvalidArchive) {
+		server.logger.Println(restoreError.Error())
+		server.encodeErrorResponse(response, http.StatusUnprocessableEntity, api.ErrorCodeInvalidSnapshot, "Snapshot rejected: "+restoreError.Error())
+		return
+	}
+	if errors.Is(restoreError, snapshot.ErrOtherEvent) {
+		server.logger.Println(restoreError.Error())
+		server.encodeErrorResponse(response, http.StatusConflict, api.ErrorCodeSnapshotOtherEvent, "Snapshot rejected: "+restoreError.Error())
+		return
+	}
+	if restoreError != nil {
+		server.reportStoreError(response, restoreError)
+		return
+	}
+
+	server.encodeResponse(response, CreateRestoreSnapshotResponse(restoration))
+}
@@ -0,0 +1,47 @@
+package requestRouting
+
+import (
+	"guestListChallenge/src/api"
+	"net/http"
+	"strconv"
+)
+
+// getHeadcount Processes the request to get the number of people in the venue, counted against its capacity
+func (server *Server) getHeadcount(response http.ResponseWriter, _ *http.Request) {
+	headcount, queryError := server.guests.Headcount()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateHeadcountResponse(headcount))
+}
+
+// setVenueCapacity Processes the request to set the largest number of people in the venue at once
+func (server *Server) setVenueCapacity(response http.ResponseWriter, request *http.Request) {
+	var requestData api.SetVenueCapacityRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	capacity := 0
+	if requestData.Capacity != nil {
+		capacity = *requestData.Capacity
+	}
+	if changeError := server.guests.ChangeVenueCapacity(request.Context(), capacity); changeError != nil {
+		server.reportStoreError(response, changeError)
+		return
+	}
+	if venue := server.guests.Venue(); venue.Capacity <= 0 {
+		server.logger.Println("Venue capacity removed")
+	} else {
+		server.logger.Println("Venue capacity set to " + strconv.Itoa(venue.Capacity) + " people")
+	}
+
+	headcount, queryError := server.guests.Headcount()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateHeadcountResponse(headcount))
+}
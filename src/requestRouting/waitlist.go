@@ -0,0 +1,68 @@
+package requestRouting
+
+import (
+	"errors"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/guestService"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// joinWaitlist Processes the request to put a party that cannot be seated yet on the waitlist
+func (server *Server) joinWaitlist(response http.ResponseWriter, request *http.Request) {
+	var requestData api.JoinWaitlistRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	entry, joinError := server.guests.JoinWaitlist(request.Context(), requestData.Name, requestData.AccompanyingGuests, requestData.Category, requestData.Age)
+	if joinError != nil {
+		server.reportServiceError(response, request, joinError)
+		return
+	}
+	server.encodeResponse(response, CreateWaitlistEntryResponse(entry, server.guests.Categories()))
+}
+
+// getWaitlist Processes the request to list the parties on the waitlist in the order they are seated
+func (server *Server) getWaitlist(response http.ResponseWriter, _ *http.Request) {
+	entries, queryError := server.guests.Waitlist()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateWaitlistResponse(entries, server.guests.Categories()))
+}
+
+// seatWaitlist Processes the request to seat the parties on the waitlist at the free tables now
+//
+// Parties are seated on their own when no-shows release seats, this lets the organiser seat them after other changes
+func (server *Server) seatWaitlist(response http.ResponseWriter, request *http.Request) {
+	seated, seatError := server.guests.SeatWaitlist(request.Context())
+	if seatError != nil {
+		server.reportStoreError(response, seatError)
+		return
+	}
+	entries, queryError := server.guests.Waitlist()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateSeatWaitlistResponse(seated, entries, server.guests.Categories()))
+}
+
+// leaveWaitlist Processes the request to remove a party from the waitlist
+func (server *Server) leaveWaitlist(response http.ResponseWriter, request *http.Request) {
+	entry, leaveError := server.guests.LeaveWaitlist(request.Context(), mux.Vars(request)["id"])
+	var refusal *guestService.Error
+	if errors.As(leaveError, &refusal) && refusal.Code == api.ErrorCodeWaitlistEntryNotFound {
+		server.encodeErrorResponse(response, http.StatusNotFound, refusal.Code, refusal.Message)
+		return
+	}
+	if leaveError != nil {
+		server.reportServiceError(response, request, leaveError)
+		return
+	}
+	server.encodeResponse(response, CreateWaitlistEntryResponse(entry, server.guests.Categories()))
+}
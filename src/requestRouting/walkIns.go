@@ -0,0 +1,59 @@
+package requestRouting
+
+import (
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/guestService"
+	"net/http"
+	"strconv"
+)
+
+// admitWalkIn Processes the request to admit a person who is not on the guest list at a table with empty seats
+func (server *Server) admitWalkIn(response http.ResponseWriter, request *http.Request) {
+	var requestData api.AdmitWalkInRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	walkIn, decision, admitError := server.guests.AdmitWalkIn(request.Context(), requestData.Name, requestData.AccompanyingGuests, requestData.SeatedWith, requestData.Age)
+	if admitError != nil {
+		server.reportServiceError(response, request, admitError)
+		return
+	}
+	server.reportAdmission(response, decision)
+	server.encodeResponse(response, CreateWalkInResponse(walkIn))
+}
+
+// getWalkIns Processes the request to list the walk-ins at the party and the tables they can be seated at
+func (server *Server) getWalkIns(response http.ResponseWriter, _ *http.Request) {
+	walkIns, queryError := server.guests.WalkIns()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateWalkInsResponse(walkIns))
+}
+
+// setWalkInCap Processes the request to set the largest number of people admitted as walk-ins
+func (server *Server) setWalkInCap(response http.ResponseWriter, request *http.Request) {
+	var requestData api.SetWalkInCapRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	cap := guestService.NoWalkInCap
+	if requestData.Cap != nil {
+		cap = *requestData.Cap
+	}
+	if changeError := server.guests.ChangeWalkInCap(request.Context(), cap); changeError != nil {
+		server.reportStoreError(response, changeError)
+		return
+	}
+	if cap := server.guests.WalkInCap(); cap == guestService.NoWalkInCap {
+		server.logger.Println("Walk-ins no longer capped")
+	} else {
+		server.logger.Println("Walk-ins capped at " + strconv.Itoa(cap) + " people")
+	}
+	server.encodeResponse(response, CreateWalkInCapResponse(server.guests.WalkInCap()))
+}
@@ -0,0 +1,116 @@
+package requestRouting
+
+import (
+	"github.com/gorilla/mux"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/webhooks"
+	"net/http"
+)
+
+// addWebhook Processes the request to register a webhook
+//
+// The webhook receives every later event of the given types, signed with its secret
+func (server *Server) addWebhook(response http.ResponseWriter, request *http.Request) {
+	if request == nil {
+		server.logger.Println("Null request")
+		return
+	}
+
+	var requestData api.AddWebhookRequest
+	if decoderError := server.decodeRequest(request, &requestData); decoderError != nil {
+		server.reportDecodeError(response, decoderError)
+		return
+	}
+
+	if urlError := server.config.Webhooks.CheckURL(requestData.URL); urlError != nil {
+		server.encodeErrorResponse(response, http.StatusUnprocessableEntity, api.ErrorCodeInvalidRequest,
+			CreateValidationErrorResponse([]string{"body.url: " + urlError.Error()}))
+		return
+	}
+
+	// Keep every event type once, in the order given
+	eventTypes := make([]string, 0, len(requestData.Events))
+	seenEventTypes := map[string]bool{}
+	for _, eventType := range requestData.Events {
+		if !seenEventTypes[eventType] {
+			seenEventTypes[eventType] = true
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	webhook := database.Webhook{
+		URL:       requestData.URL,
+		Events:    webhooks.JoinEvents(eventTypes),
+		Secret:    requestData.Secret,
+		CreatedAt: server.clock.Now(),
+	}
+	if storeError := server.store.AddWebhook(&webhook); storeError != nil {
+		server.reportStoreError(response, storeError)
+		return
+	}
+
+	server.recordAudit(request, audit.OperationAddWebhook, webhook.ID, nil, CreateWebhookResponse(webhook))
+	server.encodeResponse(response, CreateWebhookResponse(webhook))
+}
+
+// getWebhooks Processes the request to list the webhooks
+func (server *Server) getWebhooks(response http.ResponseWriter, _ *http.Request) {
+	webhookList, queryError := server.store.Webhooks()
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateGetWebhooksResponse(webhookList))
+}
+
+// deleteWebhook Processes the request to stop delivering events to a webhook
+//
+// Deliveries still pending are dropped along with the webhook's delivery log
+func (server *Server) deleteWebhook(response http.ResponseWriter, request *http.Request) {
+	webhook, found := server.findWebhook(response, request)
+	if !found {
+		return
+	}
+
+	if storeError := server.store.DeleteWebhook(webhook.ID); storeError != nil {
+		server.reportStoreError(response, storeError)
+		return
+	}
+
+	server.recordAudit(request, audit.OperationDeleteWebhook, webhook.ID, CreateWebhookResponse(webhook), nil)
+	server.encodeResponse(response, "Webhook "+webhook.ID+" was deleted")
+}
+
+// getWebhookDeliveries Processes the request to get the delivery log of a webhook, newest first
+func (server *Server) getWebhookDeliveries(response http.ResponseWriter, request *http.Request) {
+	webhook, found := server.findWebhook(response, request)
+	if !found {
+		return
+	}
+
+	deliveries, queryError := server.store.WebhookDeliveries(webhook.ID, maxWebhookDeliveries)
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return
+	}
+	server.encodeResponse(response, CreateGetWebhookDeliveriesResponse(deliveries))
+}
+
+// findWebhook Finds the webhook of the "id" path variable
+//
+// When there is no such webhook, ok is false and a 404 reply has already been sent
+func (server *Server) findWebhook(response http.ResponseWriter, request *http.Request) (webhook database.Webhook, ok bool) {
+	webhookID := mux.Vars(request)["id"]
+	webhook, queryError := server.store.WebhookByID(webhookID)
+	if queryError == database.ErrWebhookNotFound {
+		server.encodeErrorResponse(response, http.StatusNotFound, api.ErrorCodeWebhookNotFound, "Webhook "+webhookID+" is not registered")
+		return webhook, false
+	}
+	if queryError != nil {
+		server.reportStoreError(response, queryError)
+		return webhook, false
+	}
+	return webhook, true
+}
@@ -0,0 +1,132 @@
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"guestListChallenge/src/database"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// FormatVersion Version of the archive format written by this binary, archives of newer formats are refused
+const FormatVersion = 1
+
+// maxArchiveSize Largest uncompressed archive read, in bytes, so that a small compressed body cannot exhaust memory
+const maxArchiveSize = 256 << 20
+
+// fileNameTimeFormat Format of the creation time in the file names of snapshots, sorting them from oldest to newest
+const fileNameTimeFormat = "20060102T150405Z"
+
+// ErrInvalidArchive Reported when an archive cannot be restored: unreadable, corrupted or written by a newer binary
+var ErrInvalidArchive = errors.New("invalid snapshot")
+
+// ErrOtherEvent Reported when an archive taken at another event is restored without allowing it
+var ErrOtherEvent = errors.New("snapshot of another event")
+
+// Archive Snapshot of the whole state of a party
+//
+// The checksum covers the state as encoded in the archive, so that an archive damaged or edited by hand is refused
+type Archive struct {
+	FormatVersion int             `json:"format_version"`
+	SchemaVersion int             `json:"schema_version"` // database schema version of the party when the snapshot was taken
+	EventID       string          `json:"event_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Checksum      string          `json:"checksum"` // hex encoded SHA-256 of State
+	State         json.RawMessage `json:"state,omitempty"`
+}
+
+// NewArchive Creates the archive of a state
+func NewArchive(state database.State, schemaVersion int, eventID string, createdAt time.Time) (Archive, error) {
+	stateJSON, encodeError := json.Marshal(state)
+	if encodeError != nil {
+		return Archive{}, encodeError
+	}
+	return Archive{
+		FormatVersion: FormatVersion,
+		SchemaVersion: schemaVersion,
+		EventID:       eventID,
+		CreatedAt:     createdAt.UTC().Truncate(time.Second),
+		Checksum:      Checksum(stateJSON),
+		State:         stateJSON,
+	}, nil
+}
+
+// Checksum Returns the checksum of an encoded state
+func Checksum(stateJSON []byte) string {
+	hash := sha256.Sum256(stateJSON)
+	return hex.EncodeToString(hash[:])
+}
+
+// FileName Returns the name of the file holding an archive, named after its creation time
+func FileName(archive Archive) string {
+	return filePrefix + archive.CreatedAt.UTC().Format(fileNameTimeFormat) + fileSuffix
+}
+
+// Encode Returns the archive as gzip compressed JSON
+func (archive Archive) Encode() ([]byte, error) {
+	var buffer bytes.Buffer
+	compressor := gzip.NewWriter(&buffer)
+	if encodeError := json.NewEncoder(compressor).Encode(archive); encodeError != nil {
+		return nil, encodeError
+	}
+	if closeError := compressor.Close(); closeError != nil {
+		return nil, closeError
+	}
+	return buffer.Bytes(), nil
+}
+
+// Decode Reads an archive encoded by Encode and checks its format version and checksum
+//
+// Errors wrap ErrInvalidArchive
+func Decode(data []byte) (Archive, error) {
+	var archive Archive
+
+	decompressor, readError := gzip.NewReader(bytes.NewReader(data))
+	if readError != nil {
+		return archive, fmt.Errorf("%w: not a gzip archive: %v", ErrInvalidArchive, readError)
+	}
+	archiveJSON, readError := ioutil.ReadAll(io.LimitReader(decompressor, maxArchiveSize+1))
+	if readError != nil {
+		return archive, fmt.Errorf("%w: %v", ErrInvalidArchive, readError)
+	}
+	if len(archiveJSON) > maxArchiveSize {
+		return archive, fmt.Errorf("%w: larger than %d bytes once decompressed", ErrInvalidArchive, maxArchiveSize)
+	}
+
+	if decodeError := json.Unmarshal(archiveJSON, &archive); decodeError != nil {
+		return archive, fmt.Errorf("%w: malformed JSON: %v", ErrInvalidArchive, decodeError)
+	}
+	if archive.FormatVersion < 1 || archive.FormatVersion > FormatVersion {
+		return archive, fmt.Errorf("%w: format version %d is not supported, this binary reads up to version %d", ErrInvalidArchive, archive.FormatVersion, FormatVersion)
+	}
+	if len(archive.State) == 0 {
+		return archive, fmt.Errorf("%w: no state", ErrInvalidArchive)
+	}
+	if Checksum(archive.State) != archive.Checksum {
+		return archive, fmt.Errorf("%w: checksum does not match, the archive is damaged or was edited", ErrInvalidArchive)
+	}
+	return archive, nil
+}
+
+// Contents Returns the state held by the archive
+//
+// Errors wrap ErrInvalidArchive
+func (archive Archive) Contents() (database.State, error) {
+	var state database.State
+	if decodeError := json.Unmarshal(archive.State, &state); decodeError != nil {
+		return state, fmt.Errorf("%w: malformed state: %v", ErrInvalidArchive, decodeError)
+	}
+	return state, nil
+}
+
+// Summary Returns the archive without its state, to describe it
+func (archive Archive) Summary() Archive {
+	archive.State = nil
+	return archive
+}
@@ -0,0 +1,321 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/utils"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// File name prefix and suffix of the snapshots saved in the directory
+const (
+	filePrefix = "snapshot-"
+	fileSuffix = ".json.gz"
+)
+
+// SettingsKeeper Keeps the settings of the party in effect, which snapshots hold along with the records of the database
+type SettingsKeeper interface {
+	// SettingRecords Returns the settings in effect, encoded as they are stored
+	SettingRecords() ([]database.Setting, error)
+	// LoadSettings Puts in effect the settings stored in the database
+	LoadSettings() error
+}
+
+// Archiver Takes and restores snapshots of the whole state of a party, and saves them to a directory
+type Archiver struct {
+	store    *database.Store
+	clock    utils.Clock
+	logger   *log.Logger
+	auditLog *audit.Log
+	settings SettingsKeeper
+	eventID  string
+	config   Config
+
+	// saveMutex Serializes saving snapshots and restoring them
+	saveMutex    sync.Mutex
+	lastChecksum string // checksum of the latest snapshot saved, empty until the directory was looked at
+}
+
+// Restoration Outcome of the restoration of a snapshot
+type Restoration struct {
+	Archive Archive
+	State   database.State
+	Diff    Diff
+	DryRun  bool // the changes were only computed, the state was left as it was
+
+	// WebhooksWithoutSecret IDs of the webhooks of the snapshot left out of the restoration, their secret being unknown
+	WebhooksWithoutSecret []string
+}
+
+// NewArchiver Creates an Archiver working on the given store, recording restorations in the audit log
+//
+// Snapshots hold the settings in effect kept by settings, or the ones stored in the database if it is nil
+func NewArchiver(store *database.Store, clock utils.Clock, logger *log.Logger, auditLog *audit.Log, settings SettingsKeeper, eventID string, config Config) *Archiver {
+	return &Archiver{
+		store:    store,
+		clock:    clock,
+		logger:   logger,
+		auditLog: auditLog,
+		settings: settings,
+		eventID:  eventID,
+		config:   config,
+	}
+}
+
+// Take Returns a snapshot of the current state of the party
+func (archiver *Archiver) Take() (Archive, error) {
+	state, queryError := archiver.state()
+	if queryError != nil {
+		return Archive{}, queryError
+	}
+	schemaVersion, queryError := database.SchemaVersion(archiver.store.DB())
+	if queryError != nil {
+		return Archive{}, queryError
+	}
+	return NewArchive(state, schemaVersion, archiver.eventID, archiver.clock.Now())
+}
+
+// Restore Replaces the state of the party with the one of an archive encoded by Encode
+//
+// With dryRun the changes are only computed. Otherwise the current state is first saved to the directory,
+// so that the restoration can itself be reverted, and the restoration is recorded in the audit log.
+// Archives that are invalid or come from a newer database schema are reported as errors wrapping ErrInvalidArchive.
+// Archives of another event are reported as errors wrapping ErrOtherEvent, unless allowOtherEvent.
+// Archives taken before settings were part of snapshots leave the settings as they are.
+// Snapshots do not hold the secrets of the webhooks: the webhooks still registered keep theirs,
+// the others are left out and have to be registered again with their secret.
+func (archiver *Archiver) Restore(ctx context.Context, data []byte, dryRun bool, allowOtherEvent bool) (Restoration, error) {
+	archive, decodeError := Decode(data)
+	if decodeError != nil {
+		return Restoration{}, decodeError
+	}
+	restoration := Restoration{Archive: archive, DryRun: dryRun}
+	if archive.EventID != archiver.eventID && !allowOtherEvent {
+		return restoration, fmt.Errorf("%w: taken at event %q, this server runs event %q", ErrOtherEvent, archive.EventID, archiver.eventID)
+	}
+
+	schemaVersion, queryError := database.SchemaVersion(archiver.store.DB())
+	if queryError != nil {
+		return restoration, queryError
+	}
+	if archive.SchemaVersion > schemaVersion {
+		return restoration, fmt.Errorf("%w: taken with database schema version %d, newer than the current version %d", ErrInvalidArchive, archive.SchemaVersion, schemaVersion)
+	}
+	if restoration.State, decodeError = archive.Contents(); decodeError != nil {
+		return restoration, decodeError
+	}
+
+	archiver.saveMutex.Lock()
+	defer archiver.saveMutex.Unlock()
+
+	current, queryError := archiver.state()
+	if queryError != nil {
+		return restoration, queryError
+	}
+	if restoration.State.Settings == nil {
+		restoration.State.Settings = current.Settings
+	}
+	restoration.State.Webhooks, restoration.WebhooksWithoutSecret = keepSecrets(restoration.State.Webhooks, current.Webhooks)
+	restoration.Diff = Compare(current, restoration.State)
+	if dryRun {
+		return restoration, nil
+	}
+
+	if archiver.config.Directory != "" {
+		if _, saveError := archiver.save(); saveError != nil {
+			return restoration, fmt.Errorf("the current state could not be saved before restoring: %v", saveError)
+		}
+	}
+	restored := restoration.State
+	restored.Settings = make([]database.Setting, 0, len(restoration.State.Settings))
+	for _, setting := range restoration.State.Settings {
+		// Settings that came from the configuration are stored as changed by the restoration
+		if setting.ChangedAt.IsZero() {
+			setting.ChangedAt = archiver.clock.Now()
+		}
+		restored.Settings = append(restored.Settings, setting)
+	}
+	if storeError := archiver.store.ReplaceState(restored); storeError != nil {
+		return restoration, storeError
+	}
+	// The state changed behind the saved snapshots' back
+	archiver.lastChecksum = ""
+
+	if _, recordError := archiver.auditLog.Record(ctx, audit.OperationRestoreSnapshot, archive.Checksum, nil, archive.Summary()); recordError != nil {
+		archiver.logger.Println("Audit record of " + audit.OperationRestoreSnapshot + " on " + archive.Checksum + " lost: " + recordError.Error())
+	}
+	archiver.logger.Printf("Restored snapshot of %s taken at %s\n", archive.EventID, archive.CreatedAt.Format(time.RFC3339))
+
+	if archiver.settings != nil {
+		if loadError := archiver.settings.LoadSettings(); loadError != nil {
+			return restoration, fmt.Errorf("the settings of the snapshot could not be put in effect: %v", loadError)
+		}
+	}
+	return restoration, nil
+}
+
+// keepSecrets Returns the webhooks of a snapshot with the secret of the registered webhook of the same ID,
+// and the IDs of those left out because no webhook of their ID is registered
+func keepSecrets(webhooks []database.Webhook, registered []database.Webhook) ([]database.Webhook, []string) {
+	secrets := make(map[string]string, len(registered))
+	for _, webhook := range registered {
+		secrets[webhook.ID] = webhook.Secret
+	}
+
+	kept := make([]database.Webhook, 0, len(webhooks))
+	withoutSecret := []string{}
+	for _, webhook := range webhooks {
+		secret, found := secrets[webhook.ID]
+		if !found {
+			withoutSecret = append(withoutSecret, webhook.ID)
+			continue
+		}
+		webhook.Secret = secret
+		kept = append(kept, webhook)
+	}
+	return kept, withoutSecret
+}
+
+// state Returns the current state of the party, with the settings in effect
+func (archiver *Archiver) state() (database.State, error) {
+	state, queryError := archiver.store.State()
+	if queryError != nil || archiver.settings == nil {
+		return state, queryError
+	}
+	state.Settings, queryError = archiver.settings.SettingRecords()
+	return state, queryError
+}
+
+// Run Saves a snapshot to the directory at every interval until the context is done
+//
+// Does nothing when no directory or no positive interval is configured
+func (archiver *Archiver) Run(ctx context.Context) {
+	if archiver.config.Directory == "" || archiver.config.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(archiver.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		fileName, saveError := archiver.Save()
+		if saveError != nil {
+			archiver.logger.Println("Snapshot not saved: " + saveError.Error())
+		} else if fileName != "" {
+			archiver.logger.Println("Snapshot saved to " + fileName)
+		}
+	}
+}
+
+// Save Writes a snapshot to the directory and removes the oldest ones beyond the retention
+//
+// Nothing is written when the state did not change since the latest snapshot, so that an emptied database
+// does not push the snapshots holding its former state out of the retention.
+// Returns the path of the file written, empty if nothing was written.
+func (archiver *Archiver) Save() (string, error) {
+	archiver.saveMutex.Lock()
+	defer archiver.saveMutex.Unlock()
+	return archiver.save()
+}
+
+// save Does the work of Save, with saveMutex held
+func (archiver *Archiver) save() (string, error) {
+	if mkdirError := os.MkdirAll(archiver.config.Directory, 0700); mkdirError != nil {
+		return "", mkdirError
+	}
+	fileNames, listError := archiver.savedSnapshots()
+	if listError != nil {
+		return "", listError
+	}
+	if archiver.lastChecksum == "" && len(fileNames) > 0 {
+		// Compare with the latest snapshot saved before a restart, a damaged one is simply not compared with
+		if data, readError := ioutil.ReadFile(filepath.Join(archiver.config.Directory, fileNames[len(fileNames)-1])); readError == nil {
+			if latest, decodeError := Decode(data); decodeError == nil {
+				archiver.lastChecksum = latest.Checksum
+			}
+		}
+	}
+
+	archive, takeError := archiver.Take()
+	if takeError != nil {
+		return "", takeError
+	}
+	if archive.Checksum == archiver.lastChecksum {
+		return "", nil
+	}
+
+	data, encodeError := archive.Encode()
+	if encodeError != nil {
+		return "", encodeError
+	}
+	filePath := filepath.Join(archiver.config.Directory, FileName(archive))
+	if writeError := writeFile(filePath, data); writeError != nil {
+		return "", writeError
+	}
+	archiver.lastChecksum = archive.Checksum
+
+	// Remove the oldest snapshots, the one just written included in the count and always kept
+	fileNames, listError = archiver.savedSnapshots()
+	if listError != nil {
+		return filePath, listError
+	}
+	retention := archiver.config.Retention
+	if retention < 1 {
+		retention = 1
+	}
+	for index := 0; index < len(fileNames)-retention; index++ {
+		if removeError := os.Remove(filepath.Join(archiver.config.Directory, fileNames[index])); removeError != nil {
+			return filePath, removeError
+		}
+	}
+	return filePath, nil
+}
+
+// savedSnapshots Returns the names of the snapshot files of the directory, oldest first
+func (archiver *Archiver) savedSnapshots() ([]string, error) {
+	entries, readError := os.ReadDir(archiver.config.Directory)
+	if readError != nil {
+		return nil, readError
+	}
+	var fileNames []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), filePrefix) && strings.HasSuffix(entry.Name(), fileSuffix) {
+			fileNames = append(fileNames, entry.Name())
+		}
+	}
+	sort.Strings(fileNames)
+	return fileNames, nil
+}
+
+// writeFile Writes a file readable by its owner only, through a temporary file so that it is never left half written
+func writeFile(filePath string, data []byte) error {
+	temporaryFile, createError := os.CreateTemp(filepath.Dir(filePath), ".snapshot-*.tmp")
+	if createError != nil {
+		return createError
+	}
+	defer os.Remove(temporaryFile.Name())
+
+	if _, writeError := temporaryFile.Write(data); writeError != nil {
+		temporaryFile.Close()
+		return writeError
+	}
+	if closeError := temporaryFile.Close(); closeError != nil {
+		return closeError
+	}
+	return os.Rename(temporaryFile.Name(), filePath)
+}
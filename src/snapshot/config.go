@@ -0,0 +1,29 @@
+package snapshot
+
+import (
+	"time"
+)
+
+// Config Automatic snapshot configuration
+type Config struct {
+
+	// Directory Where snapshots are saved, automatic snapshots are disabled when empty
+	Directory string
+
+	// Interval How often a snapshot is saved, if the state changed since the latest one
+	Interval time.Duration
+
+	// Retention Number of snapshots kept in the directory, the oldest ones are removed
+	Retention int
+}
+
+// DefaultConfig Returns the automatic snapshot configuration used by the docker setup
+//
+// A day of snapshots taken every quarter of an hour is kept
+func DefaultConfig() Config {
+	return Config{
+		Directory: "snapshots",
+		Interval:  15 * time.Minute,
+		Retention: 96,
+	}
+}
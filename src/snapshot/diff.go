@@ -0,0 +1,156 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"guestListChallenge/src/database"
+	"sort"
+	"time"
+)
+
+// Changes IDs of the records of one kind that restoring a snapshot adds, removes and changes
+type Changes struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Diff Changes that restoring a snapshot makes to each kind of record
+type Diff struct {
+	Guests      Changes
+	Visits      Changes
+	UsedTickets Changes
+	Webhooks    Changes
+	Rooms       Changes
+	RoomAccess  Changes // identified by room ID and guest ID separated by a slash
+	Settings    Changes // identified by name, changed when their value is
+	Waitlist    Changes
+}
+
+// Compare Returns the changes made by replacing the current state with the restored one
+func Compare(current database.State, restored database.State) Diff {
+	return Diff{
+		Guests:      compareRecords(guestRecords(current.Guests), guestRecords(restored.Guests)),
+		Visits:      compareRecords(visitRecords(current.Visits), visitRecords(restored.Visits)),
+		UsedTickets: compareRecords(usedTicketRecords(current.UsedTickets), usedTicketRecords(restored.UsedTickets)),
+		Webhooks:    compareRecords(webhookRecords(current.Webhooks), webhookRecords(restored.Webhooks)),
+		Rooms:       compareRecords(roomRecords(current.Rooms), roomRecords(restored.Rooms)),
+		RoomAccess:  compareRecords(roomAccessRecords(current.RoomAccess), roomAccessRecords(restored.RoomAccess)),
+		Settings:    compareRecords(settingRecords(current.Settings), settingRecords(restored.Settings)),
+		Waitlist:    compareRecords(waitlistRecords(current.Waitlist), waitlistRecords(restored.Waitlist)),
+	}
+}
+
+// compareRecords Returns the changes between two sets of records, given as their encoding by ID
+func compareRecords(current map[string]string, restored map[string]string) Changes {
+	changes := Changes{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	for id, record := range restored {
+		currentRecord, exists := current[id]
+		if !exists {
+			changes.Added = append(changes.Added, id)
+		} else if currentRecord != record {
+			changes.Changed = append(changes.Changed, id)
+		}
+	}
+	for id := range current {
+		if _, exists := restored[id]; !exists {
+			changes.Removed = append(changes.Removed, id)
+		}
+	}
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Changed)
+	return changes
+}
+
+// encodeRecord Returns the JSON encoding of a record, equal records have equal encodings
+func encodeRecord(record interface{}) string {
+	encoded, _ := json.Marshal(record)
+	return string(encoded)
+}
+
+// guestRecords Returns the encoding of every guest by ID, times in UTC so that they compare across time zones
+func guestRecords(guests []database.GuestList) map[string]string {
+	records := make(map[string]string, len(guests))
+	for _, guest := range guests {
+		guest.AddedAt, guest.NoShowAt = optionalUTC(guest.AddedAt), optionalUTC(guest.NoShowAt)
+		records[guest.ID] = encodeRecord(guest)
+	}
+	return records
+}
+
+// visitRecords Returns the encoding of every visit by ID, times in UTC so that they compare across time zones
+func visitRecords(visits []database.Visit) map[string]string {
+	records := make(map[string]string, len(visits))
+	for _, visit := range visits {
+		visit.GuestAddedAt, visit.ArrivedAt, visit.LeftAt = optionalUTC(visit.GuestAddedAt), visit.ArrivedAt.UTC(), optionalUTC(visit.LeftAt)
+		records[visit.ID] = encodeRecord(visit)
+	}
+	return records
+}
+
+// usedTicketRecords Returns the encoding of every used ticket by ID, times in UTC so that they compare across time zones
+func usedTicketRecords(usedTickets []database.UsedTicket) map[string]string {
+	records := make(map[string]string, len(usedTickets))
+	for _, usedTicket := range usedTickets {
+		usedTicket.UsedAt = usedTicket.UsedAt.UTC()
+		records[usedTicket.ID] = encodeRecord(usedTicket)
+	}
+	return records
+}
+
+// webhookRecords Returns the encoding of every webhook by ID, times in UTC so that they compare across time zones
+func webhookRecords(webhooks []database.Webhook) map[string]string {
+	records := make(map[string]string, len(webhooks))
+	for _, webhook := range webhooks {
+		webhook.CreatedAt = webhook.CreatedAt.UTC()
+		records[webhook.ID] = encodeRecord(webhook)
+	}
+	return records
+}
+
+// roomRecords Returns the encoding of every room by ID, times in UTC so that they compare across time zones
+func roomRecords(rooms []database.Room) map[string]string {
+	records := make(map[string]string, len(rooms))
+	for _, room := range rooms {
+		room.CreatedAt = room.CreatedAt.UTC()
+		records[room.ID] = encodeRecord(room)
+	}
+	return records
+}
+
+// roomAccessRecords Returns the encoding of every access granted to a room by room ID and guest ID
+func roomAccessRecords(roomAccess []database.RoomAccess) map[string]string {
+	records := make(map[string]string, len(roomAccess))
+	for _, access := range roomAccess {
+		records[access.RoomID+"/"+access.GuestID] = encodeRecord(access)
+	}
+	return records
+}
+
+// settingRecords Returns the value of every setting by name, settings saved at different times but with the same value being equal
+func settingRecords(settings []database.Setting) map[string]string {
+	records := make(map[string]string, len(settings))
+	for _, setting := range settings {
+		records[setting.Name] = setting.Value
+	}
+	return records
+}
+
+// waitlistRecords Returns the encoding of every waitlist entry by ID, times in UTC so that they compare across time zones
+func waitlistRecords(entries []database.WaitlistEntry) map[string]string {
+	records := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		entry.JoinedAt = entry.JoinedAt.UTC()
+		records[entry.ID] = encodeRecord(entry)
+	}
+	return records
+}
+
+// optionalUTC Returns an optional time in UTC
+func optionalUTC(optionalTime *time.Time) *time.Time {
+	if optionalTime == nil {
+		return nil
+	}
+	utcTime := optionalTime.UTC()
+	return &utcTime
+}
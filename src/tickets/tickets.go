@@ -0,0 +1,121 @@
+package tickets
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrMalformed Reported when a token is not a ticket token
+var ErrMalformed = errors.New("ticket is malformed")
+
+// ErrForged Reported when a token was not signed with the server's key
+var ErrForged = errors.New("ticket is forged")
+
+// ErrWrongEvent Reported when a token was issued for another event
+var ErrWrongEvent = errors.New("ticket belongs to another event")
+
+// ErrExpired Reported when a token is past its expiry time
+var ErrExpired = errors.New("ticket has expired")
+
+// Claims Data carried by a ticket token
+type Claims struct {
+	TicketID  string `json:"jti"`
+	EventID   string `json:"evt"`
+	GuestID   string `json:"gid"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Signer Issues and verifies ticket tokens signed with HMAC-SHA256
+//
+// A token is made of its base64url encoded JSON claims and their base64url encoded signature separated by a dot
+type Signer struct {
+	key     []byte
+	eventID string
+}
+
+// NewSigner Creates a Signer for an event using a server-side key
+func NewSigner(key []byte, eventID string) *Signer {
+	return &Signer{key: key, eventID: eventID}
+}
+
+// NewKey Generates a random signing key
+func NewKey() []byte {
+	key := make([]byte, 32)
+	if _, readError := rand.Read(key); readError != nil {
+		panic("Failed to read random bytes: " + readError.Error())
+	}
+	return key
+}
+
+// Issue Creates a ticket token for a guest valid until the given time
+func (signer *Signer) Issue(guestID string, expiresAt time.Time) (string, error) {
+	ticketID := make([]byte, 16)
+	if _, readError := rand.Read(ticketID); readError != nil {
+		return "", readError
+	}
+
+	payload, encodeError := json.Marshal(Claims{
+		TicketID:  hex.EncodeToString(ticketID),
+		EventID:   signer.eventID,
+		GuestID:   guestID,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if encodeError != nil {
+		return "", encodeError
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(signer.sign(encodedPayload)), nil
+}
+
+// Verify Checks a ticket token and returns its claims
+//
+// ErrMalformed, ErrForged, ErrWrongEvent or ErrExpired are reported for tokens that must be rejected
+func (signer *Signer) Verify(token string, now time.Time) (Claims, error) {
+	var claims Claims
+
+	tokenParts := strings.Split(token, ".")
+	if len(tokenParts) != 2 {
+		return claims, ErrMalformed
+	}
+	signature, decodeError := base64.RawURLEncoding.DecodeString(tokenParts[1])
+	if decodeError != nil {
+		return claims, ErrMalformed
+	}
+
+	// Check signature before trusting any claim
+	if !hmac.Equal(signature, signer.sign(tokenParts[0])) {
+		return claims, ErrForged
+	}
+
+	payload, decodeError := base64.RawURLEncoding.DecodeString(tokenParts[0])
+	if decodeError != nil {
+		return claims, ErrMalformed
+	}
+	if decodeError := json.Unmarshal(payload, &claims); decodeError != nil || claims.TicketID == "" || claims.GuestID == "" {
+		return claims, ErrMalformed
+	}
+
+	if claims.EventID != signer.eventID {
+		return claims, ErrWrongEvent
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return claims, ErrExpired
+	}
+
+	return claims, nil
+}
+
+// sign Returns the HMAC-SHA256 signature of an encoded payload
+func (signer *Signer) sign(encodedPayload string) []byte {
+	mac := hmac.New(sha256.New, signer.key)
+	mac.Write([]byte(encodedPayload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock Source of the current time
+//
+// Handlers read the time through a Clock so that tests and rehearsals can control it
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock Clock reading the system time
+type RealClock struct{}
+
+// Now Returns the system time
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// FakeClock Clock that only moves when told to
+type FakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+// NewFakeClock Creates a FakeClock stopped at the given time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now Returns the time the clock is stopped at
+func (clock *FakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.now
+}
+
+// Set Stops the clock at the given time
+func (clock *FakeClock) Set(now time.Time) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	clock.now = now
+}
+
+// Advance Moves the clock forward by the given duration
+func (clock *FakeClock) Advance(duration time.Duration) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	clock.now = clock.now.Add(duration)
+}
+
+// SimulatedClock Clock that starts at a chosen time and then runs at real speed
+//
+// Used to rehearse a party as if it was happening at a given time
+type SimulatedClock struct {
+	start     time.Time
+	realStart time.Time
+}
+
+// NewSimulatedClock Creates a SimulatedClock starting now at the given time
+func NewSimulatedClock(start time.Time) *SimulatedClock {
+	return &SimulatedClock{start: start, realStart: time.Now()}
+}
+
+// Now Returns the simulated time
+func (clock *SimulatedClock) Now() time.Time {
+	return clock.start.Add(time.Since(clock.realStart))
+}
+
+// ParseSimulatedTime Parses the start time of a rehearsal, or another time of the party given on the command line
+//
+// Accepts either an RFC 3339 timestamp or a "hours:minutes" time of the current day
+func ParseSimulatedTime(value string, today time.Time) (time.Time, error) {
+	if moment, parseError := time.Parse(time.RFC3339, value); parseError == nil {
+		return moment, nil
+	}
+
+	moment, parseError := time.ParseInLocation("15:04", value, today.Location())
+	if parseError != nil {
+		return time.Time{}, parseError
+	}
+	return time.Date(today.Year(), today.Month(), today.Day(), moment.Hour(), moment.Minute(), 0, 0, today.Location()), nil
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// IDGenerator Source of unique identifiers
+type IDGenerator interface {
+	NewID() string
+}
+
+// UUIDGenerator IDGenerator producing random (version 4) UUIDs
+type UUIDGenerator struct{}
+
+// NewID Returns a new random UUID
+func (UUIDGenerator) NewID() string {
+	var uuid [16]byte
+	if _, readError := rand.Read(uuid[:]); readError != nil {
+		panic("Failed to read random bytes: " + readError.Error())
+	}
+
+	// Set version 4 and RFC 4122 variant bits
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+}
+
+// SequenceIDGenerator IDGenerator producing predictable identifiers made of a prefix and a counter
+type SequenceIDGenerator struct {
+	mutex   sync.Mutex
+	prefix  string
+	counter int
+}
+
+// NewSequenceIDGenerator Creates a SequenceIDGenerator whose first identifier is prefix + "1"
+func NewSequenceIDGenerator(prefix string) *SequenceIDGenerator {
+	return &SequenceIDGenerator{prefix: prefix}
+}
+
+// NewID Returns the next identifier of the sequence
+func (generator *SequenceIDGenerator) NewID() string {
+	generator.mutex.Lock()
+	defer generator.mutex.Unlock()
+	generator.counter++
+	return generator.prefix + strconv.Itoa(generator.counter)
+}
+
+// Reset Restarts the sequence from its first identifier
+func (generator *SequenceIDGenerator) Reset() {
+	generator.mutex.Lock()
+	defer generator.mutex.Unlock()
+	generator.counter = 0
+}
@@ -0,0 +1,203 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// accentFolding Base letters of the accented latin letters found in names
+var accentFolding = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'ĉ': "c", 'ċ': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g",
+	'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i",
+	'ĵ': "j",
+	'ķ': "k",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ŏ': "o", 'ő': "o",
+	'œ': "oe",
+	'ŕ': "r", 'ŗ': "r", 'ř': "r",
+	'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s", 'ß': "ss",
+	'ţ': "t", 'ť': "t", 'ŧ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ŵ': "w",
+	'ý': "y", 'ÿ': "y", 'ŷ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// soundexCodes Soundex digit of every consonant that has one
+var soundexCodes = map[rune]byte{
+	'b': '1', 'f': '1', 'p': '1', 'v': '1',
+	'c': '2', 'g': '2', 'j': '2', 'k': '2', 'q': '2', 's': '2', 'x': '2', 'z': '2',
+	'd': '3', 't': '3',
+	'l': '4',
+	'm': '5', 'n': '5',
+	'r': '6',
+}
+
+// NormalizeName Returns a name in lower case, without accents and with words separated by single spaces
+//
+// Punctuation is treated as a word separator so that "O'Neil" and "O Neil" compare equal
+func NormalizeName(name string) string {
+	var builder strings.Builder
+	for _, character := range strings.ToLower(name) {
+		if folded, isAccented := accentFolding[character]; isAccented {
+			builder.WriteString(folded)
+		} else if unicode.IsLetter(character) || unicode.IsDigit(character) {
+			builder.WriteRune(character)
+		} else if !unicode.Is(unicode.Mn, character) {
+			builder.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// LevenshteinDistance Returns the number of single character insertions, deletions or substitutions turning a into b
+func LevenshteinDistance(a string, b string) int {
+	aRunes, bRunes := []rune(a), []rune(b)
+
+	previousRow := make([]int, len(bRunes)+1)
+	currentRow := make([]int, len(bRunes)+1)
+	for index := range previousRow {
+		previousRow[index] = index
+	}
+
+	for aIndex := 1; aIndex <= len(aRunes); aIndex++ {
+		currentRow[0] = aIndex
+		for bIndex := 1; bIndex <= len(bRunes); bIndex++ {
+			substitutionCost := 1
+			if aRunes[aIndex-1] == bRunes[bIndex-1] {
+				substitutionCost = 0
+			}
+			currentRow[bIndex] = minimum(
+				previousRow[bIndex]+1,
+				currentRow[bIndex-1]+1,
+				previousRow[bIndex-1]+substitutionCost)
+		}
+		previousRow, currentRow = currentRow, previousRow
+	}
+
+	return previousRow[len(bRunes)]
+}
+
+// Soundex Returns the American Soundex code of a word, an empty string if the word has no latin letters
+func Soundex(word string) string {
+	var letters []rune
+	for _, character := range NormalizeName(word) {
+		if character >= 'a' && character <= 'z' {
+			letters = append(letters, character)
+		}
+	}
+	if len(letters) == 0 {
+		return ""
+	}
+
+	code := []byte{byte(unicode.ToUpper(letters[0]))}
+	previousDigit := soundexCodes[letters[0]]
+	for _, letter := range letters[1:] {
+		digit, hasDigit := soundexCodes[letter]
+		if hasDigit && digit != previousDigit {
+			code = append(code, digit)
+			if len(code) == 4 {
+				break
+			}
+		}
+		// "h" and "w" do not separate letters with the same digit, vowels do
+		if letter != 'h' && letter != 'w' {
+			previousDigit = digit
+		}
+	}
+
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+	return string(code)
+}
+
+// NameSimilarity Returns how close a typed query is to a name, from 0 (unrelated) to 1 (same name)
+//
+// Names are compared ignoring case and accents, both as a whole and word by word using
+// edit distance, so that partial names and typos still match. Words sounding alike
+// according to Soundex are considered close even if they are spelled differently.
+func NameSimilarity(query string, name string) float64 {
+	normalizedQuery, normalizedName := NormalizeName(query), NormalizeName(name)
+	if normalizedQuery == "" || normalizedName == "" {
+		return 0
+	}
+	if normalizedQuery == normalizedName {
+		return 1
+	}
+
+	// Compare whole names
+	similarity := stringSimilarity(normalizedQuery, normalizedName)
+
+	// Compare every query word with its closest name word
+	nameWords := strings.Fields(normalizedName)
+	queryWords := strings.Fields(normalizedQuery)
+	wordsSimilarity := 0.0
+	phoneticMatches := 0
+	for _, queryWord := range queryWords {
+		bestWordSimilarity := 0.0
+		soundsAlike := false
+		for _, nameWord := range nameWords {
+			wordSimilarity := stringSimilarity(queryWord, nameWord)
+			if strings.HasPrefix(nameWord, queryWord) && wordSimilarity < 0.9 {
+				wordSimilarity = 0.9
+			}
+			if wordSimilarity > bestWordSimilarity {
+				bestWordSimilarity = wordSimilarity
+			}
+			if Soundex(queryWord) == Soundex(nameWord) {
+				soundsAlike = true
+			}
+		}
+		wordsSimilarity += bestWordSimilarity
+		if soundsAlike {
+			phoneticMatches++
+		}
+	}
+	wordsSimilarity /= float64(len(queryWords))
+
+	// Partial names score slightly lower than the full name
+	if len(queryWords) < len(nameWords) {
+		wordsSimilarity *= 0.95
+	}
+	if wordsSimilarity > similarity {
+		similarity = wordsSimilarity
+	}
+
+	// Every query word sounding like a name word makes up for spelling differences
+	if phoneticMatches == len(queryWords) && similarity < 0.8 {
+		similarity = 0.8
+	}
+
+	return similarity
+}
+
+// stringSimilarity Returns 1 minus the edit distance between two strings relative to the longest one
+func stringSimilarity(a string, b string) float64 {
+	longestLength := len([]rune(a))
+	if bLength := len([]rune(b)); bLength > longestLength {
+		longestLength = bLength
+	}
+	if longestLength == 0 {
+		return 1
+	}
+	return 1 - float64(LevenshteinDistance(a, b))/float64(longestLength)
+}
+
+// minimum Returns the smallest of the given integers
+func minimum(first int, others ...int) int {
+	result := first
+	for _, other := range others {
+		if other < result {
+			result = other
+		}
+	}
+	return result
+}
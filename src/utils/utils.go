@@ -2,11 +2,13 @@ package utils
 
 import (
 	"strconv"
-	"time"
 )
 
-// GetHoursAndMinutesString Generates a string with the format hours::minutes
-func GetHoursAndMinutesString() (result string) {
-	result = strconv.Itoa(time.Now().Hour()) + ":" + strconv.Itoa(time.Now().Minute())
+// GetHoursAndMinutesString Generates a string with the format hours::minutes for the time given by the clock
+//
+// The clock is read once so that hours and minutes always belong to the same instant
+func GetHoursAndMinutesString(clock Clock) (result string) {
+	now := clock.Now()
+	result = strconv.Itoa(now.Hour()) + ":" + strconv.Itoa(now.Minute())
 	return
 }
@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"time"
+)
+
+// Config Webhook delivery configuration
+type Config struct {
+
+	// MaxAttempts Number of attempts after which a delivery is given up
+	MaxAttempts int
+
+	// InitialRetryDelay Delay before the second attempt, doubled after every later failure
+	InitialRetryDelay time.Duration
+
+	// MaxRetryDelay Longest delay between two attempts
+	MaxRetryDelay time.Duration
+
+	// Timeout How long receivers have to answer a delivery
+	Timeout time.Duration
+
+	// PollInterval How often the outbox is checked for deliveries due for a retry
+	PollInterval time.Duration
+
+	// BatchSize Largest number of deliveries sent in one pass over the outbox
+	BatchSize int
+
+	// AllowPrivateTargets Lets webhooks send events to loopback, private and link-local addresses, see ErrForbiddenTarget
+	AllowPrivateTargets bool
+}
+
+// DefaultConfig Returns the webhook delivery configuration used by the docker setup
+//
+// Failing deliveries are retried for about a day before being given up
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts:       12,
+		InitialRetryDelay: 30 * time.Second,
+		MaxRetryDelay:     4 * time.Hour,
+		Timeout:           10 * time.Second,
+		PollInterval:      5 * time.Second,
+		BatchSize:         50,
+	}
+}
+
+// RetryDelay Returns how long to wait before the next attempt of a delivery that failed the given number of times
+func (config Config) RetryDelay(failedAttempts int) time.Duration {
+	delay := config.InitialRetryDelay
+	for attempt := 1; attempt < failedAttempts && delay < config.MaxRetryDelay; attempt++ {
+		delay *= 2
+	}
+	if delay > config.MaxRetryDelay {
+		return config.MaxRetryDelay
+	}
+	return delay
+}
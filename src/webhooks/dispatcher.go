@@ -0,0 +1,277 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/utils"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxErrorLength Longest error message recorded for a failed attempt, the size of its column
+const maxErrorLength = 1024
+
+// Dispatcher Delivers guest lifecycle events to the registered webhooks
+//
+// Events are recorded in the webhook_deliveries outbox when they happen and sent from it afterwards,
+// so that deliveries pending or being retried survive restarts. A delivery interrupted by a restart
+// is sent again: receivers may get the same delivery more than once.
+type Dispatcher struct {
+	store  *database.Store
+	clock  utils.Clock
+	logger *log.Logger
+	config Config
+	client *http.Client
+	wake   chan struct{}
+}
+
+// NewDispatcher Creates a Dispatcher working on the given store
+func NewDispatcher(store *database.Store, clock utils.Clock, logger *log.Logger, config Config) *Dispatcher {
+	return &Dispatcher{
+		store:  store,
+		clock:  clock,
+		logger: logger,
+		config: config,
+		client: newClient(config),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// newClient Creates the HTTP client sending deliveries, refusing to connect to the forbiddenNetworks unless
+// config.AllowPrivateTargets is set
+//
+// Addresses are checked when connecting rather than when webhooks are registered, so that host names resolving to
+// other addresses later are checked too. Deliveries do not go through proxies, which would connect in their stead.
+func newClient(config Config) *http.Client {
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	if !config.AllowPrivateTargets {
+		dialer.Control = refuseForbiddenAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: config.Timeout, Transport: transport}
+}
+
+// Enqueue Records a delivery of the event for every webhook registered for its type
+//
+// Used as event handler of the guest service. Failing to record a delivery does not undo the
+// operation that caused the event, the failure is logged instead.
+func (dispatcher *Dispatcher) Enqueue(event guestService.Event) {
+	webhooks, queryError := dispatcher.store.Webhooks()
+	if queryError != nil {
+		dispatcher.logger.Println("Webhook deliveries of " + event.Type + " lost: " + queryError.Error())
+		return
+	}
+
+	webhookEvent := api.WebhookEvent{
+		Event: event.Type,
+		Time:  event.Time,
+		Guest: api.WebhookEventGuest{
+			ID:                 event.Guest.ID,
+			Name:               event.Guest.Name,
+			Table:              event.Guest.Table,
+			AccompanyingGuests: event.Guest.AccompanyingGuests,
+			TimeArrived:        event.Guest.TimeArrived,
+		},
+	}
+	if event.Headcount != nil {
+		webhookEvent.Occupancy = &api.WebhookEventOccupancy{Headcount: event.Headcount.People, Capacity: event.Headcount.Venue.Capacity}
+	}
+	payload, encodeError := json.Marshal(webhookEvent)
+	if encodeError != nil {
+		dispatcher.logger.Println(encodeError.Error())
+		return
+	}
+
+	enqueued := false
+	for _, webhook := range webhooks {
+		if !Subscribed(webhook, event.Type) {
+			continue
+		}
+		delivery := database.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			Event:         event.Type,
+			Payload:       string(payload),
+			Status:        database.DeliveryPending,
+			CreatedAt:     event.Time,
+			NextAttemptAt: event.Time,
+		}
+		if storeError := dispatcher.store.AddWebhookDelivery(&delivery); storeError != nil {
+			dispatcher.logger.Println("Webhook delivery of " + event.Type + " to " + webhook.URL + " lost: " + storeError.Error())
+			continue
+		}
+		enqueued = true
+	}
+
+	if enqueued {
+		select {
+		case dispatcher.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run Sends the deliveries of the outbox as they become due until the context is done
+func (dispatcher *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		dispatcher.DeliverDue()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-dispatcher.wake:
+		}
+	}
+}
+
+// DeliverDue Attempts every delivery of the outbox that is due and returns the number of attempts made
+func (dispatcher *Dispatcher) DeliverDue() int {
+	attempts := 0
+	for {
+		deliveries, queryError := dispatcher.store.DueWebhookDeliveries(dispatcher.clock.Now(), dispatcher.config.BatchSize)
+		if queryError != nil {
+			dispatcher.logger.Println(queryError.Error())
+			return attempts
+		}
+		if len(deliveries) == 0 {
+			return attempts
+		}
+
+		webhooks, queryError := dispatcher.webhooksByID()
+		if queryError != nil {
+			dispatcher.logger.Println(queryError.Error())
+			return attempts
+		}
+
+		for _, delivery := range deliveries {
+			attempts++
+			if !dispatcher.attempt(&delivery, webhooks[delivery.WebhookID]) {
+				return attempts
+			}
+		}
+
+		// Deliveries failing again are due later, so a full batch means there may be more due now
+		if len(deliveries) < dispatcher.config.BatchSize {
+			return attempts
+		}
+	}
+}
+
+// webhooksByID Returns the registered webhooks indexed by ID
+func (dispatcher *Dispatcher) webhooksByID() (map[string]database.Webhook, error) {
+	webhooks, queryError := dispatcher.store.Webhooks()
+	if queryError != nil {
+		return nil, queryError
+	}
+	webhooksByID := make(map[string]database.Webhook, len(webhooks))
+	for _, webhook := range webhooks {
+		webhooksByID[webhook.ID] = webhook
+	}
+	return webhooksByID, nil
+}
+
+// attempt Sends a delivery to its webhook once and records the outcome in the outbox
+//
+// Returns false if the outcome could not be recorded, the delivery is then attempted again later
+func (dispatcher *Dispatcher) attempt(delivery *database.WebhookDelivery, webhook database.Webhook) bool {
+	delivery.Attempts++
+	delivery.LastStatusCode, delivery.LastError = 0, ""
+
+	if webhook.ID == "" {
+		delivery.LastError = "webhook was deleted"
+		delivery.Status = database.DeliveryFailed
+	} else if statusCode, sendError := dispatcher.send(delivery, webhook); sendError != nil {
+		delivery.LastStatusCode = statusCode
+		delivery.LastError = truncate(sendError.Error(), maxErrorLength)
+		if delivery.Attempts >= dispatcher.config.MaxAttempts {
+			delivery.Status = database.DeliveryFailed
+		} else {
+			delivery.NextAttemptAt = dispatcher.clock.Now().Add(dispatcher.config.RetryDelay(delivery.Attempts))
+		}
+	} else {
+		deliveredAt := dispatcher.clock.Now()
+		delivery.LastStatusCode = statusCode
+		delivery.Status = database.DeliveryDelivered
+		delivery.DeliveredAt = &deliveredAt
+	}
+
+	if delivery.Status == database.DeliveryFailed {
+		dispatcher.logger.Printf("Gave up webhook delivery %s to %s after %d attempts: %s\n", delivery.ID, webhook.URL, delivery.Attempts, delivery.LastError)
+	}
+	if storeError := dispatcher.store.SaveWebhookDelivery(delivery); storeError != nil {
+		dispatcher.logger.Println(storeError.Error())
+		return false
+	}
+	return true
+}
+
+// send Posts a delivery to a webhook, an error is reported unless the receiver answers with a 2xx status
+func (dispatcher *Dispatcher) send(delivery *database.WebhookDelivery, webhook database.Webhook) (int, error) {
+	payload := []byte(delivery.Payload)
+	request, requestError := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if requestError != nil {
+		return 0, requestError
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "guestlist-webhooks")
+	request.Header.Set(api.WebhookEventHeader, delivery.Event)
+	request.Header.Set(api.WebhookDeliveryHeader, delivery.ID)
+	request.Header.Set(api.WebhookSignatureHeader, Sign(webhook.Secret, payload))
+
+	response, sendError := dispatcher.client.Do(request)
+	if sendError != nil {
+		return 0, sendError
+	}
+	defer response.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(response.Body, 64*1024))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("receiver answered %s", response.Status)
+	}
+	return response.StatusCode, nil
+}
+
+// Subscribed Checks if a webhook is registered for an event type
+func Subscribed(webhook database.Webhook, eventType string) bool {
+	for _, subscribedType := range SplitEvents(webhook.Events) {
+		if subscribedType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// JoinEvents Returns the event types of a webhook as stored in the database
+func JoinEvents(eventTypes []string) string {
+	return strings.Join(eventTypes, ",")
+}
+
+// SplitEvents Returns the event types of a webhook stored in the database
+func SplitEvents(events string) []string {
+	if events == "" {
+		return []string{}
+	}
+	return strings.Split(events, ",")
+}
+
+// truncate Shortens a text to at most maxLength bytes
+func truncate(text string, maxLength int) string {
+	if len(text) <= maxLength {
+		return text
+	}
+	return text[:maxLength]
+}
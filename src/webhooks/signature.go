@@ -0,0 +1,28 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// signaturePrefix Prefix of the signatures naming the hash function used
+const signaturePrefix = "sha256="
+
+// Sign Returns the signature of a delivery payload sent in the api.WebhookSignatureHeader
+//
+// The signature is the hex encoded HMAC-SHA256 of the payload keyed with the webhook's secret
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify Checks that a signature was made with the given secret, for receivers written in Go
+func Verify(secret string, payload []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, payload)), []byte(signature))
+}
@@ -0,0 +1,78 @@
+package webhooks
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+// ErrForbiddenTarget Reported for webhooks sending events to the loopback interface, a private network or a link-local
+// address, cloud metadata endpoints included, unless Config.AllowPrivateTargets is set
+var ErrForbiddenTarget = errors.New("webhooks cannot send events to loopback, private or link-local addresses")
+
+// forbiddenNetworks Networks webhooks cannot send events to: this host, private networks and link-local addresses,
+// where cloud providers serve instance metadata
+var forbiddenNetworks = parseNetworks(
+	"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12", "192.168.0.0/16",
+	"::/128", "::1/128", "fc00::/7", "fe80::/10",
+)
+
+// parseNetworks Parses networks in CIDR notation
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, parseError := net.ParseCIDR(cidr)
+		if parseError != nil {
+			panic(parseError)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// forbiddenAddress Checks if an address is in one of the forbiddenNetworks
+func forbiddenAddress(ip net.IP) bool {
+	for _, network := range forbiddenNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckURL Checks that a webhook URL is an absolute http or https URL its events can be sent to
+//
+// ErrForbiddenTarget is reported for addresses and local host names in the forbiddenNetworks. Other host names are
+// checked when deliveries connect, the addresses they resolve to changing over time.
+func (config Config) CheckURL(webhookURL string) error {
+	parsedURL, parseError := url.Parse(webhookURL)
+	if parseError != nil || parsedURL.Hostname() == "" || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		return errors.New("must be an absolute http or https URL")
+	}
+	if config.AllowPrivateTargets {
+		return nil
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(parsedURL.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenTarget
+	}
+	if ip := net.ParseIP(host); ip != nil && forbiddenAddress(ip) {
+		return ErrForbiddenTarget
+	}
+	return nil
+}
+
+// refuseForbiddenAddress Refuses the connections of deliveries to the forbiddenNetworks, used as net.Dialer Control
+func refuseForbiddenAddress(_ string, address string, _ syscall.RawConn) error {
+	host, _, splitError := net.SplitHostPort(address)
+	if splitError != nil {
+		return splitError
+	}
+	if ip := net.ParseIP(host); ip == nil || forbiddenAddress(ip) {
+		return ErrForbiddenTarget
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package audittest
+
+import (
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/auth"
+	"guestListChallenge/src/database"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseAPIKeys Checks that API keys are read from comma separated actor:key pairs
+func TestParseAPIKeys(t *testing.T) {
+	actorsByKey, err := auth.ParseAPIKeys(" door-staff:k3y-one, organiser:key:with:colons ,")
+	if err != nil {
+		t.Fatalf("Couldn't parse API keys: %v\n", err)
+	}
+	expectedActorsByKey := map[string]string{"k3y-one": "door-staff", "key:with:colons": "organiser"}
+	if !reflect.DeepEqual(actorsByKey, expectedActorsByKey) {
+		t.Errorf("Expected %v, got %v\n", expectedActorsByKey, actorsByKey)
+	}
+
+	for _, invalidValue := range []string{"door-staff", ":key", "door-staff:"} {
+		if _, err := auth.ParseAPIKeys(invalidValue); err == nil {
+			t.Errorf("Expected %q to be rejected\n", invalidValue)
+		}
+	}
+}
+
+// TestAuthenticator Checks that requests are attributed to the actor of their API key
+func TestAuthenticator(t *testing.T) {
+	authenticator := auth.NewAuthenticator(map[string]string{"k3y-one": "door-staff"}, map[string]string{"adm1n-k3y": "organiser"})
+
+	var testCases = []struct {
+		testCaseName  string
+		authorization string
+		expectedActor string
+		expectedOk    bool
+	}{
+		{"Known key", "Bearer k3y-one", "door-staff", true},
+		{"Admin key", "Bearer adm1n-k3y", "organiser", true},
+		{"Unknown key", "Bearer k3y-two", "", false},
+		{"Key prefix", "Bearer k3y", "", false},
+		{"Not a bearer token", "Basic k3y-one", "", false},
+		{"Missing key", "", "", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			actor, ok := authenticator.Actor(testCase.authorization)
+			if actor != testCase.expectedActor || ok != testCase.expectedOk {
+				t.Errorf("Expected %q %v, got %q %v\n", testCase.expectedActor, testCase.expectedOk, actor, ok)
+			}
+		})
+	}
+
+	if actor, ok := auth.NewAuthenticator(nil, nil).Actor(""); actor != auth.Anonymous || !ok {
+		t.Errorf("Expected anonymous requests without API keys, got %q %v\n", actor, ok)
+	}
+
+	// Only admin keys give access to the administration, and none does when no admin key is configured
+	if actor, ok := authenticator.Admin("Bearer adm1n-k3y"); actor != "organiser" || !ok {
+		t.Errorf("Expected the admin key to be accepted, got %q %v\n", actor, ok)
+	}
+	if _, ok := authenticator.Admin("Bearer k3y-one"); ok {
+		t.Errorf("Expected an API key not to give access to the administration\n")
+	}
+	if _, ok := auth.NewAuthenticator(nil, nil).Admin(""); ok {
+		t.Errorf("Expected no access to the administration without admin keys\n")
+	}
+}
+
+// TestRequestID Checks that usable client request IDs are kept and others replaced
+func TestRequestID(t *testing.T) {
+	if requestID := audit.RequestID("door-2.scan_17"); requestID != "door-2.scan_17" {
+		t.Errorf("Expected the client request ID to be kept, got %q\n", requestID)
+	}
+	for _, clientRequestID := range []string{"", "two words", "line\nbreak", strings.Repeat("a", 65)} {
+		requestID := audit.RequestID(clientRequestID)
+		if requestID == clientRequestID || len(requestID) != 36 {
+			t.Errorf("Expected %q to be replaced by a generated ID, got %q\n", clientRequestID, requestID)
+		}
+	}
+}
+
+// TestHash Checks that the hash of a record changes with any of its fields
+func TestHash(t *testing.T) {
+	record := database.AuditRecord{
+		Sequence:     1,
+		Time:         time.Date(2021, 12, 17, 20, 30, 0, 0, time.UTC),
+		Actor:        "door-staff",
+		RequestID:    "door-2.scan_17",
+		Operation:    audit.OperationCheckInGuest,
+		TargetID:     "guest-1",
+		Before:       `{"time_arrived":""}`,
+		After:        `{"time_arrived":"20:30"}`,
+		PreviousHash: "",
+	}
+	hash := audit.Hash(record)
+
+	// The sequence number and the stored hash are not covered
+	unchanged := record
+	unchanged.Sequence, unchanged.Hash = 7, "anything"
+	if audit.Hash(unchanged) != hash {
+		t.Error("Expected the hash not to cover the sequence number and the stored hash\n")
+	}
+
+	changes := map[string]func(*database.AuditRecord){
+		"time":          func(changed *database.AuditRecord) { changed.Time = changed.Time.Add(time.Second) },
+		"actor":         func(changed *database.AuditRecord) { changed.Actor = "organiser" },
+		"operation":     func(changed *database.AuditRecord) { changed.Operation = audit.OperationCheckOutGuest },
+		"after":         func(changed *database.AuditRecord) { changed.After = `{"time_arrived":"20:31"}` },
+		"previous hash": func(changed *database.AuditRecord) { changed.PreviousHash = hash },
+		"moved text": func(changed *database.AuditRecord) {
+			changed.Actor, changed.RequestID = "door-staffdoor-2", ".scan_17"
+		},
+	}
+	for changeName, change := range changes {
+		changed := record
+		change(&changed)
+		if audit.Hash(changed) == hash {
+			t.Errorf("Expected a change of %s to change the hash\n", changeName)
+		}
+	}
+}
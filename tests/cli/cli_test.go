@@ -0,0 +1,245 @@
+package clitest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"guestListChallenge/src/cli"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/requestRouting"
+	"guestListChallenge/src/utils"
+	"guestListChallenge/tests/testdatabase"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// apiKey API key the test server accepts, given by the default profile
+const apiKey = "door-staff-key"
+
+// store Database used by the test server
+var store *database.Store
+
+// ids Generator of the IDs given to guests added by the test server
+var ids = utils.NewSequenceIDGenerator("guest-")
+
+// serverURL URL of the test server
+var serverURL string
+
+// unreachableURL URL of a server that stopped
+var unreachableURL string
+
+// profileDirectory Directory of the profile file and of the CSV files written by the tests
+var profileDirectory string
+
+func TestMain(m *testing.M) {
+	db, closeDatabase := testdatabase.Open(os.Getenv(testdatabase.DriverVariable))
+	store = database.NewStore(db, ids)
+	if _, operationError := database.MigrateUp(store.DB()); operationError != nil {
+		fmt.Println(operationError.Error())
+		panic("Could not migrate database")
+	}
+
+	config := requestRouting.DefaultConfig()
+	config.APIKeys = map[string]string{apiKey: "door-staff"}
+	clock := utils.NewFakeClock(time.Date(2021, time.December, 17, 21, 5, 0, 0, time.UTC))
+	server := httptest.NewServer(requestRouting.NewServer(store, clock, log.New(ioutil.Discard, "", 0), config).Handler())
+	serverURL = server.URL
+	stoppedServer := httptest.NewServer(nil)
+	unreachableURL = stoppedServer.URL
+	stoppedServer.Close()
+
+	// The client reads its connection settings from the profile file only
+	var operationError error
+	if profileDirectory, operationError = os.MkdirTemp("", "guestlist-cli"); operationError != nil {
+		fmt.Println(operationError.Error())
+		panic("Could not create profile directory")
+	}
+	profiles, _ := json.Marshal(map[string]interface{}{
+		"default_profile": "venue",
+		"profiles": map[string]interface{}{
+			"venue":     map[string]string{"server_url": serverURL, "api_key": apiKey},
+			"elsewhere": map[string]string{"server_url": unreachableURL, "api_key": apiKey},
+			"no-key":    map[string]string{"server_url": serverURL},
+		},
+	})
+	profilePath := filepath.Join(profileDirectory, "profiles.json")
+	if operationError = ioutil.WriteFile(profilePath, profiles, 0600); operationError != nil {
+		fmt.Println(operationError.Error())
+		panic("Could not write profile file")
+	}
+	os.Setenv("GUESTLIST_CONFIG", profilePath)
+	os.Unsetenv("GUESTLIST_PROFILE")
+	os.Unsetenv("GUESTLIST_SERVER_URL")
+	os.Unsetenv("GUESTLIST_API_KEY")
+
+	code := m.Run()
+
+	server.Close()
+	closeDatabase()
+	os.RemoveAll(profileDirectory)
+
+	os.Exit(code)
+}
+
+// resetDatabase Empties the guest list
+func resetDatabase() {
+	store.DB().Delete(&database.Visit{})
+	store.DB().Delete(&database.GuestList{})
+	ids.Reset()
+}
+
+// runCommand Runs the client with the given arguments and returns its exit code and outputs
+func runCommand(arguments ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	exitCode := cli.Run(arguments, &stdout, &stderr)
+	return exitCode, stdout.String(), stderr.String()
+}
+
+// TestExitCodes Checks that each outcome of a command is told apart by its exit code
+func TestExitCodes(t *testing.T) {
+	resetDatabase()
+	runCommand("add", "Santos", "--table", "4")
+	runCommand("add", "Santos", "--table", "2")
+
+	var testCases = []struct {
+		testCaseName     string
+		arguments        []string
+		expectedExitCode int
+		expectedOutput   string
+	}{
+		{"Adding a guest", []string{"add", "Silva", "--table", "4", "--accompanying", "1"}, cli.ExitOK, "Added Silva (ID guest-3)"},
+		{"Asking for help", []string{"help"}, cli.ExitOK, "Usage: guestlist <command>"},
+		{"Adding a guest whose table is too small", []string{"add", "Costa", "--table", "2", "--accompanying", "3"}, cli.ExitRefused, "Guest will no be added"},
+		{"Checking out a guest who has not arrived", []string{"checkout", "Silva"}, cli.ExitRefused, "has not arrived yet"},
+		{"Running no command", []string{}, cli.ExitUsage, "Usage: guestlist <command>"},
+		{"Running an unknown command", []string{"invite", "Silva"}, cli.ExitUsage, `Unknown command "invite"`},
+		{"Adding a guest without table", []string{"add", "Costa"}, cli.ExitUsage, "Usage: guestlist add"},
+		{"Undoing an operation that is not a number", []string{"undo", "last"}, cli.ExitUsage, "Usage: guestlist undo"},
+		{"Adding a guest whose name starts with a digit", []string{"add", "4 Costa", "--table", "4"}, cli.ExitInvalidRequest, "path.name"},
+		{"Checking in a guest who is not invited", []string{"checkin", "Pereira"}, cli.ExitNotFound, "Pereira is not in the guest list"},
+		{"Undoing an unknown operation", []string{"undo", "999999999"}, cli.ExitNotFound, "is not in the audit log"},
+		{"Checking in a name shared by several guests", []string{"checkin", "Santos"}, cli.ExitAmbiguous, "guest-1"},
+		{"Reaching no server", []string{"seats", "--server", unreachableURL}, cli.ExitUnavailable, "connection refused"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			exitCode, stdout, stderr := runCommand(testCase.arguments...)
+			if exitCode != testCase.expectedExitCode {
+				t.Errorf("Expected exit code %d, got %d\nstdout: %s\nstderr: %s\n", testCase.expectedExitCode, exitCode, stdout, stderr)
+			}
+			if !strings.Contains(stdout+stderr, testCase.expectedOutput) {
+				t.Errorf("Expected the output to contain %q\nstdout: %s\nstderr: %s\n", testCase.expectedOutput, stdout, stderr)
+			}
+		})
+	}
+}
+
+// TestProfiles Checks that the server and API key come from the chosen profile, flags overriding them
+func TestProfiles(t *testing.T) {
+	resetDatabase()
+
+	var testCases = []struct {
+		testCaseName     string
+		arguments        []string
+		expectedExitCode int
+	}{
+		{"Default profile", []string{"seats"}, cli.ExitOK},
+		{"Profile of another server", []string{"seats", "--profile", "elsewhere"}, cli.ExitUnavailable},
+		{"Server overriding the profile's", []string{"seats", "--profile", "elsewhere", "--server", serverURL}, cli.ExitOK},
+		{"Profile without API key", []string{"seats", "--profile", "no-key"}, cli.ExitRefused},
+		{"API key overriding the profile's", []string{"seats", "--profile", "no-key", "--api-key", apiKey}, cli.ExitOK},
+		{"Unknown profile", []string{"seats", "--profile", "backstage"}, cli.ExitUsage},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			if exitCode, stdout, stderr := runCommand(testCase.arguments...); exitCode != testCase.expectedExitCode {
+				t.Errorf("Expected exit code %d, got %d\nstdout: %s\nstderr: %s\n", testCase.expectedExitCode, exitCode, stdout, stderr)
+			}
+		})
+	}
+
+	// The profile is also chosen by environment variable
+	os.Setenv("GUESTLIST_PROFILE", "elsewhere")
+	defer os.Unsetenv("GUESTLIST_PROFILE")
+	if exitCode, _, _ := runCommand("seats"); exitCode != cli.ExitUnavailable {
+		t.Errorf("Expected the profile of GUESTLIST_PROFILE to be used, got exit code %d\n", exitCode)
+	}
+}
+
+// readCSV Reads the rows of a CSV file
+func readCSV(t *testing.T, path string) [][]string {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Couldn't open %s: %v\n", path, err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Couldn't read %s: %v\n", path, err)
+	}
+	return records
+}
+
+// TestImportExportRoundTrip Checks that an exported guest list can be imported again as it is
+func TestImportExportRoundTrip(t *testing.T) {
+	resetDatabase()
+
+	importPath := filepath.Join(profileDirectory, "guests.csv")
+	guests := "name,table,accompanying_guests\nPereira,6,2\nJoão Costa,3,0\nAna O'Neill,2,1\nSantos,1,3\n"
+	if err := ioutil.WriteFile(importPath, []byte(guests), 0600); err != nil {
+		t.Fatalf("Couldn't write %s: %v\n", importPath, err)
+	}
+
+	// Rows that fail do not stop the import, their exit code is reported
+	exitCode, stdout, stderr := runCommand("import", importPath)
+	if exitCode != cli.ExitRefused || !strings.Contains(stdout, "Imported 3 of 4 guests") || !strings.Contains(stderr, "Row 5 (Santos)") {
+		t.Errorf("Expected Santos to be refused, got exit code %d\nstdout: %s\nstderr: %s\n", exitCode, stdout, stderr)
+	}
+	exportPath := filepath.Join(profileDirectory, "export.csv")
+	if exitCode, _, stderr := runCommand("export", exportPath); exitCode != cli.ExitOK {
+		t.Fatalf("Expected the export to succeed, got exit code %d: %s\n", exitCode, stderr)
+	}
+	exported := readCSV(t, exportPath)
+	expectedRows := [][]string{
+		{"name", "table", "accompanying_guests", "id"},
+		{"Ana O'Neill", "2", "1", "guest-3"},
+		{"João Costa", "3", "0", "guest-2"},
+		{"Pereira", "6", "2", "guest-1"},
+	}
+	if !reflect.DeepEqual(exported, expectedRows) {
+		t.Errorf("Expected export %q, got %q\n", expectedRows, exported)
+	}
+
+	// The exported file is imported into an empty guest list as it is, and exported the same way
+	resetDatabase()
+	if exitCode, stdout, stderr := runCommand("import", exportPath); exitCode != cli.ExitOK || !strings.Contains(stdout, "Imported 3 of 3 guests") {
+		t.Fatalf("Expected the export to be imported, got exit code %d\nstdout: %s\nstderr: %s\n", exitCode, stdout, stderr)
+	}
+	exitCode, stdout, stderr = runCommand("export")
+	if exitCode != cli.ExitOK {
+		t.Fatalf("Expected the export to succeed, got exit code %d: %s\n", exitCode, stderr)
+	}
+	reexported, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	if err != nil {
+		t.Fatalf("Couldn't read export %q: %v\n", stdout, err)
+	}
+	// Guests are given new IDs when imported
+	for _, records := range [][][]string{exported, reexported} {
+		for index := range records {
+			records[index] = records[index][:3]
+		}
+	}
+	if !reflect.DeepEqual(reexported, exported) {
+		t.Errorf("Expected the round trip to keep the guest list %q, got %q\n", exported, reexported)
+	}
+}
@@ -0,0 +1,213 @@
+package clienttest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/client"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/requestRouting"
+	"guestListChallenge/src/utils"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testConfig Client configuration retrying without noticeable waits
+func testConfig() client.Config {
+	config := client.DefaultConfig()
+	config.APIKey = "test key"
+	config.RetryDelay = time.Millisecond
+	return config
+}
+
+// replyWith Returns a handler replying with a JSON body, an HTTP status and an optional error code
+func replyWith(status int, errorCode string, reply interface{}) http.HandlerFunc {
+	return func(response http.ResponseWriter, _ *http.Request) {
+		if errorCode != "" {
+			response.Header().Set(api.ErrorCodeHeader, errorCode)
+		}
+		response.Header().Set("Content-Type", "application/json")
+		response.WriteHeader(status)
+		json.NewEncoder(response).Encode(reply)
+	}
+}
+
+// TestAddGuest Checks the request sent to add a guest and the decoding of the reply
+func TestAddGuest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost || request.URL.EscapedPath() != "/guest_list/Jo%C3%A3o%20Silva" {
+			t.Errorf("Unexpected request %s %s\n", request.Method, request.URL.EscapedPath())
+		}
+		if request.Header.Get("Authorization") != "Bearer test key" {
+			t.Errorf("Unexpected Authorization header %q\n", request.Header.Get("Authorization"))
+		}
+		if request.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected Content-Type header %q\n", request.Header.Get("Content-Type"))
+		}
+
+		var requestData api.AddGuestRequest
+		if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+			t.Errorf("Couldn't decode request: %v\n", err)
+		}
+		if requestData != (api.AddGuestRequest{Table: 4, AccompanyingGuests: 2}) {
+			t.Errorf("Unexpected request body %+v\n", requestData)
+		}
+
+		replyWith(http.StatusOK, "", requestRouting.CreateAddGuestResponse(database.GuestList{ID: "guest-1", Name: "João Silva"}))(response, request)
+	}))
+	defer server.Close()
+
+	guest, err := client.NewClient(server.URL, testConfig()).AddGuest(context.Background(), "João Silva", 4, 2)
+	if err != nil {
+		t.Fatalf("Couldn't add guest: %v\n", err)
+	}
+	if guest != (api.GuestResponse{ID: "guest-1", Name: "João Silva"}) {
+		t.Errorf("Unexpected reply %+v\n", guest)
+	}
+}
+
+// TestErrorReplies Checks that error replies are reported as errors matching the server's error codes
+func TestErrorReplies(t *testing.T) {
+	martins := []database.GuestList{{ID: "guest-1", Name: "Martins", Table: 4}, {ID: "guest-2", Name: "Martins", Table: 6}}
+
+	var testCases = []struct {
+		testCaseName       string
+		handler            http.HandlerFunc
+		expectedError      error
+		expectedCandidates []api.AmbiguousGuestCandidate
+	}{
+		{"Guest not found", replyWith(http.StatusOK, api.ErrorCodeGuestNotFound, "Guest Nobody is not in the guest list"), client.ErrGuestNotFound, nil},
+		{"Already checked in", replyWith(http.StatusOK, api.ErrorCodeAlreadyCheckedIn, "Guest Martins already checked in"), client.ErrAlreadyCheckedIn, nil},
+		{"Entourage too big", replyWith(http.StatusOK, api.ErrorCodeEntourageTooBig, "Guest Martins arrived with an entourage bigger than the registered one"), client.ErrEntourageTooBig, nil},
+		{"Ambiguous guest", replyWith(http.StatusMultipleChoices, api.ErrorCodeAmbiguousGuest, requestRouting.CreateAmbiguousGuestResponse("Martins", martins)), client.ErrAmbiguousGuest,
+			[]api.AmbiguousGuestCandidate{{ID: "guest-1", Name: "Martins", Table: 4}, {ID: "guest-2", Name: "Martins", Table: 6}}},
+		{"Internal error", replyWith(http.StatusInternalServerError, api.ErrorCodeInternal, "Database unreachable"), client.ErrInternal, nil},
+		{"Status without error code", replyWith(http.StatusNotFound, "", "404 page not found"), &client.Error{Status: http.StatusNotFound}, nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			server := httptest.NewServer(testCase.handler)
+			defer server.Close()
+
+			_, err := client.NewClient(server.URL, testConfig()).CheckInGuest(context.Background(), "Martins", 2)
+			if !errors.Is(err, testCase.expectedError) {
+				t.Fatalf("Expected %v, got %v\n", testCase.expectedError, err)
+			}
+
+			var replyError *client.Error
+			if !errors.As(err, &replyError) {
+				t.Fatalf("Error is not a *client.Error: %v\n", err)
+			}
+			if replyError.Message == "" {
+				t.Errorf("Error has no message\n")
+			}
+			if !reflect.DeepEqual(replyError.Candidates, testCase.expectedCandidates) {
+				t.Errorf("Expected candidates %+v, got %+v\n", testCase.expectedCandidates, replyError.Candidates)
+			}
+		})
+	}
+}
+
+// TestUndoConflict Checks that the later operations preventing an undo are reported with the error
+func TestUndoConflict(t *testing.T) {
+	checkOut := guestService.Operation{
+		Record: database.AuditRecord{Sequence: 8, Actor: "door-staff", Operation: "check_out_guest", TargetID: "guest-1"},
+		Guest:  database.GuestList{ID: "guest-1", Name: "Martins"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost || request.URL.Path != "/operations/7/undo" {
+			t.Errorf("Unexpected request %s %s\n", request.Method, request.URL.Path)
+		}
+		replyWith(http.StatusConflict, api.ErrorCodeUndoConflict,
+			requestRouting.CreateUndoConflictResponse("Operation 7 cannot be undone", []guestService.Operation{checkOut}))(response, request)
+	}))
+	defer server.Close()
+
+	_, err := client.NewClient(server.URL, testConfig()).UndoOperation(context.Background(), 7)
+	if !errors.Is(err, client.ErrUndoConflict) {
+		t.Fatalf("Expected %v, got %v\n", client.ErrUndoConflict, err)
+	}
+
+	var replyError *client.Error
+	errors.As(err, &replyError)
+	expectedConflicts := []api.Operation{{ID: 8, Actor: "door-staff", Operation: "check_out_guest", GuestID: "guest-1", GuestName: "Martins"}}
+	if replyError.Message != "Operation 7 cannot be undone" || !reflect.DeepEqual(replyError.Conflicts, expectedConflicts) {
+		t.Errorf("Unexpected error %+v\n", replyError)
+	}
+}
+
+// TestValidationErrors Checks that the violations found by the server in an invalid request are reported
+func TestValidationErrors(t *testing.T) {
+	guestListServer := requestRouting.NewServer(nil, utils.RealClock{}, log.New(ioutil.Discard, "", 0), requestRouting.DefaultConfig())
+	server := httptest.NewServer(guestListServer.Handler())
+	defer server.Close()
+
+	_, err := client.NewClient(server.URL, testConfig()).AddGuest(context.Background(), "Francisco", 0, -1)
+	if !errors.Is(err, client.ErrInvalidRequest) {
+		t.Fatalf("Expected %v, got %v\n", client.ErrInvalidRequest, err)
+	}
+
+	var replyError *client.Error
+	errors.As(err, &replyError)
+	if replyError.Status != http.StatusUnprocessableEntity || len(replyError.Violations) != 2 {
+		t.Errorf("Unexpected error %+v\n", replyError)
+	}
+}
+
+// TestRetries Checks that requests are sent again while the server is unavailable
+func TestRetries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			replyWith(http.StatusServiceUnavailable, "", "Try again later")(response, request)
+			return
+		}
+		replyWith(http.StatusOK, "", requestRouting.CreateGetNumberOfEmptySeatsResponse(guestService.Seats{Empty: 7, Available: 7}))(response, request)
+	}))
+	defer server.Close()
+
+	seats, err := client.NewClient(server.URL, testConfig()).EmptySeats(context.Background())
+	if err != nil {
+		t.Fatalf("Couldn't get empty seats: %v\n", err)
+	}
+	if seats != 7 || attempts != 3 {
+		t.Errorf("Expected 7 seats after 3 attempts, got %d seats after %d attempts\n", seats, attempts)
+	}
+
+	// Give up after MaxRetries retries
+	atomic.StoreInt32(&attempts, -10)
+	config := testConfig()
+	config.MaxRetries = 2
+	_, err = client.NewClient(server.URL, config).EmptySeats(context.Background())
+	if !errors.Is(err, client.ErrUnavailable) {
+		t.Errorf("Expected %v, got %v\n", client.ErrUnavailable, err)
+	}
+	if attempts != -7 {
+		t.Errorf("Expected 3 attempts, got %d\n", attempts+10)
+	}
+}
+
+// TestContextCancellation Checks that a cancelled context stops the retries
+func TestContextCancellation(t *testing.T) {
+	server := httptest.NewServer(replyWith(http.StatusServiceUnavailable, "", "Try again later"))
+	defer server.Close()
+
+	config := testConfig()
+	config.RetryDelay = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.NewClient(server.URL, config).GuestList(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %v, got %v\n", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,257 @@
+package databasetest
+
+import (
+	"guestListChallenge/src/database"
+	"guestListChallenge/tests/testdatabase"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMigrations Tests that the embedded migrations of every driver are complete and ordered by version
+func TestMigrations(t *testing.T) {
+	for _, driver := range database.Drivers {
+		migrations, err := database.Migrations(driver)
+		if err != nil {
+			t.Fatalf("Couldn't load %s migrations: %v\n", driver, err)
+		}
+
+		if len(migrations) == 0 {
+			t.Fatalf("No %s migrations embedded in the binary\n", driver)
+		}
+
+		for index, migration := range migrations {
+			if migration.Version != index+1 {
+				t.Errorf("%s migration %s has version %d, expected %d\n", driver, migration.Name, migration.Version, index+1)
+			}
+			if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+				t.Errorf("%s migration %d_%s is missing its up or down script\n", driver, migration.Version, migration.Name)
+			}
+		}
+
+		latestVersion, err := database.LatestSchemaVersion(driver)
+		if err != nil {
+			t.Fatalf("Couldn't get latest %s schema version: %v\n", driver, err)
+		}
+		if latestVersion != migrations[len(migrations)-1].Version {
+			t.Errorf("Wrong latest %s schema version %d\n", driver, latestVersion)
+		}
+	}
+
+	if _, err := database.Migrations("oracle"); err == nil {
+		t.Error("Migrations of an unknown driver were loaded\n")
+	}
+}
+
+// TestMigrationsMatchAcrossDrivers Tests that every driver has the same migrations
+func TestMigrationsMatchAcrossDrivers(t *testing.T) {
+	names := func(driver string) []string {
+		migrations, err := database.Migrations(driver)
+		if err != nil {
+			t.Fatalf("Couldn't load %s migrations: %v\n", driver, err)
+		}
+		var migrationNames []string
+		for _, migration := range migrations {
+			migrationNames = append(migrationNames, migration.Name)
+		}
+		return migrationNames
+	}
+
+	expected := names(database.DriverMySQL)
+	for _, driver := range database.Drivers {
+		if actual := names(driver); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s migrations %v differ from the mysql ones %v\n", driver, actual, expected)
+		}
+	}
+}
+
+// TestSplitStatements Tests that migration scripts are split only on the semicolons ending a statement
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected []string
+	}{
+		{"Plain statements", "CREATE TABLE a (id INT);\nDROP TABLE b;\n", []string{"CREATE TABLE a (id INT)", "DROP TABLE b"}},
+		{"Missing final semicolon", "DROP TABLE a;\nDROP TABLE b", []string{"DROP TABLE a", "DROP TABLE b"}},
+		{"Quoted semicolon", "INSERT INTO a VALUES ('x;y');", []string{"INSERT INTO a VALUES ('x;y')"}},
+		{"Comment", "-- drop; everything\nDROP TABLE a;", []string{"-- drop; everything\nDROP TABLE a"}},
+		{
+			"Dollar-quoted function body",
+			"CREATE FUNCTION f() RETURNS trigger AS $$ BEGIN RAISE EXCEPTION 'no'; END; $$ LANGUAGE plpgsql;\nDROP TABLE a;",
+			[]string{"CREATE FUNCTION f() RETURNS trigger AS $$ BEGIN RAISE EXCEPTION 'no'; END; $$ LANGUAGE plpgsql", "DROP TABLE a"},
+		},
+		{
+			"Trigger body",
+			"CREATE TRIGGER t BEFORE DELETE ON a BEGIN SELECT RAISE(ABORT, 'no'); END;\nDROP TABLE b;",
+			[]string{"CREATE TRIGGER t BEFORE DELETE ON a BEGIN SELECT RAISE(ABORT, 'no'); END", "DROP TABLE b"},
+		},
+	}
+
+	for _, test := range tests {
+		if actual := database.SplitStatements(test.script); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s: got %q, expected %q\n", test.name, actual, test.expected)
+		}
+	}
+}
+
+// TestSQLiteMigrations Tests applying and reverting every SQLite migration on a database file
+func TestSQLiteMigrations(t *testing.T) {
+	config := database.DefaultConfig()
+	config.Driver = database.DriverSQLite
+	config.Path = filepath.Join(t.TempDir(), "guestlist.db")
+
+	store, err := database.Open(config)
+	if err != nil {
+		t.Fatalf("Couldn't open the SQLite database: %v\n", err)
+	}
+	defer store.Close()
+
+	migrations, _ := database.Migrations(database.DriverSQLite)
+	migrated, err := database.MigrateUp(store.DB())
+	if err != nil {
+		t.Fatalf("Couldn't apply the migrations: %v\n", err)
+	}
+	if len(migrated) != len(migrations) {
+		t.Fatalf("Applied %d migrations, expected %d\n", len(migrated), len(migrations))
+	}
+
+	// The audit log is append-only
+	insertError := store.DB().Exec(`INSERT INTO audit_records (time, actor, request_id, operation, target_id, "before", "after", previous_hash, hash) VALUES ('2026-01-01 00:00:00', 'a', '', 'o', 't', 'null', 'null', '', 'h')`).Error
+	if insertError != nil {
+		t.Fatalf("Couldn't append an audit record: %v\n", insertError)
+	}
+	if err := store.DB().Exec("UPDATE audit_records SET actor = 'b'").Error; err == nil {
+		t.Error("An audit record was updated\n")
+	}
+	if err := store.DB().Exec("DELETE FROM audit_records").Error; err == nil {
+		t.Error("An audit record was deleted\n")
+	}
+
+	for range migrations {
+		if _, err := database.MigrateDown(store.DB()); err != nil {
+			t.Fatalf("Couldn't revert a migration: %v\n", err)
+		}
+	}
+	if version, _ := database.SchemaVersion(store.DB()); version != 0 {
+		t.Fatalf("Schema version %d after reverting every migration\n", version)
+	}
+
+	if _, err := database.MigrateUp(store.DB()); err != nil {
+		t.Fatalf("Couldn't apply the migrations again: %v\n", err)
+	}
+}
+
+// TestSharedNamesBlockGuestIDsRevert Tests that the guest list is not keyed by name again while guests share a name
+func TestSharedNamesBlockGuestIDsRevert(t *testing.T) {
+	config := database.DefaultConfig()
+	config.Driver = database.DriverSQLite
+	config.Path = filepath.Join(t.TempDir(), "guestlist.db")
+
+	store, err := database.Open(config)
+	if err != nil {
+		t.Fatalf("Couldn't open the SQLite database: %v\n", err)
+	}
+	defer store.Close()
+
+	if _, err := database.MigrateUp(store.DB()); err != nil {
+		t.Fatalf("Couldn't apply the migrations: %v\n", err)
+	}
+	for _, id := range []string{"guest-1", "guest-2"} {
+		if err := store.DB().Exec(`INSERT INTO guest_lists (id, name, "table", accompanying_guests, time_arrived) VALUES (?, 'Martins', 4, 0, '')`, id).Error; err != nil {
+			t.Fatalf("Couldn't add a guest: %v\n", err)
+		}
+	}
+
+	migrations, _ := database.Migrations(database.DriverSQLite)
+	var revertError error
+	for range migrations {
+		if _, revertError = database.MigrateDown(store.DB()); revertError != nil {
+			break
+		}
+	}
+	if revertError == nil || !strings.Contains(revertError.Error(), "guests share the names Martins") {
+		t.Errorf("Expected the revert to be refused because of the shared name, got %v\n", revertError)
+	}
+	if version, _ := database.SchemaVersion(store.DB()); version != 2 {
+		t.Errorf("Expected schema version 2 to be kept, got %d\n", version)
+	}
+	var guests int
+	store.DB().Table("guest_lists").Count(&guests)
+	if guests != 2 {
+		t.Errorf("Expected both guests to be kept, got %d\n", guests)
+	}
+}
+
+// TestMigrationRoundTrip Tests reverting the migrations one more at a time on a populated database and applying them again,
+// the guest list being kept through every table rebuild
+//
+// MySQL and PostgreSQL run in Docker, only when GUESTLIST_TEST_DRIVER asks for them
+func TestMigrationRoundTrip(t *testing.T) {
+	for _, driver := range database.Drivers {
+		t.Run(driver, func(t *testing.T) {
+			if driver != database.DriverSQLite && os.Getenv(testdatabase.DriverVariable) != driver {
+				t.Skip("Set " + testdatabase.DriverVariable + "=" + driver + " to run against " + driver + " in Docker")
+			}
+			db, closeDatabase := testdatabase.Open(driver)
+			defer closeDatabase()
+
+			migrations, _ := database.Migrations(driver)
+			if _, err := database.MigrateUp(db); err != nil {
+				t.Fatalf("Couldn't apply the migrations: %v\n", err)
+			}
+
+			now := time.Date(2021, time.December, 17, 21, 5, 0, 0, time.UTC)
+			db.Create(&database.Room{ID: "room-terrace", Name: "Terrace", Capacity: 8, CreatedAt: now})
+			db.Create(&database.GuestList{ID: "guest-francisco", Name: "Francisco", Table: 5, AccompanyingGuests: 5, TimeArrived: "21:05", AddedAt: &now, RoomID: "room-terrace", Category: "vip"})
+			db.Create(&database.GuestList{ID: "guest-martins", Name: "Martins", Table: 4, AccompanyingGuests: 2, NoShowAt: &now, ReservationHeld: true, Category: "guest"})
+			db.Create(&database.GuestList{ID: "guest-costa", Name: "Costa", AccompanyingGuests: 1, TimeArrived: "21:10", WalkIn: true, SeatedWith: "guest-francisco", Category: "guest"})
+			db.Create(&database.Visit{ID: "visit-1", GuestID: "guest-francisco", Name: "Francisco", Table: 5, AccompanyingGuests: 5, TimeArrived: "21:05", ArrivedAt: now, Category: "vip"})
+			db.Create(&database.UsedTicket{ID: "ticket-1", GuestID: "guest-francisco", UsedAt: now})
+			expectedNames := []string{"Costa", "Francisco", "Martins"}
+
+			guestNames := func(step string) {
+				var names []string
+				if err := db.Table("guest_lists").Pluck("name", &names).Error; err != nil {
+					t.Fatalf("Couldn't read the guest list %s: %v\n", step, err)
+				}
+				sort.Strings(names)
+				if !reflect.DeepEqual(names, expectedNames) {
+					t.Errorf("Expected guests %v %s, got %v\n", expectedNames, step, names)
+				}
+			}
+			guestNames("once populated")
+
+			// The first migration creates the guest list, reverting it drops the guests
+			for steps := 1; steps < len(migrations); steps++ {
+				for step := 0; step < steps; step++ {
+					if _, err := database.MigrateDown(db); err != nil {
+						t.Fatalf("Couldn't revert migration %d of %d: %v\n", step+1, steps, err)
+					}
+				}
+				version, _ := database.SchemaVersion(db)
+				if version != len(migrations)-steps {
+					t.Fatalf("Schema version %d after reverting %d migrations\n", version, steps)
+				}
+				guestNames("at schema version " + migrations[version-1].Name)
+
+				if _, err := database.MigrateUp(db); err != nil {
+					t.Fatalf("Couldn't apply the migrations again after reverting %d: %v\n", steps, err)
+				}
+				guestNames("after applying the migrations again")
+			}
+
+			var guest database.GuestList
+			if err := db.Where("name = ?", "Francisco").First(&guest).Error; err != nil {
+				t.Fatalf("Couldn't read Francisco: %v\n", err)
+			}
+			if guest.Table != 5 || guest.AccompanyingGuests != 5 || guest.TimeArrived != "21:05" {
+				t.Errorf("Francisco changed through the round trips: %+v\n", guest)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package graphqltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/requestRouting"
+	"guestListChallenge/src/utils"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// graphqlResult Result of a GraphQL request as received by clients
+type graphqlResult struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// sendQuery Sends a GraphQL request to a server without database, enough for requests refused before reaching it
+func sendQuery(t *testing.T, query string, variables map[string]interface{}) (*httptest.ResponseRecorder, graphqlResult) {
+	server := requestRouting.NewServer(nil, utils.RealClock{}, log.New(ioutil.Discard, "", 0), requestRouting.DefaultConfig())
+
+	requestBody, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatalf("Couldn't create request body: %v\n", err)
+	}
+	request := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(requestBody))
+	request.Header.Set("Content-Type", "application/json")
+
+	responseRecorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(responseRecorder, request)
+
+	var result graphqlResult
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Couldn't decode result %q: %v\n", responseRecorder.Body.String(), err)
+	}
+	return responseRecorder, result
+}
+
+// TestRefusedMutations Checks that refused mutations report the error code of the REST API in the error extensions
+func TestRefusedMutations(t *testing.T) {
+	var testCases = []struct {
+		testCaseName      string
+		query             string
+		variables         map[string]interface{}
+		expectedErrorCode string
+	}{
+		{
+			"Adding a guest without seats",
+			`mutation { addGuest(name: "Francisco", table: 0) { id } }`,
+			nil,
+			api.ErrorCodeInvalidRequest,
+		},
+		{
+			"Adding a guest whose name is too long",
+			`mutation($name: String!) { addGuest(name: $name, table: 4) { id } }`,
+			map[string]interface{}{"name": strings.Repeat("Francisco ", 7)},
+			api.ErrorCodeInvalidRequest,
+		},
+		{
+			"Adding a guest whose name holds symbols",
+			`mutation { addGuest(name: "Francisco <script>", table: 4) { id } }`,
+			nil,
+			api.ErrorCodeInvalidRequest,
+		},
+		{
+			"Adding a guest whose entourage does not fit at the table",
+			`mutation($name: String!) { addGuest(name: $name, table: 2, accompanyingGuests: 3) { id } }`,
+			map[string]interface{}{"name": "Francisco"},
+			api.ErrorCodeTableTooSmall,
+		},
+		{
+			"Checking in without choosing a guest",
+			`mutation { checkInGuest(accompanyingGuests: 1) { timeArrived } }`,
+			nil,
+			api.ErrorCodeInvalidRequest,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			responseRecorder, result := sendQuery(t, testCase.query, testCase.variables)
+			if responseRecorder.Code != http.StatusOK {
+				t.Errorf("Wrong http status received %d\n", responseRecorder.Code)
+			}
+			if len(result.Errors) != 1 {
+				t.Fatalf("Expected one error, got %v\n", result.Errors)
+			}
+			if errorCode := result.Errors[0].Extensions["code"]; errorCode != testCase.expectedErrorCode {
+				t.Errorf("Expected error code %q, got %v\n", testCase.expectedErrorCode, errorCode)
+			}
+		})
+	}
+}
+
+// TestInvalidQueries Checks that queries not matching the schema are rejected before reaching the database
+func TestInvalidQueries(t *testing.T) {
+	var testCases = []struct {
+		testCaseName       string
+		query              string
+		expectedHttpStatus int
+	}{
+		{"Unknown field", `{ guests { email } }`, http.StatusOK},
+		{"Wrong argument type", `{ guests(filter: {arrived: "yes"}) { id } }`, http.StatusOK},
+		{"Syntax error", `{ guests { id }`, http.StatusOK},
+		{"Missing query", ``, http.StatusUnprocessableEntity},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			responseRecorder, result := sendQuery(t, testCase.query, nil)
+			if responseRecorder.Code != testCase.expectedHttpStatus {
+				t.Errorf("Wrong http status received %d\n", responseRecorder.Code)
+			}
+			if responseRecorder.Code == http.StatusOK && (len(result.Errors) == 0 || result.Data != nil) {
+				t.Errorf("Expected errors and no data, got %v\n", result)
+			}
+		})
+	}
+}
+
+// TestMutationsOverGet Checks that mutations are only executed when sent with POST, queries being served over GET too
+func TestMutationsOverGet(t *testing.T) {
+	server := requestRouting.NewServer(nil, utils.RealClock{}, log.New(ioutil.Discard, "", 0), requestRouting.DefaultConfig())
+
+	var testCases = []struct {
+		testCaseName       string
+		query              url.Values
+		expectedHttpStatus int
+	}{
+		{"Mutation", url.Values{"query": {`mutation { addGuest(name: "Silva", table: 4) { id } }`}}, http.StatusMethodNotAllowed},
+		{"Mutation chosen by name", url.Values{
+			"query":         {`query Guests { guests { email } } mutation Add { addGuest(name: "Silva", table: 4) { id } }`},
+			"operationName": {"Add"},
+		}, http.StatusMethodNotAllowed},
+		{"Query next to a mutation", url.Values{
+			"query":         {`query Guests { guests { email } } mutation Add { addGuest(name: "Silva", table: 4) { id } }`},
+			"operationName": {"Guests"},
+		}, http.StatusOK},
+		{"Query", url.Values{"query": {`{ guests { email } }`}}, http.StatusOK},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			responseRecorder := httptest.NewRecorder()
+			server.Handler().ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/graphql?"+testCase.query.Encode(), nil))
+			if responseRecorder.Code != testCase.expectedHttpStatus {
+				t.Errorf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+			}
+			if testCase.expectedHttpStatus == http.StatusMethodNotAllowed && responseRecorder.Header().Get("Allow") != http.MethodPost {
+				t.Errorf("Expected POST to be the allowed method, got %q\n", responseRecorder.Header().Get("Allow"))
+			}
+		})
+	}
+}
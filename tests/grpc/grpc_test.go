@@ -0,0 +1,107 @@
+package grpctest
+
+import (
+	"context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/grpcServer"
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/guestlistpb"
+	"guestListChallenge/src/utils"
+	"io/ioutil"
+	"log"
+	"net"
+	"testing"
+)
+
+// newClient Starts a gRPC server without database, enough for requests refused before reaching it
+func newClient(t *testing.T) guestlistpb.GuestListClient {
+	logger := log.New(ioutil.Discard, "", 0)
+	server := grpcServer.NewServer(guestService.NewService(nil, utils.RealClock{}, logger), logger, grpcServer.DefaultConfig())
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	connection, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Couldn't connect to gRPC server: %v\n", err)
+	}
+	t.Cleanup(func() { connection.Close() })
+
+	return guestlistpb.NewGuestListClient(connection)
+}
+
+// TestRefusedRequests Checks that refused requests report the gRPC status and error code matching the REST API's
+func TestRefusedRequests(t *testing.T) {
+	client := newClient(t)
+
+	var testCases = []struct {
+		testCaseName       string
+		send               func() error
+		expectedStatusCode codes.Code
+		expectedErrorCode  string
+	}{
+		{
+			"Adding a guest without name",
+			func() error {
+				_, err := client.AddGuest(context.Background(), &guestlistpb.AddGuestRequest{Table: 4})
+				return err
+			},
+			codes.InvalidArgument,
+			api.ErrorCodeInvalidRequest,
+		},
+		{
+			"Adding a guest whose name does not start with a letter",
+			func() error {
+				_, err := client.AddGuest(context.Background(), &guestlistpb.AddGuestRequest{Name: "4 Francisco", Table: 4})
+				return err
+			},
+			codes.InvalidArgument,
+			api.ErrorCodeInvalidRequest,
+		},
+		{
+			"Adding a guest whose entourage does not fit at the table",
+			func() error {
+				_, err := client.AddGuest(context.Background(), &guestlistpb.AddGuestRequest{Name: "Francisco", Table: 2, AccompanyingGuests: 3})
+				return err
+			},
+			codes.FailedPrecondition,
+			api.ErrorCodeTableTooSmall,
+		},
+		{
+			"Checking in without choosing a guest",
+			func() error {
+				_, err := client.CheckInGuest(context.Background(), &guestlistpb.CheckInGuestRequest{AccompanyingGuests: 1})
+				return err
+			},
+			codes.InvalidArgument,
+			api.ErrorCodeInvalidRequest,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			replyStatus := status.Convert(testCase.send())
+			if replyStatus.Code() != testCase.expectedStatusCode {
+				t.Errorf("Expected status %v, got %v\n", testCase.expectedStatusCode, replyStatus.Code())
+			}
+
+			errorCode := ""
+			for _, detail := range replyStatus.Details() {
+				if errorInfo, isErrorInfo := detail.(*errdetails.ErrorInfo); isErrorInfo {
+					errorCode = errorInfo.Reason
+				}
+			}
+			if errorCode != testCase.expectedErrorCode {
+				t.Errorf("Expected error code %q, got %q\n", testCase.expectedErrorCode, errorCode)
+			}
+		})
+	}
+}
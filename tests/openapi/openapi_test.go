@@ -0,0 +1,334 @@
+package openapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/openapi"
+	"guestListChallenge/src/reports"
+	"guestListChallenge/src/requestRouting"
+	"guestListChallenge/src/snapshot"
+	"guestListChallenge/src/utils"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newServer Creates a server without database, enough for requests that never reach the handlers
+func newServer() *requestRouting.Server {
+	return requestRouting.NewServer(nil, utils.RealClock{}, log.New(ioutil.Discard, "", 0), requestRouting.DefaultConfig())
+}
+
+// TestEveryRouteIsDocumented Checks that the OpenAPI document describes every route of the server
+func TestEveryRouteIsDocumented(t *testing.T) {
+	document, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("Couldn't load OpenAPI document: %v\n", err)
+	}
+
+	router, isRouter := newServer().Handler().(*mux.Router)
+	if !isRouter {
+		t.Fatal("Server handler is not a router")
+	}
+
+	routeCount := 0
+	err = router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		pathTemplate, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		for _, method := range methods {
+			routeCount++
+			if document.Operation(pathTemplate, method) == nil {
+				t.Errorf("Route %s %s is not documented\n", method, pathTemplate)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Couldn't walk routes: %v\n", err)
+	}
+
+	operationCount := 0
+	for _, operations := range document.Paths {
+		operationCount += len(operations)
+	}
+	if operationCount != routeCount {
+		t.Errorf("Document describes %d operations but the server has %d routes\n", operationCount, routeCount)
+	}
+}
+
+// TestResponsesMatchDocument Checks that the generated responses match the schemas of the OpenAPI document
+func TestResponsesMatchDocument(t *testing.T) {
+	document, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("Couldn't load OpenAPI document: %v\n", err)
+	}
+
+	guests := []database.GuestList{
+		{ID: "guest-francisco", Name: "Francisco", Table: 5, AccompanyingGuests: 5, TimeArrived: "13:37", Category: guestService.CategoryGuest},
+	}
+
+	noShowAt := time.Now()
+	walkIns := []database.GuestList{
+		{ID: "guest-costa", Name: "Costa", AccompanyingGuests: 1, TimeArrived: "21:05", WalkIn: true, SeatedWith: "guest-francisco", Category: guestService.CategoryGuest},
+	}
+
+	terrace := database.Room{ID: "room-terrace", Name: "Terrace", Capacity: 8, CreatedAt: time.Now()}
+	roomGuest := guests[0]
+	roomGuest.RoomID, roomGuest.InRoom = terrace.ID, terrace.ID
+	vipGuest := guests[0]
+	vipGuest.Category = guestService.CategoryVIP
+
+	testCases := []struct {
+		path     string
+		method   string
+		status   int
+		response interface{}
+	}{
+		{"/guest_list/{name}", http.MethodPost, http.StatusOK, requestRouting.CreateAddGuestResponse(guests[0])},
+		{"/guest_list/{name}", http.MethodPost, http.StatusOK, "Guest will no be added to the guest list: guest's table cannot hold so many people."},
+		{"/guest_list/{name}", http.MethodPost, http.StatusUnprocessableEntity, requestRouting.CreateValidationErrorResponse([]string{"body: is required"})},
+		{"/guests/{name}", http.MethodPut, http.StatusMultipleChoices, requestRouting.CreateAmbiguousGuestResponse("Francisco", guests)},
+		{"/guest_list", http.MethodGet, http.StatusOK, requestRouting.CreateGetGuestListResponse(append(guests, walkIns...))},
+		{"/guest_list/search", http.MethodGet, http.StatusOK, requestRouting.CreateSearchGuestsResponse("fransisco", []requestRouting.GuestMatch{{Guest: guests[0], Score: 0.89}})},
+		{"/guest_list/search", http.MethodGet, http.StatusOK, requestRouting.CreateSearchGuestsResponse("nobody", nil)},
+		{"/guests/{name}", http.MethodPut, http.StatusOK, requestRouting.CreateCheckInGuestResponse(guests[0])},
+		{"/guests", http.MethodGet, http.StatusOK, requestRouting.CreateGetArrivedGuestsResponse(guests)},
+		{"/seats_empty", http.MethodGet, http.StatusOK, requestRouting.CreateGetNumberOfEmptySeatsResponse(guestService.Seats{Empty: 4})},
+		{"/guest_list/{name}/no_show", http.MethodPut, http.StatusOK, requestRouting.CreateNoShowGuestResponse(database.GuestList{ID: "guest-martins", Name: "Martins", Table: 4, ReservationHeld: true})},
+		{"/no_shows", http.MethodGet, http.StatusOK, requestRouting.CreateNoShowsResponse(time.Now(), []database.GuestList{{ID: "guest-martins", Name: "Martins", Table: 4, NoShowAt: &noShowAt}})},
+		{"/no_shows/cutoff", http.MethodPut, http.StatusOK, requestRouting.CreateNoShowCutoffResponse(time.Time{})},
+		{"/walk_ins", http.MethodPost, http.StatusOK, requestRouting.CreateWalkInResponse(walkIns[0])},
+		{"/walk_ins", http.MethodPost, http.StatusOK, "No table has 3 empty seats"},
+		{"/walk_ins", http.MethodGet, http.StatusOK, requestRouting.CreateWalkInsResponse(guestService.WalkIns{
+			Cap:        guestService.NoWalkInCap,
+			Admitted:   2,
+			Guests:     walkIns,
+			FreeTables: []guestService.FreeTable{{Guest: guests[0], Seats: 1}},
+		})},
+		{"/walk_ins/cap", http.MethodPut, http.StatusOK, requestRouting.CreateWalkInCapResponse(10)},
+		{"/occupancy", http.MethodGet, http.StatusOK, requestRouting.CreateHeadcountResponse(guestService.Headcount{People: 8, Venue: guestService.DefaultVenueConfig()})},
+		{"/occupancy/capacity", http.MethodPut, http.StatusOK, requestRouting.CreateHeadcountResponse(guestService.Headcount{
+			People: 8,
+			Venue:  guestService.VenueConfig{Capacity: 10, WarningThreshold: 0.8, CriticalThreshold: 0.95},
+		})},
+		{"/rooms", http.MethodGet, http.StatusOK, requestRouting.CreateRoomsResponse([]guestService.RoomOccupancy{
+			{Room: terrace, People: 6, Tables: 1, Seats: guestService.Seats{}},
+			{Room: database.Room{ID: "room-lounge", Name: "Lounge", Restricted: true}},
+		})},
+		{"/rooms/{room}", http.MethodPost, http.StatusOK, requestRouting.CreateRoomResponse(guestService.RoomOccupancy{Room: terrace})},
+		{"/rooms/{room}", http.MethodPost, http.StatusOK, "Room Terrace is already in the venue"},
+		{"/rooms/{room}", http.MethodDelete, http.StatusOK, "Room Terrace removed"},
+		{"/rooms/{room}/capacity", http.MethodPut, http.StatusOK, requestRouting.CreateRoomResponse(guestService.RoomOccupancy{Room: terrace, People: 6})},
+		{"/guest_list/{name}/room", http.MethodPut, http.StatusOK, requestRouting.CreateAssignRoomResponse(roomGuest)},
+		{"/rooms/{room}/access/{name}", http.MethodPut, http.StatusOK, "Guest Francisco can enter room Terrace"},
+		{"/rooms/{room}/guests/{name}", http.MethodPut, http.StatusOK, requestRouting.CreateRoomGuestResponse(roomGuest)},
+		{"/rooms/{room}/guests/{name}", http.MethodPut, http.StatusOK, "Room Terrace holds 6 people of its capacity of 8: Martins cannot come in with 4 accompanying guests"},
+		{"/guests", http.MethodGet, http.StatusOK, requestRouting.CreateGetArrivedGuestsResponse([]database.GuestList{roomGuest})},
+		{"/guest_list", http.MethodGet, http.StatusUnprocessableEntity, requestRouting.CreateValidationErrorResponse([]string{"query.category: must be one of guest, vip"})},
+		{"/guest_list/{name}/category", http.MethodPut, http.StatusOK, requestRouting.CreateAssignRoomResponse(vipGuest)},
+		{"/guest_list/{name}/category", http.MethodPut, http.StatusOK, "Guests in category staff may bring at most 0 accompanying guests: Francisco cannot come with 5"},
+		{"/categories", http.MethodGet, http.StatusOK, requestRouting.CreateCategoriesResponse(
+			guestService.CountCategories(append(guests, vipGuest)), guestService.DefaultCategoryConfig())},
+		{"/categories/{category}", http.MethodPut, http.StatusOK, requestRouting.CreateCategoryResponse(
+			guestService.CategoryCount{Category: guestService.CategoryStaff}, guestService.CategoryRules{BypassCapacity: true, MaxAccompanyingGuests: 1})},
+		{"/admission/rules", http.MethodGet, http.StatusOK, requestRouting.CreateAdmissionRulesResponse(guestService.DefaultAdmissionPolicy())},
+		{"/admission/rules", http.MethodPut, http.StatusOK, requestRouting.CreateAdmissionRulesResponse(guestService.AdmissionPolicy{
+			Event: "end-of-year-party",
+			Rules: []guestService.AdmissionRule{
+				guestService.BanListRule{Names: []string{"Mallory"}},
+				guestService.TimeWindowRule{Opens: time.Date(2021, 12, 17, 19, 0, 0, 0, time.UTC)},
+				guestService.AgeRestrictionRule{MinimumAge: 18},
+			},
+		})},
+		{"/reports/occupancy", http.MethodGet, http.StatusOK, requestRouting.CreateOccupancyReportResponse(
+			reports.NewOccupancy(guests, nil, reports.Window{From: time.Now().Add(-time.Hour), Until: time.Now(), Interval: 15 * time.Minute}))},
+		{"/admin/restore", http.MethodPost, http.StatusOK, requestRouting.CreateRestoreSnapshotResponse(snapshot.Restoration{
+			State: database.State{Guests: guests},
+			Diff:  snapshot.Compare(database.State{}, database.State{Guests: guests}),
+		})},
+	}
+
+	for _, testCase := range testCases {
+		encodedResponse, err := json.Marshal(testCase.response)
+		if err != nil {
+			t.Fatalf("Couldn't encode response to Json: %v\n", err)
+		}
+		value, err := openapi.DecodeJSON(encodedResponse)
+		if err != nil {
+			t.Fatalf("Couldn't decode response: %v\n", err)
+		}
+
+		schema := document.ResponseSchema(document.Operation(testCase.path, testCase.method), testCase.status)
+		if schema == nil {
+			t.Errorf("No %d response documented for %s %s\n", testCase.status, testCase.method, testCase.path)
+			continue
+		}
+		if violations := document.Validate(schema, value); len(violations) > 0 {
+			t.Errorf("Response of %s %s does not match the document: %v\n", testCase.method, testCase.path, violations)
+		}
+	}
+}
+
+// TestInvalidRequests Checks that requests not matching the OpenAPI document are rejected before reaching the handlers
+func TestInvalidRequests(t *testing.T) {
+	server := newServer()
+
+	testCases := []struct {
+		testCaseName       string
+		requestPath        string
+		requestType        string
+		contentType        string
+		requestBody        string
+		expectedStatus     int
+		expectedViolations []string
+	}{
+		{"Garbled body", "/guest_list/Francisco", http.MethodPost, "application/json", `{"table": 5,`, http.StatusUnprocessableEntity, nil},
+		{"Missing body", "/guest_list/Francisco", http.MethodPost, "application/json", ``, http.StatusUnprocessableEntity,
+			[]string{"body: is required"}},
+		{"Missing field", "/guest_list/Francisco", http.MethodPost, "application/json", `{"table": 5}`, http.StatusUnprocessableEntity,
+			[]string{"body.accompanying_guests: is required"}},
+		{"Wrong field type", "/guest_list/Francisco", http.MethodPost, "application/json", `{"table": "five", "accompanying_guests": 2}`, http.StatusUnprocessableEntity,
+			[]string{"body.table: must be of type integer"}},
+		{"Fractional number", "/guests/Francisco", http.MethodPut, "application/json", `{"accompanying_guests": 2.5}`, http.StatusUnprocessableEntity,
+			[]string{"body.accompanying_guests: must be of type integer"}},
+		{"Body is not an object", "/guests/Francisco", http.MethodPut, "application/json", `[2]`, http.StatusUnprocessableEntity,
+			[]string{"body: must be of type object"}},
+		{"Unknown field", "/guests/Francisco", http.MethodPut, "application/json", `{"accompanying_guests": 2, "table": 3}`, http.StatusUnprocessableEntity,
+			[]string{"body.table: is not allowed"}},
+		{"Every violation is reported", "/guest_list/-Francisco", http.MethodPost, "application/json", `{"table": 0, "accompanying_guests": -1}`, http.StatusUnprocessableEntity,
+			[]string{"path.name: has an invalid format", "body.accompanying_guests: must be at least 0", "body.table: must be at least 1"}},
+		{"Name too long", "/guests/" + strings.Repeat("a", 65), http.MethodDelete, "", ``, http.StatusUnprocessableEntity,
+			[]string{"path.name: must be at most 64 characters long"}},
+		{"Missing search query", "/guest_list/search", http.MethodGet, "", ``, http.StatusUnprocessableEntity,
+			[]string{"query.q: is required"}},
+		{"Unknown category", "/guest_list/Francisco", http.MethodPost, "application/json", `{"table": 5, "accompanying_guests": 2, "category": "royalty"}`, http.StatusUnprocessableEntity,
+			[]string{"body.category: must be one of the allowed values"}},
+		{"Unknown admission rule", "/admission/rules", http.MethodPut, "application/json", `{"rules": [{"rule": "dress_code"}]}`, http.StatusUnprocessableEntity,
+			[]string{"body.rules[0].rule: must be one of the allowed values"}},
+		{"Audit limit too large", "/audit?limit=5000", http.MethodGet, "", ``, http.StatusUnprocessableEntity,
+			[]string{"query.limit: must be between 1 and 1000"}},
+		{"Audit time not RFC 3339", "/audit?since=yesterday", http.MethodGet, "", ``, http.StatusUnprocessableEntity,
+			[]string{"query.since: must be an RFC 3339 time"}},
+		{"Wrong content type", "/guest_list/Francisco", http.MethodPost, "text/plain", `{"table": 5, "accompanying_guests": 2}`, http.StatusUnsupportedMediaType, nil},
+		{"Body too large", "/guest_list/Francisco", http.MethodPost, "application/json", `{"table": 5, "accompanying_guests": 2` + strings.Repeat(" ", 5000) + `}`, http.StatusRequestEntityTooLarge, nil},
+	}
+
+	for _, testCase := range testCases {
+		request, err := http.NewRequest(testCase.requestType, testCase.requestPath, bytes.NewReader([]byte(testCase.requestBody)))
+		if err != nil {
+			t.Fatalf("Couldn't create request: %v\n", err)
+		}
+		if testCase.contentType != "" {
+			request.Header.Set("Content-Type", testCase.contentType)
+		}
+
+		responseRecorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(responseRecorder, request)
+
+		if responseRecorder.Code != testCase.expectedStatus {
+			t.Errorf("%s: wrong http status received %d\n", testCase.testCaseName, responseRecorder.Code)
+			continue
+		}
+
+		if testCase.expectedViolations != nil {
+			var receivedResponse struct {
+				Violations []string `json:"violations"`
+			}
+			if err := json.Unmarshal(responseRecorder.Body.Bytes(), &receivedResponse); err != nil {
+				t.Fatalf("Couldn't decode response: %v\n", err)
+			}
+			if !reflect.DeepEqual(receivedResponse.Violations, testCase.expectedViolations) {
+				t.Errorf("%s: wrong violations\nexpected:%q\nreceived:%q\n", testCase.testCaseName, testCase.expectedViolations, receivedResponse.Violations)
+			}
+		}
+	}
+}
+
+// TestOpenAPIDocumentIsServed Checks that the OpenAPI document and its Swagger UI page are served, with the assets of the page
+func TestOpenAPIDocumentIsServed(t *testing.T) {
+	server := newServer()
+
+	for _, path := range []string{"/openapi.json", "/docs", "/docs/docs.js", "/docs/docs.css"} {
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatalf("Couldn't create request: %v\n", err)
+		}
+
+		responseRecorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(responseRecorder, request)
+
+		if responseRecorder.Code != http.StatusOK || responseRecorder.Body.Len() == 0 {
+			t.Errorf("%s was not served: status %d\n", path, responseRecorder.Code)
+		}
+	}
+
+	// The page only loads assets served by the service
+	if page := string(openapi.SwaggerPage()); strings.Contains(page, "https://") {
+		t.Errorf("Expected the documentation page to work offline, got %s\n", page)
+	}
+	responseRecorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/docs/missing.js", nil))
+	if responseRecorder.Code != http.StatusNotFound {
+		t.Errorf("Expected an unknown asset not to be found, got %d\n", responseRecorder.Code)
+	}
+}
+
+// TestAPIKeys Checks that requests without a configured API key are rejected, except for the API documentation,
+// and that only admin keys give access to the administration routes
+func TestAPIKeys(t *testing.T) {
+	config := requestRouting.DefaultConfig()
+	config.APIKeys = map[string]string{"k3y-one": "door-staff"}
+	config.AdminKeys = map[string]string{"4dm1n-k3y": "organiser"}
+	server := requestRouting.NewServer(nil, utils.RealClock{}, log.New(ioutil.Discard, "", 0), config)
+
+	var testCases = []struct {
+		testCaseName   string
+		requestPath    string
+		authorization  string
+		expectedStatus int
+	}{
+		{"Missing key", "/guest_list/search?q=Francisco", "", http.StatusUnauthorized},
+		{"Unknown key", "/guest_list/search?q=Francisco", "Bearer k3y-two", http.StatusUnauthorized},
+		{"Known key", "/guest_list/search", "Bearer k3y-one", http.StatusUnprocessableEntity},
+		{"Admin key", "/guest_list/search", "Bearer 4dm1n-k3y", http.StatusUnprocessableEntity},
+		{"Administration with an API key", "/admin/snapshot", "Bearer k3y-one", http.StatusForbidden},
+		{"Documentation", "/openapi.json", "", http.StatusOK},
+		{"Documentation assets", "/docs/docs.js", "", http.StatusOK},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, testCase.requestPath, nil)
+			request.Header.Set("X-Request-ID", "door-2.scan_17")
+			if testCase.authorization != "" {
+				request.Header.Set("Authorization", testCase.authorization)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			server.Handler().ServeHTTP(responseRecorder, request)
+
+			if responseRecorder.Code != testCase.expectedStatus {
+				t.Errorf("Wrong http status received %d\n", responseRecorder.Code)
+			}
+			if requestID := responseRecorder.Header().Get("X-Request-ID"); requestID != "door-2.scan_17" {
+				t.Errorf("Expected the request ID to be sent back, got %q\n", requestID)
+			}
+			if responseRecorder.Code == http.StatusUnauthorized && responseRecorder.Header().Get("X-Error-Code") != "unauthorized" {
+				t.Errorf("Wrong error code received %q\n", responseRecorder.Header().Get("X-Error-Code"))
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package reportstest
+
+import (
+	"bytes"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/reports"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// partyStart Time the party of the tests starts
+var partyStart = time.Date(2021, time.December, 17, 20, 0, 0, 0, time.UTC)
+
+// at Returns the time some minutes into the party
+func at(minutes int) *time.Time {
+	atTime := partyStart.Add(time.Duration(minutes) * time.Minute)
+	return &atTime
+}
+
+// occupancy Returns the report of the first two hours of a party, by half hours
+//
+// Francisco checked in before visits were recorded, Martins came with 2 people from 0:10 to 1:10,
+// Silva came alone at 0:40 and is still there, Costa never came and Pereira was added after the report
+func occupancy() reports.Occupancy {
+	guestList := []database.GuestList{
+		{ID: "guest-francisco", Name: "Francisco", Table: 5, AccompanyingGuests: 5, TimeArrived: "13:37"},
+		{ID: "guest-silva", Name: "Silva", Table: 2, AccompanyingGuests: 0, TimeArrived: "20:40", AddedAt: at(-60)},
+		{ID: "guest-costa", Name: "Costa", Table: 3, AccompanyingGuests: 1, AddedAt: at(-60)},
+		{ID: "guest-pereira", Name: "Pereira", Table: 3, AccompanyingGuests: 1, AddedAt: at(150)},
+	}
+	visits := []database.Visit{
+		{ID: "visit-1", GuestID: "guest-martins", Name: "Martins", Table: 4, AccompanyingGuests: 2, ArrivedAt: *at(10), LeftAt: at(70)},
+		{ID: "visit-2", GuestID: "guest-silva", Name: "Silva", Table: 2, AccompanyingGuests: 0, ArrivedAt: *at(40)},
+	}
+	return reports.NewOccupancy(guestList, visits, reports.Window{From: partyStart, Until: *at(120), Interval: 30 * time.Minute})
+}
+
+// TestOccupancy Checks the timeline, peak, stays, table utilisation and no-shows of a report
+func TestOccupancy(t *testing.T) {
+	report := occupancy()
+
+	expectedTimeline := []reports.Bin{
+		{Start: *at(0), Present: 9, Arrivals: 3},
+		{Start: *at(30), Present: 10, Arrivals: 1},
+		{Start: *at(60), Present: 10, Departures: 3},
+		{Start: *at(90), Present: 7},
+	}
+	if !reflect.DeepEqual(report.Timeline, expectedTimeline) {
+		t.Errorf("Expected timeline %+v, got %+v\n", expectedTimeline, report.Timeline)
+	}
+	if report.Peak != 10 || report.PeakAt == nil || !report.PeakAt.Equal(*at(40)) {
+		t.Errorf("Expected a peak of 10 people at 0:40, got %d at %v\n", report.Peak, report.PeakAt)
+	}
+
+	// Martins stayed an hour and Silva an hour and twenty minutes so far
+	if report.Visits != 2 || report.AverageStay != 70*time.Minute {
+		t.Errorf("Expected 2 visits of 70 minutes on average, got %d of %v\n", report.Visits, report.AverageStay)
+	}
+	if report.Invited != 4 || report.NoShows != 1 || report.NoShowRate() != 0.25 {
+		t.Errorf("Expected 1 no-show out of 4 invited guests, got %d out of %d\n", report.NoShows, report.Invited)
+	}
+
+	expectedTables := []reports.TableUtilisation{
+		{GuestID: "guest-costa", Name: "Costa", Seats: 3, Utilisation: 0},
+		{GuestID: "guest-francisco", Name: "Francisco", Seats: 5, Utilisation: 1},
+		{GuestID: "guest-martins", Name: "Martins", Seats: 4, Utilisation: 0.25},
+		{GuestID: "guest-silva", Name: "Silva", Seats: 2, Utilisation: 0},
+	}
+	if !reflect.DeepEqual(report.Tables, expectedTables) {
+		t.Errorf("Expected tables %+v, got %+v\n", expectedTables, report.Tables)
+	}
+}
+
+// TestEmptyOccupancy Checks the report of a party nobody came to
+func TestEmptyOccupancy(t *testing.T) {
+	report := reports.NewOccupancy(nil, nil, reports.Window{From: partyStart, Until: *at(45), Interval: 30 * time.Minute})
+	if len(report.Timeline) != 2 || report.Peak != 0 || report.PeakAt != nil || report.NoShowRate() != 0 {
+		t.Errorf("Unexpected report %+v\n", report)
+	}
+}
+
+// TestWalkInOccupancy Checks that walk-ins are counted apart from the invited guests and take seats at the table they sit at
+func TestWalkInOccupancy(t *testing.T) {
+	guestList := []database.GuestList{
+		{ID: "guest-silva", Name: "Silva", Table: 4, TimeArrived: "20:00", AddedAt: at(-60)},
+		{ID: "guest-costa", Name: "Costa", AccompanyingGuests: 1, TimeArrived: "20:30", AddedAt: at(30), WalkIn: true, SeatedWith: "guest-silva"},
+	}
+	visits := []database.Visit{
+		{ID: "visit-1", GuestID: "guest-silva", Name: "Silva", Table: 4, ArrivedAt: *at(0)},
+		{ID: "visit-2", GuestID: "guest-costa", Name: "Costa", AccompanyingGuests: 1, ArrivedAt: *at(30), LeftAt: at(90), WalkIn: true, SeatedWith: "guest-silva"},
+	}
+	report := reports.NewOccupancy(guestList, visits, reports.Window{From: partyStart, Until: *at(120), Interval: 30 * time.Minute})
+
+	if report.WalkIns != 2 || report.Invited != 1 || report.NoShows != 0 || report.Peak != 3 {
+		t.Errorf("Expected 2 walk-ins with 1 invited guest and a peak of 3 people, got %+v\n", report)
+	}
+	// Costa and their accompanying guest take 2 of Silva's 4 seats for half of the report
+	expectedTables := []reports.TableUtilisation{{GuestID: "guest-silva", Name: "Silva", Seats: 4, Utilisation: 0.25}}
+	if !reflect.DeepEqual(report.Tables, expectedTables) {
+		t.Errorf("Expected tables %+v, got %+v\n", expectedTables, report.Tables)
+	}
+}
+
+// TestRenderHTML Checks that the chart of a report is rendered with a bar by interval
+func TestRenderHTML(t *testing.T) {
+	var page bytes.Buffer
+	if err := reports.RenderHTML(&page, occupancy(), "end-of-year-party <2021>"); err != nil {
+		t.Fatalf("Couldn't render report: %v\n", err)
+	}
+	if bars := strings.Count(page.String(), `<rect class="present"`); bars != 4 {
+		t.Errorf("Expected 4 bars, got %d\n", bars)
+	}
+	if !strings.Contains(page.String(), "end-of-year-party &lt;2021&gt;") {
+		t.Errorf("Expected the event ID escaped in the page\n")
+	}
+}
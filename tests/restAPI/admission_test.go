@@ -0,0 +1,148 @@
+package restapitest
+
+import (
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/guestService"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// expectAdmission Sends a request adding, checking in or admitting a guest and checks the admission rule named in the reply
+func expectAdmission(t *testing.T, requestType string, requestPath string, requestContent interface{}, expectedRule string, expectedCode string) {
+	responseRecorder := sendRequest(t, requestType, requestPath, requestContent)
+	if rule := responseRecorder.Header().Get(api.AdmissionRuleHeader); rule != expectedRule {
+		t.Errorf("Expected %s %s to be decided by rule %q, got %q: %s\n", requestType, requestPath, expectedRule, rule, responseRecorder.Body.String())
+	}
+	if code := responseRecorder.Header().Get(api.ErrorCodeHeader); code != expectedCode {
+		t.Errorf("Expected %s %s to reply with error code %q, got %q\n", requestType, requestPath, expectedCode, code)
+	}
+}
+
+// TestAdmissionRules Checks that the admission rules decide who is added and let in, every decision naming the rule that took it
+func TestAdmissionRules(t *testing.T) {
+	resetDatabase()
+
+	// Other tests expect the default rules
+	defer sendRequest(t, http.MethodPut, "/admission/rules", map[string]interface{}{"rules": []map[string]interface{}{
+		{"rule": "table_capacity"}, {"rule": "entourage_limit"}, {"rule": "venue_capacity"},
+	}})
+
+	var rules api.AdmissionRulesResponse
+	decodeReply(t, http.MethodGet, "/admission/rules", nil, &rules)
+	if rules.Event != "end-of-year-party" || len(rules.Rules) != 3 || rules.Rules[0].Rule != guestService.AdmissionRuleTableCapacity {
+		t.Errorf("Expected the default rules, got %+v\n", rules)
+	}
+	expectAdmission(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 2, "accompanying_guests": 3},
+		guestService.AdmissionRuleTableCapacity, api.ErrorCodeTableTooSmall)
+	expectAdmission(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 2, "accompanying_guests": 2},
+		guestService.AdmissionPolicyRule, "")
+
+	// The party started at 21:05 and the doors close at 23:00
+	decodeReply(t, http.MethodPut, "/admission/rules", map[string]interface{}{"rules": []map[string]interface{}{
+		{"rule": "ban_list", "names": []string{"Mallory"}},
+		{"rule": "time_window", "opens": "2021-12-17T20:00:00Z", "closes": "2021-12-17T23:00:00Z"},
+		{"rule": "table_capacity"},
+		{"rule": "entourage_limit", "limits": map[string]int{"guest": 1}},
+		{"rule": "age_restriction", "minimum_age": 18},
+	}}, &rules)
+	ruleNames := []string{}
+	for _, rule := range rules.Rules {
+		ruleNames = append(ruleNames, rule.Rule)
+	}
+	if expectedNames := []string{"ban_list", "time_window", "table_capacity", "entourage_limit", "age_restriction"}; !reflect.DeepEqual(ruleNames, expectedNames) {
+		t.Errorf("Expected rules %v, got %v\n", expectedNames, ruleNames)
+	}
+
+	expectRefusal(t, http.MethodPost, "/guest_list/Mallory", map[string]interface{}{"table": 2, "accompanying_guests": 0}, "Guest Mallory is banned from the event")
+	expectAdmission(t, http.MethodPost, "/guest_list/MALLORY", map[string]interface{}{"table": 2, "accompanying_guests": 0},
+		guestService.AdmissionRuleBanList, api.ErrorCodeGuestBanned)
+
+	expectRefusal(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 2, "age": 30},
+		"Guests in category guest may bring at most 1 accompanying guests")
+	expectAdmission(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 1},
+		guestService.AdmissionRuleAgeRestriction, api.ErrorCodeAgeRestricted)
+	expectRefusal(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 1, "age": 16},
+		"Guest Martins is 16, under the minimum age of 18")
+	expectAdmission(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 1, "age": 30},
+		guestService.AdmissionPolicyRule, "")
+
+	// Walk-ins are let in by the same rules
+	expectAdmission(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Costa", "accompanying_guests": 0},
+		guestService.AdmissionRuleAgeRestriction, api.ErrorCodeAgeRestricted)
+	expectAdmission(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Costa", "accompanying_guests": 0, "age": 25},
+		guestService.AdmissionPolicyRule, "")
+
+	// Nobody is let in once the doors close
+	sendRequest(t, http.MethodPut, "/admission/rules", map[string]interface{}{"rules": []map[string]interface{}{
+		{"rule": "time_window", "closes": "2021-12-17T21:00:00Z"},
+	}})
+	expectRefusal(t, http.MethodPut, "/guests/Silva", map[string]interface{}{"accompanying_guests": 0}, "the doors closed at 2021-12-17T21:00:00Z")
+	expectAdmission(t, http.MethodPost, "/guest_list/Pereira", map[string]interface{}{"table": 2, "accompanying_guests": 0},
+		guestService.AdmissionPolicyRule, "")
+
+	if responseRecorder := sendRequest(t, http.MethodPut, "/admission/rules", map[string]interface{}{"rules": []map[string]interface{}{
+		{"rule": "entourage_limit", "limits": map[string]int{"royalty": 2}},
+	}}); responseRecorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected an entourage limit of an unknown category to be refused, got %d\n", responseRecorder.Code)
+	}
+}
+
+// TestAdmissionOnUndo Checks that a guest put back at the party by undoing their check-out is let in by the admission rules again
+func TestAdmissionOnUndo(t *testing.T) {
+	resetDatabase()
+
+	// Other tests expect the default rules
+	defer sendRequest(t, http.MethodPut, "/admission/rules", map[string]interface{}{"rules": []map[string]interface{}{
+		{"rule": "table_capacity"}, {"rule": "entourage_limit"}, {"rule": "venue_capacity"},
+	}})
+
+	sendRequest(t, http.MethodDelete, "/guests/Francisco", nil)
+	checkOut := latestOperation(t)
+	sendRequest(t, http.MethodPut, "/admission/rules", map[string]interface{}{"rules": []map[string]interface{}{
+		{"rule": "ban_list", "names": []string{"Francisco"}}, {"rule": "age_restriction", "minimum_age": 18},
+	}})
+	expectAdmission(t, http.MethodPost, "/operations/"+strconv.FormatInt(checkOut.ID, 10)+"/undo", nil,
+		guestService.AdmissionRuleBanList, api.ErrorCodeGuestBanned)
+	if francisco, _ := store.GuestByID("guest-francisco"); francisco.TimeArrived != "" {
+		t.Errorf("Expected Francisco to stay out, got %+v\n", francisco)
+	}
+
+	// The age of the guest was checked when they first arrived
+	sendRequest(t, http.MethodPut, "/admission/rules", map[string]interface{}{"rules": []map[string]interface{}{
+		{"rule": "age_restriction", "minimum_age": 18},
+	}})
+	if responseRecorder := undo(t, checkOut.ID); responseRecorder.Code != http.StatusOK {
+		t.Errorf("Expected Francisco back at the party, got %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+}
+
+// TestParseAdmissionRules Checks that the admission rules of an event are read from a rules file configuring several events
+func TestParseAdmissionRules(t *testing.T) {
+	rulesFile := []byte(`{
+		"events": {
+			"end-of-year-party": {"rules": [{"rule": "ban_list", "guest_ids": ["guest-martins"]}, {"rule": "venue_capacity"}]},
+			"summer-picnic": {"rules": [{"rule": "age_restriction", "minimum_age": 21}]}
+		}
+	}`)
+
+	policy, parseError := guestService.ParseAdmissionRules(rulesFile, "end-of-year-party")
+	if parseError != nil {
+		t.Fatalf("Couldn't parse rules file: %v\n", parseError)
+	}
+	expectedPolicy := guestService.AdmissionPolicy{
+		Event: "end-of-year-party",
+		Rules: []guestService.AdmissionRule{guestService.BanListRule{GuestIDs: []string{"guest-martins"}}, guestService.VenueCapacityRule{}},
+	}
+	if !reflect.DeepEqual(policy, expectedPolicy) {
+		t.Errorf("Expected %+v, got %+v\n", expectedPolicy, policy)
+	}
+
+	if _, parseError := guestService.ParseAdmissionRules(rulesFile, "new-year-eve"); parseError == nil {
+		t.Errorf("Expected an event without rules to be reported\n")
+	}
+	if _, parseError := guestService.ParseAdmissionRules([]byte(`{"events": {"end-of-year-party": {"rules": [{"rule": "dress_code"}]}}}`), "end-of-year-party"); parseError == nil {
+		t.Errorf("Expected an unknown rule to be reported\n")
+	}
+}
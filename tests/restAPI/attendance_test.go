@@ -0,0 +1,102 @@
+package restapitest
+
+import (
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// arrivedGuestNames Returns the names of the guests at the party, at the time given by the query if any
+func arrivedGuestNames(t *testing.T, query string) []string {
+	responseRecorder := sendRequest(t, http.MethodGet, "/guests"+query, nil)
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	var receivedResponse api.ArrivedGuestsResponse
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &receivedResponse); err != nil {
+		t.Fatalf("Couldn't decode response: %v\n", err)
+	}
+	names := []string{}
+	for _, guest := range receivedResponse.Guests {
+		names = append(names, guest.Name)
+	}
+	return names
+}
+
+// emptySeats Returns the number of empty seats, at the time given by the query if any
+func emptySeats(t *testing.T, query string) int {
+	responseRecorder := sendRequest(t, http.MethodGet, "/seats_empty"+query, nil)
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	var receivedResponse api.EmptySeatsResponse
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &receivedResponse); err != nil {
+		t.Fatalf("Couldn't decode response: %v\n", err)
+	}
+	return receivedResponse.SeatsEmpty
+}
+
+// TestPointInTimeAttendance Checks that the guests at the party and the empty seats are reconstructed at past times
+func TestPointInTimeAttendance(t *testing.T) {
+	resetDatabase()
+
+	// Other tests expect the clock at its initial time
+	initialTime := clock.Now()
+	defer clock.Set(initialTime)
+
+	// The party goes on, every ten minutes
+	clock.Advance(10 * time.Minute)
+	sendRequest(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 3, "accompanying_guests": 1})
+	clock.Advance(10 * time.Minute)
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 2})
+	clock.Advance(10 * time.Minute)
+	sendRequest(t, http.MethodPut, "/guests/Silva", map[string]interface{}{"accompanying_guests": 1})
+	clock.Advance(10 * time.Minute)
+	sendRequest(t, http.MethodDelete, "/guests/Martins", nil)
+
+	var testCases = []struct {
+		testCaseName       string
+		at                 time.Time
+		expectedGuests     []string
+		expectedEmptySeats int
+	}{
+		{"Before Silva was added", initialTime.Add(5 * time.Minute), []string{"Francisco"}, 4},
+		{"Before anyone else arrived", initialTime.Add(15 * time.Minute), []string{"Francisco"}, 7},
+		{"After Martins arrived", initialTime.Add(25 * time.Minute), []string{"Francisco", "Martins"}, 5},
+		{"After Silva arrived", initialTime.Add(35 * time.Minute), []string{"Francisco", "Martins", "Silva"}, 4},
+		{"When Martins left", initialTime.Add(40 * time.Minute), []string{"Francisco", "Silva"}, 2},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			query := "?at=" + testCase.at.Format(time.RFC3339)
+			if names := arrivedGuestNames(t, query); !reflect.DeepEqual(names, testCase.expectedGuests) {
+				t.Errorf("Expected guests %v, got %v\n", testCase.expectedGuests, names)
+			}
+			if seats := emptySeats(t, query); seats != testCase.expectedEmptySeats {
+				t.Errorf("Expected %d empty seats, got %d\n", testCase.expectedEmptySeats, seats)
+			}
+		})
+	}
+
+	// The latest reconstruction matches the current state
+	if names := arrivedGuestNames(t, ""); !reflect.DeepEqual(names, []string{"Francisco", "Silva"}) {
+		t.Errorf("Expected guests Francisco and Silva, got %v\n", names)
+	}
+	if seats := emptySeats(t, ""); seats != 2 {
+		t.Errorf("Expected 2 empty seats, got %d\n", seats)
+	}
+
+	// Times that are not RFC 3339 or are in the future are refused
+	for _, query := range []string{"?at=yesterday", "?at=" + clock.Now().Add(time.Minute).Format(time.RFC3339)} {
+		for _, requestPath := range []string{"/guests", "/seats_empty"} {
+			responseRecorder := sendRequest(t, http.MethodGet, requestPath+query, nil)
+			if responseRecorder.Code != http.StatusUnprocessableEntity || responseRecorder.Header().Get(api.ErrorCodeHeader) != api.ErrorCodeInvalidRequest {
+				t.Errorf("Expected %s%s to be refused, got %d %s\n", requestPath, query, responseRecorder.Code, responseRecorder.Body.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package restapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/database"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// sendIdentifiedRequest Sends a request with a JSON body under the given request ID to the test server
+func sendIdentifiedRequest(t *testing.T, requestType string, requestPath string, requestID string, requestContent interface{}) *httptest.ResponseRecorder {
+	requestBody, err := json.Marshal(requestContent)
+	if err != nil {
+		t.Fatalf("Couldn't create request body: %v\n", err)
+	}
+	request, err := http.NewRequest(requestType, requestPath, bytes.NewReader(requestBody))
+	if err != nil {
+		t.Fatalf("Couldn't create request: %v\n", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(api.RequestIDHeader, requestID)
+
+	responseRecorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(responseRecorder, request)
+	return responseRecorder
+}
+
+// auditRecords Returns the audit records matching a query string
+//
+// The audit log outlives resetDatabase, so tests only look at the records of their own requests
+func auditRecords(t *testing.T, query string) []api.AuditRecord {
+	responseRecorder := sendRequest(t, http.MethodGet, "/audit?"+query, nil)
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d\n", responseRecorder.Code)
+	}
+	var receivedResponse api.AuditRecordsResponse
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &receivedResponse); err != nil {
+		t.Fatalf("Couldn't decode response: %v\n", err)
+	}
+	return receivedResponse.Records
+}
+
+// auditedGuest The fields of a recorded guest state the audit tests look at
+type auditedGuest struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Table              int    `json:"table"`
+	AccompanyingGuests int    `json:"accompanying_guests"`
+	TimeArrived        string `json:"time_arrived"`
+	Category           string `json:"category"`
+}
+
+// TestAuditLog Checks that mutations are recorded with their origin and states, and that tampering is detected
+func TestAuditLog(t *testing.T) {
+	resetDatabase()
+
+	responseRecorder := sendIdentifiedRequest(t, http.MethodPost, "/guest_list/Silva", "audit-test-add", map[string]interface{}{"table": 3, "accompanying_guests": 1})
+	if requestID := responseRecorder.Header().Get(api.RequestIDHeader); requestID != "audit-test-add" {
+		t.Errorf("Expected the request ID to be sent back, got %q\n", requestID)
+	}
+	sendIdentifiedRequest(t, http.MethodPut, "/guests/Silva", "audit-test-check-in", map[string]interface{}{"accompanying_guests": 2})
+	sendIdentifiedRequest(t, http.MethodDelete, "/guests/Silva", "audit-test-check-out", nil)
+
+	// Refused operations change nothing and are not recorded
+	sendIdentifiedRequest(t, http.MethodDelete, "/guests/Silva", "audit-test-refused", nil)
+	if records := auditRecords(t, "request_id=audit-test-refused"); len(records) != 0 {
+		t.Errorf("Expected no record of a refused operation, got %v\n", records)
+	}
+
+	addedGuest := &auditedGuest{ID: "guest-1", Name: "Silva", Table: 3, AccompanyingGuests: 1, Category: "guest"}
+	checkedInGuest := &auditedGuest{ID: "guest-1", Name: "Silva", Table: 3, AccompanyingGuests: 2, TimeArrived: "21:5", Category: "guest"}
+	var testCases = []struct {
+		testCaseName      string
+		requestID         string
+		expectedOperation string
+		expectedBefore    *auditedGuest
+		expectedAfter     *auditedGuest
+	}{
+		{"Adding a guest", "audit-test-add", audit.OperationAddGuest, nil, addedGuest},
+		{"Checking in a guest", "audit-test-check-in", audit.OperationCheckInGuest, addedGuest, checkedInGuest},
+		{"Checking out a guest", "audit-test-check-out", audit.OperationCheckOutGuest, checkedInGuest, nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			records := auditRecords(t, "request_id="+testCase.requestID)
+			if len(records) != 1 {
+				t.Fatalf("Expected one record, got %v\n", records)
+			}
+			record := records[0]
+			if record.Actor != "anonymous" || record.Operation != testCase.expectedOperation || record.TargetID != "guest-1" {
+				t.Errorf("Unexpected record %+v\n", record)
+			}
+			var before, after *auditedGuest
+			if err := json.Unmarshal(record.Before, &before); err != nil {
+				t.Fatalf("Couldn't decode state before: %v\n", err)
+			}
+			if err := json.Unmarshal(record.After, &after); err != nil {
+				t.Fatalf("Couldn't decode state after: %v\n", err)
+			}
+			if !reflect.DeepEqual(before, testCase.expectedBefore) || !reflect.DeepEqual(after, testCase.expectedAfter) {
+				t.Errorf("Unexpected states\nbefore:%s\nafter:%s\n", record.Before, record.After)
+			}
+		})
+	}
+
+	// Filters combine, newest records first
+	records := auditRecords(t, "target_id=guest-1&operation=check_in_guest&request_id=audit-test-check-in")
+	if len(records) != 1 || records[0].RequestID != "audit-test-check-in" {
+		t.Errorf("Expected the check in first, got %v\n", records)
+	}
+	records = auditRecords(t, "actor=anonymous&limit=2")
+	if len(records) != 2 || records[0].RequestID != "audit-test-check-out" || records[1].PreviousHash == "" || records[0].PreviousHash != records[1].Hash {
+		t.Errorf("Expected the two last records chained together, got %v\n", records)
+	}
+
+	var verification api.AuditVerificationResponse
+	responseRecorder = sendRequest(t, http.MethodGet, "/audit/verify", nil)
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &verification); err != nil {
+		t.Fatalf("Couldn't decode response: %v\n", err)
+	}
+	if !verification.Valid || verification.LastHash != records[0].Hash {
+		t.Errorf("Expected a valid log ending with the last record, got %+v\n", verification)
+	}
+
+	// Records cannot be changed once appended
+	if err := store.DB().Model(&database.AuditRecord{}).Where("request_id = ?", "audit-test-add").Update("actor", "someone else").Error; err == nil {
+		t.Error("Expected audit records to be read only\n")
+	}
+
+	// A record forged without knowing how records are hashed breaks the chain
+	forgedRecord := database.AuditRecord{
+		Time:         records[0].Time,
+		Actor:        "anonymous",
+		RequestID:    "audit-test-forged",
+		Operation:    audit.OperationCheckOutGuest,
+		TargetID:     "guest-francisco",
+		Before:       "null",
+		After:        "null",
+		PreviousHash: verification.LastHash,
+		Hash:         verification.LastHash,
+	}
+	if err := store.AppendAuditRecord(&forgedRecord); err != nil {
+		t.Fatalf("Couldn't forge record: %v\n", err)
+	}
+	responseRecorder = sendRequest(t, http.MethodGet, "/audit/verify", nil)
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &verification); err != nil {
+		t.Fatalf("Couldn't decode response: %v\n", err)
+	}
+	if verification.Valid || verification.BrokenSequence != forgedRecord.Sequence {
+		t.Errorf("Expected the forged record to break the log, got %+v\n", verification)
+	}
+}
@@ -0,0 +1,125 @@
+package restapitest
+
+import (
+	"guestListChallenge/src/api"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// TestCategories Checks that the rules of each category of guests are applied: reserved tables, capacity bypass
+// and entourage limits, and that guests can be filtered and counted by category
+func TestCategories(t *testing.T) {
+	resetDatabase()
+
+	// Other tests expect the default rules and no venue capacity
+	defer sendRequest(t, http.MethodPut, "/categories/vip", map[string]interface{}{"reserved_table": true, "bypass_capacity": false, "max_accompanying_guests": nil})
+	defer sendRequest(t, http.MethodPut, "/occupancy/capacity", map[string]interface{}{"capacity": nil})
+
+	sendRequest(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 4, "accompanying_guests": 1, "category": "vip"})
+	expectRefusal(t, http.MethodPost, "/guest_list/Costa", map[string]interface{}{"table": 2, "accompanying_guests": 1, "category": "staff"},
+		"Guests in category staff may bring at most 0 accompanying guests")
+	sendRequest(t, http.MethodPost, "/guest_list/Costa", map[string]interface{}{"table": 2, "accompanying_guests": 0, "category": "staff"})
+
+	var guestList api.GuestListResponse
+	decodeReply(t, http.MethodGet, "/guest_list?category=vip", nil, &guestList)
+	if len(guestList.Guests) != 1 || guestList.Guests[0].Name != "Silva" || guestList.Guests[0].Category != "vip" {
+		t.Errorf("Expected Silva as the only VIP, got %+v\n", guestList.Guests)
+	}
+
+	// Silva's table is kept for them although they arrived
+	sendRequest(t, http.MethodPut, "/guests/Silva", map[string]interface{}{"accompanying_guests": 1})
+	expectRefusal(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Pereira", "accompanying_guests": 0}, "No table has 1 empty seats")
+	expectRefusal(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Pereira", "accompanying_guests": 0, "seated_with": "guest-1"},
+		"The table of Silva is reserved for guests in category vip")
+	var seats api.EmptySeatsResponse
+	decodeReply(t, http.MethodGet, "/seats_empty", nil, &seats)
+	if seats.SeatsEmpty != 9 || seats.SeatsAvailable != 0 {
+		t.Errorf("Expected 9 empty seats none available, got %+v\n", seats)
+	}
+
+	// Without the reservation rule the table can be given away
+	var vipRules api.CategoryResponse
+	decodeReply(t, http.MethodPut, "/categories/vip", map[string]interface{}{"reserved_table": false, "bypass_capacity": false, "max_accompanying_guests": nil}, &vipRules)
+	if vipRules.ReservedTable || vipRules.MaxAccompanyingGuests != nil || vipRules.Guests != 1 || vipRules.ArrivedGuests != 1 {
+		t.Errorf("Unexpected VIP rules %+v\n", vipRules)
+	}
+	var walkIn api.WalkInResponse
+	decodeReply(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Pereira", "accompanying_guests": 0}, &walkIn)
+	if walkIn.SeatedWith != "guest-1" {
+		t.Errorf("Expected Pereira seated at Silva's table, got %+v\n", walkIn)
+	}
+
+	// Staff get in when the venue is full, other guests do not
+	sendRequest(t, http.MethodPut, "/occupancy/capacity", map[string]interface{}{"capacity": 9})
+	expectRefusal(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 0}, "The venue holds 9 people of its capacity of 9")
+	var arrival api.GuestResponse
+	decodeReply(t, http.MethodPut, "/guests/Costa", map[string]interface{}{"accompanying_guests": 0}, &arrival)
+	if arrival.Name != "Costa" {
+		t.Errorf("Expected Costa to check in, got %+v\n", arrival)
+	}
+
+	// Martins registered 2 accompanying guests, more than plus-ones may bring
+	expectRefusal(t, http.MethodPut, "/guest_list/Martins/category", map[string]interface{}{"category": "plus_one"},
+		"Guests in category plus_one may bring at most 0 accompanying guests")
+	var speaker api.ListedGuest
+	decodeReply(t, http.MethodPut, "/guest_list/Martins/category", map[string]interface{}{"category": "speaker"}, &speaker)
+	if speaker.Category != "speaker" {
+		t.Errorf("Expected Martins to be a speaker, got %+v\n", speaker)
+	}
+	if responseRecorder := sendRequest(t, http.MethodPut, "/guest_list/Pereira/category", map[string]interface{}{"category": "vip"}); responseRecorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected walk-ins not to be put in a category, got %d\n", responseRecorder.Code)
+	}
+
+	var arrivedGuests api.ArrivedGuestsResponse
+	decodeReply(t, http.MethodGet, "/guests?category=staff", nil, &arrivedGuests)
+	if len(arrivedGuests.Guests) != 1 || arrivedGuests.Guests[0].Name != "Costa" {
+		t.Errorf("Expected Costa as the only staff at the party, got %+v\n", arrivedGuests.Guests)
+	}
+
+	var categories api.CategoriesResponse
+	decodeReply(t, http.MethodGet, "/categories", nil, &categories)
+	counts := map[string][3]int{}
+	for _, category := range categories.Categories {
+		counts[category.Category] = [3]int{category.Guests, category.ArrivedGuests, category.PeopleAtParty}
+	}
+	expectedCounts := map[string][3]int{
+		"guest":    {2, 2, 7},
+		"vip":      {1, 1, 2},
+		"staff":    {1, 1, 1},
+		"speaker":  {1, 0, 0},
+		"plus_one": {0, 0, 0},
+		"vendor":   {0, 0, 0},
+	}
+	if !reflect.DeepEqual(counts, expectedCounts) {
+		t.Errorf("Unexpected category counts %v\n", counts)
+	}
+}
+
+// TestUndoCategoryChange Checks that a guest is put back in their former category, if its rules let them in
+func TestUndoCategoryChange(t *testing.T) {
+	resetDatabase()
+
+	// Other tests expect the default rules
+	defer sendRequest(t, http.MethodPut, "/categories/guest", map[string]interface{}{"reserved_table": false, "bypass_capacity": false, "max_accompanying_guests": nil})
+
+	sendRequest(t, http.MethodPut, "/guest_list/Martins/category", map[string]interface{}{"category": "vip"})
+	change := latestOperation(t)
+	if change.Operation != "set_category" || !change.Undoable {
+		t.Errorf("Unexpected operation %+v\n", change)
+	}
+
+	// Martins registered 2 accompanying guests, more than regular guests may now bring
+	sendRequest(t, http.MethodPut, "/categories/guest", map[string]interface{}{"reserved_table": false, "bypass_capacity": false, "max_accompanying_guests": 1})
+	if responseRecorder := undo(t, change.ID); responseRecorder.Code != http.StatusUnprocessableEntity || responseRecorder.Header().Get(api.ErrorCodeHeader) != api.ErrorCodeEntourageTooBig {
+		t.Errorf("Expected Martins not to be a regular guest again, got %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+
+	sendRequest(t, http.MethodPut, "/categories/guest", map[string]interface{}{"reserved_table": false, "bypass_capacity": false, "max_accompanying_guests": nil})
+	if responseRecorder := undo(t, change.ID); responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	if martins, _ := store.GuestByID("guest-martins"); martins.Category != "guest" {
+		t.Errorf("Expected Martins back as a regular guest, got %+v\n", martins)
+	}
+}
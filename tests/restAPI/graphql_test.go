@@ -0,0 +1,194 @@
+package restapitest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/jinzhu/gorm"
+	"guestListChallenge/src/guestService"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// queryCount Number of database queries run since the query counter was registered
+var queryCount int64
+
+// countQueries Counts the database queries run while f executes
+func countQueries(f func()) int64 {
+	if store.DB().Callback().Query().Get("test:count_queries") == nil {
+		store.DB().Callback().Query().After("gorm:query").Register("test:count_queries", func(*gorm.Scope) {
+			atomic.AddInt64(&queryCount, 1)
+		})
+	}
+	before := atomic.LoadInt64(&queryCount)
+	f()
+	return atomic.LoadInt64(&queryCount) - before
+}
+
+// sendGraphQLQuery Sends a GraphQL request to the test server and decodes its result
+func sendGraphQLQuery(t *testing.T, query string) map[string]interface{} {
+	responseRecorder := sendRequest(t, http.MethodPost, "/graphql", map[string]interface{}{"query": query})
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d\n", responseRecorder.Code)
+	}
+
+	var result struct {
+		Data   map[string]interface{}   `json:"data"`
+		Errors []map[string]interface{} `json:"errors"`
+	}
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Couldn't decode result: %v\n", err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors %v\n", result.Errors)
+	}
+	return result.Data
+}
+
+// TestGraphQLQueries Checks that GraphQL queries read the same guest list as the REST API
+func TestGraphQLQueries(t *testing.T) {
+	resetDatabase()
+
+	data := sendGraphQLQuery(t, `{
+		event { id stats { guests arrivedGuests peopleAtParty seats seatsEmpty } }
+		tables(filter: {arrived: false}) { seatsEmpty guest { name } }
+		visits { partySize guest { name } }
+	}`)
+
+	expectedData := map[string]interface{}{
+		"event": map[string]interface{}{
+			"id": "end-of-year-party",
+			"stats": map[string]interface{}{
+				"guests": 2.0, "arrivedGuests": 1.0, "peopleAtParty": 6.0, "seats": 9.0, "seatsEmpty": 4.0,
+			},
+		},
+		"tables": []interface{}{
+			map[string]interface{}{"seatsEmpty": 4.0, "guest": map[string]interface{}{"name": "Martins"}},
+		},
+		"visits": []interface{}{
+			map[string]interface{}{"partySize": 6.0, "guest": map[string]interface{}{"name": "Francisco"}},
+		},
+	}
+	expectedJSON, _ := json.Marshal(expectedData)
+	actualJSON, _ := json.Marshal(data)
+	if !bytes.Equal(expectedJSON, actualJSON) {
+		t.Errorf("Expected %s, got %s\n", expectedJSON, actualJSON)
+	}
+}
+
+// TestGraphQLBatchedLoading Checks that guests looked up by ID in one request are loaded with a single query
+func TestGraphQLBatchedLoading(t *testing.T) {
+	resetDatabase()
+
+	queries := countQueries(func() {
+		data := sendGraphQLQuery(t, `{
+			francisco: guest(id: "guest-francisco") { name table { seats guest { name } } }
+			martins: guest(id: "guest-martins") { name visit { timeArrived } }
+			nobody: guest(id: "guest-nobody") { name }
+		}`)
+		if data["nobody"] != nil {
+			t.Errorf("Expected no guest, got %v\n", data["nobody"])
+		}
+	})
+	if queries != 1 {
+		t.Errorf("Expected a single query, got %d\n", queries)
+	}
+
+	// The guest list is loaded once no matter how many fields list it
+	queries = countQueries(func() {
+		sendGraphQLQuery(t, `{ guests { name } tables { guest { name } } visits { guest { name } } stats { seatsEmpty } }`)
+	})
+	if queries != 1 {
+		t.Errorf("Expected a single query, got %d\n", queries)
+	}
+}
+
+// TestGraphQLMutations Checks that GraphQL mutations apply the same rules as the REST API
+func TestGraphQLMutations(t *testing.T) {
+	resetDatabase()
+
+	// Admissions name the rule that let the guest in
+	data := sendGraphQLQuery(t, `mutation { addGuest(name: "Silva", table: 3, accompanyingGuests: 1) { id name admission { rule } } }`)
+	if added := data["addGuest"].(map[string]interface{}); added["id"] != "guest-1" || added["name"] != "Silva" ||
+		added["admission"].(map[string]interface{})["rule"] != guestService.AdmissionPolicyRule {
+		t.Errorf("Unexpected guest %v\n", added)
+	}
+
+	data = sendGraphQLQuery(t, `mutation { checkInGuest(name: "Silva", accompanyingGuests: 2) { timeArrived partySize admission { rule reason } guest { table { seatsEmpty } } } }`)
+	expectedJSON := `{"checkInGuest":{"admission":{"reason":"Passed table_capacity, entourage_limit and venue_capacity","rule":"policy"},` +
+		`"guest":{"table":{"seatsEmpty":1}},"partySize":3,"timeArrived":"21:5"}}`
+	if actualJSON, _ := json.Marshal(data); string(actualJSON) != expectedJSON {
+		t.Errorf("Expected %s, got %s\n", expectedJSON, actualJSON)
+	}
+
+	responseRecorder := sendRequest(t, http.MethodPost, "/graphql", map[string]interface{}{
+		"query": `mutation { checkInGuest(name: "Silva") { timeArrived } }`,
+	})
+	if !strings.Contains(responseRecorder.Body.String(), `"code":"already_checked_in"`) {
+		t.Errorf("Expected already_checked_in error, got %s\n", responseRecorder.Body.String())
+	}
+
+	// Refusals of the admission rules name the rule that refused the guest
+	responseRecorder = sendRequest(t, http.MethodPost, "/graphql", map[string]interface{}{
+		"query": `mutation { checkInGuest(name: "Martins", accompanyingGuests: 5) { timeArrived } }`,
+	})
+	if !strings.Contains(responseRecorder.Body.String(), `"rule":"table_capacity"`) {
+		t.Errorf("Expected a refusal of the table_capacity rule, got %s\n", responseRecorder.Body.String())
+	}
+
+	// Guests are counted and filtered by category
+	sendGraphQLQuery(t, `mutation { addGuest(name: "Costa", table: 2, category: "staff") { id } }`)
+	data = sendGraphQLQuery(t, `{ guests(filter: {category: "staff"}) { name category admission { rule } } stats { categories { category guests } } }`)
+	if actualJSON, _ := json.Marshal(data["guests"]); string(actualJSON) != `[{"admission":null,"category":"staff","name":"Costa"}]` {
+		t.Errorf("Expected Costa as the only staff, got %s\n", actualJSON)
+	}
+	expectedJSON = `[{"category":"guest","guests":3},{"category":"vip","guests":0},{"category":"staff","guests":1},` +
+		`{"category":"speaker","guests":0},{"category":"plus_one","guests":0},{"category":"vendor","guests":0}]`
+	if actualJSON, _ := json.Marshal(data["stats"].(map[string]interface{})["categories"]); string(actualJSON) != expectedJSON {
+		t.Errorf("Expected %s, got %s\n", expectedJSON, actualJSON)
+	}
+}
+
+// TestGraphQLArrivalSubscription Checks that check-ins made through the REST API are streamed to GraphQL subscribers
+func TestGraphQLArrivalSubscription(t *testing.T) {
+	resetDatabase()
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	query := url.Values{"query": {`subscription { guestArrived { accompanyingGuests guest { name } } }`}}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/graphql?"+query.Encode(), nil)
+	if err != nil {
+		t.Fatalf("Couldn't create request: %v\n", err)
+	}
+	request.Header.Set("Accept", "text/event-stream")
+
+	// Headers are sent once the server subscribed
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Couldn't subscribe: %v\n", err)
+	}
+	defer response.Body.Close()
+
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 3})
+
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		if !strings.HasPrefix(scanner.Text(), "data: ") {
+			continue
+		}
+		expectedJSON := `{"data":{"guestArrived":{"accompanyingGuests":3,"guest":{"name":"Martins"}}}}`
+		if payload := strings.TrimPrefix(scanner.Text(), "data: "); payload != expectedJSON {
+			t.Errorf("Expected %s, got %s\n", expectedJSON, payload)
+		}
+		return
+	}
+	t.Fatalf("Subscription ended without result: %v\n", scanner.Err())
+}
@@ -0,0 +1,153 @@
+package restapitest
+
+import (
+	"context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/grpcServer"
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/guestlistpb"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newGRPCClient Starts a gRPC server sharing the guest service of the REST server under test
+func newGRPCClient(t *testing.T) guestlistpb.GuestListClient {
+	rpcServer := grpcServer.NewServer(server.GuestService(), log.New(ioutil.Discard, "", 0), grpcServer.DefaultConfig())
+
+	listener := bufconn.Listen(1024 * 1024)
+	go rpcServer.Serve(listener)
+	t.Cleanup(rpcServer.Stop)
+
+	connection, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Couldn't connect to gRPC server: %v\n", err)
+	}
+	t.Cleanup(func() { connection.Close() })
+
+	return guestlistpb.NewGuestListClient(connection)
+}
+
+// TestGRPCAPI Checks that the gRPC API works on the same guest list as the REST API
+func TestGRPCAPI(t *testing.T) {
+	resetDatabase()
+	client := newGRPCClient(t)
+	ctx := context.Background()
+
+	added, err := client.AddGuest(ctx, &guestlistpb.AddGuestRequest{Name: "Silva", Table: 3, AccompanyingGuests: 1})
+	if err != nil {
+		t.Fatalf("Couldn't add guest: %v\n", err)
+	}
+	if added.Id != "guest-1" || added.Name != "Silva" || added.Admission.GetRule() != guestService.AdmissionPolicyRule {
+		t.Errorf("Unexpected guest %v\n", added)
+	}
+
+	guestList, err := client.ListGuests(ctx, &guestlistpb.ListGuestsRequest{})
+	if err != nil {
+		t.Fatalf("Couldn't list guests: %v\n", err)
+	}
+	if len(guestList.Guests) != 3 {
+		t.Errorf("Expected 3 guests, got %d\n", len(guestList.Guests))
+	}
+
+	arrived, err := client.CheckInGuest(ctx, &guestlistpb.CheckInGuestRequest{Guest: &guestlistpb.CheckInGuestRequest_Name{Name: "Silva"}, AccompanyingGuests: 2})
+	if err != nil {
+		t.Fatalf("Couldn't check guest in: %v\n", err)
+	}
+	if arrived.TimeArrived != "21:5" || arrived.Admission.GetReason() != "Passed table_capacity, entourage_limit and venue_capacity" {
+		t.Errorf("Expected arrival at 21:5 let in by every rule, got %v\n", arrived)
+	}
+
+	seats, err := client.CountEmptySeats(ctx, &guestlistpb.CountEmptySeatsRequest{})
+	if err != nil {
+		t.Fatalf("Couldn't count empty seats: %v\n", err)
+	}
+	// Francisco's table is full, Martins has not arrived and Silva's table has one seat left
+	if seats.SeatsEmpty != 5 {
+		t.Errorf("Expected 5 empty seats, got %d\n", seats.SeatsEmpty)
+	}
+}
+
+// TestGRPCAgeRestriction Checks that gRPC check-ins carry the age the age_restriction rule needs, refusals naming the rule
+func TestGRPCAgeRestriction(t *testing.T) {
+	resetDatabase()
+	client := newGRPCClient(t)
+	ctx := context.Background()
+
+	// Other tests expect the default rules
+	defer sendRequest(t, http.MethodPut, "/admission/rules", map[string]interface{}{"rules": []map[string]interface{}{
+		{"rule": "table_capacity"}, {"rule": "entourage_limit"}, {"rule": "venue_capacity"},
+	}})
+	sendRequest(t, http.MethodPut, "/admission/rules", map[string]interface{}{"rules": []map[string]interface{}{
+		{"rule": "table_capacity"}, {"rule": "age_restriction", "minimum_age": 18},
+	}})
+
+	martins := &guestlistpb.CheckInGuestRequest_Name{Name: "Martins"}
+	_, err := client.CheckInGuest(ctx, &guestlistpb.CheckInGuestRequest{Guest: martins, AccompanyingGuests: 1})
+	var errorInfo *errdetails.ErrorInfo
+	for _, detail := range status.Convert(err).Details() {
+		if info, isErrorInfo := detail.(*errdetails.ErrorInfo); isErrorInfo {
+			errorInfo = info
+		}
+	}
+	if errorInfo == nil || errorInfo.Reason != api.ErrorCodeAgeRestricted || errorInfo.Metadata["rule"] != guestService.AdmissionRuleAgeRestriction {
+		t.Errorf("Expected a refusal of the age_restriction rule, got %v\n", err)
+	}
+
+	age := int32(30)
+	arrived, err := client.CheckInGuest(ctx, &guestlistpb.CheckInGuestRequest{Guest: martins, AccompanyingGuests: 1, Age: &age})
+	if err != nil {
+		t.Fatalf("Couldn't check guest in: %v\n", err)
+	}
+	if arrived.Admission.GetRule() != guestService.AdmissionPolicyRule {
+		t.Errorf("Expected Martins let in by every rule, got %v\n", arrived.Admission)
+	}
+}
+
+// TestGRPCAttendanceUpdates Checks that check-ins and check-outs made through the REST API are streamed to gRPC watchers
+func TestGRPCAttendanceUpdates(t *testing.T) {
+	resetDatabase()
+	client := newGRPCClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := client.WatchAttendance(ctx, &guestlistpb.WatchAttendanceRequest{})
+	if err != nil {
+		t.Fatalf("Couldn't watch attendance: %v\n", err)
+	}
+	// Headers are sent once the server subscribed
+	if _, err := stream.Header(); err != nil {
+		t.Fatalf("Couldn't watch attendance: %v\n", err)
+	}
+
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 3})
+	sendRequest(t, http.MethodDelete, "/guests/Francisco", nil)
+
+	var expectedUpdates = []struct {
+		kind       guestlistpb.AttendanceUpdate_Kind
+		guestName  string
+		seatsEmpty int32
+	}{
+		{guestlistpb.AttendanceUpdate_KIND_ARRIVED, "Martins", 1},
+		{guestlistpb.AttendanceUpdate_KIND_LEFT, "Francisco", 1},
+	}
+
+	for _, expectedUpdate := range expectedUpdates {
+		update, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Couldn't receive attendance update: %v\n", err)
+		}
+		if update.Kind != expectedUpdate.kind || update.Guest.Name != expectedUpdate.guestName || update.SeatsEmpty != expectedUpdate.seatsEmpty {
+			t.Errorf("Expected %v of %s with %d empty seats, got %v\n", expectedUpdate.kind, expectedUpdate.guestName, expectedUpdate.seatsEmpty, update)
+		}
+	}
+}
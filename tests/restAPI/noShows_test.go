@@ -0,0 +1,108 @@
+package restapitest
+
+import (
+	"context"
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// decodeReply Decodes the reply to a request, failing the test unless it succeeded
+func decodeReply(t *testing.T, requestType string, requestPath string, requestContent interface{}, reply interface{}) {
+	responseRecorder := sendRequest(t, requestType, requestPath, requestContent)
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), reply); err != nil {
+		t.Fatalf("Couldn't decode response: %v\n", err)
+	}
+}
+
+// noShowNames Returns the names of the guests listed in a no-shows reply
+func noShowNames(reply api.NoShowsResponse) []string {
+	names := []string{}
+	for _, guest := range reply.Guests {
+		names = append(names, guest.Name)
+	}
+	return names
+}
+
+// TestNoShows Checks that guests who did not arrive by the cutoff are marked as no-shows, unless their reservation is held,
+// and that their seats become available
+func TestNoShows(t *testing.T) {
+	resetDatabase()
+
+	// Other tests expect the clock at its initial time
+	initialTime := clock.Now()
+	defer clock.Set(initialTime)
+
+	sendRequest(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 3, "accompanying_guests": 1})
+
+	var heldGuest api.NoShowGuest
+	decodeReply(t, http.MethodPut, "/guest_list/Silva/no_show", map[string]interface{}{"no_show": false}, &heldGuest)
+	if !heldGuest.ReservationHeld || heldGuest.NoShowAt != nil {
+		t.Errorf("Expected Silva's reservation held, got %+v\n", heldGuest)
+	}
+
+	cutoff := initialTime.Add(30 * time.Minute)
+	var cutoffReply api.NoShowCutoffResponse
+	decodeReply(t, http.MethodPut, "/no_shows/cutoff", map[string]interface{}{"cutoff": cutoff.Format(time.RFC3339)}, &cutoffReply)
+	if cutoffReply.Cutoff == nil || !cutoffReply.Cutoff.Equal(cutoff) {
+		t.Errorf("Expected cutoff %v, got %v\n", cutoff, cutoffReply.Cutoff)
+	}
+
+	// Nobody is marked before the cutoff
+	if marked, err := server.GuestService().DetectNoShows(context.Background()); err != nil || len(marked) != 0 {
+		t.Errorf("Expected nobody marked before the cutoff, got %v (%v)\n", marked, err)
+	}
+
+	// Martins did not arrive and is marked, Silva's reservation is held
+	clock.Advance(31 * time.Minute)
+	if marked, err := server.GuestService().DetectNoShows(context.Background()); err != nil || len(marked) != 1 || marked[0].Name != "Martins" {
+		t.Errorf("Expected Martins marked after the cutoff, got %v (%v)\n", marked, err)
+	}
+
+	var seats api.EmptySeatsResponse
+	decodeReply(t, http.MethodGet, "/seats_empty", nil, &seats)
+	if seats.SeatsEmpty != 7 || seats.SeatsAvailable != 4 {
+		t.Errorf("Expected 7 empty seats of which 4 available, got %+v\n", seats)
+	}
+
+	var noShows api.NoShowsResponse
+	decodeReply(t, http.MethodGet, "/no_shows", nil, &noShows)
+	if names := noShowNames(noShows); len(names) != 1 || names[0] != "Martins" || noShows.SeatsReleased != 4 {
+		t.Errorf("Expected Martins' 4 seats released, got %+v\n", noShows)
+	}
+
+	// Guests who arrived cannot be marked
+	responseRecorder := sendRequest(t, http.MethodPut, "/guest_list/Francisco/no_show", map[string]interface{}{"no_show": true})
+	if responseRecorder.Code != http.StatusOK || !strings.Contains(responseRecorder.Body.String(), "already checked in") {
+		t.Errorf("Expected Francisco not to be marked, got %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+
+	// Releasing the tables marks guests who did not arrive whatever the cutoff
+	sendRequest(t, http.MethodPost, "/guest_list/Costa", map[string]interface{}{"table": 2, "accompanying_guests": 1})
+	decodeReply(t, http.MethodPost, "/no_shows/release", nil, &noShows)
+	if names := noShowNames(noShows); len(names) != 1 || names[0] != "Costa" || noShows.SeatsReleased != 2 {
+		t.Errorf("Expected Costa's 2 seats released, got %+v\n", noShows)
+	}
+
+	// Guests marked as no-shows can still check in late
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 2})
+	decodeReply(t, http.MethodGet, "/no_shows", nil, &noShows)
+	if names := noShowNames(noShows); len(names) != 1 || names[0] != "Costa" {
+		t.Errorf("Expected only Costa left as a no-show, got %v\n", names)
+	}
+	decodeReply(t, http.MethodGet, "/seats_empty", nil, &seats)
+	if seats.SeatsEmpty != 7 || seats.SeatsAvailable != 4 {
+		t.Errorf("Expected 7 empty seats of which 4 available, got %+v\n", seats)
+	}
+
+	decodeReply(t, http.MethodPut, "/no_shows/cutoff", map[string]interface{}{"cutoff": nil}, &cutoffReply)
+	if cutoffReply.Cutoff != nil {
+		t.Errorf("Expected no-show detection disabled, got cutoff %v\n", cutoffReply.Cutoff)
+	}
+}
@@ -0,0 +1,183 @@
+package restapitest
+
+import (
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/database"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// latestOperation Returns the latest operation on guests
+func latestOperation(t *testing.T) api.Operation {
+	responseRecorder := sendRequest(t, http.MethodGet, "/operations?limit=1", nil)
+	var receivedResponse api.OperationsResponse
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &receivedResponse); err != nil {
+		t.Fatalf("Couldn't decode response: %v\n", err)
+	}
+	if len(receivedResponse.Operations) != 1 {
+		t.Fatalf("Expected an operation, got %s\n", responseRecorder.Body.String())
+	}
+	return receivedResponse.Operations[0]
+}
+
+// undo Sends the request to undo an operation
+func undo(t *testing.T, operationID int64) *httptest.ResponseRecorder {
+	return sendRequest(t, http.MethodPost, "/operations/"+strconv.FormatInt(operationID, 10)+"/undo", nil)
+}
+
+// TestUndo Checks that operations on guests are reverted, and left as they are when the guest changed since
+func TestUndo(t *testing.T) {
+	resetDatabase()
+
+	// Checking in the wrong guest
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 3})
+	checkIn := latestOperation(t)
+	if checkIn.Operation != "check_in_guest" || checkIn.GuestName != "Martins" || !checkIn.Undoable {
+		t.Errorf("Unexpected operation %+v\n", checkIn)
+	}
+	responseRecorder := undo(t, checkIn.ID)
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	var undoResponse api.UndoResponse
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &undoResponse); err != nil {
+		t.Fatalf("Couldn't decode response: %v\n", err)
+	}
+	if undoResponse.Undone != checkIn.ID || undoResponse.Operation.Operation != "undo_check_in_guest" || undoResponse.Operation.GuestID != "guest-martins" {
+		t.Errorf("Unexpected reply %+v\n", undoResponse)
+	}
+	if martins, _ := store.GuestByID("guest-martins"); martins.TimeArrived != "" || martins.AccompanyingGuests != 2 {
+		t.Errorf("Expected Martins back to not arrived, got %+v\n", martins)
+	}
+
+	// Undoing twice conflicts with the first undoing
+	responseRecorder = undo(t, checkIn.ID)
+	var conflictResponse api.UndoConflictResponse
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &conflictResponse); err != nil {
+		t.Fatalf("Couldn't decode response: %v\n", err)
+	}
+	if responseRecorder.Code != http.StatusConflict || responseRecorder.Header().Get(api.ErrorCodeHeader) != api.ErrorCodeUndoConflict ||
+		len(conflictResponse.Conflicts) != 1 || conflictResponse.Conflicts[0].ID != undoResponse.Operation.ID {
+		t.Errorf("Expected a conflict with the undoing, got %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+
+	// Checking out the wrong guest
+	sendRequest(t, http.MethodDelete, "/guests/Francisco", nil)
+	if responseRecorder = undo(t, latestOperation(t).ID); responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	if francisco, err := store.GuestByID("guest-francisco"); err != nil || francisco.TimeArrived != "13:37" {
+		t.Errorf("Expected Francisco back at the party, got %+v %v\n", francisco, err)
+	}
+
+	// Adding a guest that checked in since
+	sendRequest(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 3, "accompanying_guests": 1})
+	add := latestOperation(t)
+	sendRequest(t, http.MethodPut, "/guests/Silva", map[string]interface{}{"accompanying_guests": 1})
+	if responseRecorder = undo(t, add.ID); responseRecorder.Code != http.StatusConflict {
+		t.Errorf("Expected a conflict with the check in, got %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	if !latestOperation(t).Undoable {
+		t.Error("Expected the check in to be undoable\n")
+	}
+
+	// Adding a guest that was changed outside the audit log
+	sendRequest(t, http.MethodPost, "/guest_list/Costa", map[string]interface{}{"table": 3, "accompanying_guests": 1})
+	add = latestOperation(t)
+	store.DB().Model(&database.GuestList{}).Where("id = ?", add.GuestID).Update("table", 4)
+	if responseRecorder = undo(t, add.ID); responseRecorder.Code != http.StatusConflict {
+		t.Errorf("Expected a conflict with the outside change, got %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	store.DB().Model(&database.GuestList{}).Where("id = ?", add.GuestID).Update("table", 3)
+	if responseRecorder = undo(t, add.ID); responseRecorder.Code != http.StatusOK {
+		t.Errorf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	if _, err := store.GuestByID(add.GuestID); err != database.ErrGuestNotFound {
+		t.Errorf("Expected Costa to be removed, got %v\n", err)
+	}
+
+	var testCases = []struct {
+		testCaseName       string
+		requestPath        string
+		expectedHttpStatus int
+		expectedErrorCode  string
+	}{
+		{"Unknown operation", "/operations/999999999/undo", http.StatusNotFound, api.ErrorCodeOperationNotFound},
+		{"Undoing an undoing", "/operations/" + strconv.FormatInt(undoResponse.Operation.ID, 10) + "/undo", http.StatusUnprocessableEntity, api.ErrorCodeNotUndoable},
+		{"Invalid operation ID", "/operations/first/undo", http.StatusUnprocessableEntity, api.ErrorCodeInvalidRequest},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			responseRecorder := sendRequest(t, http.MethodPost, testCase.requestPath, nil)
+			if responseRecorder.Code != testCase.expectedHttpStatus || responseRecorder.Header().Get(api.ErrorCodeHeader) != testCase.expectedErrorCode {
+				t.Errorf("Unexpected reply %d %s\n", responseRecorder.Code, responseRecorder.Header().Get(api.ErrorCodeHeader))
+			}
+		})
+	}
+}
+
+// TestUndoWithSeatedWalkIns Checks that a guest whose table was given to walk-ins cannot be taken back from the party
+func TestUndoWithSeatedWalkIns(t *testing.T) {
+	resetDatabase()
+
+	sendRequest(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 6, "accompanying_guests": 1})
+	sendRequest(t, http.MethodPut, "/guests/Silva", map[string]interface{}{"accompanying_guests": 1})
+	checkIn := latestOperation(t)
+	sendRequest(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Pereira", "accompanying_guests": 2, "seated_with": "guest-1"})
+
+	responseRecorder := undo(t, checkIn.ID)
+	var conflictResponse api.UndoConflictResponse
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &conflictResponse); err != nil {
+		t.Fatalf("Couldn't decode response: %v\n", err)
+	}
+	if responseRecorder.Code != http.StatusConflict || len(conflictResponse.Conflicts) != 1 ||
+		conflictResponse.Conflicts[0].GuestName != "Pereira" || conflictResponse.Conflicts[0].Operation != "admit_walk_in" {
+		t.Errorf("Expected a conflict with Pereira's admission, got %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+
+	// A guest who is back to not arrived while walk-ins sit at their table only gets the seats left
+	store.DB().Model(&database.GuestList{}).Where("id = ?", "guest-1").Update("time_arrived", "")
+	expectRefusal(t, http.MethodPut, "/guests/Silva", map[string]interface{}{"accompanying_guests": 4}, "was given to walk-ins, only 3 seats are left")
+
+	// Once the walk-ins leave nothing holds the guest back
+	store.DB().Model(&database.GuestList{}).Where("id = ?", "guest-1").Update("time_arrived", "21:5")
+	sendRequest(t, http.MethodDelete, "/guests/Pereira", nil)
+	if responseRecorder = undo(t, checkIn.ID); responseRecorder.Code != http.StatusOK {
+		t.Errorf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+}
+
+// TestUndoDelete Checks that guests who have not arrived are removed from the guest list, and put back by undoing the removal
+func TestUndoDelete(t *testing.T) {
+	resetDatabase()
+
+	expectRefusal(t, http.MethodDelete, "/guest_list/Francisco", nil, "Guest Francisco is at the party and has to be checked out instead")
+	if responseRecorder := sendRequest(t, http.MethodDelete, "/guest_list/Martins", nil); responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	if _, err := store.GuestByID("guest-martins"); err != database.ErrGuestNotFound {
+		t.Fatalf("Expected Martins to be removed, got %v\n", err)
+	}
+
+	deletion := latestOperation(t)
+	if deletion.Operation != "delete_guest" || deletion.GuestName != "Martins" || !deletion.Undoable {
+		t.Errorf("Unexpected operation %+v\n", deletion)
+	}
+	if responseRecorder := undo(t, deletion.ID); responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	if martins, err := store.GuestByID("guest-martins"); err != nil || martins.Table != 4 || martins.AccompanyingGuests != 2 {
+		t.Errorf("Expected Martins back on the guest list, got %+v %v\n", martins, err)
+	}
+	if responseRecorder := undo(t, deletion.ID); responseRecorder.Code != http.StatusConflict {
+		t.Errorf("Expected a conflict with the undoing, got %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+
+	// Walk-ins seated at the table of a no-show keep their host
+	sendRequest(t, http.MethodPut, "/guest_list/Martins/no_show", map[string]interface{}{"no_show": true})
+	sendRequest(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Pereira", "accompanying_guests": 0, "seated_with": "guest-martins"})
+	expectRefusal(t, http.MethodDelete, "/guest_list/id/guest-martins", nil, "walk-ins are seated at their table")
+}
@@ -0,0 +1,60 @@
+package restapitest
+
+import (
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestOccupancyReport Checks that the occupancy report is computed from the check-ins and check-outs, as JSON and as a chart
+func TestOccupancyReport(t *testing.T) {
+	resetDatabase()
+
+	// Other tests expect the clock at its initial time
+	initialTime := clock.Now()
+	defer clock.Set(initialTime)
+
+	clock.Advance(10 * time.Minute)
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 2})
+	clock.Advance(20 * time.Minute)
+	sendRequest(t, http.MethodDelete, "/guests/Martins", nil)
+	clock.Advance(10 * time.Minute)
+
+	query := "?from=" + initialTime.Format(time.RFC3339) + "&interval=20m"
+	responseRecorder := sendRequest(t, http.MethodGet, "/reports/occupancy"+query, nil)
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	var report api.OccupancyReportResponse
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Couldn't decode response: %v\n", err)
+	}
+
+	// Francisco and their 5 accompanying guests were there before, Martins came with 2 people for 20 minutes
+	if len(report.Timeline) != 2 || report.Timeline[0].Present != 9 || report.Timeline[1].Present != 9 || report.Timeline[1].Departures != 3 {
+		t.Errorf("Unexpected timeline %+v\n", report.Timeline)
+	}
+	if report.Peak != 9 || report.PeakAt == nil || !report.PeakAt.Equal(initialTime.Add(10*time.Minute)) {
+		t.Errorf("Expected a peak of 9 people 10 minutes in, got %d at %v\n", report.Peak, report.PeakAt)
+	}
+	if report.Visits != 1 || report.AverageStaySeconds != 1200 || report.Invited != 2 || report.NoShows != 0 {
+		t.Errorf("Unexpected report %+v\n", report)
+	}
+
+	responseRecorder = sendRequest(t, http.MethodGet, "/reports/occupancy.html"+query, nil)
+	if responseRecorder.Code != http.StatusOK || !strings.HasPrefix(responseRecorder.Header().Get("Content-Type"), "text/html") ||
+		!strings.Contains(responseRecorder.Body.String(), "<svg") {
+		t.Errorf("Expected an HTML chart, got %d %s\n", responseRecorder.Code, responseRecorder.Header().Get("Content-Type"))
+	}
+
+	// Invalid windows are refused
+	for _, query := range []string{"?interval=10s", "?interval=1m&from=2021-01-01T00:00:00Z", "?from=" + clock.Now().Format(time.RFC3339)} {
+		responseRecorder := sendRequest(t, http.MethodGet, "/reports/occupancy"+query, nil)
+		if responseRecorder.Code != http.StatusUnprocessableEntity || responseRecorder.Header().Get(api.ErrorCodeHeader) != api.ErrorCodeInvalidRequest {
+			t.Errorf("Expected %s to be refused, got %d %s\n", query, responseRecorder.Code, responseRecorder.Body.String())
+		}
+	}
+}
@@ -4,19 +4,40 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	_ "github.com/go-sql-driver/mysql"
-	"github.com/jinzhu/gorm"
-	"github.com/ory/dockertest/v3"
-	"github.com/ory/dockertest/v3/docker"
+	"guestListChallenge/src/api"
 	"guestListChallenge/src/database"
+	"guestListChallenge/src/guestService"
 	"guestListChallenge/src/requestRouting"
+	"guestListChallenge/src/tickets"
+	"guestListChallenge/src/utils"
+	"guestListChallenge/tests/testdatabase"
+	"log"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
+// store Database used by the test server
+var store *database.Store
+
+// ids Generator of the IDs given to guests added by the test server
+var ids = utils.NewSequenceIDGenerator("guest-")
+
+// clock Clock driving the arrival times recorded by the test server
+var clock = utils.NewFakeClock(time.Date(2021, time.December, 17, 21, 5, 0, 0, time.UTC))
+
+// ticketKey Key signing the tickets of the test server
+var ticketKey = []byte("test ticket key")
+
+// adminKey Admin key of the test server, the only key needed to download and restore snapshots
+const adminKey = "4dm1n-k3y"
+
+// server Server instance under test
+var server *requestRouting.Server
+
 // testCasesRESTAPI Structure holding all the test scenarios
 var testCasesRESTAPI = []struct {
 	testCaseName     string
@@ -35,6 +56,7 @@ var testCasesRESTAPI = []struct {
 		},
 		requestRouting.CreateAddGuestResponse(
 			database.GuestList{
+				ID:   "guest-1",
 				Name: "Francisco",
 			}),
 	},
@@ -55,14 +77,37 @@ var testCasesRESTAPI = []struct {
 		requestRouting.CreateGetGuestListResponse(
 			[]database.GuestList{
 				{
+					ID:                 "guest-francisco",
 					Name:               "Francisco",
 					Table:              5,
 					AccompanyingGuests: 5,
+					Category:           guestService.CategoryGuest,
 				},
 				{
+					ID:                 "guest-martins",
 					Name:               "Martins",
 					Table:              4,
 					AccompanyingGuests: 2,
+					Category:           guestService.CategoryGuest,
+				},
+			}),
+	},
+	{
+		"Searching the guest list with a misspelled name",
+		"/guest_list/search?q=fransisco",
+		http.MethodGet,
+		map[string]interface{}{},
+		requestRouting.CreateSearchGuestsResponse("fransisco",
+			[]requestRouting.GuestMatch{
+				{
+					Guest: database.GuestList{
+						ID:                 "guest-francisco",
+						Name:               "Francisco",
+						Table:              5,
+						AccompanyingGuests: 5,
+						TimeArrived:        "13:37",
+					},
+					Score: 0.89,
 				},
 			}),
 	},
@@ -75,9 +120,32 @@ var testCasesRESTAPI = []struct {
 		},
 		requestRouting.CreateCheckInGuestResponse(
 			database.GuestList{
+				ID:   "guest-martins",
 				Name: "Martins",
 			}),
 	},
+	{
+		"Checking in valid guest by ID",
+		"/guests/id/guest-martins",
+		http.MethodPut,
+		map[string]interface{}{
+			"accompanying_guests": 2,
+		},
+		requestRouting.CreateCheckInGuestResponse(
+			database.GuestList{
+				ID:   "guest-martins",
+				Name: "Martins",
+			}),
+	},
+	{
+		"Checking in an invalid guest by ID",
+		"/guests/id/guest-nobody",
+		http.MethodPut,
+		map[string]interface{}{
+			"accompanying_guests": 0,
+		},
+		"Guest with ID guest-nobody is not in the guest list",
+	},
 	{
 		"Checking in an invalid guest",
 		"/guests/ForeverAlone",
@@ -112,6 +180,13 @@ var testCasesRESTAPI = []struct {
 		map[string]interface{}{},
 		"Guest Francisco left the party",
 	},
+	{
+		"Checking out valid guest by ID",
+		"/guests/id/guest-francisco",
+		http.MethodDelete,
+		map[string]interface{}{},
+		"Guest Francisco left the party",
+	},
 	{
 		"Checking out guest that hasn't checked int yet",
 		"/guests/Martins",
@@ -134,9 +209,11 @@ var testCasesRESTAPI = []struct {
 		requestRouting.CreateGetArrivedGuestsResponse(
 			[]database.GuestList{
 				{
+					ID:                 "guest-francisco",
 					Name:               "Francisco",
 					AccompanyingGuests: 5,
 					TimeArrived:        "13:37",
+					Category:           guestService.CategoryGuest,
 				},
 			}),
 	},
@@ -145,7 +222,7 @@ var testCasesRESTAPI = []struct {
 		"/seats_empty",
 		http.MethodGet,
 		map[string]interface{}{},
-		requestRouting.CreateGetNumberOfEmptySeatsResponse(4),
+		requestRouting.CreateGetNumberOfEmptySeatsResponse(guestService.Seats{Empty: 4, Available: 0}),
 	},
 }
 
@@ -153,91 +230,73 @@ var testCasesRESTAPI = []struct {
 func resetDatabase() {
 
 	// Delete database contents
-	database.Connector.Delete(&database.GuestList{})
+	store.DB().Delete(&database.GuestList{})
+	store.DB().Delete(&database.Visit{})
+	store.DB().Delete(&database.UsedTicket{})
+	store.DB().Delete(&database.WebhookDelivery{})
+	store.DB().Delete(&database.Webhook{})
+	store.DB().Delete(&database.RoomAccess{})
+	store.DB().Delete(&database.Room{})
+	store.DB().Delete(&database.Setting{})
+	store.DB().Delete(&database.WaitlistEntry{})
+	ids.Reset()
 
 	// Populate database
 	guests := []database.GuestList{
 		{
+			ID:                 "guest-francisco",
 			Name:               "Francisco",
 			Table:              5,
 			AccompanyingGuests: 5,
 			TimeArrived:        "13:37",
+			Category:           guestService.CategoryGuest,
 		},
 		{
+			ID:                 "guest-martins",
 			Name:               "Martins",
 			Table:              4,
 			AccompanyingGuests: 2,
+			Category:           guestService.CategoryGuest,
 		},
 	}
 	for _, guest := range guests {
-		database.Connector.Create(&guest)
+		store.DB().Create(&guest)
 	}
 }
 
 // TestMain Setups all the necessary dependencies for the testing scenarios
+//
+// Tests run against SQLite unless GUESTLIST_TEST_DRIVER asks for mysql or postgres, which are run in Docker
 func TestMain(m *testing.M) {
 
-	// Create pool for MySQL Docker container
-	pool, operationError := dockertest.NewPool("")
-	if operationError != nil {
-		fmt.Println(operationError.Error())
-		panic("Could not connect to Docker")
-	}
-
-	// Setup MySQL container options
-	opts := dockertest.RunOptions{
-		Repository: "mysql",
-		Tag:        "5.7",
-		Env: []string{
-			"MYSQL_ROOT_PASSWORD=password",
-			"MYSQL_DATABASE=getground",
-			"MYSQL_USER=francisco",
-			"MYSQL_PASSWORD=password"},
-		ExposedPorts: []string{"3306"},
-		PortBindings: map[docker.Port][]docker.PortBinding{
-			"3306": {
-				{HostIP: "127.0.0.1", HostPort: "3306"},
-			},
-		},
-	}
+	db, closeDatabase := testdatabase.Open(os.Getenv(testdatabase.DriverVariable))
+	store = database.NewStore(db, ids)
 
-	// Run MySQL Docker container
-	resource, operationError := pool.RunWithOptions(&opts)
-	if operationError != nil {
+	// Setup test database
+	if _, operationError := database.MigrateUp(store.DB()); operationError != nil {
 		fmt.Println(operationError.Error())
-		panic("Could not start MySQL Docker container")
+		panic("Could not migrate database")
 	}
 
-	// Exponential backoff mechanism to wait for MySQL boot
-	if operationError := pool.Retry(func() error {
-
-		database.Connector, operationError = gorm.Open("mysql", fmt.Sprintf("francisco:password@(localhost:%s)/getground", resource.GetPort("3306/tcp")))
-		if operationError != nil {
-			fmt.Println("MySQL database still booting")
-			return operationError
-		}
-
-		// Check if database is reachable
-		return database.Connector.DB().Ping()
-	}); operationError != nil {
+	// Setup server under test, saving snapshots to a temporary directory
+	snapshotDirectory, operationError := os.MkdirTemp("", "guestlist-snapshots")
+	if operationError != nil {
 		fmt.Println(operationError.Error())
-		panic("Could not connect to MySQL Docker container")
+		panic("Could not create snapshot directory")
 	}
-
-	// Setup test database
-	database.Connector.AutoMigrate(&database.GuestList{})
-
-	// Setup request router
-	requestRouting.Setup()
+	config := requestRouting.DefaultConfig()
+	config.TicketKey = ticketKey
+	config.Snapshots.Directory = snapshotDirectory
+	config.AdminKeys = map[string]string{adminKey: "organiser"}
+	// Webhook receivers run on the loopback interface
+	config.Webhooks.AllowPrivateTargets = true
+	server = requestRouting.NewServer(store, clock, log.New(os.Stdout, "", log.LstdFlags), config)
 
 	// Run test scenarios
 	code := m.Run()
 
-	// Delete MySQL Docker container
-	if operationError := pool.Purge(resource); operationError != nil {
-		fmt.Println(operationError.Error())
-		panic("Could not purge MySQL Docker container")
-	}
+	closeDatabase()
+	os.RemoveAll(snapshotDirectory)
 
 	os.Exit(code)
 }
@@ -261,10 +320,11 @@ func TestRESAPI(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Couldn't create request: %v\n", err)
 		}
+		request.Header.Set("Content-Type", "application/json")
 
 		// Send request and register response
 		responseRecorder := httptest.NewRecorder()
-		requestRouting.Router.ServeHTTP(responseRecorder, request)
+		server.Handler().ServeHTTP(responseRecorder, request)
 
 		// Check response correctness
 		if responseRecorder.Code != http.StatusOK {
@@ -286,3 +346,176 @@ func TestRESAPI(t *testing.T) {
 		}
 	}
 }
+
+// TestCheckInTimeArrived Checks that a guest's arrival time is the time given by the server clock
+func TestCheckInTimeArrived(t *testing.T) {
+	resetDatabase()
+
+	// Check in guest
+	requestBody, err := json.Marshal(map[string]interface{}{"accompanying_guests": 3})
+	if err != nil {
+		t.Fatalf("Couldn't create request body: %v\n", err)
+	}
+	request, err := http.NewRequest(http.MethodPut, "/guests/Martins", bytes.NewReader(requestBody))
+	if err != nil {
+		t.Fatalf("Couldn't create request: %v\n", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	server.Handler().ServeHTTP(httptest.NewRecorder(), request)
+
+	// Get arrived guests
+	request, err = http.NewRequest(http.MethodGet, "/guests", nil)
+	if err != nil {
+		t.Fatalf("Couldn't create request: %v\n", err)
+	}
+	responseRecorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(responseRecorder, request)
+
+	// Assert arrival time
+	expectedResponse, err := json.Marshal(requestRouting.CreateGetArrivedGuestsResponse(
+		[]database.GuestList{
+			{
+				ID:                 "guest-francisco",
+				Name:               "Francisco",
+				AccompanyingGuests: 5,
+				TimeArrived:        "13:37",
+				Category:           guestService.CategoryGuest,
+			},
+			{
+				ID:                 "guest-martins",
+				Name:               "Martins",
+				AccompanyingGuests: 3,
+				TimeArrived:        "21:5",
+				Category:           guestService.CategoryGuest,
+			},
+		}))
+	if err != nil {
+		t.Fatalf("Couldn't encode expected response to Json: %v\n", err)
+	}
+	receivedResponse := strings.TrimSuffix(responseRecorder.Body.String(), "\n")
+	if receivedResponse != string(expectedResponse) {
+		t.Errorf("Incorrect response:\nexpected:%q\nreceived:%q \n", string(expectedResponse), receivedResponse)
+	}
+}
+
+// sendRequest Sends a request with a JSON body to the test server and returns the recorded response
+func sendRequest(t *testing.T, requestType string, requestPath string, requestContent interface{}) *httptest.ResponseRecorder {
+	requestBody, err := json.Marshal(requestContent)
+	if err != nil {
+		t.Fatalf("Couldn't create request body: %v\n", err)
+	}
+	request, err := http.NewRequest(requestType, requestPath, bytes.NewReader(requestBody))
+	if err != nil {
+		t.Fatalf("Couldn't create request: %v\n", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	responseRecorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(responseRecorder, request)
+	return responseRecorder
+}
+
+// TestGuestsSharingName Checks that guests sharing a name can be added and told apart by ID
+func TestGuestsSharingName(t *testing.T) {
+	resetDatabase()
+
+	// Add a second guest called Martins
+	sendRequest(t, http.MethodPost, "/guest_list/Martins", map[string]interface{}{"table": 3, "accompanying_guests": 1})
+
+	// Checking in by name must ask to choose between both guests
+	responseRecorder := sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 1})
+	if responseRecorder.Code != http.StatusMultipleChoices {
+		t.Errorf("Wrong http status received %d\n", responseRecorder.Code)
+	}
+	expectedResponse, err := json.Marshal(requestRouting.CreateAmbiguousGuestResponse("Martins", []database.GuestList{
+		{ID: "guest-1", Name: "Martins", Table: 3},
+		{ID: "guest-martins", Name: "Martins", Table: 4},
+	}))
+	if err != nil {
+		t.Fatalf("Couldn't encode expected response to Json: %v\n", err)
+	}
+	if receivedResponse := strings.TrimSuffix(responseRecorder.Body.String(), "\n"); receivedResponse != string(expectedResponse) {
+		t.Errorf("Incorrect response:\nexpected:%q\nreceived:%q \n", string(expectedResponse), receivedResponse)
+	}
+
+	// Checking in by ID picks the right guest
+	responseRecorder = sendRequest(t, http.MethodPut, "/guests/id/guest-1", map[string]interface{}{"accompanying_guests": 1})
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Wrong http status received %d\n", responseRecorder.Code)
+	}
+	guest, err := store.GuestByID("guest-1")
+	if err != nil {
+		t.Fatalf("Couldn't get guest: %v\n", err)
+	}
+	if guest.TimeArrived == "" {
+		t.Error("Guest chosen by ID was not checked in")
+	}
+}
+
+// TestScanTicket Checks that a scanned ticket checks its guest in once and is rejected afterwards, unless the check-in was refused
+func TestScanTicket(t *testing.T) {
+	resetDatabase()
+
+	// Tickets are served as QR code images
+	responseRecorder := sendRequest(t, http.MethodGet, "/guest_list/Martins/ticket.png", nil)
+	if responseRecorder.Code != http.StatusOK || responseRecorder.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("Ticket image was not served: status %d\n", responseRecorder.Code)
+	}
+
+	signer := tickets.NewSigner(ticketKey, requestRouting.DefaultConfig().EventID)
+	token, err := signer.Issue("guest-martins", clock.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Couldn't issue ticket: %v\n", err)
+	}
+	forgedToken, err := tickets.NewSigner([]byte("guessed key"), requestRouting.DefaultConfig().EventID).Issue("guest-martins", clock.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Couldn't issue ticket: %v\n", err)
+	}
+
+	testCases := []struct {
+		testCaseName       string
+		token              string
+		accompanyingGuests int
+		expectedStatus     int
+		expectedResponse   interface{}
+	}{
+		{"Scanning a forged ticket", forgedToken, 2, http.StatusForbidden, "Ticket rejected: ticket is forged"},
+		{"Scanning a ticket whose guest is refused", token, 5, http.StatusOK, "Guest Martins arrived with an entourage bigger than the registered one"},
+		{"Scanning a valid ticket", token, 2, http.StatusOK, requestRouting.CreateCheckInGuestResponse(database.GuestList{ID: "guest-martins", Name: "Martins"})},
+		{"Scanning a used ticket", token, 2, http.StatusConflict, "Ticket rejected: ticket was already used"},
+	}
+
+	for _, testCase := range testCases {
+		responseRecorder := sendRequest(t, http.MethodPost, "/checkin/scan", map[string]interface{}{"token": testCase.token, "accompanying_guests": testCase.accompanyingGuests})
+		if responseRecorder.Code != testCase.expectedStatus {
+			t.Errorf("%s: wrong http status received %d\n", testCase.testCaseName, responseRecorder.Code)
+		}
+
+		expectedResponse, err := json.Marshal(testCase.expectedResponse)
+		if err != nil {
+			t.Fatalf("Couldn't encode expected response to Json: %v\n", err)
+		}
+		if receivedResponse := strings.TrimSuffix(responseRecorder.Body.String(), "\n"); receivedResponse != string(expectedResponse) {
+			t.Errorf("%s: incorrect response:\nexpected:%q\nreceived:%q \n", testCase.testCaseName, string(expectedResponse), receivedResponse)
+		}
+	}
+}
+
+// TestScanTicketOfGuestInside Checks that the ticket of a guest already checked in is used by the first scan
+func TestScanTicketOfGuestInside(t *testing.T) {
+	resetDatabase()
+
+	token, err := tickets.NewSigner(ticketKey, requestRouting.DefaultConfig().EventID).Issue("guest-francisco", clock.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Couldn't issue ticket: %v\n", err)
+	}
+
+	responseRecorder := sendRequest(t, http.MethodPost, "/checkin/scan", map[string]interface{}{"token": token, "accompanying_guests": 5})
+	if responseRecorder.Header().Get(api.ErrorCodeHeader) != api.ErrorCodeAlreadyCheckedIn {
+		t.Errorf("Expected Francisco to be already checked in, got %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	responseRecorder = sendRequest(t, http.MethodPost, "/checkin/scan", map[string]interface{}{"token": token, "accompanying_guests": 5})
+	if responseRecorder.Code != http.StatusConflict || responseRecorder.Header().Get(api.ErrorCodeHeader) != api.ErrorCodeTicketUsed {
+		t.Errorf("Expected the ticket to be used, got %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+}
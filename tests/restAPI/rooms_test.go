@@ -0,0 +1,168 @@
+package restapitest
+
+import (
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/guestService"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// roomGuestNames Returns the names of the guests listed in an arrived guests reply
+func roomGuestNames(reply api.ArrivedGuestsResponse) []string {
+	names := []string{}
+	for _, guest := range reply.Guests {
+		names = append(names, guest.Name)
+	}
+	return names
+}
+
+// TestRooms Checks that tables are put in rooms, that guests at the party enter the rooms they have access to
+// up to their capacity, and that the guests and seats are filtered by room
+func TestRooms(t *testing.T) {
+	resetDatabase()
+
+	var terrace, lounge api.RoomResponse
+	decodeReply(t, http.MethodPost, "/rooms/Terrace", map[string]interface{}{"capacity": 8}, &terrace)
+	decodeReply(t, http.MethodPost, "/rooms/Lounge", map[string]interface{}{"restricted": true}, &lounge)
+	if terrace.Capacity == nil || *terrace.Capacity != 8 || terrace.Restricted || lounge.Capacity != nil || !lounge.Restricted {
+		t.Errorf("Unexpected rooms %+v and %+v\n", terrace, lounge)
+	}
+	expectRefusal(t, http.MethodPost, "/rooms/Terrace", map[string]interface{}{}, "Room Terrace is already in the venue")
+
+	var assigned api.ListedGuest
+	decodeReply(t, http.MethodPut, "/guest_list/Francisco/room", map[string]interface{}{"room": "Terrace"}, &assigned)
+	if assigned.RoomID != terrace.ID {
+		t.Errorf("Expected the table of Francisco in the terrace, got %+v\n", assigned)
+	}
+	sendRequest(t, http.MethodPut, "/guest_list/Martins/room", map[string]interface{}{"room": "Lounge"})
+
+	// Martins has not arrived, their table is reserved
+	var seats api.EmptySeatsResponse
+	decodeReply(t, http.MethodGet, "/seats_empty?room=Lounge", nil, &seats)
+	if seats.SeatsEmpty != 4 || seats.SeatsAvailable != 0 {
+		t.Errorf("Expected 4 empty seats in the lounge of which none available, got %+v\n", seats)
+	}
+	decodeReply(t, http.MethodGet, "/seats_empty?room=Terrace", nil, &seats)
+	if seats.SeatsEmpty != 0 {
+		t.Errorf("Expected no empty seats in the terrace, got %+v\n", seats)
+	}
+
+	// Only the guests whose table is in a restricted room and those granted access enter it
+	expectRefusal(t, http.MethodPut, "/rooms/Lounge/guests/Francisco", nil, "Guest Francisco has no access to room Lounge")
+	sendRequest(t, http.MethodPut, "/rooms/Lounge/access/Francisco", nil)
+	var inRoom api.ArrivedGuest
+	decodeReply(t, http.MethodPut, "/rooms/Lounge/guests/Francisco", nil, &inRoom)
+	if inRoom.InRoom != lounge.ID {
+		t.Errorf("Expected Francisco in the lounge, got %+v\n", inRoom)
+	}
+	expectRefusal(t, http.MethodPut, "/rooms/Lounge/guests/Martins", nil, "Guest Martins has not arrived yet")
+
+	// Rooms hold no more people than their capacity, entries being decided by the admission rules
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 2})
+	sendRequest(t, http.MethodPut, "/rooms/Terrace/capacity", map[string]interface{}{"capacity": 2})
+	expectRefusal(t, http.MethodPut, "/rooms/Terrace/guests/Martins", nil, "Room Terrace holds 0 people of its capacity of 2")
+	expectAdmission(t, http.MethodPut, "/rooms/Terrace/guests/Martins", nil, guestService.AdmissionRuleVenueCapacity, api.ErrorCodeRoomFull)
+	expectAdmission(t, http.MethodPut, "/rooms/Lounge/guests/Martins", nil, guestService.AdmissionPolicyRule, "")
+	sendRequest(t, http.MethodPut, "/rooms/Terrace/capacity", map[string]interface{}{"capacity": nil})
+	sendRequest(t, http.MethodPut, "/rooms/Terrace/guests/Francisco", nil)
+
+	var arrivedGuests api.ArrivedGuestsResponse
+	decodeReply(t, http.MethodGet, "/guests?room=Lounge", nil, &arrivedGuests)
+	if names := roomGuestNames(arrivedGuests); len(names) != 1 || names[0] != "Martins" {
+		t.Errorf("Expected only Martins in the lounge, got %v\n", names)
+	}
+
+	var rooms api.RoomsResponse
+	decodeReply(t, http.MethodGet, "/rooms", nil, &rooms)
+	if len(rooms.Rooms) != 2 || rooms.Rooms[0].Name != "Lounge" || rooms.Rooms[0].Headcount != 3 || rooms.Rooms[0].SeatsEmpty != 2 ||
+		rooms.Rooms[1].Name != "Terrace" || rooms.Rooms[1].Headcount != 6 || rooms.Rooms[1].Tables != 1 {
+		t.Errorf("Unexpected rooms %+v\n", rooms.Rooms)
+	}
+
+	// Leaving a room keeps the guest at the party
+	var outOfRoom api.ArrivedGuest
+	decodeReply(t, http.MethodDelete, "/rooms/Terrace/guests/Francisco", nil, &outOfRoom)
+	if outOfRoom.InRoom != "" || outOfRoom.TimeArrived == "" {
+		t.Errorf("Expected Francisco out of the terrace, got %+v\n", outOfRoom)
+	}
+	expectRefusal(t, http.MethodDelete, "/rooms/Terrace/guests/Francisco", nil, "Guest Francisco is not in room Terrace")
+	sendRequest(t, http.MethodDelete, "/rooms/Lounge/access/Francisco", nil)
+	expectRefusal(t, http.MethodPut, "/rooms/Lounge/guests/Francisco", nil, "Guest Francisco has no access to room Lounge")
+
+	expectRefusal(t, http.MethodDelete, "/rooms/Terrace", nil, "Room Terrace cannot be removed")
+	expectRefusal(t, http.MethodGet, "/guests?room=Garden", nil, "Room Garden is not in the venue")
+	responseRecorder := sendRequest(t, http.MethodGet, "/guests?room=Lounge&at="+clock.Now().Add(-time.Minute).Format(time.RFC3339), nil)
+	if responseRecorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected presence in rooms in the past refused, got %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+
+	sendRequest(t, http.MethodPut, "/guest_list/Francisco/room", map[string]interface{}{"room": nil})
+	decodeReply(t, http.MethodDelete, "/rooms/Terrace", nil, new(string))
+	decodeReply(t, http.MethodGet, "/rooms", nil, &rooms)
+	if len(rooms.Rooms) != 1 {
+		t.Errorf("Expected the terrace removed, got %+v\n", rooms.Rooms)
+	}
+}
+
+// TestUndoRoomMoves Checks that moves of tables and guests between rooms are undone, the walk-ins following the table they sit at
+func TestUndoRoomMoves(t *testing.T) {
+	resetDatabase()
+
+	var terrace api.RoomResponse
+	decodeReply(t, http.MethodPost, "/rooms/Terrace", map[string]interface{}{"capacity": 8}, &terrace)
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 2})
+	sendRequest(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Pereira", "accompanying_guests": 0, "seated_with": "guest-martins"})
+
+	// Every walk-in following the table is audited, and moves back with it
+	sendRequest(t, http.MethodPut, "/guest_list/Martins/room", map[string]interface{}{"room": "Terrace"})
+	if records := auditRecords(t, "operation=assign_room"); len(records) < 2 || records[0].TargetID == "guest-martins" || records[1].TargetID != "guest-martins" ||
+		records[0].RequestID != records[1].RequestID {
+		t.Fatalf("Expected the moves of Martins and Pereira to be audited, got %+v\n", records)
+	}
+	walkInMove := latestOperation(t)
+	if walkInMove.GuestName != "Pereira" || walkInMove.Undoable {
+		t.Errorf("Expected the move of Pereira not to be undoable on its own, got %+v\n", walkInMove)
+	}
+	if responseRecorder := undo(t, walkInMove.ID); responseRecorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected the move of Pereira not to be undone, got %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	if responseRecorder := undo(t, auditRecords(t, "operation=assign_room&target_id=guest-martins")[0].Sequence); responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	martins, _ := store.GuestByID("guest-martins")
+	pereira, _ := store.GuestByID(walkInMove.GuestID)
+	if martins.RoomID != "" || pereira.RoomID != "" {
+		t.Errorf("Expected the table of Martins out of the terrace with Pereira, got %+v and %+v\n", martins, pereira)
+	}
+	if records := auditRecords(t, "operation=undo_assign_room"); len(records) < 2 || records[0].TargetID != pereira.ID || records[1].TargetID != "guest-martins" {
+		t.Errorf("Expected the moves back of Martins and Pereira to be audited, got %+v\n", records)
+	}
+
+	// Entering a room is undone by going back to no room
+	sendRequest(t, http.MethodPut, "/rooms/Terrace/guests/Martins", nil)
+	if responseRecorder := undo(t, latestOperation(t).ID); responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	if martins, _ = store.GuestByID("guest-martins"); martins.InRoom != "" {
+		t.Errorf("Expected Martins out of the terrace, got %+v\n", martins)
+	}
+
+	// Leaving a room is undone by going back in, if the room has space for the guest
+	sendRequest(t, http.MethodPut, "/rooms/Terrace/guests/Martins", nil)
+	sendRequest(t, http.MethodDelete, "/rooms/Terrace/guests/Martins", nil)
+	leaving := latestOperation(t)
+	sendRequest(t, http.MethodPut, "/rooms/Terrace/guests/Francisco", nil)
+	undoRefusal := undo(t, leaving.ID)
+	if undoRefusal.Code != http.StatusUnprocessableEntity || undoRefusal.Header().Get(api.ErrorCodeHeader) != api.ErrorCodeRoomFull ||
+		undoRefusal.Header().Get(api.AdmissionRuleHeader) != guestService.AdmissionRuleVenueCapacity {
+		t.Errorf("Expected Martins not to fit in the terrace again, got %d %s\n", undoRefusal.Code, undoRefusal.Body.String())
+	}
+	sendRequest(t, http.MethodDelete, "/rooms/Terrace/guests/Francisco", nil)
+	if responseRecorder := undo(t, leaving.ID); responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	if martins, _ = store.GuestByID("guest-martins"); martins.InRoom != terrace.ID {
+		t.Errorf("Expected Martins back in the terrace, got %+v\n", martins)
+	}
+}
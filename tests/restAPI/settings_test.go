@@ -0,0 +1,80 @@
+package restapitest
+
+import (
+	"encoding/json"
+	"guestListChallenge/src/guestService"
+	"log"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestSettingsPersisted Checks that the settings changed while the server runs are saved, put back in effect by a new service
+// on the same database, and that each change is recorded in the audit log
+func TestSettingsPersisted(t *testing.T) {
+	resetDatabase()
+
+	// Other tests expect the default settings
+	defer sendRequest(t, http.MethodPut, "/admission/rules", map[string]interface{}{"rules": []map[string]interface{}{
+		{"rule": "table_capacity"}, {"rule": "entourage_limit"}, {"rule": "venue_capacity"},
+	}})
+	defer sendRequest(t, http.MethodPut, "/categories/vip", map[string]interface{}{"reserved_table": true, "bypass_capacity": false, "max_accompanying_guests": nil})
+	defer sendRequest(t, http.MethodPut, "/no_shows/cutoff", map[string]interface{}{"cutoff": nil})
+	defer sendRequest(t, http.MethodPut, "/walk_ins/cap", map[string]interface{}{"cap": nil})
+	defer sendRequest(t, http.MethodPut, "/occupancy/capacity", map[string]interface{}{"capacity": nil})
+
+	sendRequest(t, http.MethodPut, "/occupancy/capacity", map[string]interface{}{"capacity": 120})
+	sendRequest(t, http.MethodPut, "/walk_ins/cap", map[string]interface{}{"cap": 20})
+	sendRequest(t, http.MethodPut, "/no_shows/cutoff", map[string]interface{}{"cutoff": "2021-12-17T22:00:00Z"})
+	sendRequest(t, http.MethodPut, "/categories/vip", map[string]interface{}{"reserved_table": false, "bypass_capacity": true, "max_accompanying_guests": 3})
+	sendRequest(t, http.MethodPut, "/admission/rules", map[string]interface{}{"rules": []map[string]interface{}{
+		{"rule": "ban_list", "names": []string{"Mallory"}}, {"rule": "table_capacity"},
+	}})
+
+	restarted := guestService.NewService(store, clock, log.New(os.Stdout, "", log.LstdFlags))
+	if loadError := restarted.LoadSettings(); loadError != nil {
+		t.Fatalf("Couldn't load settings: %v\n", loadError)
+	}
+	settings := restarted.Settings()
+	if settings.VenueCapacity != 120 || settings.WalkInCap != 20 {
+		t.Errorf("Expected a venue capacity of 120 and a walk-in cap of 20, got %+v\n", settings)
+	}
+	if expectedCutoff := time.Date(2021, time.December, 17, 22, 0, 0, 0, time.UTC); settings.NoShowCutoff == nil || !settings.NoShowCutoff.Equal(expectedCutoff) {
+		t.Errorf("Expected a no-show cutoff at %v, got %v\n", expectedCutoff, settings.NoShowCutoff)
+	}
+	if expectedRules := (guestService.CategoryRules{BypassCapacity: true, MaxAccompanyingGuests: 3, WaitlistPriority: 2}); settings.CategoryRules.Rules(guestService.CategoryVIP) != expectedRules {
+		t.Errorf("Expected VIP rules %+v, got %+v\n", expectedRules, settings.CategoryRules.Rules(guestService.CategoryVIP))
+	}
+	if expectedRules := guestService.DefaultCategoryConfig().Rules(guestService.CategoryStaff); settings.CategoryRules.Rules(guestService.CategoryStaff) != expectedRules {
+		t.Errorf("Expected the default staff rules %+v, got %+v\n", expectedRules, settings.CategoryRules.Rules(guestService.CategoryStaff))
+	}
+	if len(settings.AdmissionRules) != 2 || settings.AdmissionRules[0].Rule != guestService.AdmissionRuleBanList ||
+		!reflect.DeepEqual(settings.AdmissionRules[0].Names, []string{"Mallory"}) || settings.AdmissionRules[1].Rule != guestService.AdmissionRuleTableCapacity {
+		t.Errorf("Expected the ban list and table capacity rules, got %+v\n", settings.AdmissionRules)
+	}
+
+	stored, _ := store.Settings()
+	for _, setting := range stored {
+		if !setting.ChangedAt.Equal(clock.Now()) {
+			t.Errorf("Expected %s changed at %v, got %v\n", setting.Name, clock.Now(), setting.ChangedAt)
+		}
+	}
+
+	records := auditRecords(t, "operation=change_setting&target_id="+guestService.SettingWalkInCap+"&limit=1")
+	if len(records) != 1 {
+		t.Fatalf("Expected the walk-in cap change to be audited, got %+v\n", records)
+	}
+	var before, after int
+	json.Unmarshal(records[0].Before, &before)
+	json.Unmarshal(records[0].After, &after)
+	if before != guestService.NoWalkInCap || after != 20 {
+		t.Errorf("Expected the walk-in cap to go from %d to 20, got %d to %d\n", guestService.NoWalkInCap, before, after)
+	}
+	for _, setting := range []string{guestService.SettingVenueCapacity, guestService.SettingNoShowCutoff, guestService.SettingCategoryRules, guestService.SettingAdmissionRules} {
+		if records := auditRecords(t, "operation=change_setting&target_id="+setting); len(records) == 0 {
+			t.Errorf("Expected the change of %s to be audited\n", setting)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package restapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/audit"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/guestService"
+	"guestListChallenge/src/snapshot"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// downloadSnapshot Returns a snapshot of the test server's party
+func downloadSnapshot(t *testing.T) []byte {
+	request := httptest.NewRequest(http.MethodGet, "/admin/snapshot", nil)
+	request.Header.Set("Authorization", "Bearer "+adminKey)
+	responseRecorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(responseRecorder, request)
+	if responseRecorder.Code != http.StatusOK || responseRecorder.Header().Get("Content-Type") != "application/gzip" {
+		t.Fatalf("Couldn't download snapshot: %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	return responseRecorder.Body.Bytes()
+}
+
+// restoreSnapshot Sends the request to restore a snapshot with the given content type
+func restoreSnapshot(t *testing.T, requestPath string, contentType string, archiveData []byte) *httptest.ResponseRecorder {
+	request, err := http.NewRequest(http.MethodPost, requestPath, bytes.NewReader(archiveData))
+	if err != nil {
+		t.Fatalf("Couldn't create request: %v\n", err)
+	}
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("Authorization", "Bearer "+adminKey)
+
+	responseRecorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(responseRecorder, request)
+	return responseRecorder
+}
+
+// TestSnapshotRestore Checks that a snapshot brings the party back to the state it was in, settings included,
+// and that dry runs change nothing
+func TestSnapshotRestore(t *testing.T) {
+	resetDatabase()
+
+	// Other tests expect no walk-in cap
+	defer sendRequest(t, http.MethodPut, "/walk_ins/cap", map[string]interface{}{"cap": nil})
+	sendRequest(t, http.MethodPut, "/walk_ins/cap", map[string]interface{}{"cap": 20})
+	if err := store.MarkTicketUsed("ticket-francisco", "guest-francisco", clock.Now()); err != nil {
+		t.Fatalf("Couldn't mark ticket used: %v\n", err)
+	}
+	registerWebhook(t, "https://example.com/hook", "guest_arrived")
+	before, _ := store.State()
+	archiveData := downloadSnapshot(t)
+
+	// The party goes on
+	var silva api.GuestResponse
+	json.Unmarshal(sendRequest(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 3, "accompanying_guests": 1}).Body.Bytes(), &silva)
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 2})
+	sendRequest(t, http.MethodDelete, "/guests/Francisco", nil)
+	sendRequest(t, http.MethodPut, "/walk_ins/cap", map[string]interface{}{"cap": 5})
+	after, _ := store.State()
+
+	// A dry run reports the changes without making them
+	responseRecorder := restoreSnapshot(t, "/admin/restore?dry_run=true", "application/gzip", archiveData)
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	var dryRun api.RestoreSnapshotResponse
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &dryRun); err != nil {
+		t.Fatalf("Couldn't decode response: %v\n", err)
+	}
+	expectedGuestChanges := api.RecordChanges{Added: []string{"guest-francisco"}, Removed: []string{silva.ID}, Changed: []string{"guest-martins"}}
+	expectedSettingChanges := api.RecordChanges{Added: []string{}, Removed: []string{}, Changed: []string{guestService.SettingWalkInCap}}
+	if !dryRun.DryRun || !reflect.DeepEqual(dryRun.Changes.Guests, expectedGuestChanges) || dryRun.Snapshot.Guests != 2 || dryRun.Snapshot.UsedTickets != 1 || dryRun.Snapshot.Webhooks != 1 ||
+		!reflect.DeepEqual(dryRun.Changes.Settings, expectedSettingChanges) || dryRun.Snapshot.Settings != 5 {
+		t.Errorf("Unexpected dry run %+v\n", dryRun)
+	}
+	if state, _ := store.State(); !reflect.DeepEqual(state, after) {
+		t.Errorf("Dry run changed the state to %+v\n", state)
+	}
+
+	// Restoring brings the party back, the state it replaced is saved first
+	responseRecorder = restoreSnapshot(t, "/admin/restore", "application/gzip", archiveData)
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	// Every setting in effect is stored once restored
+	state, _ := store.State()
+	if len(state.Settings) != 5 {
+		t.Errorf("Expected the 5 settings stored after restoring, got %+v\n", state.Settings)
+	}
+	state.Settings, before.Settings = nil, nil
+	if !reflect.DeepEqual(state, before) {
+		t.Errorf("Expected state %+v after restoring, got %+v\n", before, state)
+	}
+	var walkIns api.WalkInsResponse
+	decodeReply(t, http.MethodGet, "/walk_ins", nil, &walkIns)
+	if walkIns.Cap == nil || *walkIns.Cap != 20 {
+		t.Errorf("Expected the walk-in cap of 20 back, got %v\n", walkIns.Cap)
+	}
+	records := auditRecords(t, "operation="+audit.OperationRestoreSnapshot+"&limit=1")
+	if len(records) != 1 || records[0].TargetID != dryRun.Snapshot.Checksum {
+		t.Errorf("Expected the restoration in the audit log, got %+v\n", records)
+	}
+
+	// Restoring again changes nothing
+	responseRecorder = restoreSnapshot(t, "/admin/restore?dry_run=true", "application/gzip", archiveData)
+	var again api.RestoreSnapshotResponse
+	json.Unmarshal(responseRecorder.Body.Bytes(), &again)
+	noChanges := api.RecordChanges{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	if !reflect.DeepEqual(again.Changes, api.SnapshotChanges{Guests: noChanges, UsedTickets: noChanges, Visits: noChanges, Webhooks: noChanges, Rooms: noChanges, RoomAccess: noChanges, Settings: noChanges, Waitlist: noChanges}) {
+		t.Errorf("Expected no changes, got %+v\n", again.Changes)
+	}
+}
+
+// TestInvalidSnapshots Checks that damaged snapshots and other bodies are refused
+func TestInvalidSnapshots(t *testing.T) {
+	resetDatabase()
+	archiveData := downloadSnapshot(t)
+
+	archive, _ := snapshot.Decode(archiveData)
+	archive.State = json.RawMessage(bytes.Replace(archive.State, []byte("Martins"), []byte("Mallory"), 1))
+	editedData, _ := archive.Encode()
+
+	var testCases = []struct {
+		testCaseName      string
+		contentType       string
+		archiveData       []byte
+		expectedStatus    int
+		expectedErrorCode string
+	}{
+		{"Edited snapshot", "application/gzip", editedData, http.StatusUnprocessableEntity, api.ErrorCodeInvalidSnapshot},
+		{"Truncated snapshot", "application/gzip", archiveData[:len(archiveData)/2], http.StatusUnprocessableEntity, api.ErrorCodeInvalidSnapshot},
+		{"JSON body", "application/json", []byte(`{}`), http.StatusUnsupportedMediaType, api.ErrorCodeUnsupportedMediaType},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			responseRecorder := restoreSnapshot(t, "/admin/restore", testCase.contentType, testCase.archiveData)
+			if responseRecorder.Code != testCase.expectedStatus || responseRecorder.Header().Get(api.ErrorCodeHeader) != testCase.expectedErrorCode {
+				t.Errorf("Expected %d %s, got %d %s\n", testCase.expectedStatus, testCase.expectedErrorCode, responseRecorder.Code, responseRecorder.Body.String())
+			}
+		})
+	}
+
+	if martins, err := store.GuestByID("guest-martins"); err != nil || martins.Name != "Martins" {
+		t.Errorf("Expected Martins untouched, got %+v %v\n", martins, err)
+	}
+}
+
+// TestSnapshotOfOtherEvent Checks that a snapshot taken at another event is only restored when explicitly allowed
+func TestSnapshotOfOtherEvent(t *testing.T) {
+	resetDatabase()
+	archive, _ := snapshot.Decode(downloadSnapshot(t))
+	archive.EventID = "summer-picnic"
+	archiveData, _ := archive.Encode()
+
+	sendRequest(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 3, "accompanying_guests": 1})
+	responseRecorder := restoreSnapshot(t, "/admin/restore", "application/gzip", archiveData)
+	if responseRecorder.Code != http.StatusConflict || responseRecorder.Header().Get(api.ErrorCodeHeader) != api.ErrorCodeSnapshotOtherEvent {
+		t.Errorf("Expected the snapshot of another event to be refused, got %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	if guests, err := store.GuestsByName("Silva"); err != nil || len(guests) != 1 {
+		t.Errorf("Expected Silva still on the guest list, got %+v %v\n", guests, err)
+	}
+
+	responseRecorder = restoreSnapshot(t, "/admin/restore?allow_other_event=true", "application/gzip", archiveData)
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Wrong http status received %d: %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	var restored api.RestoreSnapshotResponse
+	json.Unmarshal(responseRecorder.Body.Bytes(), &restored)
+	if restored.Snapshot.EventID != "summer-picnic" || len(restored.Changes.Guests.Removed) != 1 {
+		t.Errorf("Expected Silva removed by the snapshot of summer-picnic, got %+v\n", restored)
+	}
+}
+
+// TestSnapshotsNeedAdminKey Checks that snapshots are only downloaded and restored with an admin key
+func TestSnapshotsNeedAdminKey(t *testing.T) {
+	resetDatabase()
+	archiveData := downloadSnapshot(t)
+
+	var testCases = []struct {
+		testCaseName  string
+		request       *http.Request
+		authorization string
+	}{
+		{"Download without key", httptest.NewRequest(http.MethodGet, "/admin/snapshot", nil), ""},
+		{"Download with another key", httptest.NewRequest(http.MethodGet, "/admin/snapshot", nil), "Bearer k3y-one"},
+		{"Restore without key", httptest.NewRequest(http.MethodPost, "/admin/restore", bytes.NewReader(archiveData)), ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			testCase.request.Header.Set("Content-Type", "application/gzip")
+			if testCase.authorization != "" {
+				testCase.request.Header.Set("Authorization", testCase.authorization)
+			}
+			responseRecorder := httptest.NewRecorder()
+			server.Handler().ServeHTTP(responseRecorder, testCase.request)
+			if responseRecorder.Code != http.StatusForbidden || responseRecorder.Header().Get(api.ErrorCodeHeader) != api.ErrorCodeForbidden {
+				t.Errorf("Expected 403 forbidden, got %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+			}
+		})
+	}
+}
+
+// TestSnapshotWebhookSecrets Checks that snapshots do not give away the secrets of the webhooks, which registered webhooks
+// keep when restored while the others are left out
+func TestSnapshotWebhookSecrets(t *testing.T) {
+	resetDatabase()
+	kept := registerWebhook(t, "https://example.com/kept", "guest_arrived")
+	removed := registerWebhook(t, "https://example.com/removed", "guest_left")
+	archiveData := downloadSnapshot(t)
+
+	archive, _ := snapshot.Decode(archiveData)
+	if bytes.Contains(archive.State, []byte(webhookSecret)) {
+		t.Fatalf("Snapshot gives away the secret of the webhooks: %s\n", archive.State)
+	}
+
+	sendRequest(t, http.MethodDelete, "/webhooks/"+removed, nil)
+	responseRecorder := restoreSnapshot(t, "/admin/restore", "application/gzip", archiveData)
+	var restored api.RestoreSnapshotResponse
+	json.Unmarshal(responseRecorder.Body.Bytes(), &restored)
+	if responseRecorder.Code != http.StatusOK || !reflect.DeepEqual(restored.WebhooksWithoutSecret, []string{removed}) || len(restored.Changes.Webhooks.Added) != 0 {
+		t.Fatalf("Expected the removed webhook to be left out, got %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+
+	webhookList, _ := store.Webhooks()
+	if len(webhookList) != 1 || webhookList[0].ID != kept || webhookList[0].Secret != webhookSecret {
+		t.Errorf("Expected the kept webhook restored with its secret, got %+v\n", webhookList)
+	}
+}
+
+// TestSavedSnapshots Checks that snapshots are saved when the state changed, and that only the latest ones are kept
+func TestSavedSnapshots(t *testing.T) {
+	resetDatabase()
+
+	// Other tests expect the clock at its initial time
+	initialTime := clock.Now()
+	defer clock.Set(initialTime)
+	directory := t.TempDir()
+	archiver := snapshot.NewArchiver(store, clock, log.New(ioutil.Discard, "", 0), audit.NewLog(store, clock), nil,
+		"end-of-year-party", snapshot.Config{Directory: directory, Interval: time.Minute, Retention: 2})
+
+	save := func() string {
+		clock.Advance(time.Minute)
+		filePath, err := archiver.Save()
+		if err != nil {
+			t.Fatalf("Couldn't save snapshot: %v\n", err)
+		}
+		return filePath
+	}
+
+	first := save()
+	if first == "" {
+		t.Fatal("No snapshot saved\n")
+	}
+	if unchanged := save(); unchanged != "" {
+		t.Errorf("Saved %s although the state did not change\n", unchanged)
+	}
+	store.AddGuest(&database.GuestList{Name: "Silva", Table: 3, AccompanyingGuests: 1})
+	second := save()
+	store.AddGuest(&database.GuestList{Name: "Costa", Table: 2, AccompanyingGuests: 0})
+	third := save()
+
+	entries, _ := os.ReadDir(directory)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 snapshots kept, got %d\n", len(entries))
+	}
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Errorf("Expected the oldest snapshot to be removed\n")
+	}
+	for _, filePath := range []string{second, third} {
+		archiveData, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("Couldn't read snapshot: %v\n", err)
+		}
+		if _, err := snapshot.Decode(archiveData); err != nil {
+			t.Errorf("Saved snapshot %s is invalid: %v\n", filePath, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package restapitest
+
+import (
+	"guestListChallenge/src/api"
+	"net/http"
+	"testing"
+)
+
+// TestVenueCapacity Checks that people are let in up to the venue capacity, whatever the seats at the tables,
+// and that the occupancy events are emitted as the thresholds are reached
+func TestVenueCapacity(t *testing.T) {
+	resetDatabase()
+	receiver := newWebhookReceiver(t)
+	registerWebhook(t, receiver.server.URL, api.EventOccupancyWarning, api.EventOccupancyCritical)
+
+	// Other tests expect no venue capacity
+	defer sendRequest(t, http.MethodPut, "/occupancy/capacity", map[string]interface{}{"capacity": nil})
+
+	// Francisco is in with 5 accompanying guests
+	var headcount api.HeadcountResponse
+	decodeReply(t, http.MethodPut, "/occupancy/capacity", map[string]interface{}{"capacity": 10}, &headcount)
+	if headcount.Headcount != 6 || headcount.Capacity == nil || *headcount.Capacity != 10 || headcount.Remaining == nil || *headcount.Remaining != 4 ||
+		headcount.Level != "normal" {
+		t.Errorf("Expected 6 people of 10 at the normal level, got %+v\n", headcount)
+	}
+
+	sendRequest(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 6, "accompanying_guests": 1})
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 2})
+	expectRefusal(t, http.MethodPut, "/guests/Silva", map[string]interface{}{"accompanying_guests": 1}, "The venue holds 9 people of its capacity of 10")
+	sendRequest(t, http.MethodPut, "/guests/Silva", map[string]interface{}{"accompanying_guests": 0})
+
+	decodeReply(t, http.MethodGet, "/occupancy", nil, &headcount)
+	if headcount.Headcount != 10 || *headcount.Remaining != 0 || headcount.Level != "critical" {
+		t.Errorf("Expected a full venue at the critical level, got %+v\n", headcount)
+	}
+
+	// Walk-ins are counted against the capacity too, going from the normal level to the critical one emits both events
+	sendRequest(t, http.MethodDelete, "/guests/Martins", nil)
+	expectRefusal(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Costa", "accompanying_guests": 3}, "The venue holds 7 people of its capacity of 10")
+	sendRequest(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Costa", "accompanying_guests": 2})
+
+	server.Webhooks().DeliverDue()
+	expectedEvents := []struct {
+		event     string
+		guestName string
+		headcount int
+	}{
+		{api.EventOccupancyWarning, "Martins", 9},
+		{api.EventOccupancyCritical, "Silva", 10},
+		{api.EventOccupancyWarning, "Costa", 10},
+		{api.EventOccupancyCritical, "Costa", 10},
+	}
+	deliveries := receiver.received()
+	if len(deliveries) != len(expectedEvents) {
+		t.Fatalf("Expected %d deliveries, got %v\n", len(expectedEvents), deliveries)
+	}
+	for index, expectedEvent := range expectedEvents {
+		body := deliveries[index].body
+		if body.Event != expectedEvent.event || body.Guest.Name != expectedEvent.guestName || body.Occupancy == nil ||
+			body.Occupancy.Headcount != expectedEvent.headcount || body.Occupancy.Capacity != 10 {
+			t.Errorf("Expected %s of %s at %d people, got %+v\n", expectedEvent.event, expectedEvent.guestName, expectedEvent.headcount, body)
+		}
+	}
+
+	decodeReply(t, http.MethodPut, "/occupancy/capacity", map[string]interface{}{"capacity": nil}, &headcount)
+	if headcount.Capacity != nil || headcount.Remaining != nil || headcount.Level != "normal" {
+		t.Errorf("Expected no venue capacity, got %+v\n", headcount)
+	}
+}
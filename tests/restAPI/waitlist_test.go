@@ -0,0 +1,78 @@
+package restapitest
+
+import (
+	"guestListChallenge/src/api"
+	"net/http"
+	"testing"
+)
+
+// TestWaitlist Checks that the parties on the waitlist are seated by the priority of their category when a no-show releases
+// seats, the parties that do not fit keeping their place
+func TestWaitlist(t *testing.T) {
+	resetDatabase()
+
+	var costa, pereira, sousa api.WaitlistEntryResponse
+	decodeReply(t, http.MethodPost, "/waitlist", map[string]interface{}{"name": "Costa", "accompanying_guests": 1}, &costa)
+	decodeReply(t, http.MethodPost, "/waitlist", map[string]interface{}{"name": "Pereira", "accompanying_guests": 2, "category": "vip"}, &pereira)
+	decodeReply(t, http.MethodPost, "/waitlist", map[string]interface{}{"name": "Sousa", "accompanying_guests": 0}, &sousa)
+	if costa.Category != "guest" || costa.Priority != 0 || pereira.Priority != 2 || !costa.JoinedAt.Equal(clock.Now()) {
+		t.Errorf("Unexpected parties %+v and %+v\n", costa, pereira)
+	}
+	if responseRecorder := sendRequest(t, http.MethodPost, "/waitlist", map[string]interface{}{"name": "Santos", "accompanying_guests": 0, "category": "band"}); responseRecorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for an unknown category, got %d\n", responseRecorder.Code)
+	}
+
+	// The VIPs come first, then first come first
+	var waitlist api.WaitlistResponse
+	decodeReply(t, http.MethodGet, "/waitlist", nil, &waitlist)
+	if len(waitlist.Waitlist) != 3 || waitlist.Waitlist[0].ID != pereira.ID || waitlist.Waitlist[1].ID != costa.ID || waitlist.Waitlist[2].ID != sousa.ID {
+		t.Fatalf("Expected Pereira, Costa and Sousa in that order, got %+v\n", waitlist.Waitlist)
+	}
+
+	// Nothing is free yet
+	var seated api.SeatWaitlistResponse
+	decodeReply(t, http.MethodPost, "/waitlist/seat", nil, &seated)
+	if len(seated.Seated) != 0 || len(seated.Waitlist) != 3 {
+		t.Errorf("Expected nobody seated, got %+v\n", seated)
+	}
+
+	// Martins' 4 seats take Pereira's party of 3, Costa's party of 2 no longer fits but Sousa does
+	sendRequest(t, http.MethodPut, "/guest_list/Martins/no_show", map[string]interface{}{"no_show": true})
+	decodeReply(t, http.MethodGet, "/waitlist", nil, &waitlist)
+	if len(waitlist.Waitlist) != 1 || waitlist.Waitlist[0].ID != costa.ID {
+		t.Errorf("Expected Costa to be left waiting, got %+v\n", waitlist.Waitlist)
+	}
+
+	var walkIns api.WalkInsResponse
+	decodeReply(t, http.MethodGet, "/walk_ins", nil, &walkIns)
+	if len(walkIns.WalkIns) != 2 || walkIns.WalkIns[0].SeatedWith != "guest-martins" || walkIns.WalkIns[1].SeatedWith != "guest-martins" {
+		t.Errorf("Expected Pereira and Sousa seated at Martins' table, got %+v\n", walkIns.WalkIns)
+	}
+	var categories api.CategoriesResponse
+	decodeReply(t, http.MethodGet, "/categories", nil, &categories)
+	for _, category := range categories.Categories {
+		if category.Category == "vip" && category.PeopleAtParty != 3 {
+			t.Errorf("Expected Pereira's party seated as VIPs, got %+v\n", category)
+		}
+	}
+
+	if records := auditRecords(t, "operation=seat_from_waitlist"); len(records) != 2 {
+		t.Errorf("Expected 2 parties seated from the waitlist to be audited, got %+v\n", records)
+	}
+	if records := auditRecords(t, "operation=join_waitlist"); len(records) != 3 {
+		t.Errorf("Expected 3 parties joining the waitlist to be audited, got %+v\n", records)
+	}
+
+	// Costa gives up waiting
+	var left api.WaitlistEntryResponse
+	decodeReply(t, http.MethodDelete, "/waitlist/"+costa.ID, nil, &left)
+	if left.Name != "Costa" {
+		t.Errorf("Expected Costa to leave the waitlist, got %+v\n", left)
+	}
+	if responseRecorder := sendRequest(t, http.MethodDelete, "/waitlist/"+costa.ID, nil); responseRecorder.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a party no longer waiting, got %d\n", responseRecorder.Code)
+	}
+	if records := auditRecords(t, "operation=leave_waitlist&target_id="+costa.ID); len(records) != 1 {
+		t.Errorf("Expected Costa leaving the waitlist to be audited, got %+v\n", records)
+	}
+}
@@ -0,0 +1,86 @@
+package restapitest
+
+import (
+	"guestListChallenge/src/api"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// expectRefusal Sends a request and checks that it is refused with a message containing the given text
+func expectRefusal(t *testing.T, requestType string, requestPath string, requestContent interface{}, expectedMessage string) {
+	responseRecorder := sendRequest(t, requestType, requestPath, requestContent)
+	if responseRecorder.Code != http.StatusOK || !strings.Contains(responseRecorder.Body.String(), expectedMessage) {
+		t.Errorf("Expected %s %s to be refused with %q, got %d: %s\n", requestType, requestPath, expectedMessage, responseRecorder.Code, responseRecorder.Body.String())
+	}
+}
+
+// TestWalkIns Checks that walk-ins are seated at tables with empty seats that can be given away, up to the walk-in cap
+func TestWalkIns(t *testing.T) {
+	resetDatabase()
+
+	// Francisco's table is full and Martins' one reserved until they arrive
+	expectRefusal(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Costa", "accompanying_guests": 0}, "No table has 1 empty seats")
+
+	sendRequest(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 6, "accompanying_guests": 1})
+	sendRequest(t, http.MethodPut, "/guests/Silva", map[string]interface{}{"accompanying_guests": 1})
+	sendRequest(t, http.MethodPut, "/guest_list/Martins/no_show", map[string]interface{}{"no_show": true})
+
+	// Martins' 4 seats fit a party of 3 better than Silva's 5
+	var walkIn api.WalkInResponse
+	decodeReply(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Costa", "accompanying_guests": 2}, &walkIn)
+	if walkIn.Name != "Costa" || walkIn.SeatedWith != "guest-martins" {
+		t.Errorf("Expected Costa seated at Martins' table, got %+v\n", walkIn)
+	}
+	decodeReply(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Pereira", "accompanying_guests": 0, "seated_with": "guest-1"}, &walkIn)
+	if walkIn.Name != "Pereira" || walkIn.SeatedWith != "guest-1" {
+		t.Errorf("Expected Pereira seated at Silva's table, got %+v\n", walkIn)
+	}
+	expectRefusal(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Santos", "accompanying_guests": 1, "seated_with": "guest-martins"},
+		"The table of Martins only has 1 empty seats")
+	expectRefusal(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Santos", "accompanying_guests": 0, "seated_with": "guest-francisco"},
+		"The table of Francisco only has 0 empty seats")
+
+	// 4 people were admitted, a party of 2 would exceed a cap of 5
+	sendRequest(t, http.MethodPut, "/walk_ins/cap", map[string]interface{}{"cap": 5})
+	expectRefusal(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Santos", "accompanying_guests": 1}, "Walk-ins are capped at 5 people")
+
+	var walkIns api.WalkInsResponse
+	decodeReply(t, http.MethodGet, "/walk_ins", nil, &walkIns)
+	expectedTables := []api.FreeTable{{GuestID: "guest-martins", Name: "Martins", EmptySeats: 1}, {GuestID: "guest-1", Name: "Silva", EmptySeats: 4}}
+	if walkIns.Cap == nil || *walkIns.Cap != 5 || walkIns.Admitted != 4 || len(walkIns.WalkIns) != 2 || !reflect.DeepEqual(walkIns.FreeTables, expectedTables) {
+		t.Errorf("Unexpected walk-ins %+v\n", walkIns)
+	}
+
+	var arrivedGuests api.ArrivedGuestsResponse
+	decodeReply(t, http.MethodGet, "/guests", nil, &arrivedGuests)
+	for _, guest := range arrivedGuests.Guests {
+		if expectedWalkIn := guest.Name == "Costa" || guest.Name == "Pereira"; guest.WalkIn != expectedWalkIn {
+			t.Errorf("Expected %s to be flagged as a walk-in: %v\n", guest.Name, expectedWalkIn)
+		}
+	}
+
+	// Martins arrives late, their table was partly given away
+	expectRefusal(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 2}, "was given to walk-ins, only 1 seats are left")
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 1})
+
+	var seats api.EmptySeatsResponse
+	decodeReply(t, http.MethodGet, "/seats_empty", nil, &seats)
+	if seats.SeatsEmpty != 4 || seats.SeatsAvailable != 4 {
+		t.Errorf("Expected 4 empty seats all available, got %+v\n", seats)
+	}
+
+	// Walk-ins who left still count towards the cap
+	sendRequest(t, http.MethodDelete, "/guests/Costa", nil)
+	decodeReply(t, http.MethodGet, "/walk_ins", nil, &walkIns)
+	if walkIns.Admitted != 4 || len(walkIns.WalkIns) != 1 {
+		t.Errorf("Expected 4 people admitted and 1 walk-in left, got %+v\n", walkIns)
+	}
+
+	var capReply api.WalkInCapResponse
+	decodeReply(t, http.MethodPut, "/walk_ins/cap", map[string]interface{}{"cap": nil}, &capReply)
+	if capReply.Cap != nil {
+		t.Errorf("Expected no walk-in cap, got %d\n", *capReply.Cap)
+	}
+}
@@ -0,0 +1,245 @@
+package restapitest
+
+import (
+	"encoding/json"
+	"guestListChallenge/src/api"
+	"guestListChallenge/src/webhooks"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// webhookSecret Secret of the webhooks registered by the tests
+const webhookSecret = "secret shared with the receiver"
+
+// receivedDelivery Delivery received by a test receiver
+type receivedDelivery struct {
+	event      string
+	deliveryID string
+	validSig   bool
+	body       api.WebhookEvent
+}
+
+// webhookReceiver Local webhook receiver answering with the statuses it is given, then with 200
+type webhookReceiver struct {
+	mutex      sync.Mutex
+	statuses   []int
+	deliveries []receivedDelivery
+	server     *httptest.Server
+}
+
+// newWebhookReceiver Starts a webhook receiver failing its first deliveries with the given statuses
+func newWebhookReceiver(t *testing.T, failingStatuses ...int) *webhookReceiver {
+	receiver := &webhookReceiver{statuses: failingStatuses}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		payload, _ := ioutil.ReadAll(request.Body)
+		delivery := receivedDelivery{
+			event:      request.Header.Get(api.WebhookEventHeader),
+			deliveryID: request.Header.Get(api.WebhookDeliveryHeader),
+			validSig:   webhooks.Verify(webhookSecret, payload, request.Header.Get(api.WebhookSignatureHeader)),
+		}
+		json.Unmarshal(payload, &delivery.body)
+
+		receiver.mutex.Lock()
+		defer receiver.mutex.Unlock()
+		receiver.deliveries = append(receiver.deliveries, delivery)
+		if len(receiver.statuses) > 0 {
+			response.WriteHeader(receiver.statuses[0])
+			receiver.statuses = receiver.statuses[1:]
+		}
+	}))
+	t.Cleanup(receiver.server.Close)
+	return receiver
+}
+
+// received Returns the deliveries received so far
+func (receiver *webhookReceiver) received() []receivedDelivery {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	return append([]receivedDelivery(nil), receiver.deliveries...)
+}
+
+// registerWebhook Registers a webhook for the given events and returns its ID
+func registerWebhook(t *testing.T, url string, events ...string) string {
+	responseRecorder := sendRequest(t, http.MethodPost, "/webhooks", map[string]interface{}{"url": url, "events": events, "secret": webhookSecret})
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Couldn't register webhook: %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	var webhook api.WebhookResponse
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &webhook); err != nil {
+		t.Fatalf("Couldn't decode webhook: %v\n", err)
+	}
+	return webhook.ID
+}
+
+// webhookDeliveries Returns the delivery log of a webhook
+func webhookDeliveries(t *testing.T, webhookID string) []api.WebhookDelivery {
+	responseRecorder := sendRequest(t, http.MethodGet, "/webhooks/"+webhookID+"/deliveries", nil)
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("Couldn't get delivery log: %d %s\n", responseRecorder.Code, responseRecorder.Body.String())
+	}
+	var deliveries api.WebhookDeliveriesResponse
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &deliveries); err != nil {
+		t.Fatalf("Couldn't decode delivery log: %v\n", err)
+	}
+	return deliveries.Deliveries
+}
+
+// TestWebhookEvents Checks that the guest lifecycle events are delivered, signed, to the webhooks registered for them
+func TestWebhookEvents(t *testing.T) {
+	resetDatabase()
+	receiver := newWebhookReceiver(t)
+	registerWebhook(t, receiver.server.URL, api.EventGuestArrived, api.EventTableFull, api.EventGuestLeft)
+	otherReceiver := newWebhookReceiver(t)
+	registerWebhook(t, otherReceiver.server.URL, api.EventGuestAdded)
+
+	sendRequest(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 3, "accompanying_guests": 1})
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 4})
+	sendRequest(t, http.MethodDelete, "/guests/Francisco", nil)
+	server.Webhooks().DeliverDue()
+
+	expectedEvents := []struct {
+		event     string
+		guestName string
+	}{
+		{api.EventGuestArrived, "Martins"},
+		{api.EventTableFull, "Martins"},
+		{api.EventGuestLeft, "Francisco"},
+	}
+	deliveries := receiver.received()
+	if len(deliveries) != len(expectedEvents) {
+		t.Fatalf("Expected %d deliveries, got %v\n", len(expectedEvents), deliveries)
+	}
+	for index, expectedEvent := range expectedEvents {
+		delivery := deliveries[index]
+		if delivery.event != expectedEvent.event || delivery.body.Event != expectedEvent.event || delivery.body.Guest.Name != expectedEvent.guestName {
+			t.Errorf("Expected %s of %s, got %v\n", expectedEvent.event, expectedEvent.guestName, delivery)
+		}
+		if !delivery.validSig {
+			t.Errorf("Delivery %s has an invalid signature\n", delivery.deliveryID)
+		}
+	}
+
+	otherDeliveries := otherReceiver.received()
+	if len(otherDeliveries) != 1 || otherDeliveries[0].body.Guest.Name != "Silva" {
+		t.Errorf("Expected the addition of Silva, got %v\n", otherDeliveries)
+	}
+}
+
+// TestTableFullWithWalkIns Checks that a table is full once its guest and the walk-ins seated at it took every seat
+func TestTableFullWithWalkIns(t *testing.T) {
+	resetDatabase()
+	receiver := newWebhookReceiver(t)
+	registerWebhook(t, receiver.server.URL, api.EventTableFull)
+
+	// Walk-ins take 2 of the 4 seats of Martins, who fills the table when checking in with 2 accompanying guests
+	sendRequest(t, http.MethodPut, "/guest_list/Martins/no_show", map[string]interface{}{"no_show": true})
+	sendRequest(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Pereira", "accompanying_guests": 1, "seated_with": "guest-martins"})
+	server.Webhooks().DeliverDue()
+	if deliveries := receiver.received(); len(deliveries) != 0 {
+		t.Errorf("Expected the table of Martins not to be full yet, got %v\n", deliveries)
+	}
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 2})
+	server.Webhooks().DeliverDue()
+	if deliveries := receiver.received(); len(deliveries) != 1 || deliveries[0].body.Guest.Name != "Martins" {
+		t.Errorf("Expected the table of Martins to be full, got %v\n", deliveries)
+	}
+
+	// Walk-ins taking the last seats fill the table as well
+	sendRequest(t, http.MethodPost, "/guest_list/Silva", map[string]interface{}{"table": 3, "accompanying_guests": 1})
+	sendRequest(t, http.MethodPut, "/guests/Silva", map[string]interface{}{"accompanying_guests": 1})
+	sendRequest(t, http.MethodPost, "/walk_ins", map[string]interface{}{"name": "Sousa", "accompanying_guests": 1})
+	server.Webhooks().DeliverDue()
+	if deliveries := receiver.received(); len(deliveries) != 2 || deliveries[1].body.Guest.Name != "Silva" {
+		t.Errorf("Expected the table of Silva to be full, got %v\n", deliveries)
+	}
+}
+
+// TestWebhookRetries Checks that failed deliveries are retried with backoff, logged, and given up after too many attempts
+func TestWebhookRetries(t *testing.T) {
+	resetDatabase()
+
+	// Other tests expect the clock at its initial time
+	initialTime := clock.Now()
+	defer clock.Set(initialTime)
+	receiver := newWebhookReceiver(t, http.StatusServiceUnavailable, http.StatusInternalServerError)
+	webhookID := registerWebhook(t, receiver.server.URL, api.EventGuestArrived)
+
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 1})
+
+	// First attempt fails and is retried after the initial delay only
+	server.Webhooks().DeliverDue()
+	deliveries := webhookDeliveries(t, webhookID)
+	if len(deliveries) != 1 || deliveries[0].Status != "pending" || deliveries[0].Attempts != 1 || deliveries[0].LastStatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a pending delivery after one failed attempt, got %v\n", deliveries)
+	}
+	if server.Webhooks().DeliverDue() != 0 {
+		t.Error("Delivery retried before its delay")
+	}
+
+	// Second attempt fails, third one succeeds
+	config := webhooks.DefaultConfig()
+	clock.Advance(config.RetryDelay(1))
+	server.Webhooks().DeliverDue()
+	clock.Advance(config.RetryDelay(2))
+	server.Webhooks().DeliverDue()
+
+	deliveries = webhookDeliveries(t, webhookID)
+	if len(deliveries) != 1 || deliveries[0].Status != "delivered" || deliveries[0].Attempts != 3 || deliveries[0].DeliveredAt == nil {
+		t.Errorf("Expected a delivery delivered after three attempts, got %v\n", deliveries)
+	}
+
+	received := receiver.received()
+	if len(received) != 3 || received[0].deliveryID != received[2].deliveryID {
+		t.Errorf("Expected the same delivery three times, got %v\n", received)
+	}
+
+	// Unreachable receivers are given up
+	receiver.server.Close()
+	resetDatabase()
+	unreachableID := registerWebhook(t, receiver.server.URL, api.EventGuestArrived)
+	sendRequest(t, http.MethodPut, "/guests/Martins", map[string]interface{}{"accompanying_guests": 1})
+	for attempt := 1; attempt <= config.MaxAttempts; attempt++ {
+		server.Webhooks().DeliverDue()
+		clock.Advance(config.RetryDelay(attempt))
+	}
+	deliveries = webhookDeliveries(t, unreachableID)
+	if len(deliveries) != 1 || deliveries[0].Status != "failed" || deliveries[0].Attempts != config.MaxAttempts || deliveries[0].LastError == "" {
+		t.Errorf("Expected a failed delivery, got %v\n", deliveries)
+	}
+}
+
+// TestWebhookRegistration Checks the registration, listing and deletion of webhooks
+func TestWebhookRegistration(t *testing.T) {
+	resetDatabase()
+	webhookID := registerWebhook(t, "https://hr.example.com/hooks/party", api.EventGuestArrived, api.EventGuestArrived)
+
+	responseRecorder := sendRequest(t, http.MethodGet, "/webhooks", nil)
+	var webhookList api.WebhookListResponse
+	json.Unmarshal(responseRecorder.Body.Bytes(), &webhookList)
+	if len(webhookList.Webhooks) != 1 || len(webhookList.Webhooks[0].Events) != 1 || !webhookList.Webhooks[0].CreatedAt.Equal(clock.Now()) {
+		t.Errorf("Unexpected webhooks %v\n", webhookList.Webhooks)
+	}
+
+	var invalidRequests = []map[string]interface{}{
+		{"url": "ftp://hr.example.com", "events": []string{api.EventGuestArrived}, "secret": webhookSecret},
+		{"url": "https://", "events": []string{api.EventGuestArrived}, "secret": webhookSecret},
+		{"url": "https://hr.example.com", "events": []string{"guest_danced"}, "secret": webhookSecret},
+		{"url": "https://hr.example.com", "events": []string{api.EventGuestArrived}, "secret": "short"},
+	}
+	for _, invalidRequest := range invalidRequests {
+		if responseRecorder := sendRequest(t, http.MethodPost, "/webhooks", invalidRequest); responseRecorder.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected %v to be rejected, got %d\n", invalidRequest, responseRecorder.Code)
+		}
+	}
+
+	if responseRecorder := sendRequest(t, http.MethodDelete, "/webhooks/"+webhookID, nil); responseRecorder.Code != http.StatusOK {
+		t.Errorf("Couldn't delete webhook: %d\n", responseRecorder.Code)
+	}
+	responseRecorder = sendRequest(t, http.MethodGet, "/webhooks/"+webhookID+"/deliveries", nil)
+	if responseRecorder.Code != http.StatusNotFound || responseRecorder.Header().Get(api.ErrorCodeHeader) != api.ErrorCodeWebhookNotFound {
+		t.Errorf("Expected deleted webhook to be gone, got %d\n", responseRecorder.Code)
+	}
+}
@@ -0,0 +1,141 @@
+package snapshottest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"guestListChallenge/src/database"
+	"guestListChallenge/src/snapshot"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// state State of a party used by the tests
+var state = database.State{
+	Guests: []database.GuestList{
+		{ID: "guest-francisco", Name: "Francisco", Table: 5, AccompanyingGuests: 5, TimeArrived: "13:37"},
+		{ID: "guest-martins", Name: "Martins", Table: 4, AccompanyingGuests: 2},
+	},
+	UsedTickets: []database.UsedTicket{
+		{ID: "ticket-1", GuestID: "guest-francisco", UsedAt: time.Date(2021, time.December, 17, 13, 37, 0, 0, time.UTC)},
+	},
+	Webhooks: []database.Webhook{
+		{ID: "webhook-1", URL: "https://example.com/hook?a=1&b=2", Events: "guest_arrived", Secret: "s3cret", CreatedAt: time.Date(2021, time.December, 17, 12, 0, 0, 0, time.UTC)},
+	},
+	Settings: []database.Setting{
+		{Name: "venue_capacity", Value: "120", ChangedAt: time.Date(2021, time.December, 17, 20, 0, 0, 0, time.UTC)},
+		{Name: "walk_in_cap", Value: "20", ChangedAt: time.Date(2021, time.December, 17, 20, 0, 0, 0, time.UTC)},
+	},
+	Waitlist: []database.WaitlistEntry{
+		{ID: "waitlist-1", Name: "Costa", AccompanyingGuests: 1, Category: "guest", JoinedAt: time.Date(2021, time.December, 17, 20, 30, 0, 0, time.UTC)},
+	},
+}
+
+// gzipped Returns data compressed with gzip
+func gzipped(t *testing.T, data []byte) []byte {
+	var buffer bytes.Buffer
+	compressor := gzip.NewWriter(&buffer)
+	if _, err := compressor.Write(data); err != nil {
+		t.Fatalf("Couldn't compress data: %v\n", err)
+	}
+	compressor.Close()
+	return buffer.Bytes()
+}
+
+// TestArchiveRoundTrip Checks that an encoded archive decodes to the same state, but for the secrets of the webhooks it leaves out
+func TestArchiveRoundTrip(t *testing.T) {
+	archive, err := snapshot.NewArchive(state, 5, "end-of-year-party", time.Date(2021, time.December, 17, 21, 5, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Couldn't create archive: %v\n", err)
+	}
+	if snapshot.FileName(archive) != "snapshot-20211217T210500Z.json.gz" {
+		t.Errorf("Unexpected file name %s\n", snapshot.FileName(archive))
+	}
+
+	archiveData, err := archive.Encode()
+	if err != nil {
+		t.Fatalf("Couldn't encode archive: %v\n", err)
+	}
+	decoded, err := snapshot.Decode(archiveData)
+	if err != nil {
+		t.Fatalf("Couldn't decode archive: %v\n", err)
+	}
+	if decoded.Checksum != archive.Checksum || decoded.SchemaVersion != 5 || decoded.EventID != "end-of-year-party" || decoded.FormatVersion != snapshot.FormatVersion {
+		t.Errorf("Unexpected archive %+v\n", decoded.Summary())
+	}
+
+	if bytes.Contains(decoded.State, []byte("s3cret")) {
+		t.Errorf("Archive gives away the secret of the webhooks: %s\n", decoded.State)
+	}
+
+	contents, err := decoded.Contents()
+	if err != nil {
+		t.Fatalf("Couldn't read archive contents: %v\n", err)
+	}
+	expected := state
+	expected.Webhooks = append([]database.Webhook{}, state.Webhooks...)
+	expected.Webhooks[0].Secret = ""
+	if !reflect.DeepEqual(contents, expected) {
+		t.Errorf("Expected state %+v, got %+v\n", expected, contents)
+	}
+}
+
+// TestInvalidArchives Checks that damaged, edited and newer archives are refused
+func TestInvalidArchives(t *testing.T) {
+	var testCases = []struct {
+		testCaseName string
+		archiveData  []byte
+	}{
+		{"Not gzip", []byte(`{"format_version": 1}`)},
+		{"Not JSON", gzipped(t, []byte("guests"))},
+		{"Newer format", gzipped(t, []byte(`{"format_version": 2, "checksum": "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", "state": {}}`))},
+		{"Missing state", gzipped(t, []byte(`{"format_version": 1, "checksum": ""}`))},
+		{"Edited state", gzipped(t, []byte(`{"format_version": 1, "checksum": "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", "state": {"guests": []}}`))},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			if _, err := snapshot.Decode(testCase.archiveData); !errors.Is(err, snapshot.ErrInvalidArchive) {
+				t.Errorf("Expected an invalid archive, got %v\n", err)
+			}
+		})
+	}
+
+	// The checksum of the empty state used above is the one of its encoding
+	if _, err := snapshot.Decode(gzipped(t, []byte(`{"format_version": 1, "checksum": "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", "state": {}}`))); err != nil {
+		t.Errorf("Expected a valid archive, got %v\n", err)
+	}
+}
+
+// TestCompare Checks that the records added, removed and changed by a restoration are reported
+func TestCompare(t *testing.T) {
+	restored := database.State{
+		Guests: []database.GuestList{
+			{ID: "guest-francisco", Name: "Francisco", Table: 5, AccompanyingGuests: 5},
+			{ID: "guest-silva", Name: "Silva", Table: 2, AccompanyingGuests: 1},
+		},
+		UsedTickets: []database.UsedTicket{
+			{ID: "ticket-1", GuestID: "guest-francisco", UsedAt: state.UsedTickets[0].UsedAt.In(time.FixedZone("CET", 3600))},
+		},
+		Settings: []database.Setting{
+			{Name: "venue_capacity", Value: "120"},
+			{Name: "walk_in_cap", Value: "10", ChangedAt: state.Settings[1].ChangedAt},
+		},
+	}
+
+	diff := snapshot.Compare(state, restored)
+	expectedDiff := snapshot.Diff{
+		Guests:      snapshot.Changes{Added: []string{"guest-silva"}, Removed: []string{"guest-martins"}, Changed: []string{"guest-francisco"}},
+		Visits:      snapshot.Changes{Added: []string{}, Removed: []string{}, Changed: []string{}},
+		UsedTickets: snapshot.Changes{Added: []string{}, Removed: []string{}, Changed: []string{}},
+		Webhooks:    snapshot.Changes{Added: []string{}, Removed: []string{"webhook-1"}, Changed: []string{}},
+		Rooms:       snapshot.Changes{Added: []string{}, Removed: []string{}, Changed: []string{}},
+		RoomAccess:  snapshot.Changes{Added: []string{}, Removed: []string{}, Changed: []string{}},
+		Settings:    snapshot.Changes{Added: []string{}, Removed: []string{}, Changed: []string{"walk_in_cap"}},
+		Waitlist:    snapshot.Changes{Added: []string{}, Removed: []string{"waitlist-1"}, Changed: []string{}},
+	}
+	if !reflect.DeepEqual(diff, expectedDiff) {
+		t.Errorf("Expected changes %+v, got %+v\n", expectedDiff, diff)
+	}
+}
@@ -0,0 +1,132 @@
+// Package testdatabase Databases the tests run against, shared by the test packages
+package testdatabase
+
+import (
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"guestListChallenge/src/database"
+	"os"
+	"path/filepath"
+)
+
+// DriverVariable Environment variable choosing the database driver the tests run against, SQLite by default
+const DriverVariable = "GUESTLIST_TEST_DRIVER"
+
+// Open Opens an empty database of the given driver, SQLite when empty, MySQL and PostgreSQL being run in Docker
+//
+// Returns a function closing the database and removing it
+func Open(driver string) (*gorm.DB, func()) {
+	switch driver {
+	case "", database.DriverSQLite:
+		return OpenSQLite()
+	case database.DriverMySQL, database.DriverPostgres:
+		return OpenDocker(driver)
+	default:
+		panic("Unknown database driver " + driver + " in " + DriverVariable)
+	}
+}
+
+// OpenSQLite Opens a SQLite database in a temporary file
+//
+// Returns a function removing the file
+func OpenSQLite() (*gorm.DB, func()) {
+	directory, operationError := os.MkdirTemp("", "guestlist-test")
+	if operationError != nil {
+		fmt.Println(operationError.Error())
+		panic("Could not create SQLite database directory")
+	}
+
+	config := database.DefaultConfig()
+	config.Driver = database.DriverSQLite
+	config.Path = filepath.Join(directory, "guestlist.db")
+
+	testStore, operationError := database.Open(config)
+	if operationError != nil {
+		fmt.Println(operationError.Error())
+		panic("Could not open SQLite database")
+	}
+
+	return testStore.DB(), func() {
+		testStore.Close()
+		os.RemoveAll(directory)
+	}
+}
+
+// OpenDocker Opens a MySQL or PostgreSQL database running in a Docker container
+//
+// Returns a function deleting the container
+func OpenDocker(driver string) (*gorm.DB, func()) {
+
+	// Create pool for the Docker container
+	pool, operationError := dockertest.NewPool("")
+	if operationError != nil {
+		fmt.Println(operationError.Error())
+		panic("Could not connect to Docker")
+	}
+
+	// Setup container options
+	config := database.DefaultConfig()
+	config.Driver = driver
+	config.ServerName = "localhost"
+	opts := dockertest.RunOptions{
+		Repository: "mysql",
+		Tag:        "5.7",
+		Env: []string{
+			"MYSQL_ROOT_PASSWORD=password",
+			"MYSQL_DATABASE=" + config.DBName,
+			"MYSQL_USER=" + config.User,
+			"MYSQL_PASSWORD=" + config.Password},
+		ExposedPorts: []string{"3306"},
+	}
+	port := "3306/tcp"
+	if driver == database.DriverPostgres {
+		opts = dockertest.RunOptions{
+			Repository: "postgres",
+			Tag:        "14-alpine",
+			Env: []string{
+				"POSTGRES_DB=" + config.DBName,
+				"POSTGRES_USER=" + config.User,
+				"POSTGRES_PASSWORD=" + config.Password},
+			ExposedPorts: []string{"5432"},
+		}
+		port = "5432/tcp"
+	}
+
+	// Run Docker container
+	resource, operationError := pool.RunWithOptions(&opts, func(hostConfig *docker.HostConfig) {
+		hostConfig.AutoRemove = true
+	})
+	if operationError != nil {
+		fmt.Println(operationError.Error())
+		panic("Could not start " + driver + " Docker container")
+	}
+	config.ServerPort = resource.GetPort(port)
+
+	// Exponential backoff mechanism to wait for the database boot
+	var testStore *database.Store
+	if operationError := pool.Retry(func() error {
+		testStore, operationError = database.Open(config)
+		if operationError != nil {
+			fmt.Println(driver + " database still booting")
+			return operationError
+		}
+
+		// Check if database is reachable
+		return testStore.DB().DB().Ping()
+	}); operationError != nil {
+		fmt.Println(operationError.Error())
+		panic("Could not connect to " + driver + " Docker container")
+	}
+
+	return testStore.DB(), func() {
+		testStore.Close()
+
+		// Delete Docker container
+		if operationError := pool.Purge(resource); operationError != nil {
+			fmt.Println(operationError.Error())
+			panic("Could not purge " + driver + " Docker container")
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package ticketstest
+
+import (
+	"guestListChallenge/src/tickets"
+	"strings"
+	"testing"
+	"time"
+)
+
+// now Time at which the tickets are verified
+var now = time.Date(2021, time.December, 17, 21, 5, 0, 0, time.UTC)
+
+// TestValidTicket Tests that an issued ticket is accepted and carries its guest
+func TestValidTicket(t *testing.T) {
+	signer := tickets.NewSigner([]byte("secret"), "party")
+
+	token, err := signer.Issue("guest-francisco", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Couldn't issue ticket: %v", err)
+	}
+
+	claims, err := signer.Verify(token, now)
+	if err != nil {
+		t.Fatalf("Valid ticket was rejected: %v", err)
+	}
+	if claims.GuestID != "guest-francisco" || claims.EventID != "party" || claims.TicketID == "" {
+		t.Errorf("Wrong ticket claims %+v", claims)
+	}
+
+	otherToken, err := signer.Issue("guest-francisco", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Couldn't issue ticket: %v", err)
+	}
+	if otherClaims, _ := signer.Verify(otherToken, now); otherClaims.TicketID == claims.TicketID {
+		t.Error("Two tickets share the same ID")
+	}
+}
+
+// TestRejectedTickets Tests that forged, expired and foreign tickets are rejected
+func TestRejectedTickets(t *testing.T) {
+	signer := tickets.NewSigner([]byte("secret"), "party")
+
+	token, err := signer.Issue("guest-francisco", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Couldn't issue ticket: %v", err)
+	}
+	forgedToken, err := tickets.NewSigner([]byte("guessed"), "party").Issue("guest-francisco", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Couldn't issue ticket: %v", err)
+	}
+	foreignToken, err := tickets.NewSigner([]byte("secret"), "other party").Issue("guest-francisco", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Couldn't issue ticket: %v", err)
+	}
+	tokenParts := strings.Split(token, ".")
+
+	testCases := []struct {
+		testCaseName  string
+		token         string
+		verifiedAt    time.Time
+		expectedError error
+	}{
+		{"Garbage", "not a ticket", now, tickets.ErrMalformed},
+		{"Signed with another key", forgedToken, now, tickets.ErrForged},
+		{"Tampered claims", forgedToken[:strings.Index(forgedToken, ".")] + "." + tokenParts[1], now, tickets.ErrForged},
+		{"Issued for another event", foreignToken, now, tickets.ErrWrongEvent},
+		{"Expired", token, now.Add(time.Hour), tickets.ErrExpired},
+	}
+
+	for _, testCase := range testCases {
+		if _, err := signer.Verify(testCase.token, testCase.verifiedAt); err != testCase.expectedError {
+			t.Errorf("%s: expected %v, received %v", testCase.testCaseName, testCase.expectedError, err)
+		}
+	}
+}
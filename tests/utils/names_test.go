@@ -0,0 +1,93 @@
+package utilstest
+
+import (
+	"guestListChallenge/src/utils"
+	"testing"
+)
+
+// TestNormalizeName Tests that names are compared ignoring case, accents and punctuation
+func TestNormalizeName(t *testing.T) {
+	testCases := map[string]string{
+		"Francisco":              "francisco",
+		"  JOSÉ   Martíns ":      "jose martins",
+		"Zoë O'Neil":             "zoe o neil",
+		"Łukasz Żółć":            "lukasz zolc",
+		"Jürgen Großmann":        "jurgen grossmann",
+		"Ana-Maria da Conceição": "ana maria da conceicao",
+	}
+
+	for name, expectedName := range testCases {
+		if normalizedName := utils.NormalizeName(name); normalizedName != expectedName {
+			t.Errorf("Wrong normalization of %q: expected %q, received %q", name, expectedName, normalizedName)
+		}
+	}
+}
+
+// TestLevenshteinDistance Tests the edit distance between strings
+func TestLevenshteinDistance(t *testing.T) {
+	testCases := []struct {
+		a        string
+		b        string
+		distance int
+	}{
+		{"", "", 0},
+		{"kitten", "sitting", 3},
+		{"francisco", "fransisco", 1},
+		{"martins", "", 7},
+		{"josé", "jose", 1},
+	}
+
+	for _, testCase := range testCases {
+		if distance := utils.LevenshteinDistance(testCase.a, testCase.b); distance != testCase.distance {
+			t.Errorf("Wrong distance between %q and %q: expected %d, received %d", testCase.a, testCase.b, testCase.distance, distance)
+		}
+	}
+}
+
+// TestSoundex Tests the Soundex codes of reference names
+func TestSoundex(t *testing.T) {
+	testCases := map[string]string{
+		"Robert":   "R163",
+		"Rupert":   "R163",
+		"Ashcraft": "A261",
+		"Tymczak":  "T522",
+		"Pfister":  "P236",
+		"Lee":      "L000",
+		"Müller":   "M460",
+		"42":       "",
+	}
+
+	for word, expectedCode := range testCases {
+		if code := utils.Soundex(word); code != expectedCode {
+			t.Errorf("Wrong Soundex code of %q: expected %q, received %q", word, expectedCode, code)
+		}
+	}
+}
+
+// TestNameSimilarity Tests that typed names are close to the names they refer to
+func TestNameSimilarity(t *testing.T) {
+	if similarity := utils.NameSimilarity("jose martins", "José Martins"); similarity != 1 {
+		t.Errorf("Same names with different case and accents are not identical: %v", similarity)
+	}
+
+	closeQueries := []struct {
+		query string
+		name  string
+	}{
+		{"Fransisco", "Francisco"},
+		{"Martins", "José Martins"},
+		{"Smyth", "Alex Smith"},
+		{"alx smith", "Alex Smith"},
+	}
+	for _, closeQuery := range closeQueries {
+		closeSimilarity := utils.NameSimilarity(closeQuery.query, closeQuery.name)
+		unrelatedSimilarity := utils.NameSimilarity(closeQuery.query, "Bartholomew Quint")
+		if closeSimilarity < 0.6 || closeSimilarity <= unrelatedSimilarity {
+			t.Errorf("%q is not close enough to %q: %v (unrelated name: %v)", closeQuery.query, closeQuery.name, closeSimilarity, unrelatedSimilarity)
+		}
+	}
+
+	if similarity := utils.NameSimilarity("", "Francisco"); similarity != 0 {
+		t.Errorf("Empty query matches: %v", similarity)
+	}
+}
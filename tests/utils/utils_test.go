@@ -2,14 +2,16 @@ package utilstest
 
 import (
 	"guestListChallenge/src/utils"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestGetHoursAndMinutesString Tests the correctness of the GetHoursAndMinutesString return
 func TestGetHoursAndMinutesString(t *testing.T) {
-	timeString := utils.GetHoursAndMinutesString()
+	timeString := utils.GetHoursAndMinutesString(utils.RealClock{})
 	timeStringContents := strings.Split(timeString, ":")
 
 	if len(timeStringContents) != 2 {
@@ -22,3 +24,51 @@ func TestGetHoursAndMinutesString(t *testing.T) {
 		}
 	}
 }
+
+// TestGetHoursAndMinutesStringFakeClock Tests that GetHoursAndMinutesString reports the time of the given clock
+func TestGetHoursAndMinutesStringFakeClock(t *testing.T) {
+	clock := utils.NewFakeClock(time.Date(2021, time.December, 17, 13, 37, 59, 0, time.UTC))
+
+	if timeString := utils.GetHoursAndMinutesString(clock); timeString != "13:37" {
+		t.Errorf("Wrong time %q, expected %q", timeString, "13:37")
+	}
+
+	clock.Advance(time.Second)
+	if timeString := utils.GetHoursAndMinutesString(clock); timeString != "13:38" {
+		t.Errorf("Wrong time %q, expected %q", timeString, "13:38")
+	}
+}
+
+// TestParseSimulatedTime Tests the parsing of rehearsal start times
+func TestParseSimulatedTime(t *testing.T) {
+	today := time.Date(2021, time.December, 17, 9, 0, 0, 0, time.UTC)
+
+	start, err := utils.ParseSimulatedTime("22:30", today)
+	if err != nil {
+		t.Fatalf("Couldn't parse time: %v", err)
+	}
+	if !start.Equal(time.Date(2021, time.December, 17, 22, 30, 0, 0, time.UTC)) {
+		t.Errorf("Wrong simulated time %v", start)
+	}
+
+	if _, err := utils.ParseSimulatedTime("half past ten", today); err == nil {
+		t.Error("Invalid time was accepted")
+	}
+}
+
+// TestUUIDGenerator Tests that generated IDs are well formed version 4 UUIDs and unique
+func TestUUIDGenerator(t *testing.T) {
+	uuidFormat := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	generatedIDs := map[string]bool{}
+
+	for index := 0; index < 100; index++ {
+		id := utils.UUIDGenerator{}.NewID()
+		if !uuidFormat.MatchString(id) {
+			t.Errorf("Wrong UUID format %q", id)
+		}
+		if generatedIDs[id] {
+			t.Errorf("UUID %q generated twice", id)
+		}
+		generatedIDs[id] = true
+	}
+}
@@ -0,0 +1,95 @@
+package webhookstest
+
+import (
+	"guestListChallenge/src/webhooks"
+	"testing"
+	"time"
+)
+
+// TestSignature Checks that payloads are signed with the webhook's secret
+func TestSignature(t *testing.T) {
+	payload := []byte(`{"event":"guest_arrived"}`)
+	signature := webhooks.Sign("a secret of the webhook", payload)
+
+	// Known HMAC-SHA256 of the payload, so that receivers in other languages can check their implementation
+	expectedSignature := "sha256=2d30bcaf1d00b26bc16f8b95d4394b829cdd7fcf625f7af4ba4c7e8a38040f13"
+	if signature != expectedSignature {
+		t.Errorf("Unexpected signature %q\n", signature)
+	}
+
+	var testCases = []struct {
+		testCaseName string
+		secret       string
+		payload      []byte
+		signature    string
+		expectedOk   bool
+	}{
+		{"Matching signature", "a secret of the webhook", payload, signature, true},
+		{"Other secret", "another secret of the webhook", payload, signature, false},
+		{"Tampered payload", "a secret of the webhook", []byte(`{"event":"guest_left"}`), signature, false},
+		{"Missing prefix", "a secret of the webhook", payload, signature[len("sha256="):], false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testCaseName, func(t *testing.T) {
+			if ok := webhooks.Verify(testCase.secret, testCase.payload, testCase.signature); ok != testCase.expectedOk {
+				t.Errorf("Expected verification %v, got %v\n", testCase.expectedOk, ok)
+			}
+		})
+	}
+}
+
+// TestRetryDelay Checks that the delay between attempts doubles up to the maximum
+func TestRetryDelay(t *testing.T) {
+	config := webhooks.Config{InitialRetryDelay: 30 * time.Second, MaxRetryDelay: 5 * time.Minute}
+
+	expectedDelays := []time.Duration{
+		30 * time.Second,
+		time.Minute,
+		2 * time.Minute,
+		4 * time.Minute,
+		5 * time.Minute,
+		5 * time.Minute,
+	}
+	for index, expectedDelay := range expectedDelays {
+		if delay := config.RetryDelay(index + 1); delay != expectedDelay {
+			t.Errorf("Expected a delay of %v after %d failed attempts, got %v\n", expectedDelay, index+1, delay)
+		}
+	}
+}
+
+// TestCheckURL Checks that webhooks only send events to http and https URLs outside the loopback interface, private networks
+// and link-local addresses, unless private targets are allowed
+func TestCheckURL(t *testing.T) {
+	testCases := []struct {
+		url                  string
+		expectedError        bool
+		expectedPrivateError bool
+	}{
+		{"https://hr.example.com/hooks/party", false, false},
+		{"http://203.0.113.7:8080/hooks", false, false},
+		{"ftp://hr.example.com/hooks", true, true},
+		{"/hooks/party", true, true},
+		{"http://localhost:4242/hooks", true, false},
+		{"http://127.0.0.1/hooks", true, false},
+		{"http://[::1]/hooks", true, false},
+		{"http://10.1.2.3/hooks", true, false},
+		{"http://172.20.0.5/hooks", true, false},
+		{"http://192.168.1.10/hooks", true, false},
+		{"http://169.254.169.254/latest/meta-data/", true, false},
+		{"http://[fe80::1]/hooks", true, false},
+		{"http://[::ffff:127.0.0.1]/hooks", true, false},
+	}
+
+	config := webhooks.DefaultConfig()
+	privateConfig := webhooks.DefaultConfig()
+	privateConfig.AllowPrivateTargets = true
+	for _, testCase := range testCases {
+		if checkError := config.CheckURL(testCase.url); (checkError != nil) != testCase.expectedError {
+			t.Errorf("%s: expected an error %v, got %v\n", testCase.url, testCase.expectedError, checkError)
+		}
+		if checkError := privateConfig.CheckURL(testCase.url); (checkError != nil) != testCase.expectedPrivateError {
+			t.Errorf("%s with private targets allowed: expected an error %v, got %v\n", testCase.url, testCase.expectedPrivateError, checkError)
+		}
+	}
+}